@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -9,8 +10,11 @@ import (
 	"syscall"
 	"time"
 
+	"survey2earn-backend/internal/apperror"
 	"survey2earn-backend/internal/config"
 	"survey2earn-backend/internal/database"
+	"survey2earn-backend/internal/middleware"
+	"survey2earn-backend/internal/routes"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -33,9 +37,13 @@ func main() {
 	}
 	defer db.Close()
 
-	// Run migrations
-	if err := db.AutoMigrate(); err != nil {
-		logrus.Fatalf("Failed to run migrations: %v", err)
+	// Refuse to serve an outdated schema; migrations run with
+	// "survey2earnctl migrate up" before the server starts
+	if err := checkSchema(db); err != nil {
+		logrus.Fatalf("Database schema is not ready: %v", err)
+	}
+	if err := db.Seed(); err != nil {
+		logrus.Warnf("Failed to seed data: %v", err)
 	}
 
 	// Setup Gin mode
@@ -46,9 +54,16 @@ func main() {
 	// Create Gin router
 	router := gin.New()
 
+	// Client IPs, which requests are rate limited by, are only taken from
+	// X-Forwarded-For when a trusted proxy sent the request
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logrus.Fatalf("Invalid trusted proxies: %v", err)
+	}
+
 	// Add middleware
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
+	router.Use(middleware.Errors())
 
 	// Add CORS middleware
 	router.Use(corsMiddleware(cfg))
@@ -78,27 +93,37 @@ func main() {
 			"name":        "Survey2Earn Backend",
 			"version":     cfg.Server.APIVersion,
 			"environment": cfg.Server.Env,
-			"api_docs":    "/api/v1/docs",
+			"api_docs":    "/api/" + cfg.Server.APIVersion + "/docs",
 		})
 	})
 
-	// Setup API routes (will be implemented in next phase)
-	api := router.Group("/api/" + cfg.Server.APIVersion)
-	{
-		api.GET("/status", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{
-				"status":    "ok",
-				"timestamp": time.Now().UTC(),
-				"db_stats":  db.GetStats(),
-			})
+	// Status endpoint
+	router.GET("/api/"+cfg.Server.APIVersion+"/status", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status":    "ok",
+			"timestamp": time.Now().UTC(),
+			"db_stats":  db.GetStats(),
 		})
-	}
+	})
+
+	// Setup API routes and start the background workers
+	components := routes.SetupRoutes(router, cfg, db)
+
+	// Unknown routes and methods are answered as problems too
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(func(c *gin.Context) {
+		c.Error(apperror.New(apperror.NotFound, "Route not found"))
+	})
+	router.NoMethod(func(c *gin.Context) {
+		c.Error(apperror.Newf(apperror.MethodNotAllowed, "Method %s is not allowed on this route", c.Request.Method))
+	})
 
 	// Create HTTP server
 	server := &http.Server{
 		Addr:    ":" + cfg.Server.Port,
 		Handler: router,
 	}
+	server.RegisterOnShutdown(components.CloseStreams)
 
 	// Start server in a goroutine
 	go func() {
@@ -126,9 +151,42 @@ func main() {
 		logrus.Errorf("Server forced to shutdown: %v", err)
 	}
 
+	// Stop the background workers once no request can queue more work
+	components.Stop()
+
 	logrus.Info("Server exited")
 }
 
+// checkSchema fails if migrations are pending or an applied migration has
+// changed, and warns about differences between the schema and the models
+func checkSchema(db *database.Database) error {
+	migrator, err := db.Migrator()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d migrations are pending, the first is %d_%s; run \"survey2earnctl migrate up\"",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+
+	drift, err := db.CheckDrift()
+	if err != nil {
+		return err
+	}
+	for _, difference := range drift {
+		logrus.WithField("drift", difference).Warn("Database schema differs from the models")
+	}
+	return nil
+}
+
 // setupLogger configures the application logger
 func setupLogger(cfg *config.Config) {
 	// Set log level
@@ -172,7 +230,8 @@ func corsMiddleware(cfg *config.Config) gin.HandlerFunc {
 		}
 
 		c.Header("Access-Control-Allow-Methods", "GET,POST,PUT,DELETE,OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type,Authorization,X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Content-Type,Authorization,X-Requested-With,If-Match,Idempotency-Key")
+		c.Header("Access-Control-Expose-Headers", "ETag,Idempotent-Replayed")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Max-Age", "86400")
 
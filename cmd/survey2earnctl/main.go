@@ -0,0 +1,264 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"survey2earn-backend/internal/cache"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/database"
+	"survey2earn-backend/internal/repository"
+	"survey2earn-backend/internal/service"
+	"survey2earn-backend/internal/surveydef"
+
+	"github.com/sirupsen/logrus"
+)
+
+const usage = `survey2earnctl - Survey2Earn operator tool
+
+Usage:
+  survey2earnctl <command> [flags]
+
+Commands:
+  validate   Validate a survey definition file without touching the database
+  import     Import a survey definition file as a draft survey
+  export     Export a survey as a survey definition
+  migrate    Apply, revert or inspect database migrations
+  seed       Seed the platform templates, and demo data with -demo
+  create-admin
+             Make a wallet's user an admin, registering the wallet if needed
+  recompute-stats
+             Rebuild survey response summaries and user statistics
+  reconcile-balances
+             Compare user balances with the reward ledger, and fix them with -fix
+  expire-responses
+             Abandon responses left in progress for too long
+  retry-rewards
+             Requeue failed reward transactions that have retries left
+  webhook-listen
+             Print the webhook deliveries sent to a local endpoint
+  openapi    Generate the OpenAPI document from the handlers, or check it with -check
+
+Run "survey2earnctl <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch command, args := os.Args[1], os.Args[2:]; command {
+	case "validate":
+		err = runValidate(args)
+	case "import":
+		err = runImport(args)
+	case "export":
+		err = runExport(args)
+	case "seed":
+		err = runSeed(args)
+	case "create-admin":
+		err = runCreateAdmin(args)
+	case "recompute-stats":
+		err = runRecomputeStats(args)
+	case "reconcile-balances":
+		err = runReconcileBalances(args)
+	case "expire-responses":
+		err = runExpireResponses(args)
+	case "retry-rewards":
+		err = runRetryRewards(args)
+	case "migrate":
+		err = runMigrate(args)
+	case "webhook-listen":
+		err = runWebhookListen(args)
+	case "openapi":
+		err = runOpenAPI(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	file := flags.String("file", "", "survey definition file (.json, .yaml or .yml)")
+	flags.Parse(args)
+
+	definition, err := readDefinition(*file)
+	if err != nil {
+		return err
+	}
+	if err := definition.Validate(); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is a valid survey definition (%d questions)\n", *file, len(definition.Questions))
+	return nil
+}
+
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	file := flags.String("file", "", "survey definition file (.json, .yaml or .yml)")
+	userID := flags.Uint("user", 0, "ID of the user who will own the imported survey")
+	flags.Parse(args)
+
+	if *userID == 0 {
+		return fmt.Errorf("-user is required")
+	}
+
+	definition, err := readDefinition(*file)
+	if err != nil {
+		return err
+	}
+
+	services, closeDB, err := newServices()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	survey, err := services.survey.ImportSurvey(uint(*userID), definition)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %q as draft survey %d\n", survey.Title, survey.ID)
+	return nil
+}
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	surveyID := flags.Uint("survey", 0, "ID of the survey to export")
+	userID := flags.Uint("user", 0, "ID of the survey's creator")
+	format := flags.String("format", "", "json or yaml (defaults to the output file extension, then json)")
+	output := flags.String("o", "", "output file (defaults to stdout)")
+	flags.Parse(args)
+
+	if *surveyID == 0 || *userID == 0 {
+		return fmt.Errorf("-survey and -user are required")
+	}
+	if *format == "" {
+		*format = surveydef.FormatFromPath(*output)
+	}
+
+	services, closeDB, err := newServices()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	definition, err := services.survey.ExportSurvey(uint(*userID), uint(*surveyID))
+	if err != nil {
+		return err
+	}
+
+	data, err := surveydef.Encode(definition, *format)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0o644)
+}
+
+func readDefinition(path string) (*surveydef.SurveyDefinition, error) {
+	if path == "" {
+		return nil, fmt.Errorf("-file is required")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return surveydef.Decode(data, surveydef.FormatFromPath(path))
+}
+
+// openDatabase loads the environment configuration and connects to the
+// database it names
+func openDatabase() (*config.Config, *database.Database, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	logrus.SetLevel(logrus.WarnLevel)
+
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return cfg, db, nil
+}
+
+// services are what the commands work with, built the same way the API
+// server builds them
+type services struct {
+	db          *database.Database
+	survey      service.SurveyService
+	maintenance service.MaintenanceService
+}
+
+// newServices connects to the database from the environment configuration
+// and builds the services. The returned function closes the connections.
+func newServices() (*services, func(), error) {
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Changes drop the affected entries from the server's read cache
+	readCache := cache.New(cfg.Redis, cfg.Cache)
+
+	userRepo := repository.NewUserRepository(db.DB)
+	surveyRepo := repository.NewCachedSurveyRepository(repository.NewSurveyRepository(db.DB), readCache, cfg.Cache)
+	responseRepo := repository.NewResponseRepository(db.DB)
+	rewardRepo := repository.NewRewardRepository(db.DB)
+	templateRepo := repository.NewTemplateRepository(db.DB)
+	categoryRepo := repository.NewCachedCategoryRepository(repository.NewCategoryRepository(db.DB), readCache, cfg.Cache)
+
+	surveyService := service.NewSurveyService(
+		surveyRepo,
+		userRepo,
+		rewardRepo,
+		repository.NewQuotaRepository(db.DB),
+		responseRepo,
+		templateRepo,
+		repository.NewTextAnalysisRepository(db.DB),
+		categoryRepo,
+		readCache,
+		cfg.Cache,
+	)
+	templateService := service.NewTemplateService(templateRepo, categoryRepo, surveyService)
+	maintenanceService := service.NewMaintenanceService(
+		repository.NewMaintenanceRepository(db.DB),
+		userRepo,
+		responseRepo,
+		rewardRepo,
+		surveyService,
+		templateService,
+	)
+
+	closeDB := func() {
+		readCache.Close()
+		if err := db.Close(); err != nil {
+			logrus.WithError(err).Warn("Failed to close database connection")
+		}
+	}
+	return &services{
+		db:          db,
+		survey:      surveyService,
+		maintenance: maintenanceService,
+	}, closeDB, nil
+}
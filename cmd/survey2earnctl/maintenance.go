@@ -0,0 +1,180 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+func runSeed(args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	demo := flags.Bool("demo", false, "also create a demo creator, respondent and published surveys")
+	flags.Parse(args)
+
+	services, closeDB, err := newServices()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	if err := services.db.Seed(); err != nil {
+		return err
+	}
+	fmt.Println("Seeded the platform data")
+
+	if !*demo {
+		return nil
+	}
+	result, err := services.maintenance.SeedDemoData()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Demo creator is user %d, demo respondent is user %d\n", result.CreatorID, result.RespondentID)
+	if len(result.SurveyIDs) == 0 {
+		fmt.Println("Demo surveys already exist")
+	} else {
+		fmt.Printf("Published demo surveys %v\n", result.SurveyIDs)
+	}
+	return nil
+}
+
+func runCreateAdmin(args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	walletAddress := flags.String("wallet", "", "wallet address of the admin")
+	flags.Parse(args)
+
+	if *walletAddress == "" {
+		return fmt.Errorf("-wallet is required")
+	}
+
+	services, closeDB, err := newServices()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	user, created, err := services.maintenance.CreateAdmin(*walletAddress)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Printf("Registered %s as admin user %d\n", user.WalletAddress, user.ID)
+	} else {
+		fmt.Printf("User %d (%s) is an admin\n", user.ID, user.WalletAddress)
+	}
+	return nil
+}
+
+func runRecomputeStats(args []string) error {
+	flags := flag.NewFlagSet("recompute-stats", flag.ExitOnError)
+	surveyID := flags.Uint("survey", 0, "only recompute this survey's response summary")
+	userID := flags.Uint("user", 0, "only recompute this user's statistics")
+	flags.Parse(args)
+
+	services, closeDB, err := newServices()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	// With neither flag everything is recomputed
+	if *userID == 0 || *surveyID != 0 {
+		surveys, err := services.maintenance.RecomputeSurveyStatistics(*surveyID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Recomputed the statistics of %d surveys\n", surveys)
+	}
+	if *surveyID == 0 || *userID != 0 {
+		users, err := services.maintenance.RecomputeUserStats(*userID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Recomputed the statistics of %d users\n", users)
+	}
+	return nil
+}
+
+func runReconcileBalances(args []string) error {
+	flags := flag.NewFlagSet("reconcile-balances", flag.ExitOnError)
+	fix := flags.Bool("fix", false, "rewrite the differing balances from the reward ledger")
+	flags.Parse(args)
+
+	services, closeDB, err := newServices()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	discrepancies, err := services.maintenance.ReconcileBalances(*fix)
+	if err != nil {
+		return err
+	}
+	if len(discrepancies) == 0 {
+		fmt.Println("All balances match the reward ledger")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "USER\tEARNED\tLEDGER EARNED\tUSER EARNED\tWITHDRAWN\tLEDGER WITHDRAWN\tPENDING\tLEDGER PENDING\tAVAILABLE\tLEDGER AVAILABLE\t")
+	for _, d := range discrepancies {
+		recordedEarned := fmt.Sprintf("%.6f", d.Recorded.TotalEarned)
+		if d.Missing {
+			recordedEarned = "no balance"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%.6f\t%.6f\t%.6f\t%.6f\t%.6f\t%.6f\t%.6f\t%.6f\t\n",
+			d.UserID, recordedEarned, d.Expected.TotalEarned, d.UserTotalEarned,
+			d.Recorded.TotalWithdrawn, d.Expected.TotalWithdrawn,
+			d.Recorded.PendingBalance, d.Expected.PendingBalance,
+			d.Recorded.AvailableBalance, d.Expected.AvailableBalance)
+	}
+	w.Flush()
+
+	if *fix {
+		fmt.Printf("Fixed %d balances\n", len(discrepancies))
+		return nil
+	}
+	return fmt.Errorf("%d balances differ from the reward ledger, run with -fix to rewrite them", len(discrepancies))
+}
+
+func runExpireResponses(args []string) error {
+	flags := flag.NewFlagSet("expire-responses", flag.ExitOnError)
+	olderThan := flags.Duration("older-than", 24*time.Hour, "abandon responses in progress idle for longer than this")
+	flags.Parse(args)
+
+	services, closeDB, err := newServices()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	abandoned, err := services.maintenance.ExpireStaleResponses(*olderThan)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Abandoned %d responses idle for more than %s\n", abandoned, *olderThan)
+	return nil
+}
+
+func runRetryRewards(args []string) error {
+	flags := flag.NewFlagSet("retry-rewards", flag.ExitOnError)
+	flags.Parse(args)
+
+	services, closeDB, err := newServices()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	requeued, exhausted, err := services.maintenance.RetryFailedRewards()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Requeued %d failed reward transactions\n", requeued)
+	if exhausted > 0 {
+		fmt.Printf("%d failed transactions have no retries left and need a manual look\n", exhausted)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"survey2earn-backend/internal/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+const migrateUsage = `Usage:
+  survey2earnctl migrate <action> [flags]
+
+Actions:
+  up                   Apply every pending migration
+  down [-steps n]      Revert the last n applied migrations (default 1)
+  to-version <version> Apply or revert migrations until <version> is the
+                       newest applied one; 0 reverts everything
+  status               List the migrations and differences between the
+                       schema and the models
+`
+
+// runMigrate applies, reverts or lists the database migrations. Migrations
+// hold an advisory lock, so running it from several replicas at once is safe.
+func runMigrate(args []string) error {
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	action, args := args[0], args[1:]
+	var version, steps int
+	switch action {
+	case "up", "status":
+	case "down":
+		flags := flag.NewFlagSet("migrate down", flag.ExitOnError)
+		flags.IntVar(&steps, "steps", 1, "number of migrations to revert")
+		flags.Parse(args)
+	case "to-version":
+		if len(args) != 1 {
+			return fmt.Errorf("to-version needs a version")
+		}
+		var err error
+		if version, err = strconv.Atoi(args[0]); err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[0])
+		}
+	case "help", "-h", "--help":
+		fmt.Print(migrateUsage)
+		return nil
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate action %q\n\n%s", action, migrateUsage)
+		os.Exit(2)
+	}
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			logrus.WithError(err).Warn("Failed to close database connection")
+		}
+	}()
+
+	migrator, err := db.Migrator()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var ran []database.Migration
+	switch action {
+	case "status":
+		return printMigrationStatus(ctx, db, migrator)
+	case "up":
+		ran, err = migrator.Up(ctx)
+	case "down":
+		ran, err = migrator.Down(ctx, steps)
+	case "to-version":
+		ran, err = migrator.To(ctx, version)
+	}
+
+	// Report what ran before a failure too
+	for _, migration := range ran {
+		// to-version reverts the migrations newer than the target
+		verb := "Applied"
+		if action == "down" || (action == "to-version" && migration.Version > version) {
+			verb = "Reverted"
+		}
+		fmt.Printf("%s %d_%s\n", verb, migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(ran) == 0 {
+		fmt.Println("Nothing to migrate")
+	}
+	return nil
+}
+
+func printMigrationStatus(ctx context.Context, db *database.Database, migrator *database.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+	modified := false
+	for _, status := range statuses {
+		state := "pending"
+		if status.Applied {
+			state = "applied " + status.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		if status.Modified {
+			state += " (changed since applied)"
+			modified = true
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, state)
+	}
+	w.Flush()
+
+	drift, err := db.CheckDrift()
+	if err != nil {
+		return err
+	}
+	if len(drift) > 0 {
+		fmt.Println("\nSchema drift:")
+		for _, difference := range drift {
+			fmt.Printf("  %s\n", difference)
+		}
+	}
+
+	if modified {
+		return database.ErrChecksumMismatch
+	}
+	if len(drift) > 0 {
+		return errors.New("the schema differs from the models")
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"survey2earn-backend/internal/openapi"
+)
+
+// runOpenAPI writes the OpenAPI document generated from the handlers, or
+// with -check fails when the document written before is out of date
+func runOpenAPI(args []string) error {
+	flags := flag.NewFlagSet("openapi", flag.ExitOnError)
+	root := flags.String("root", ".", "root directory of the module")
+	output := flags.String("o", "internal/apidocs/openapi.json", "file to write the document to")
+	check := flags.Bool("check", false, "only check that the file is up to date")
+	flags.Parse(args)
+
+	doc, err := openapi.Generate(*root)
+	if err != nil {
+		return err
+	}
+	data, err := openapi.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	if *check {
+		current, err := os.ReadFile(*output)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(current, data) {
+			return fmt.Errorf("%s is out of date, run \"go generate ./internal/apidocs\"", *output)
+		}
+		fmt.Printf("%s is up to date\n", *output)
+		return nil
+	}
+
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s (%d operations)\n", *output, countOperations(doc))
+	return nil
+}
+
+func countOperations(doc *openapi.Document) int {
+	count := 0
+	for _, item := range doc.Paths {
+		count += len(item)
+	}
+	return count
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+
+	"survey2earn-backend/internal/webhook"
+)
+
+// runWebhookListen starts a local endpoint that prints the webhook deliveries
+// it receives, to try webhooks out without a real receiver
+func runWebhookListen(args []string) error {
+	flags := flag.NewFlagSet("webhook-listen", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:4000", "address to listen on")
+	secret := flags.String("secret", "", "webhook secret to verify signatures with (optional)")
+	status := flags.Int("status", http.StatusOK, "status code to answer deliveries with, to try out retries")
+	flags.Parse(args)
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fmt.Printf("%s %s delivery %s\n", r.Header.Get(webhook.EventHeader), r.URL.Path, r.Header.Get(webhook.DeliveryHeader))
+		if *secret != "" {
+			if err := webhook.Verify(*secret, r.Header.Get(webhook.SignatureHeader), body, webhook.DefaultTolerance); err != nil {
+				fmt.Printf("  signature: %v\n", err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			fmt.Println("  signature: ok")
+		}
+
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, body, "  ", "  "); err != nil {
+			pretty.Reset()
+			pretty.Write(body)
+		}
+		fmt.Printf("  %s\n\n", pretty.String())
+
+		w.WriteHeader(*status)
+	})
+
+	fmt.Printf("Listening for webhook deliveries on http://%s\n", *addr)
+	return http.ListenAndServe(*addr, nil)
+}
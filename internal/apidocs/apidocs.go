@@ -0,0 +1,54 @@
+// internal/apidocs/apidocs.go
+//
+// Package apidocs serves the OpenAPI document of the API, and a page to
+// browse the document and try requests out. The document is generated from
+// the handlers' annotations and the dto types; regenerate it with
+// "go generate ./internal/apidocs" after changing either.
+package apidocs
+
+//go:generate go run ../../cmd/survey2earnctl openapi -root ../.. -o openapi.json
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+//go:embed openapi.json
+var document []byte
+
+//go:embed index.html
+var page []byte
+
+// Spec serves the OpenAPI document, its server set to apiPath, the path the
+// API is served under
+func Spec(apiPath string) gin.HandlerFunc {
+	served, err := withServer(document, apiPath)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to read the embedded OpenAPI document")
+	}
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", served)
+	}
+}
+
+// UI serves the page that browses the document
+func UI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+}
+
+func withServer(document []byte, apiPath string) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(document, &fields); err != nil {
+		return nil, err
+	}
+	servers, err := json.Marshal([]map[string]string{{"url": apiPath}})
+	if err != nil {
+		return nil, err
+	}
+	fields["servers"] = servers
+	return json.Marshal(fields)
+}
@@ -0,0 +1,190 @@
+// internal/apperror/apperror.go
+
+// Package apperror is the error model shared by services and handlers.
+// Services return an *Error carrying a stable code; the Errors middleware maps
+// the code to an HTTP status and renders it as an RFC 7807 problem.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// Code is a stable, machine-readable error code. Clients branch on codes
+// rather than on messages, so a code is never renamed or reused once released.
+type Code string
+
+// Generic codes
+const (
+	InvalidRequest   Code = "invalid_request"
+	Validation       Code = "validation_failed"
+	Unauthorized     Code = "unauthorized"
+	Forbidden        Code = "forbidden"
+	NotFound         Code = "not_found"
+	MethodNotAllowed Code = "method_not_allowed"
+	Conflict         Code = "conflict"
+	RateLimited      Code = "rate_limited"
+	Internal         Code = "internal_error"
+	NotImplemented   Code = "not_implemented"
+	Unavailable      Code = "service_unavailable"
+)
+
+// Specific codes
+const (
+	InvalidID                Code = "invalid_id"
+	InvalidFilter            Code = "invalid_filter"
+	InvalidCategory          Code = "invalid_category"
+	InvalidDefinition        Code = "invalid_definition"
+	InvalidFormat            Code = "invalid_format"
+	InvalidWebhook           Code = "invalid_webhook"
+	InvalidIdempotencyKey    Code = "invalid_idempotency_key"
+	AlreadyRegistered        Code = "already_registered"
+	ProfileConflict          Code = "profile_conflict"
+	CategoryInUse            Code = "category_in_use"
+	SurveyNotActive          Code = "survey_not_active"
+	SurveyFull               Code = "survey_full"
+	AlreadyResponded         Code = "already_responded"
+	ResponseNotActive        Code = "response_not_active"
+	PoolExhausted            Code = "pool_exhausted"
+	ExportNotReady           Code = "export_not_ready"
+	IdempotencyKeyInProgress Code = "idempotency_key_in_progress"
+	ResponseExpired          Code = "response_expired"
+	TimeLimitExceeded        Code = "time_limit_exceeded"
+	VersionConflict          Code = "version_conflict"
+	IdempotencyKeyReused     Code = "idempotency_key_reused"
+)
+
+// statuses maps every code to the HTTP status it is answered with
+var statuses = map[Code]int{
+	InvalidRequest:   http.StatusBadRequest,
+	Validation:       http.StatusBadRequest,
+	Unauthorized:     http.StatusUnauthorized,
+	Forbidden:        http.StatusForbidden,
+	NotFound:         http.StatusNotFound,
+	MethodNotAllowed: http.StatusMethodNotAllowed,
+	Conflict:         http.StatusConflict,
+	RateLimited:      http.StatusTooManyRequests,
+	Internal:         http.StatusInternalServerError,
+	NotImplemented:   http.StatusNotImplemented,
+	Unavailable:      http.StatusServiceUnavailable,
+
+	InvalidID:                http.StatusBadRequest,
+	InvalidFilter:            http.StatusBadRequest,
+	InvalidCategory:          http.StatusBadRequest,
+	InvalidDefinition:        http.StatusBadRequest,
+	InvalidFormat:            http.StatusBadRequest,
+	InvalidWebhook:           http.StatusBadRequest,
+	InvalidIdempotencyKey:    http.StatusBadRequest,
+	AlreadyRegistered:        http.StatusConflict,
+	ProfileConflict:          http.StatusConflict,
+	CategoryInUse:            http.StatusConflict,
+	SurveyNotActive:          http.StatusConflict,
+	SurveyFull:               http.StatusConflict,
+	AlreadyResponded:         http.StatusConflict,
+	ResponseNotActive:        http.StatusConflict,
+	PoolExhausted:            http.StatusConflict,
+	ExportNotReady:           http.StatusConflict,
+	IdempotencyKeyInProgress: http.StatusConflict,
+	ResponseExpired:          http.StatusGone,
+	TimeLimitExceeded:        http.StatusGone,
+	VersionConflict:          http.StatusPreconditionFailed,
+	IdempotencyKeyReused:     http.StatusUnprocessableEntity,
+}
+
+// Status is the HTTP status of a code. Unknown codes are internal errors.
+func (c Code) Status() int {
+	if status, ok := statuses[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// FieldError describes what is wrong with a single field of a request
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error is an error clients can act on. Message is shown to clients as is;
+// Err is the underlying cause, kept for logs and errors.Is.
+type Error struct {
+	Code    Code
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func Newf(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap gives err a code and a message for clients, keeping it as the cause
+func Wrap(err error, code Code, message string) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// Invalid is a validation error listing the fields at fault
+func Invalid(message string, fields ...FieldError) *Error {
+	return &Error{Code: Validation, Message: message, Fields: fields}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Typed is implemented by the error types of other packages, which
+// describe themselves as an *Error
+type Typed interface {
+	error
+	AppError() *Error
+}
+
+// NotFoundAs names a missing record in a not_found error. Any other error,
+// including nil, is returned unchanged.
+func NotFoundAs(err error, message string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Wrap(err, NotFound, message)
+	}
+	return err
+}
+
+// From converts any error to an *Error. Errors that aren't from the domain
+// become internal errors, whose cause is never shown to clients.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var typed Typed
+	if errors.As(err, &typed) {
+		converted := typed.AppError()
+		converted.Err = err
+		return converted
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Wrap(err, NotFound, "Resource not found")
+	}
+	return Wrap(err, Internal, "An unexpected error occurred")
+}
+
+// CodeOf is the code of err, Internal for errors outside the domain
+func CodeOf(err error) Code {
+	return From(err).Code
+}
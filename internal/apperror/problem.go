@@ -0,0 +1,37 @@
+// internal/apperror/problem.go
+package apperror
+
+import "net/http"
+
+// ProblemContentType is the media type of problem details (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix makes a code into the URI of its problem type
+const problemTypePrefix = "urn:survey2earn:problem:"
+
+// Problem is the RFC 7807 body of every error response. Code is the stable
+// code clients branch on, and Errors the field-level details, if any.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     Code         `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem describes err as a problem of the request to instance
+func NewProblem(err error, instance string) *Problem {
+	appErr := From(err)
+	status := appErr.Code.Status()
+	return &Problem{
+		Type:     problemTypePrefix + string(appErr.Code),
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   appErr.Message,
+		Instance: instance,
+		Code:     appErr.Code,
+		Errors:   appErr.Fields,
+	}
+}
@@ -0,0 +1,64 @@
+// internal/cache/cache.go
+
+// Package cache provides the read cache shared by the API server instances,
+// backed by Redis with an in-process fallback.
+package cache
+
+import (
+	"context"
+	"errors"
+	"survey2earn-backend/internal/config"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrMiss is returned by Get when a key isn't cached
+var ErrMiss = errors.New("cache miss")
+
+// Cache stores byte values under string keys. Implementations are safe for
+// concurrent use.
+type Cache interface {
+	// Get returns the value cached at key, or ErrMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set caches value at key for ttl, or without expiry if ttl is zero
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// SetNX caches value at key only if the key isn't set yet, and reports
+	// whether it did
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+
+	// Delete removes keys. Keys that aren't cached are ignored.
+	Delete(ctx context.Context, keys ...string) error
+
+	// Incr increments the integer at key, starting from zero, and returns
+	// the new value. Counters don't expire.
+	Incr(ctx context.Context, key string) (int64, error)
+
+	Close() error
+}
+
+// New returns the cache configured by cfg. The Redis driver falls back to an
+// in-process cache when Redis can't be reached, so the server still starts;
+// entries are then only invalidated on other instances through domain
+// events, and may lag by the outbox poll interval.
+func New(redisConfig config.RedisConfig, cfg config.CacheConfig) Cache {
+	switch cfg.Driver {
+	case "memory":
+		return NewMemory()
+	case "redis", "":
+	default:
+		logrus.WithField("driver", cfg.Driver).Warn("Unknown cache driver, using the in-memory cache")
+		return NewMemory()
+	}
+
+	redisCache, err := NewRedis(redisConfig, cfg.KeyPrefix)
+	if err != nil {
+		logrus.WithError(err).Warn("Redis is unavailable, falling back to the in-memory cache")
+		return NewMemory()
+	}
+
+	logrus.Info("Connected to Redis cache")
+	return redisCache
+}
@@ -0,0 +1,149 @@
+// internal/cache/fetch.go
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// fillLockTTL bounds how long a loading instance keeps other instances
+	// waiting for it, should it fail without releasing the lock
+	fillLockTTL = 5 * time.Second
+
+	// fillWaitTimeout is how long an instance waits for another instance's
+	// load before loading the value itself
+	fillWaitTimeout = 2 * time.Second
+
+	// fillPollInterval is how often a waiting instance checks for the value
+	fillPollInterval = 50 * time.Millisecond
+
+	// ttlJitter spreads the expiry of entries cached at the same time by up
+	// to this fraction of their TTL
+	ttlJitter = 0.1
+)
+
+// fills makes concurrent misses for the same key in this process share a
+// single load
+var fills singleflight.Group
+
+// Fetch decodes the JSON value cached at key into dest. On a miss it calls
+// load, caches the JSON encoding of its result for about ttl and decodes that
+// into dest, so callers always get their own copy.
+//
+// Misses are protected against stampedes: in one process concurrent misses
+// share a single load, and across processes the first to miss takes a short
+// lock while the others wait for its result. Cache errors are logged and
+// treated as misses, so the cache never fails a request; errors from load are
+// returned as is and not cached.
+func Fetch(ctx context.Context, c Cache, key string, ttl time.Duration, dest interface{}, load func() (interface{}, error)) error {
+	if data, err := c.Get(ctx, key); err == nil {
+		if err := json.Unmarshal(data, dest); err == nil {
+			return nil
+		}
+		logrus.WithField("key", key).Warn("Dropping undecodable cache entry")
+	} else if !errors.Is(err, ErrMiss) {
+		logrus.WithError(err).WithField("key", key).Warn("Cache read failed")
+	}
+
+	data, err, _ := fills.Do(key, func() (interface{}, error) {
+		return fill(ctx, c, key, ttl, load)
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data.([]byte), dest)
+}
+
+// fill loads and caches the value at key, or waits for another instance
+// that is already doing so
+func fill(ctx context.Context, c Cache, key string, ttl time.Duration, load func() (interface{}, error)) ([]byte, error) {
+	lockKey := key + ":lock"
+	locked, err := c.SetNX(ctx, lockKey, []byte("1"), fillLockTTL)
+	if err != nil {
+		logrus.WithError(err).WithField("key", key).Warn("Cache lock failed")
+	} else if !locked {
+		if data, ok := await(ctx, c, key); ok {
+			return data, nil
+		}
+	}
+
+	value, err := load()
+	if err != nil {
+		if locked {
+			c.Delete(ctx, lockKey)
+		}
+		return nil, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Set(ctx, key, data, jitter(ttl)); err != nil {
+		logrus.WithError(err).WithField("key", key).Warn("Cache write failed")
+	}
+	if locked {
+		c.Delete(ctx, lockKey)
+	}
+	return data, nil
+}
+
+// await polls key until another instance caches it or fillWaitTimeout passes
+func await(ctx context.Context, c Cache, key string) ([]byte, bool) {
+	deadline := time.After(fillWaitTimeout)
+	ticker := time.NewTicker(fillPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-deadline:
+			return nil, false
+		case <-ticker.C:
+		}
+
+		if data, err := c.Get(ctx, key); err == nil {
+			return data, true
+		} else if !errors.Is(err, ErrMiss) {
+			return nil, false
+		}
+	}
+}
+
+func jitter(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(rand.Float64()*ttlJitter*float64(ttl))
+}
+
+// Generation returns the current value of the generation counter at key.
+// Embedding it in the keys of a group of entries lets InvalidateGeneration
+// drop the whole group at once; the old entries are left to expire.
+func Generation(ctx context.Context, c Cache, key string) int64 {
+	data, err := c.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrMiss) {
+			logrus.WithError(err).WithField("key", key).Warn("Cache read failed")
+		}
+		return 0
+	}
+	generation, _ := strconv.ParseInt(string(data), 10, 64)
+	return generation
+}
+
+// InvalidateGeneration moves the generation counter at key on
+func InvalidateGeneration(ctx context.Context, c Cache, key string) {
+	if _, err := c.Incr(ctx, key); err != nil {
+		logrus.WithError(err).WithField("key", key).Warn("Cache invalidation failed")
+	}
+}
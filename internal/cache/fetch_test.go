@@ -0,0 +1,241 @@
+// internal/cache/fetch_test.go
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testValue struct {
+	Name  string   `json:"name"`
+	Items []string `json:"items"`
+}
+
+func newTestMemory(t *testing.T) *memoryCache {
+	c := NewMemory().(*memoryCache)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// countLoads returns a load function handing out value and the number of
+// times it was called
+func countLoads(value interface{}) (func() (interface{}, error), *int32) {
+	var loads int32
+	return func() (interface{}, error) {
+		atomic.AddInt32(&loads, 1)
+		return value, nil
+	}, &loads
+}
+
+func TestFetch(t *testing.T) {
+	c := newTestMemory(t)
+	ctx := context.Background()
+	load, loads := countLoads(testValue{Name: "survey", Items: []string{"a", "b"}})
+
+	for i := 0; i < 3; i++ {
+		var got testValue
+		if err := Fetch(ctx, c, "survey:1", time.Minute, &got, load); err != nil {
+			t.Fatal(err)
+		}
+		if got.Name != "survey" || len(got.Items) != 2 {
+			t.Fatalf("fetch %d = %+v", i, got)
+		}
+		got.Items[0] = "changed" // callers get their own copy
+	}
+	if *loads != 1 {
+		t.Errorf("loaded %d times, want once", *loads)
+	}
+
+	entry := c.entries["survey:1"]
+	if ttl := time.Until(entry.expiresAt); ttl < 59*time.Second || ttl > time.Minute+time.Minute/10 {
+		t.Errorf("entry expires in %v, want a minute plus up to 10%%", ttl)
+	}
+	if _, ok := c.entries["survey:1:lock"]; ok {
+		t.Error("the fill lock is still held")
+	}
+}
+
+func TestFetchConcurrentMissesLoadOnce(t *testing.T) {
+	const callers = 20
+	c := &missCountingCache{Cache: newTestMemory(t), misses: make(chan struct{}, callers)}
+	ctx := context.Background()
+
+	var loads int32
+	load := func() (interface{}, error) {
+		if atomic.AddInt32(&loads, 1) > 1 {
+			return testValue{Name: "categories"}, nil
+		}
+		// Hold the load until every caller has missed and queued behind it
+		for i := 0; i < callers; i++ {
+			<-c.misses
+		}
+		time.Sleep(20 * time.Millisecond)
+		return testValue{Name: "categories"}, nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var got testValue
+			if err := Fetch(ctx, c, "categories", time.Minute, &got, load); err != nil {
+				errs <- err
+			} else if got.Name != "categories" {
+				errs <- fmt.Errorf("got %+v", got)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if loads != 1 {
+		t.Errorf("%d concurrent misses loaded %d times, want once", callers, loads)
+	}
+}
+
+func TestFetchWaitsForAnotherInstance(t *testing.T) {
+	c := newTestMemory(t)
+	ctx := context.Background()
+
+	// Another instance is loading the value
+	c.SetNX(ctx, "survey:2:lock", []byte("1"), fillLockTTL)
+	go func() {
+		time.Sleep(2 * fillPollInterval)
+		c.Set(ctx, "survey:2", []byte(`{"name":"from another instance"}`), time.Minute)
+	}()
+
+	load, loads := countLoads(testValue{Name: "loaded here"})
+	var got testValue
+	if err := Fetch(ctx, c, "survey:2", time.Minute, &got, load); err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "from another instance" || *loads != 0 {
+		t.Errorf("got %+v after %d loads, want the other instance's value", got, *loads)
+	}
+}
+
+func TestFetchLoadError(t *testing.T) {
+	c := newTestMemory(t)
+	ctx := context.Background()
+	errLoad := errors.New("database is down")
+
+	var got testValue
+	err := Fetch(ctx, c, "survey:3", time.Minute, &got, func() (interface{}, error) { return nil, errLoad })
+	if !errors.Is(err, errLoad) {
+		t.Fatalf("Fetch() = %v, want the load error", err)
+	}
+	if len(c.entries) != 0 {
+		t.Errorf("entries = %v, want neither the error nor the lock cached", c.entries)
+	}
+
+	// The next fetch loads again
+	load, loads := countLoads(testValue{Name: "survey"})
+	if err := Fetch(ctx, c, "survey:3", time.Minute, &got, load); err != nil || *loads != 1 {
+		t.Errorf("Fetch() = %v after %d loads, want a fresh load", err, *loads)
+	}
+}
+
+func TestFetchReplacesUndecodableEntries(t *testing.T) {
+	c := newTestMemory(t)
+	ctx := context.Background()
+	c.Set(ctx, "survey:4", []byte(`{"name": [`), time.Minute)
+
+	load, loads := countLoads(testValue{Name: "survey"})
+	var got testValue
+	if err := Fetch(ctx, c, "survey:4", time.Minute, &got, load); err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "survey" || *loads != 1 {
+		t.Errorf("got %+v after %d loads, want the loaded value", got, *loads)
+	}
+	if data, _ := c.Get(ctx, "survey:4"); string(data) != `{"name":"survey","items":null}` {
+		t.Errorf("cached %s, want the loaded value", data)
+	}
+}
+
+func TestFetchWithoutCache(t *testing.T) {
+	load, loads := countLoads(testValue{Name: "survey"})
+	for i := 0; i < 2; i++ {
+		var got testValue
+		if err := Fetch(context.Background(), failingCache{}, "survey:5", time.Minute, &got, load); err != nil {
+			t.Fatalf("Fetch() = %v, want cache errors ignored", err)
+		}
+		if got.Name != "survey" {
+			t.Fatalf("got %+v", got)
+		}
+	}
+	if *loads != 2 {
+		t.Errorf("loaded %d times, want every fetch to load", *loads)
+	}
+}
+
+func TestGeneration(t *testing.T) {
+	c := newTestMemory(t)
+	ctx := context.Background()
+	key := func() string {
+		return fmt.Sprintf("surveys:public:%d:page:1", Generation(ctx, c, "surveys:public:generation"))
+	}
+
+	if generation := Generation(ctx, c, "surveys:public:generation"); generation != 0 {
+		t.Fatalf("generation = %d before any invalidation, want 0", generation)
+	}
+	load, loads := countLoads(testValue{Name: "page"})
+	var got testValue
+	Fetch(ctx, c, key(), time.Minute, &got, load)
+	Fetch(ctx, c, key(), time.Minute, &got, load)
+	if *loads != 1 {
+		t.Fatalf("loaded %d times, want once", *loads)
+	}
+
+	InvalidateGeneration(ctx, c, "surveys:public:generation")
+	if key() != "surveys:public:1:page:1" {
+		t.Errorf("key = %s after an invalidation, want generation 1", key())
+	}
+	Fetch(ctx, c, key(), time.Minute, &got, load)
+	if *loads != 2 {
+		t.Errorf("loaded %d times, want the invalidated page loaded again", *loads)
+	}
+	// The old entry is left to expire
+	if _, err := c.Get(ctx, "surveys:public:0:page:1"); err != nil {
+		t.Errorf("old generation entry: %v", err)
+	}
+}
+
+// missCountingCache signals every miss
+type missCountingCache struct {
+	Cache
+	misses chan struct{}
+}
+
+func (c *missCountingCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.Cache.Get(ctx, key)
+	if errors.Is(err, ErrMiss) && key == "categories" {
+		c.misses <- struct{}{}
+	}
+	return data, err
+}
+
+// failingCache fails every operation, like an unreachable Redis
+type failingCache struct{}
+
+var errUnavailable = errors.New("connection refused")
+
+func (failingCache) Get(context.Context, string) ([]byte, error) { return nil, errUnavailable }
+func (failingCache) Set(context.Context, string, []byte, time.Duration) error {
+	return errUnavailable
+}
+func (failingCache) SetNX(context.Context, string, []byte, time.Duration) (bool, error) {
+	return false, errUnavailable
+}
+func (failingCache) Delete(context.Context, ...string) error     { return errUnavailable }
+func (failingCache) Incr(context.Context, string) (int64, error) { return 0, errUnavailable }
+func (failingCache) Close() error                                { return nil }
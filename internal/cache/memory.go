@@ -0,0 +1,130 @@
+// internal/cache/memory.go
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is how often expired entries are dropped
+const memorySweepInterval = time.Minute
+
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	done    chan struct{}
+	closed  sync.Once
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time // zero for entries that don't expire
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// NewMemory returns a cache held in this process. It isn't shared between
+// server instances.
+func NewMemory() Cache {
+	c := &memoryCache{
+		entries: make(map[string]memoryEntry),
+		done:    make(chan struct{}),
+	}
+	go c.sweep()
+	return c
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || entry.expired(time.Now()) {
+		return nil, ErrMiss
+	}
+	return entry.value, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = newMemoryEntry(value, ttl)
+	return nil
+}
+
+func (c *memoryCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok && !entry.expired(time.Now()) {
+		return false, nil
+	}
+	c.entries[key] = newMemoryEntry(value, ttl)
+	return true, nil
+}
+
+func (c *memoryCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+func (c *memoryCache) Incr(ctx context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var value int64
+	if entry, ok := c.entries[key]; ok && !entry.expired(time.Now()) {
+		current, err := strconv.ParseInt(string(entry.value), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		value = current
+	}
+	value++
+	c.entries[key] = memoryEntry{value: []byte(strconv.FormatInt(value, 10))}
+	return value, nil
+}
+
+func (c *memoryCache) Close() error {
+	c.closed.Do(func() { close(c.done) })
+	return nil
+}
+
+func newMemoryEntry(value []byte, ttl time.Duration) memoryEntry {
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	return entry
+}
+
+// sweep drops expired entries until the cache is closed
+func (c *memoryCache) sweep() {
+	ticker := time.NewTicker(memorySweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case now := <-ticker.C:
+			c.mu.Lock()
+			for key, entry := range c.entries {
+				if entry.expired(now) {
+					delete(c.entries, key)
+				}
+			}
+			c.mu.Unlock()
+		}
+	}
+}
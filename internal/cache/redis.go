@@ -0,0 +1,87 @@
+// internal/cache/redis.go
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"survey2earn-backend/internal/config"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis timeouts are short: a slow cache is treated as a miss rather than
+// holding up requests
+const (
+	redisDialTimeout = 2 * time.Second
+	redisIOTimeout   = 500 * time.Millisecond
+)
+
+type redisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedis connects to Redis and checks that it is reachable. Keys are
+// stored under prefix so the cache can share a Redis database.
+func NewRedis(cfg config.RedisConfig, prefix string) (Cache, error) {
+	options, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	if cfg.Password != "" {
+		options.Password = cfg.Password
+	}
+	if cfg.DB != 0 {
+		options.DB = cfg.DB
+	}
+	options.DialTimeout = redisDialTimeout
+	options.ReadTimeout = redisIOTimeout
+	options.WriteTimeout = redisIOTimeout
+
+	client := redis.NewClient(options)
+	ctx, cancel := context.WithTimeout(context.Background(), redisDialTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &redisCache{client: client, prefix: prefix}, nil
+}
+
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	return value, err
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+func (c *redisCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, c.prefix+key, value, ttl).Result()
+}
+
+func (c *redisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	return c.client.Del(ctx, prefixed...).Err()
+}
+
+func (c *redisCache) Incr(ctx context.Context, key string) (int64, error) {
+	return c.client.Incr(ctx, c.prefix+key).Result()
+}
+
+func (c *redisCache) Close() error {
+	return c.client.Close()
+}
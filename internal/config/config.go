@@ -11,20 +11,31 @@ import (
 )
 
 type Config struct {
-	Server     ServerConfig
-	Database   DatabaseConfig
-	Redis      RedisConfig
-	JWT        JWTConfig
-	Blockchain BlockchainConfig
-	CORS       CORSConfig
-	RateLimit  RateLimitConfig
-	Logging    LoggingConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	Cache       CacheConfig
+	JWT         JWTConfig
+	Admin       AdminConfig
+	Blockchain  BlockchainConfig
+	CORS        CORSConfig
+	RateLimit   RateLimitConfig
+	Logging     LoggingConfig
+	Export      ExportConfig
+	Response    ResponseConfig
+	Idempotency IdempotencyConfig
+	Webhook     WebhookConfig
+	Outbox      OutboxConfig
 }
 
+// ServerConfig.TrustedProxies lists the addresses or CIDRs of the proxies in
+// front of the server. The client IP is only taken from X-Forwarded-For
+// when a trusted proxy sent the request; by default none is trusted.
 type ServerConfig struct {
-	Port       string
-	Env        string
-	APIVersion string
+	Port           string
+	Env            string
+	APIVersion     string
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -42,9 +53,27 @@ type RedisConfig struct {
 	DB       int
 }
 
+// CacheConfig configures the read cache. Driver is "redis", which falls back
+// to an in-process cache when Redis can't be reached at startup, or "memory".
+type CacheConfig struct {
+	Driver    string
+	KeyPrefix string
+
+	SurveyTTLSeconds        int
+	PublicSurveysTTLSeconds int
+	AnalyticsTTLSeconds     int
+	CategoriesTTLSeconds    int
+}
+
 type JWTConfig struct {
-	Secret          string
-	ExpirationHours int
+	Secret                 string
+	ExpirationHours        int
+	RefreshExpirationHours int
+}
+
+// AdminConfig lists the wallets allowed to use the admin routes
+type AdminConfig struct {
+	WalletAddresses []string
 }
 
 type BlockchainConfig struct {
@@ -62,9 +91,26 @@ type CORSConfig struct {
 	AllowedHeaders []string
 }
 
+// RateLimitConfig configures request rate limiting. RequestsPerMinute and
+// Burst apply to every route; the other rules replace them on sensitive
+// routes. Driver is "redis", to share counters between instances, which
+// falls back to per-instance counters when Redis can't be reached at
+// startup, or "memory".
 type RateLimitConfig struct {
 	RequestsPerMinute int
 	Burst             int
+	Driver            string
+	KeyPrefix         string
+
+	Login         RateLimitRule
+	StartResponse RateLimitRule
+}
+
+// RateLimitRule is a token bucket holding up to Burst requests, refilled at
+// RequestsPerMinute
+type RateLimitRule struct {
+	RequestsPerMinute int
+	Burst             int
 }
 
 type LoggingConfig struct {
@@ -72,6 +118,55 @@ type LoggingConfig struct {
 	Format string
 }
 
+// ExportConfig configures response exports. Background exports run on
+// Workers workers.
+type ExportConfig struct {
+	Dir              string
+	SyncMaxResponses int
+	Workers          int
+}
+
+// ResponseConfig configures responses in progress. Every
+// ExpiryIntervalSeconds, responses past their deadline are closed and those
+// without any activity for IdleTimeoutMinutes are abandoned; 0 disables
+// either.
+type ResponseConfig struct {
+	IdleTimeoutMinutes    int
+	ExpiryIntervalSeconds int
+}
+
+// IdempotencyConfig configures Idempotency-Key handling. Keys, and the
+// responses replayed for them, expire after TTLHours.
+type IdempotencyConfig struct {
+	TTLHours int
+}
+
+// WebhookConfig configures webhook deliveries. Webhooks can only point at
+// public addresses unless AllowPrivateNetworks is set, such as to try them
+// out against a local receiver.
+type WebhookConfig struct {
+	TimeoutSeconds       int
+	MaxAttempts          int
+	AllowPrivateNetworks bool
+}
+
+// OutboxConfig configures relaying domain events. Each sink is enabled by
+// setting its address.
+type OutboxConfig struct {
+	PollIntervalMs int
+	BatchSize      int
+	RetentionHours int
+
+	RedisURL    string
+	RedisStream string
+
+	NATSURL           string
+	NATSSubjectPrefix string
+
+	KafkaBrokers []string
+	KafkaTopic   string
+}
+
 // LoadConfig loads configuration from environment variables
 func LoadConfig() (*Config, error) {
 	// Load .env file if it exists
@@ -81,9 +176,10 @@ func LoadConfig() (*Config, error) {
 
 	config := &Config{
 		Server: ServerConfig{
-			Port:       getEnv("PORT", "8080"),
-			Env:        getEnv("ENV", "development"),
-			APIVersion: getEnv("API_VERSION", "v1"),
+			Port:           getEnv("PORT", "8080"),
+			Env:            getEnv("ENV", "development"),
+			APIVersion:     getEnv("API_VERSION", "v1"),
+			TrustedProxies: splitNonEmpty(getEnv("TRUSTED_PROXIES", "")),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -98,9 +194,21 @@ func LoadConfig() (*Config, error) {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
+		Cache: CacheConfig{
+			Driver:                  getEnv("CACHE_DRIVER", "redis"),
+			KeyPrefix:               getEnv("CACHE_KEY_PREFIX", "survey2earn:cache:"),
+			SurveyTTLSeconds:        getEnvAsInt("CACHE_SURVEY_TTL_SECONDS", 300),
+			PublicSurveysTTLSeconds: getEnvAsInt("CACHE_PUBLIC_SURVEYS_TTL_SECONDS", 60),
+			AnalyticsTTLSeconds:     getEnvAsInt("CACHE_ANALYTICS_TTL_SECONDS", 300),
+			CategoriesTTLSeconds:    getEnvAsInt("CACHE_CATEGORIES_TTL_SECONDS", 300),
+		},
 		JWT: JWTConfig{
-			Secret:          getEnv("JWT_SECRET", "change-this-secret-key"),
-			ExpirationHours: getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
+			Secret:                 getEnv("JWT_SECRET", "change-this-secret-key"),
+			ExpirationHours:        getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
+			RefreshExpirationHours: getEnvAsInt("JWT_REFRESH_EXPIRATION_HOURS", 720),
+		},
+		Admin: AdminConfig{
+			WalletAddresses: splitNonEmpty(getEnv("ADMIN_WALLET_ADDRESSES", "")),
 		},
 		Blockchain: BlockchainConfig{
 			LiskRPCURL:           getEnv("LISK_RPC_URL", "https://rpc.api.lisk.com"),
@@ -118,11 +226,49 @@ func LoadConfig() (*Config, error) {
 		RateLimit: RateLimitConfig{
 			RequestsPerMinute: getEnvAsInt("RATE_LIMIT_REQUESTS_PER_MINUTE", 60),
 			Burst:             getEnvAsInt("RATE_LIMIT_BURST", 10),
+			Driver:            getEnv("RATE_LIMIT_DRIVER", "redis"),
+			KeyPrefix:         getEnv("RATE_LIMIT_KEY_PREFIX", "survey2earn:ratelimit:"),
+			Login: RateLimitRule{
+				RequestsPerMinute: getEnvAsInt("RATE_LIMIT_LOGIN_REQUESTS_PER_MINUTE", 10),
+				Burst:             getEnvAsInt("RATE_LIMIT_LOGIN_BURST", 5),
+			},
+			StartResponse: RateLimitRule{
+				RequestsPerMinute: getEnvAsInt("RATE_LIMIT_START_RESPONSE_REQUESTS_PER_MINUTE", 10),
+				Burst:             getEnvAsInt("RATE_LIMIT_START_RESPONSE_BURST", 3),
+			},
 		},
 		Logging: LoggingConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
 			Format: getEnv("LOG_FORMAT", "json"),
 		},
+		Export: ExportConfig{
+			Dir:              getEnv("EXPORT_DIR", "./exports"),
+			SyncMaxResponses: getEnvAsInt("EXPORT_SYNC_MAX_RESPONSES", 5000),
+			Workers:          getEnvAsInt("EXPORT_WORKERS", 2),
+		},
+		Response: ResponseConfig{
+			IdleTimeoutMinutes:    getEnvAsInt("RESPONSE_IDLE_TIMEOUT_MINUTES", 1440),
+			ExpiryIntervalSeconds: getEnvAsInt("RESPONSE_EXPIRY_INTERVAL_SECONDS", 300),
+		},
+		Idempotency: IdempotencyConfig{
+			TTLHours: getEnvAsInt("IDEMPOTENCY_KEY_TTL_HOURS", 24),
+		},
+		Webhook: WebhookConfig{
+			TimeoutSeconds:       getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10),
+			MaxAttempts:          getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			AllowPrivateNetworks: getEnvAsBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false),
+		},
+		Outbox: OutboxConfig{
+			PollIntervalMs:    getEnvAsInt("OUTBOX_POLL_INTERVAL_MS", 500),
+			BatchSize:         getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			RetentionHours:    getEnvAsInt("OUTBOX_RETENTION_HOURS", 168),
+			RedisURL:          getEnv("OUTBOX_REDIS_URL", ""),
+			RedisStream:       getEnv("OUTBOX_REDIS_STREAM", "survey2earn:events"),
+			NATSURL:           getEnv("OUTBOX_NATS_URL", ""),
+			NATSSubjectPrefix: getEnv("OUTBOX_NATS_SUBJECT_PREFIX", "survey2earn"),
+			KafkaBrokers:      splitNonEmpty(getEnv("OUTBOX_KAFKA_BROKERS", "")),
+			KafkaTopic:        getEnv("OUTBOX_KAFKA_TOPIC", "survey2earn.events"),
+		},
 	}
 
 	return config, nil
@@ -154,6 +300,26 @@ func getEnvAsInt64(key string, defaultValue int64) int64 {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// splitNonEmpty splits a comma-separated list, dropping empty items
+func splitNonEmpty(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // GetDatabaseDSN returns the PostgreSQL connection string
 func (c *Config) GetDatabaseDSN() string {
 	dsn := "host=" + c.Database.Host +
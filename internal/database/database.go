@@ -56,82 +56,28 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 	return &Database{DB: db}, nil
 }
 
-func (d *Database) AutoMigrate() error {
-	log.Println("Running database migrations...")
-	
-	err := d.DB.AutoMigrate(
-		&models.User{},
-		&models.AuthSession{},
-		&models.UserStats{},
-		&models.UserBalance{},
-		
-		&models.Survey{},
-		&models.Question{},
-		
-		&models.Response{},
-		&models.Answer{},
-		&models.ResponseSummary{},
-		
-		&models.RewardPool{},
-		&models.RewardTransaction{},
-		&models.WithdrawalRequest{},
-	)
-	
-	if err != nil {
-		return fmt.Errorf("failed to run migrations: %w", err)
-	}
-	
-	if err := d.createIndexes(); err != nil {
-		return fmt.Errorf("failed to create indexes: %w", err)
-	}
-	
-	if err := d.seedData(); err != nil {
-		log.Printf("Warning: failed to seed data: %v", err)
-	}
-	
-	log.Println("Database migrations completed successfully")
-	return nil
+// Seed adds the data the platform needs, such as the curated survey
+// templates, when it is missing. The schema and the default categories come
+// from the migrations.
+func (d *Database) Seed() error {
+	return d.seedTemplates()
 }
 
-func (d *Database) createIndexes() error {
-	d.DB.Exec("CREATE INDEX IF NOT EXISTS idx_users_wallet_lower ON users(LOWER(wallet_address))")
-	d.DB.Exec("CREATE INDEX IF NOT EXISTS idx_users_reputation ON users(reputation_score DESC)")
-	
-	d.DB.Exec("CREATE INDEX IF NOT EXISTS idx_surveys_status_category ON surveys(status, category)")
-	d.DB.Exec("CREATE INDEX IF NOT EXISTS idx_surveys_created_at ON surveys(created_at DESC)")
-	d.DB.Exec("CREATE INDEX IF NOT EXISTS idx_surveys_active ON surveys(status, start_date, end_date)")
-	
-	d.DB.Exec("CREATE INDEX IF NOT EXISTS idx_responses_user_survey ON responses(user_id, survey_id)")
-	d.DB.Exec("CREATE INDEX IF NOT EXISTS idx_responses_completed_at ON responses(completed_at DESC)")
-	
-	d.DB.Exec("CREATE INDEX IF NOT EXISTS idx_transactions_status_created ON reward_transactions(status, created_at)")
-	d.DB.Exec("CREATE INDEX IF NOT EXISTS idx_transactions_user_status ON reward_transactions(user_id, status)")
-	
-	return nil
-}
-
-func (d *Database) seedData() error {
+func (d *Database) seedTemplates() error {
 	var count int64
-	d.DB.Model(&models.User{}).Count(&count)
+	if err := d.DB.Model(&models.SurveyTemplate{}).Where("owner_id IS NULL").Count(&count).Error; err != nil {
+		return err
+	}
 	if count > 0 {
-		return nil 
+		return nil
 	}
 	
-	categories := []string{
-		"Technology",
-		"Finance",
-		"Healthcare", 
-		"Education",
-		"Entertainment",
-		"Gaming",
-		"DeFi",
-		"NFT",
-		"AI/ML",
-		"General",
+	templates := defaultTemplates()
+	if err := d.DB.Create(&templates).Error; err != nil {
+		return fmt.Errorf("failed to seed survey templates: %w", err)
 	}
 	
-	log.Printf("Available survey categories: %v", categories)
-	
+	log.Printf("Seeded %d platform survey templates", len(templates))
 	return nil
 }
 
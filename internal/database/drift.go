@@ -0,0 +1,106 @@
+// internal/database/drift.go
+package database
+
+import (
+	"fmt"
+	"sort"
+
+	"survey2earn-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// schemaModels are the models stored in the database, checked against the
+// live schema for drift
+var schemaModels = []interface{}{
+	&models.User{},
+	&models.AuthSession{},
+	&models.UserStats{},
+	&models.UserBalance{},
+
+	&models.Survey{},
+	&models.Question{},
+	&models.SurveyQuota{},
+	&models.BankQuestion{},
+	&models.SurveyTemplate{},
+	&models.Category{},
+
+	&models.Response{},
+	&models.Answer{},
+	&models.ResponseSummary{},
+	&models.TextAnalysis{},
+	&models.ExportJob{},
+
+	&models.RewardPool{},
+	&models.RewardTransaction{},
+	&models.WithdrawalRequest{},
+
+	&models.Webhook{},
+	&models.WebhookDelivery{},
+
+	&models.OutboxEvent{},
+	&models.OutboxOffset{},
+
+	&models.IdempotencyKey{},
+}
+
+// CheckDrift compares the live schema with the models and describes every
+// difference: missing tables, columns and indexes, columns the models don't
+// know and nullability the models don't expect. A model change without a
+// migration shows up here.
+func (d *Database) CheckDrift() ([]string, error) {
+	migrator := d.DB.Migrator()
+	var drift []string
+
+	for _, model := range schemaModels {
+		stmt := &gorm.Statement{DB: d.DB}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		table := stmt.Schema.Table
+
+		if !migrator.HasTable(model) {
+			drift = append(drift, fmt.Sprintf("table %s is missing", table))
+			continue
+		}
+
+		columnTypes, err := migrator.ColumnTypes(model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the columns of %s: %w", table, err)
+		}
+		live := make(map[string]gorm.ColumnType, len(columnTypes))
+		for _, columnType := range columnTypes {
+			live[columnType.Name()] = columnType
+		}
+
+		for _, name := range stmt.Schema.DBNames {
+			field := stmt.Schema.FieldsByDBName[name]
+			columnType, ok := live[name]
+			if !ok {
+				drift = append(drift, fmt.Sprintf("column %s.%s is missing", table, name))
+				continue
+			}
+			delete(live, name)
+
+			if nullable, ok := columnType.Nullable(); ok && nullable && field.NotNull {
+				drift = append(drift, fmt.Sprintf("column %s.%s allows NULL but the model requires a value", table, name))
+			}
+		}
+		extra := make([]string, 0, len(live))
+		for name := range live {
+			extra = append(extra, name)
+		}
+		sort.Strings(extra)
+		for _, name := range extra {
+			drift = append(drift, fmt.Sprintf("column %s.%s is not in the model", table, name))
+		}
+
+		for _, index := range stmt.Schema.ParseIndexes() {
+			if !migrator.HasIndex(model, index.Name) {
+				drift = append(drift, fmt.Sprintf("index %s on %s is missing", index.Name, table))
+			}
+		}
+	}
+
+	return drift, nil
+}
@@ -0,0 +1,363 @@
+// internal/database/migrate.go
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey identifies the advisory lock held while migrating, so
+// replicas starting together don't run the same migration twice
+const migrationLockKey int64 = 0x5332454d4947 // "S2EMIG"
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// ErrChecksumMismatch is returned when an applied migration was edited after
+// it ran. Applied migrations must never change; add a new one instead.
+var ErrChecksumMismatch = errors.New("applied migration has changed")
+
+// Migration is a versioned schema change, read from
+// migrations/<version>_<name>.up.sql and the matching .down.sql
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// MigrationStatus is a migration and whether it has been applied
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+	// Modified is set when the applied migration's checksum differs from the
+	// embedded file's
+	Modified bool
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrator applies and reverts the embedded migrations, recording them in
+// the schema_migrations table
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// Migrator returns a migrator for the embedded migrations
+func (d *Database) Migrator() (*Migrator, error) {
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
+
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: sqlDB, migrations: migrations}, nil
+}
+
+// loadMigrations reads the migrations in fsys, sorted by version
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	paths, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, path := range paths {
+		fileName := path[len("migrations/"):]
+		match := migrationFilePattern.FindStringSubmatch(fileName)
+		if match == nil {
+			return nil, fmt.Errorf("migration file %s must be named <version>_<name>.up.sql or .down.sql", fileName)
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s", version, migration.Name, match[2])
+		}
+
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, err
+		}
+		if match[3] == "up" {
+			migration.Up = string(data)
+			sum := sha256.Sum256(data)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Latest returns the version of the newest embedded migration
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status lists every embedded migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i].Migration = migration
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.appliedAt
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &appliedAt
+			statuses[i].Modified = record.checksum != migration.Checksum
+		}
+	}
+	return statuses, nil
+}
+
+// Pending returns the migrations that haven't been applied. It fails with
+// ErrChecksumMismatch if an applied migration has changed since it ran.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	return m.pending(applied)
+}
+
+// Up applies every pending migration and returns the applied ones
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.To(ctx, m.Latest())
+}
+
+// Down reverts the last steps applied migrations and returns the reverted ones
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1")
+	}
+
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// To applies or reverts migrations until version is the newest applied one,
+// returning the migrations it ran. Version 0 reverts everything.
+func (m *Migrator) To(ctx context.Context, version int) ([]Migration, error) {
+	if version != 0 && !m.exists(version) {
+		return nil, fmt.Errorf("migration version %d does not exist", version)
+	}
+
+	var ran []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		pending, err := m.pending(applied)
+		if err != nil {
+			return err
+		}
+
+		// Revert newest first, then apply oldest first
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok || migration.Version <= version {
+				continue
+			}
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			ran = append(ran, migration)
+		}
+		for _, migration := range pending {
+			if migration.Version > version {
+				break
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			ran = append(ran, migration)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+func (m *Migrator) exists(version int) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Migrator) pending(applied map[int]appliedMigration) ([]Migration, error) {
+	var pending []Migration
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Version]
+		if !ok {
+			pending = append(pending, migration)
+			continue
+		}
+		if record.checksum != migration.Checksum {
+			return nil, fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, migration.Version, migration.Name)
+		}
+	}
+	return pending, nil
+}
+
+// queryer is what reading the applied migrations needs from a *sql.DB or a
+// *sql.Conn
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// applied returns the applied migrations by version, creating the
+// schema_migrations table on first use
+func (m *Migrator) applied(ctx context.Context, q queryer) (map[int]appliedMigration, error) {
+	_, err := q.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		checksum text NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	rows, err := q.QueryContext(ctx, "SELECT version, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var record appliedMigration
+		if err := rows.Scan(&version, &record.checksum, &record.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = record
+	}
+	return applied, rows.Err()
+}
+
+// withLock runs fn on a single connection holding the migration advisory
+// lock, waiting for any other migrator to finish first
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to take the migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+
+	return fn(conn)
+}
+
+// apply runs a migration and records it in one transaction, so a failed
+// migration leaves no trace
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	err := inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+			migration.Version, migration.Name, migration.Checksum)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	err := inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("reverting migration %d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
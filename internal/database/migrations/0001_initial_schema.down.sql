@@ -0,0 +1,21 @@
+DROP TABLE IF EXISTS outbox_offsets;
+DROP TABLE IF EXISTS outbox_events;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+DROP TABLE IF EXISTS withdrawal_requests;
+DROP TABLE IF EXISTS reward_transactions;
+DROP TABLE IF EXISTS reward_pools;
+DROP TABLE IF EXISTS export_jobs;
+DROP TABLE IF EXISTS text_analyses;
+DROP TABLE IF EXISTS response_summaries;
+DROP TABLE IF EXISTS answers;
+DROP TABLE IF EXISTS responses;
+DROP TABLE IF EXISTS survey_templates;
+DROP TABLE IF EXISTS bank_questions;
+DROP TABLE IF EXISTS survey_quotas;
+DROP TABLE IF EXISTS questions;
+DROP TABLE IF EXISTS surveys;
+DROP TABLE IF EXISTS user_balances;
+DROP TABLE IF EXISTS user_stats;
+DROP TABLE IF EXISTS auth_sessions;
+DROP TABLE IF EXISTS users;
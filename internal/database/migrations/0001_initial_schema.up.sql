@@ -0,0 +1,455 @@
+-- The schema as GORM AutoMigrate created it before versioned migrations.
+-- Every statement is IF NOT EXISTS, so databases AutoMigrate built adopt
+-- this version without changes.
+
+-- Users and sign-in sessions
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    wallet_address text NOT NULL,
+    nonce text NOT NULL,
+    is_active boolean DEFAULT true,
+    last_login_at timestamptz,
+    username text,
+    email text,
+    profile_picture text,
+    bio text,
+    reputation_score decimal DEFAULT 0,
+    total_earned decimal DEFAULT 0,
+    total_responses bigint DEFAULT 0,
+    total_surveys bigint DEFAULT 0,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_users_email UNIQUE (email),
+    CONSTRAINT uni_users_wallet_address UNIQUE (wallet_address),
+    CONSTRAINT uni_users_username UNIQUE (username)
+);
+CREATE INDEX IF NOT EXISTS idx_users_wallet_address ON users (wallet_address);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL,
+    token text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    is_active boolean DEFAULT true,
+    ip_address text,
+    user_agent text,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_users_auth_sessions FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT uni_auth_sessions_token UNIQUE (token)
+);
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_token ON auth_sessions (token);
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user_id ON auth_sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_deleted_at ON auth_sessions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS user_stats (
+    user_id bigserial,
+    total_surveys_created bigint DEFAULT 0,
+    total_surveys_answered bigint DEFAULT 0,
+    total_earned decimal DEFAULT 0,
+    total_spent decimal DEFAULT 0,
+    average_rating decimal DEFAULT 0,
+    last_activity_at timestamptz,
+    PRIMARY KEY (user_id),
+    CONSTRAINT fk_user_stats_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS user_balances (
+    user_id bigserial,
+    total_earned decimal DEFAULT 0,
+    total_withdrawn decimal DEFAULT 0,
+    available_balance decimal DEFAULT 0,
+    pending_balance decimal DEFAULT 0,
+    last_updated_at timestamptz,
+    PRIMARY KEY (user_id),
+    CONSTRAINT fk_user_balances_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Surveys, questions, quotas and templates
+
+CREATE TABLE IF NOT EXISTS surveys (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    creator_id bigint NOT NULL,
+    title varchar(255) NOT NULL,
+    description text,
+    category varchar(100) NOT NULL,
+    status text DEFAULT 'draft',
+    max_responses bigint DEFAULT 100,
+    min_responses bigint DEFAULT 1,
+    reward_per_response decimal NOT NULL,
+    total_reward_pool decimal NOT NULL,
+    start_date timestamptz,
+    end_date timestamptz,
+    estimated_duration bigint,
+    is_anonymous boolean DEFAULT true,
+    is_public boolean DEFAULT true,
+    require_login boolean DEFAULT true,
+    allow_multiple boolean DEFAULT false,
+    response_count bigint DEFAULT 0,
+    completion_rate decimal DEFAULT 0,
+    average_rating decimal DEFAULT 0,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_users_surveys FOREIGN KEY (creator_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_surveys_status ON surveys (status);
+CREATE INDEX IF NOT EXISTS idx_surveys_category ON surveys (category);
+CREATE INDEX IF NOT EXISTS idx_surveys_creator_id ON surveys (creator_id);
+CREATE INDEX IF NOT EXISTS idx_surveys_deleted_at ON surveys (deleted_at);
+
+CREATE TABLE IF NOT EXISTS questions (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    survey_id bigint NOT NULL,
+    type text NOT NULL,
+    text text NOT NULL,
+    description text,
+    options json,
+    required boolean DEFAULT false,
+    "order" bigint NOT NULL,
+    min_length bigint,
+    max_length bigint,
+    min_value decimal,
+    max_value decimal,
+    "rows" json,
+    sum_total decimal,
+    file_config json,
+    show_if json,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_surveys_questions FOREIGN KEY (survey_id) REFERENCES surveys(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_questions_survey_id ON questions (survey_id);
+CREATE INDEX IF NOT EXISTS idx_questions_deleted_at ON questions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS survey_quotas (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    survey_id bigint NOT NULL,
+    name varchar(100) NOT NULL,
+    question_id bigint,
+    metadata_field varchar(50),
+    value text NOT NULL,
+    "limit" bigint,
+    percentage decimal,
+    current_count bigint DEFAULT 0,
+    is_active boolean DEFAULT true,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_surveys_quotas FOREIGN KEY (survey_id) REFERENCES surveys(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_survey_quotas_question_id ON survey_quotas (question_id);
+CREATE INDEX IF NOT EXISTS idx_survey_quotas_survey_id ON survey_quotas (survey_id);
+CREATE INDEX IF NOT EXISTS idx_survey_quotas_deleted_at ON survey_quotas (deleted_at);
+
+CREATE TABLE IF NOT EXISTS bank_questions (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    owner_id bigint,
+    category varchar(100),
+    definition json NOT NULL,
+    usage_count bigint DEFAULT 0,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_bank_questions_owner FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_bank_questions_category ON bank_questions (category);
+CREATE INDEX IF NOT EXISTS idx_bank_questions_owner_id ON bank_questions (owner_id);
+CREATE INDEX IF NOT EXISTS idx_bank_questions_deleted_at ON bank_questions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS survey_templates (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    owner_id bigint,
+    name varchar(255) NOT NULL,
+    description text,
+    category varchar(100) NOT NULL,
+    estimated_duration bigint,
+    questions json NOT NULL,
+    is_curated boolean DEFAULT false,
+    is_active boolean DEFAULT true,
+    usage_count bigint DEFAULT 0,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_survey_templates_owner FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_survey_templates_is_curated ON survey_templates (is_curated);
+CREATE INDEX IF NOT EXISTS idx_survey_templates_category ON survey_templates (category);
+CREATE INDEX IF NOT EXISTS idx_survey_templates_owner_id ON survey_templates (owner_id);
+CREATE INDEX IF NOT EXISTS idx_survey_templates_deleted_at ON survey_templates (deleted_at);
+
+-- Responses, answers and their analysis
+
+CREATE TABLE IF NOT EXISTS responses (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    survey_id bigint NOT NULL,
+    user_id bigint NOT NULL,
+    status text DEFAULT 'started',
+    started_at timestamptz NOT NULL,
+    completed_at timestamptz,
+    duration bigint,
+    ip_address text,
+    user_agent text,
+    timezone text,
+    language text DEFAULT 'en',
+    quality_score decimal DEFAULT 0,
+    is_valid boolean DEFAULT true,
+    flagged_reason text,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_surveys_responses FOREIGN KEY (survey_id) REFERENCES surveys(id),
+    CONSTRAINT fk_users_responses FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_responses_status ON responses (status);
+CREATE INDEX IF NOT EXISTS idx_responses_user_id ON responses (user_id);
+CREATE INDEX IF NOT EXISTS idx_responses_survey_id ON responses (survey_id);
+CREATE INDEX IF NOT EXISTS idx_responses_deleted_at ON responses (deleted_at);
+
+CREATE TABLE IF NOT EXISTS answers (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    response_id bigint NOT NULL,
+    question_id bigint NOT NULL,
+    answer_text text,
+    answer_value json,
+    time_spent bigint,
+    is_skipped boolean DEFAULT false,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_questions_answers FOREIGN KEY (question_id) REFERENCES questions(id),
+    CONSTRAINT fk_responses_answers FOREIGN KEY (response_id) REFERENCES responses(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_answers_question_id ON answers (question_id);
+CREATE INDEX IF NOT EXISTS idx_answers_response_id ON answers (response_id);
+CREATE INDEX IF NOT EXISTS idx_answers_deleted_at ON answers (deleted_at);
+
+CREATE TABLE IF NOT EXISTS response_summaries (
+    survey_id bigserial,
+    total_responses bigint DEFAULT 0,
+    completed_count bigint DEFAULT 0,
+    abandoned_count bigint DEFAULT 0,
+    average_duration decimal DEFAULT 0,
+    completion_rate decimal DEFAULT 0,
+    average_quality decimal DEFAULT 0,
+    last_response_at timestamptz,
+    PRIMARY KEY (survey_id),
+    CONSTRAINT fk_response_summaries_survey FOREIGN KEY (survey_id) REFERENCES surveys(id)
+);
+
+CREATE TABLE IF NOT EXISTS text_analyses (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    survey_id bigint NOT NULL,
+    question_id bigint NOT NULL,
+    state json,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_text_analyses_survey FOREIGN KEY (survey_id) REFERENCES surveys(id),
+    CONSTRAINT fk_text_analyses_question FOREIGN KEY (question_id) REFERENCES questions(id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_text_analyses_question_id ON text_analyses (question_id);
+CREATE INDEX IF NOT EXISTS idx_text_analyses_survey_id ON text_analyses (survey_id);
+CREATE INDEX IF NOT EXISTS idx_text_analyses_deleted_at ON text_analyses (deleted_at);
+
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    survey_id bigint NOT NULL,
+    requested_by bigint NOT NULL,
+    format varchar(20) NOT NULL,
+    status text DEFAULT 'pending',
+    file_name varchar(255),
+    file_path varchar(500),
+    row_count bigint DEFAULT 0,
+    size_bytes bigint DEFAULT 0,
+    error text,
+    completed_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_export_jobs_survey FOREIGN KEY (survey_id) REFERENCES surveys(id)
+);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_status ON export_jobs (status);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_requested_by ON export_jobs (requested_by);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_survey_id ON export_jobs (survey_id);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_deleted_at ON export_jobs (deleted_at);
+
+-- Rewards
+
+CREATE TABLE IF NOT EXISTS reward_pools (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    survey_id bigint NOT NULL,
+    total_amount decimal NOT NULL,
+    reward_per_response decimal NOT NULL,
+    max_responses bigint NOT NULL,
+    current_responses bigint DEFAULT 0,
+    paid_out decimal DEFAULT 0,
+    remaining_amount decimal NOT NULL,
+    is_active boolean DEFAULT true,
+    contract_address text,
+    tx_hash text,
+    block_number bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_surveys_reward_pool FOREIGN KEY (survey_id) REFERENCES surveys(id),
+    CONSTRAINT uni_reward_pools_survey_id UNIQUE (survey_id)
+);
+CREATE INDEX IF NOT EXISTS idx_reward_pools_survey_id ON reward_pools (survey_id);
+CREATE INDEX IF NOT EXISTS idx_reward_pools_deleted_at ON reward_pools (deleted_at);
+
+CREATE TABLE IF NOT EXISTS reward_transactions (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL,
+    survey_id bigint NOT NULL,
+    response_id bigint,
+    pool_id bigint,
+    type text NOT NULL,
+    amount decimal NOT NULL,
+    status text DEFAULT 'pending',
+    tx_hash text,
+    block_number bigint,
+    gas_used bigint,
+    gas_fee decimal,
+    processed_at timestamptz,
+    failure_reason text,
+    retry_count bigint DEFAULT 0,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_reward_pools_transactions FOREIGN KEY (pool_id) REFERENCES reward_pools(id),
+    CONSTRAINT fk_responses_transaction FOREIGN KEY (response_id) REFERENCES responses(id),
+    CONSTRAINT fk_users_transactions FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_reward_transactions_survey FOREIGN KEY (survey_id) REFERENCES surveys(id)
+);
+CREATE INDEX IF NOT EXISTS idx_reward_transactions_status ON reward_transactions (status);
+CREATE INDEX IF NOT EXISTS idx_reward_transactions_pool_id ON reward_transactions (pool_id);
+CREATE INDEX IF NOT EXISTS idx_reward_transactions_response_id ON reward_transactions (response_id);
+CREATE INDEX IF NOT EXISTS idx_reward_transactions_survey_id ON reward_transactions (survey_id);
+CREATE INDEX IF NOT EXISTS idx_reward_transactions_user_id ON reward_transactions (user_id);
+CREATE INDEX IF NOT EXISTS idx_reward_transactions_deleted_at ON reward_transactions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS withdrawal_requests (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL,
+    amount decimal NOT NULL,
+    wallet_address text NOT NULL,
+    status text DEFAULT 'pending',
+    transaction_id bigint,
+    processed_at timestamptz,
+    failure_reason text,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_withdrawal_requests_user FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_withdrawal_requests_transaction FOREIGN KEY (transaction_id) REFERENCES reward_transactions(id)
+);
+CREATE INDEX IF NOT EXISTS idx_withdrawal_requests_user_id ON withdrawal_requests (user_id);
+CREATE INDEX IF NOT EXISTS idx_withdrawal_requests_deleted_at ON withdrawal_requests (deleted_at);
+
+-- Webhooks
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL,
+    survey_id bigint,
+    url varchar(2048) NOT NULL,
+    secret varchar(100) NOT NULL,
+    events json NOT NULL,
+    description varchar(255),
+    is_active boolean DEFAULT true,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_webhooks_user FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_webhooks_survey FOREIGN KEY (survey_id) REFERENCES surveys(id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_survey_id ON webhooks (survey_id);
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks (user_id);
+CREATE INDEX IF NOT EXISTS idx_webhooks_deleted_at ON webhooks (deleted_at);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    webhook_id bigint NOT NULL,
+    event_id varchar(40) NOT NULL,
+    event_type varchar(50) NOT NULL,
+    payload text NOT NULL,
+    status text DEFAULT 'pending',
+    attempts bigint DEFAULT 0,
+    next_attempt_at timestamptz,
+    last_attempt_at timestamptz,
+    response_status bigint,
+    response_body text,
+    error text,
+    delivered_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_webhook_deliveries_webhook FOREIGN KEY (webhook_id) REFERENCES webhooks(id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries (status);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_event_id ON webhook_deliveries (event_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_deleted_at ON webhook_deliveries (deleted_at);
+
+-- Domain event outbox
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id bigserial,
+    event_id varchar(40) NOT NULL,
+    sequence bigint,
+    type varchar(50) NOT NULL,
+    aggregate_type varchar(50) NOT NULL,
+    aggregate_id bigint NOT NULL,
+    survey_id bigint NOT NULL,
+    payload text NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_created_at ON outbox_events (created_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_survey_id ON outbox_events (survey_id);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_type ON outbox_events (type);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_events_sequence ON outbox_events (sequence);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_events_event_id ON outbox_events (event_id);
+
+CREATE TABLE IF NOT EXISTS outbox_offsets (
+    consumer varchar(100),
+    sequence bigint NOT NULL DEFAULT 0,
+    updated_at timestamptz,
+    PRIMARY KEY (consumer)
+);
+
+-- Indexes for common queries
+
+CREATE INDEX IF NOT EXISTS idx_users_wallet_lower ON users (LOWER(wallet_address));
+CREATE INDEX IF NOT EXISTS idx_users_reputation ON users (reputation_score DESC);
+CREATE INDEX IF NOT EXISTS idx_surveys_status_category ON surveys (status, category);
+CREATE INDEX IF NOT EXISTS idx_surveys_created_at ON surveys (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_surveys_active ON surveys (status, start_date, end_date);
+CREATE INDEX IF NOT EXISTS idx_survey_quotas_survey_active ON survey_quotas (survey_id, is_active);
+CREATE INDEX IF NOT EXISTS idx_responses_user_survey ON responses (user_id, survey_id);
+CREATE INDEX IF NOT EXISTS idx_responses_completed_at ON responses (completed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_status_created ON reward_transactions (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_user_status ON reward_transactions (user_id, status);
@@ -0,0 +1,2 @@
+-- Admins can be granted in the database, besides ADMIN_WALLET_ADDRESSES
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin boolean DEFAULT false;
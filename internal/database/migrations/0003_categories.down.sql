@@ -0,0 +1,12 @@
+ALTER TABLE survey_templates DROP CONSTRAINT IF EXISTS fk_survey_templates_category;
+ALTER TABLE surveys DROP CONSTRAINT IF EXISTS fk_surveys_category;
+
+-- Back to free-text categories, using the English names
+UPDATE surveys s SET category = left(c.names->>'en', 100)
+FROM categories c WHERE c.slug = s.category AND c.names->>'en' IS NOT NULL;
+UPDATE survey_templates t SET category = left(c.names->>'en', 100)
+FROM categories c WHERE c.slug = t.category AND c.names->>'en' IS NOT NULL;
+UPDATE bank_questions q SET category = left(c.names->>'en', 100)
+FROM categories c WHERE c.slug = q.category AND c.names->>'en' IS NOT NULL;
+
+DROP TABLE IF EXISTS categories;
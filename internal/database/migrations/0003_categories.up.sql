@@ -0,0 +1,69 @@
+-- Managed survey categories. Surveys, templates and bank questions keep
+-- their category column, which now holds a category slug instead of a
+-- free-text name.
+
+CREATE TABLE IF NOT EXISTS categories (
+    id bigserial,
+    created_at timestamptz,
+    updated_at timestamptz,
+    slug varchar(100) NOT NULL,
+    names json NOT NULL,
+    icon varchar(255),
+    parent_id bigint,
+    sort_order bigint DEFAULT 0,
+    is_active boolean DEFAULT true,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id) REFERENCES categories(id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON categories (slug);
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+-- The categories the platform curated so far
+INSERT INTO categories (created_at, updated_at, slug, names, sort_order, is_active)
+SELECT now(), now(), slug, json_build_object('en', name), sort_order, true
+FROM (VALUES
+    ('technology', 'Technology', 1),
+    ('finance', 'Finance', 2),
+    ('healthcare', 'Healthcare', 3),
+    ('education', 'Education', 4),
+    ('entertainment', 'Entertainment', 5),
+    ('gaming', 'Gaming', 6),
+    ('defi', 'DeFi', 7),
+    ('nft', 'NFT', 8),
+    ('ai-ml', 'AI/ML', 9),
+    ('general', 'General', 10)
+) AS defaults (slug, name, sort_order)
+ON CONFLICT (slug) DO NOTHING;
+
+-- Free-text categories already in use become categories of their own, so
+-- no survey changes category. The curated names slug to the slugs above.
+INSERT INTO categories (created_at, updated_at, slug, names, sort_order, is_active)
+SELECT DISTINCT ON (slug) now(), now(), slug, json_build_object('en', name), 100, true
+FROM (
+    SELECT category AS name,
+        trim(both '-' from regexp_replace(lower(category), '[^a-z0-9]+', '-', 'g')) AS slug
+    FROM (
+        SELECT category FROM surveys
+        UNION SELECT category FROM survey_templates
+        UNION SELECT category FROM bank_questions
+    ) AS used
+    WHERE category IS NOT NULL
+) AS named
+WHERE slug <> ''
+ORDER BY slug, name
+ON CONFLICT (slug) DO NOTHING;
+
+UPDATE surveys
+SET category = COALESCE(NULLIF(trim(both '-' from regexp_replace(lower(category), '[^a-z0-9]+', '-', 'g')), ''), 'general');
+UPDATE survey_templates
+SET category = COALESCE(NULLIF(trim(both '-' from regexp_replace(lower(category), '[^a-z0-9]+', '-', 'g')), ''), 'general');
+UPDATE bank_questions
+SET category = trim(both '-' from regexp_replace(lower(category), '[^a-z0-9]+', '-', 'g'))
+WHERE category <> '';
+
+-- A category can't be deleted while surveys or templates, including
+-- soft-deleted ones, still refer to it
+ALTER TABLE surveys ADD CONSTRAINT fk_surveys_category
+    FOREIGN KEY (category) REFERENCES categories(slug);
+ALTER TABLE survey_templates ADD CONSTRAINT fk_survey_templates_category
+    FOREIGN KEY (category) REFERENCES categories(slug);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_surveys_public_end_date;
+DROP INDEX IF EXISTS idx_surveys_public_created_at;
+DROP INDEX IF EXISTS idx_surveys_search_vector;
+ALTER TABLE surveys DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS survey_search_config(text);
+ALTER TABLE surveys DROP COLUMN IF EXISTS language;
@@ -0,0 +1,42 @@
+-- Full-text search over survey titles and descriptions, stemmed for the
+-- survey's language
+
+ALTER TABLE surveys ADD COLUMN IF NOT EXISTS language varchar(10) NOT NULL DEFAULT 'en';
+
+-- survey_search_config maps a base language code to its text search
+-- configuration. Languages without a stemmer get the unstemmed simple one.
+CREATE OR REPLACE FUNCTION survey_search_config(language text) RETURNS regconfig AS $$
+    SELECT (CASE language
+        WHEN 'da' THEN 'danish'
+        WHEN 'de' THEN 'german'
+        WHEN 'en' THEN 'english'
+        WHEN 'es' THEN 'spanish'
+        WHEN 'fi' THEN 'finnish'
+        WHEN 'fr' THEN 'french'
+        WHEN 'hu' THEN 'hungarian'
+        WHEN 'it' THEN 'italian'
+        WHEN 'nl' THEN 'dutch'
+        WHEN 'no' THEN 'norwegian'
+        WHEN 'pt' THEN 'portuguese'
+        WHEN 'ro' THEN 'romanian'
+        WHEN 'ru' THEN 'russian'
+        WHEN 'sv' THEN 'swedish'
+        WHEN 'tr' THEN 'turkish'
+        ELSE 'simple'
+    END)::regconfig
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- Titles weigh more than descriptions. The unstemmed words are added with
+-- the lowest weight, so searches in another language still find exact words.
+ALTER TABLE surveys ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector(survey_search_config(language), coalesce(title, '')), 'A') ||
+    setweight(to_tsvector(survey_search_config(language), coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('simple'::regconfig, coalesce(title, '') || ' ' || coalesce(description, '')), 'D')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_surveys_search_vector ON surveys USING gin (search_vector);
+
+-- The public feed's default and ending-soon orders
+CREATE INDEX IF NOT EXISTS idx_surveys_public_created_at ON surveys (created_at DESC, id DESC)
+    WHERE is_public AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_surveys_public_end_date ON surveys ((COALESCE(end_date, 'infinity'::timestamptz)), id)
+    WHERE is_public AND deleted_at IS NULL;
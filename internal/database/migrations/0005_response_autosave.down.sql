@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_responses_started_activity;
+ALTER TABLE responses DROP COLUMN IF EXISTS last_activity_at;
+ALTER TABLE responses DROP COLUMN IF EXISTS version;
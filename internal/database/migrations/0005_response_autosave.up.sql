@@ -0,0 +1,12 @@
+-- Autosave of responses in progress: a version that moves on with every
+-- change of the answers, and the last activity idle responses expire after
+ALTER TABLE responses ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+ALTER TABLE responses ADD COLUMN IF NOT EXISTS last_activity_at timestamptz;
+
+UPDATE responses r SET last_activity_at = GREATEST(r.started_at, (
+    SELECT MAX(a.updated_at) FROM answers a WHERE a.response_id = r.id
+))
+WHERE r.last_activity_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_responses_started_activity ON responses (COALESCE(last_activity_at, started_at), id)
+    WHERE status = 'started' AND deleted_at IS NULL;
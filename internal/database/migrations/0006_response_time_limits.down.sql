@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_responses_started_deadline;
+ALTER TABLE responses DROP COLUMN IF EXISTS deadline;
+ALTER TABLE surveys DROP COLUMN IF EXISTS time_limit_minutes;
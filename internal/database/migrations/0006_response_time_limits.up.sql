@@ -0,0 +1,7 @@
+-- Optional hard time limit of surveys, and the deadline it sets for each
+-- response when it starts
+ALTER TABLE surveys ADD COLUMN IF NOT EXISTS time_limit_minutes bigint;
+ALTER TABLE responses ADD COLUMN IF NOT EXISTS deadline timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_responses_started_deadline ON responses (deadline, id)
+    WHERE status = 'started' AND deadline IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,19 @@
+-- Writes made with an Idempotency-Key header and their responses, replayed
+-- to retries until they expire
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    key varchar(255) NOT NULL,
+    method varchar(10) NOT NULL,
+    path varchar(255) NOT NULL,
+    fingerprint varchar(64) NOT NULL,
+    status_code bigint,
+    content_type varchar(100),
+    response_body bytea,
+    completed_at timestamptz,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_keys_user_key ON idempotency_keys (user_id, key);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"survey2earn-backend/internal/models"
+)
+
+// defaultTemplates returns the platform survey templates seeded on first boot
+func defaultTemplates() []models.SurveyTemplate {
+	return []models.SurveyTemplate{
+		{
+			Name:              "Net Promoter Score",
+			Description:       "Measure how likely respondents are to recommend your product, with a follow-up on why",
+			Category:          "general",
+			EstimatedDuration: 3,
+			IsCurated:         true,
+			IsActive:          true,
+			Questions: models.QuestionDefinitions{
+				{
+					Type:     models.QuestionTypeNPS,
+					Text:     "How likely are you to recommend us to a friend or colleague?",
+					Required: true,
+					Order:    1,
+					MinValue: floatPtr(models.NPSMinScore),
+					MaxValue: floatPtr(models.NPSMaxScore),
+				},
+				{
+					Type:      models.QuestionTypeTextArea,
+					Text:      "What is the main reason for your score?",
+					Order:     2,
+					MaxLength: intPtr(1000),
+				},
+			},
+		},
+		{
+			Name:              "Product Feedback",
+			Description:       "Collect satisfaction, most valued features and improvement ideas for a product",
+			Category:          "technology",
+			EstimatedDuration: 5,
+			IsCurated:         true,
+			IsActive:          true,
+			Questions: models.QuestionDefinitions{
+				{
+					Type:     models.QuestionTypeRating,
+					Text:     "How satisfied are you with the product overall?",
+					Required: true,
+					Order:    1,
+					MinValue: floatPtr(1),
+					MaxValue: floatPtr(5),
+				},
+				{
+					Type:     models.QuestionTypeSingleChoice,
+					Text:     "How often do you use the product?",
+					Required: true,
+					Order:    2,
+					Options: models.QuestionOptions{
+						{ID: "daily", Label: "Daily", Value: "daily", Order: 1},
+						{ID: "weekly", Label: "Weekly", Value: "weekly", Order: 2},
+						{ID: "monthly", Label: "Monthly", Value: "monthly", Order: 3},
+						{ID: "rarely", Label: "Rarely", Value: "rarely", Order: 4},
+					},
+				},
+				{
+					Type:      models.QuestionTypeTextArea,
+					Text:      "What should we improve first?",
+					Order:     3,
+					MaxLength: intPtr(1000),
+				},
+			},
+		},
+		{
+			Name:              "Demographics",
+			Description:       "A standard demographic block to prepend or append to any survey",
+			Category:          "general",
+			EstimatedDuration: 2,
+			IsCurated:         true,
+			IsActive:          true,
+			Questions: models.QuestionDefinitions{
+				{
+					Type:     models.QuestionTypeSingleChoice,
+					Text:     "What is your age group?",
+					Required: true,
+					Order:    1,
+					Options: models.QuestionOptions{
+						{ID: "18-24", Label: "18-24", Value: "18-24", Order: 1},
+						{ID: "25-34", Label: "25-34", Value: "25-34", Order: 2},
+						{ID: "35-44", Label: "35-44", Value: "35-44", Order: 3},
+						{ID: "45-54", Label: "45-54", Value: "45-54", Order: 4},
+						{ID: "55+", Label: "55+", Value: "55+", Order: 5},
+					},
+				},
+				{
+					Type:  models.QuestionTypeText,
+					Text:  "Which country do you live in?",
+					Order: 2,
+				},
+				{
+					Type:  models.QuestionTypeSingleChoice,
+					Text:  "How experienced are you with crypto?",
+					Order: 3,
+					Options: models.QuestionOptions{
+						{ID: "new", Label: "New to crypto", Value: "new", Order: 1},
+						{ID: "intermediate", Label: "Intermediate", Value: "intermediate", Order: 2},
+						{ID: "expert", Label: "Expert", Value: "expert", Order: 3},
+					},
+				},
+			},
+		},
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
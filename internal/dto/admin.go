@@ -0,0 +1,55 @@
+// internal/dto/admin.go
+package dto
+
+import "time"
+
+// AdminListSurveysRequest for browsing every survey on the platform
+type AdminListSurveysRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=draft published paused completed cancelled"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// AdminListUsersRequest for browsing the platform's users
+type AdminListUsersRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// AdminUserResponse represents a user as admins see them
+type AdminUserResponse struct {
+	ID              uint       `json:"id"`
+	WalletAddress   string     `json:"wallet_address"`
+	Username        *string    `json:"username"`
+	Email           *string    `json:"email"`
+	IsActive        bool       `json:"is_active"`
+	IsAdmin         bool       `json:"is_admin"`
+	ReputationScore float64    `json:"reputation_score"`
+	TotalEarned     float64    `json:"total_earned"`
+	TotalResponses  int        `json:"total_responses"`
+	TotalSurveys    int        `json:"total_surveys"`
+	LastLoginAt     *time.Time `json:"last_login_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// AdminUserListResponse for a page of users
+type AdminUserListResponse struct {
+	Users      []AdminUserResponse `json:"users"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	TotalPages int                 `json:"total_pages"`
+}
+
+// PlatformAnalyticsResponse sums up the platform. Rewards are counted over
+// the reward pools of published surveys.
+type PlatformAnalyticsResponse struct {
+	TotalUsers        int64            `json:"total_users"`
+	ActiveUsers       int64            `json:"active_users"`
+	TotalSurveys      int64            `json:"total_surveys"`
+	SurveysByStatus   map[string]int64 `json:"surveys_by_status"`
+	TotalResponses    int64            `json:"total_responses"`
+	ResponsesByStatus map[string]int64 `json:"responses_by_status"`
+	RewardsFunded     float64          `json:"rewards_funded"`
+	RewardsPaid       float64          `json:"rewards_paid"`
+}
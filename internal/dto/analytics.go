@@ -0,0 +1,91 @@
+// internal/dto/analytics.go
+package dto
+
+// CrosstabRequest for cross-tabulating the answers to two questions.
+// Questions are referenced by their order in the survey.
+type CrosstabRequest struct {
+	ResponseFilterRequest
+	Row        int     `form:"row" binding:"required,min=1"`
+	Column     int     `form:"column" binding:"required,min=1"`
+	Confidence float64 `form:"confidence" binding:"omitempty,gt=0,lt=1"` // default 0.95
+}
+
+// CrosstabResponse is a contingency table of two questions with significance testing
+type CrosstabResponse struct {
+	SurveyID        uint               `json:"survey_id"`
+	RowQuestion     CrosstabQuestion   `json:"row_question"`
+	ColumnQuestion  CrosstabQuestion   `json:"column_question"`
+	Rows            []CrosstabCategory `json:"rows"`
+	Columns         []CrosstabCategory `json:"columns"`
+	Cells           [][]CrosstabCell   `json:"cells"` // indexed [row][column]
+	RowTotals       []int              `json:"row_totals"`
+	ColumnTotals    []int              `json:"column_totals"`
+	Total           int                `json:"total"`
+	ConfidenceLevel float64            `json:"confidence_level"`
+	ChiSquare       *ChiSquareResponse `json:"chi_square"` // nil when the table is smaller than 2x2
+}
+
+type CrosstabQuestion struct {
+	ID    uint   `json:"id"`
+	Order int    `json:"order"`
+	Text  string `json:"text"`
+	Type  string `json:"type"`
+}
+
+type CrosstabCategory struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+type CrosstabCell struct {
+	Count         int                `json:"count"`
+	RowPercent    float64            `json:"row_percent"`
+	ColumnPercent float64            `json:"column_percent"`
+	TotalPercent  float64            `json:"total_percent"`
+	ColumnCI      ConfidenceInterval `json:"column_ci"` // interval of column_percent
+}
+
+// ConfidenceInterval bounds a percentage
+type ConfidenceInterval struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+type ChiSquareResponse struct {
+	Statistic        float64 `json:"statistic"`
+	DegreesOfFreedom int     `json:"degrees_of_freedom"`
+	PValue           float64 `json:"p_value"`
+	CramersV         float64 `json:"cramers_v"`
+	Significant      bool    `json:"significant"`        // p_value below 1 - confidence_level
+	LowExpectedCells int     `json:"low_expected_cells"` // cells with expected count below 5
+}
+
+// TextAnalysisResponse summarizes the answers to a text question. It is
+// returned in the answer_distribution of text and textarea questions.
+type TextAnalysisResponse struct {
+	Keywords    []KeywordFrequency `json:"keywords"`
+	Sentiment   SentimentSummary   `json:"sentiment"`
+	Clusters    []TextCluster      `json:"clusters"`
+	Unclustered int                `json:"unclustered"` // answers not similar to any cluster
+}
+
+type KeywordFrequency struct {
+	Term      string `json:"term"`
+	Count     int    `json:"count"`
+	Responses int    `json:"responses"`
+}
+
+type SentimentSummary struct {
+	AverageScore float64 `json:"average_score"` // from -1 (negative) to 1 (positive)
+	Positive     int     `json:"positive"`
+	Neutral      int     `json:"neutral"`
+	Negative     int     `json:"negative"`
+}
+
+// TextCluster is a group of similar answers, described by its most weighted terms
+type TextCluster struct {
+	Terms    []string `json:"terms"`
+	Size     int      `json:"size"`
+	Percent  float64  `json:"percent"`
+	Examples []string `json:"examples"`
+}
@@ -20,6 +20,13 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+// NonceResponse holds the message a wallet signs to log in
+type NonceResponse struct {
+	WalletAddress string `json:"wallet_address"`
+	Nonce         string `json:"nonce"`
+	Message       string `json:"message"`
+}
+
 // LoginResponse represents the login response
 type LoginResponse struct {
 	User         UserResponse `json:"user"`
@@ -28,7 +35,8 @@ type LoginResponse struct {
 	ExpiresIn    int          `json:"expires_in"`
 }
 
-// RegisterResponse represents the registration response
+// RegisterResponse represents the registration response. Message is the
+// message to sign to log in.
 type RegisterResponse struct {
 	User    UserResponse `json:"user"`
 	Message string       `json:"message"`
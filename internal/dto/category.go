@@ -0,0 +1,55 @@
+// internal/dto/category.go
+package dto
+
+// CreateCategoryRequest represents the request to create a survey category.
+// Parent is the slug of the parent category, empty for a top-level one.
+type CreateCategoryRequest struct {
+	Slug      string            `json:"slug" binding:"required,max=100"`
+	Names     map[string]string `json:"names" binding:"required"`
+	Icon      string            `json:"icon" binding:"max=255"`
+	Parent    string            `json:"parent"`
+	SortOrder int               `json:"sortOrder"`
+	IsActive  *bool             `json:"isActive"`
+}
+
+// UpdateCategoryRequest for updating a category. Names replaces every
+// localized name, and an empty Parent moves the category to the top level.
+// The slug can't change.
+type UpdateCategoryRequest struct {
+	Names     map[string]string `json:"names"`
+	Icon      *string           `json:"icon" binding:"omitempty,max=255"`
+	Parent    *string           `json:"parent"`
+	SortOrder *int              `json:"sortOrder"`
+	IsActive  *bool             `json:"isActive"`
+}
+
+// ListCategoriesRequest for listing categories. Status filters the surveys
+// counted, like the public survey listing. IncludeInactive is set by the
+// route, only admins list inactive categories.
+type ListCategoriesRequest struct {
+	Status          string `form:"status"`
+	IncludeInactive bool   `form:"-"`
+}
+
+// CategoryResponse represents a category with its subcategories. SurveyCount
+// counts the public surveys in the category itself and TotalSurveyCount also
+// those in its subcategories.
+type CategoryResponse struct {
+	ID               uint               `json:"id"`
+	Slug             string             `json:"slug"`
+	Name             string             `json:"name"`
+	Names            map[string]string  `json:"names"`
+	Icon             string             `json:"icon"`
+	Parent           string             `json:"parent,omitempty"`
+	SortOrder        int                `json:"sort_order"`
+	IsActive         bool               `json:"is_active"`
+	SurveyCount      int64              `json:"survey_count"`
+	TotalSurveyCount int64              `json:"total_survey_count"`
+	Children         []CategoryResponse `json:"children"`
+}
+
+// CategoryListResponse is the category tree, with names in Locale
+type CategoryListResponse struct {
+	Categories []CategoryResponse `json:"categories"`
+	Locale     string             `json:"locale"`
+}
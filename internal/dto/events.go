@@ -0,0 +1,77 @@
+// internal/dto/events.go
+package dto
+
+import "time"
+
+// ResponseEventData is the payload of response.started, response.abandoned
+// and response.screened_out events, and of live response.completed events
+type ResponseEventData struct {
+	ResponseID   uint     `json:"response_id"`
+	Status       string   `json:"status"`
+	QualityScore *float64 `json:"quality_score,omitempty"` // completed responses only
+	Duration     *int     `json:"duration,omitempty"`      // completed responses only, in seconds
+	RewardAmount *float64 `json:"reward_amount,omitempty"` // completed responses only
+	Reason       string   `json:"reason,omitempty"`        // screened out responses only
+}
+
+// PoolEventData is the payload of pool.updated and pool.exhausted events
+type PoolEventData struct {
+	PoolID           uint    `json:"pool_id"`
+	SurveyID         uint    `json:"survey_id"`
+	TotalAmount      float64 `json:"total_amount"`
+	PaidOut          float64 `json:"paid_out"`
+	RemainingAmount  float64 `json:"remaining_amount"`
+	CurrentResponses int     `json:"current_responses"`
+	MaxResponses     int     `json:"max_responses"`
+}
+
+// SurveyPublishedEventData is the payload of survey.published events
+type SurveyPublishedEventData struct {
+	SurveyID          uint       `json:"survey_id"`
+	Title             string     `json:"title"`
+	Category          string     `json:"category"`
+	StartDate         *time.Time `json:"start_date"`
+	EndDate           *time.Time `json:"end_date"`
+	MaxResponses      int        `json:"max_responses"`
+	RewardPerResponse float64    `json:"reward_per_response"`
+	TotalRewardPool   float64    `json:"total_reward_pool"`
+}
+
+// SurveyEventData is the payload of survey.updated and survey.deleted events
+type SurveyEventData struct {
+	SurveyID uint   `json:"survey_id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+}
+
+// ResponseCompletedEventData is the payload of response.completed events.
+// The respondent is left out for anonymous surveys.
+type ResponseCompletedEventData struct {
+	ResponseID   uint              `json:"response_id"`
+	SurveyID     uint              `json:"survey_id"`
+	UserID       *uint             `json:"user_id,omitempty"`
+	QualityScore float64           `json:"quality_score"`
+	Duration     int               `json:"duration"`
+	CompletedAt  time.Time         `json:"completed_at"`
+	RewardAmount float64           `json:"reward_amount"`
+	XpEarned     int               `json:"xp_earned"`
+	Answers      []AnswerEventData `json:"answers"`
+}
+
+type AnswerEventData struct {
+	QuestionID uint   `json:"question_id"`
+	Order      int    `json:"order"`
+	Question   string `json:"question"`
+	Answer     string `json:"answer"`
+	IsSkipped  bool   `json:"is_skipped"`
+}
+
+// RewardPaidEventData is the payload of reward.paid events. The respondent
+// is left out for anonymous surveys.
+type RewardPaidEventData struct {
+	TransactionID uint    `json:"transaction_id"`
+	ResponseID    uint    `json:"response_id"`
+	SurveyID      uint    `json:"survey_id"`
+	UserID        *uint   `json:"user_id,omitempty"`
+	Amount        float64 `json:"amount"`
+}
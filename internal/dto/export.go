@@ -0,0 +1,20 @@
+// internal/dto/export.go
+package dto
+
+import (
+	"time"
+)
+
+// ExportJobResponse represents a background response export
+type ExportJobResponse struct {
+	ID          uint       `json:"id"`
+	SurveyID    uint       `json:"survey_id"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	FileName    string     `json:"file_name,omitempty"`
+	RowCount    int        `json:"row_count"`
+	SizeBytes   int64      `json:"size_bytes"`
+	Error       *string    `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,45 @@
+// internal/dto/recommendation.go
+package dto
+
+// RecommendedSurveysRequest asks for the surveys that suit the respondent
+// best. Language and Timezone are matched against survey quotas, like the
+// metadata sent when starting a survey.
+type RecommendedSurveysRequest struct {
+	Limit    int    `form:"limit"`
+	Language string `form:"lang"`
+	Timezone string `form:"timezone"`
+}
+
+// RecommendationFactor is one part of a recommendation score: Value, from 0
+// to 1, times Weight gives the Points it adds out of 100
+type RecommendationFactor struct {
+	Name   string  `json:"name"` // category, reward, length or capacity
+	Weight float64 `json:"weight"`
+	Value  float64 `json:"value"`
+	Points float64 `json:"points"`
+	Reason string  `json:"reason"`
+}
+
+// RecommendationResponse is a recommended survey with its score out of 100
+// and how the score came about
+type RecommendationResponse struct {
+	Survey  SurveyItemResponse     `json:"survey"`
+	Score   float64                `json:"score"`
+	Factors []RecommendationFactor `json:"factors"`
+}
+
+// RespondentProfile is the respondent's record the recommendations are
+// based on. CompletionRate and AverageQuality are estimates that start out
+// neutral for new respondents.
+type RespondentProfile struct {
+	CompletedResponses int            `json:"completed_responses"`
+	CompletionRate     float64        `json:"completion_rate"`
+	AverageQuality     float64        `json:"average_quality"` // out of 5
+	Categories         map[string]int `json:"categories"`      // completed responses per category
+}
+
+// RecommendationListResponse lists the recommended surveys, best first
+type RecommendationListResponse struct {
+	Recommendations []RecommendationResponse `json:"recommendations"`
+	Profile         RespondentProfile        `json:"profile"`
+}
@@ -24,19 +24,30 @@ type SubmitAnswerRequest struct {
 
 // AnswerValue represents the answer value structure
 type AnswerValue struct {
-	Type     string      `json:"type" binding:"required"`    // text, number, array, boolean, rating, scale, date
+	Type     string      `json:"type" binding:"required"`    // text, number, array, boolean, rating, scale, date, matrix, ranking, constant_sum, nps, email, url, phone, file_upload
 	Content  interface{} `json:"value"`                      // The actual answer value
 	Options  []string    `json:"options"`                    // Selected options for multiple choice
 	Rating   *int        `json:"rating"`                     // Rating value (1-5)
 	Scale    *int        `json:"scale"`                      // Scale value (1-10)
 	Date     *time.Time  `json:"date"`                       // Date value
+	Matrix      map[string]string  `json:"matrix,omitempty"`      // Matrix row ID -> column value
+	Ranking     []string           `json:"ranking,omitempty"`     // Option values, most preferred first
+	Allocations map[string]float64 `json:"allocations,omitempty"` // Constant-sum option value -> amount
+	Files       []FileAnswer       `json:"files,omitempty"`       // Uploaded files
+}
+
+// FileAnswer represents an uploaded file in an answer
+type FileAnswer struct {
+	URL         string `json:"url" binding:"required"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
 }
 
 // CompleteSurveyRequest represents the final survey submission
 type CompleteSurveyRequest struct {
 	ResponseID uint                      `json:"response_id" binding:"required"`
 	Answers    []SubmitAnswerRequest     `json:"answers" binding:"required"`
-	Duration   int                       `json:"duration"` // total time spent in seconds
 }
 
 // UpdateAnswerRequest for updating a single answer
@@ -44,6 +55,18 @@ type UpdateAnswerRequest struct {
 	Answer    AnswerValue `json:"answer" binding:"required"`
 	TimeSpent int         `json:"time_spent"`
 	IsSkipped bool        `json:"is_skipped"`
+	Version   int         `json:"version"` // version the answer was made on, 0 to save regardless; If-Match takes precedence
+}
+
+// AnswerSavedResponse confirms an autosaved answer. Version is the new
+// version of the response, to send with the next answer.
+type AnswerSavedResponse struct {
+	ResponseID     uint       `json:"response_id"`
+	QuestionID     uint       `json:"question_id"`
+	Version        int        `json:"version"`
+	SavedAt        time.Time  `json:"saved_at"`
+	NextQuestionID *uint      `json:"next_question_id"` // nil once every visible question is answered
+	ExpiresAt      *time.Time `json:"expires_at"`       // when the response is abandoned if left idle
 }
 
 // ResponseStartResponse represents the response when starting a survey
@@ -52,7 +75,17 @@ type ResponseStartResponse struct {
 	SurveyID   uint      `json:"survey_id"`
 	Status     string    `json:"status"`
 	StartedAt  time.Time `json:"started_at"`
-	TimeLeft   *int      `json:"time_left"` // in seconds, if survey has time limit
+	TimeLeft   *int       `json:"time_left"` // in seconds, until the deadline or else the estimated duration is up
+	Deadline   *time.Time `json:"deadline"`  // when the survey's time limit is up, if it has one
+	Message    string    `json:"message,omitempty"`
+}
+
+// ScreenOutResponse is returned when a respondent falls into a full quota
+type ScreenOutResponse struct {
+	ResponseID uint   `json:"response_id"`
+	Status     string `json:"status"`
+	Quota      string `json:"quota"`
+	Message    string `json:"message"`
 }
 
 // AnswerResponse represents an answer in response
@@ -70,7 +103,7 @@ type AnswerResponse struct {
 type SurveyResponseResponse struct {
 	ID            uint             `json:"id"`
 	SurveyID      uint             `json:"survey_id"`
-	UserID        uint             `json:"user_id"`
+	UserID        uint             `json:"user_id,omitempty"`
 	Status        string           `json:"status"`
 	StartedAt     time.Time        `json:"started_at"`
 	CompletedAt   *time.Time       `json:"completed_at"`
@@ -108,6 +141,31 @@ type SurveyProgressResponse struct {
 	TimeLeft          *int      `json:"time_left"`
 	StartedAt         time.Time `json:"started_at"`
 	LastAnsweredAt    *time.Time `json:"last_answered_at"`
+	Deadline          *time.Time `json:"deadline"`
+	Version           int       `json:"version"`
+	NextQuestionID    *uint     `json:"next_question_id"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+}
+
+// ResumeResponse is where a response in progress was left, to pick it up
+// on any device. Totals count the questions visible with the answers given
+// so far; NextQuestion is nil once all of them are answered.
+type ResumeResponse struct {
+	ResponseID        uint              `json:"response_id"`
+	SurveyID          uint              `json:"survey_id"`
+	Status            string            `json:"status"`
+	Version           int               `json:"version"`
+	NextQuestion      *QuestionResponse `json:"next_question"`
+	Answers           []AnswerResponse  `json:"answers"`
+	Progress          float64           `json:"progress"` // percentage (0-100)
+	QuestionsTotal    int               `json:"questions_total"`
+	QuestionsAnswered int               `json:"questions_answered"`
+	TimeSpent         int               `json:"time_spent"`
+	TimeLeft          *int              `json:"time_left"`
+	StartedAt         time.Time         `json:"started_at"`
+	LastAnsweredAt    *time.Time        `json:"last_answered_at"`
+	Deadline          *time.Time        `json:"deadline"`
+	ExpiresAt         *time.Time        `json:"expires_at"`
 }
 
 // ListResponsesRequest for filtering user responses
@@ -120,6 +178,24 @@ type ListResponsesRequest struct {
 	Limit     int    `form:"limit" binding:"min=1,max=100"`
 }
 
+// ResponseFilterRequest holds the response filters shared by the survey creator endpoints
+type ResponseFilterRequest struct {
+	Status     string   `form:"status"`
+	IsValid    *bool    `form:"is_valid"`
+	MinQuality *float64 `form:"min_quality"`
+	MaxQuality *float64 `form:"max_quality"`
+	StartDate  string   `form:"start_date"` // YYYY-MM-DD or RFC 3339
+	EndDate    string   `form:"end_date"`   // YYYY-MM-DD (inclusive) or RFC 3339
+	Answers    []string `form:"answer"`     // <question order>:<value>, e.g. 3:option_b
+}
+
+// ListSurveyResponsesRequest for filtering the responses of a survey as its creator
+type ListSurveyResponsesRequest struct {
+	ResponseFilterRequest
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
 // ResponseListResponse for listing user responses
 type ResponseListResponse struct {
 	Responses  []ResponseItemResponse `json:"responses"`
@@ -142,4 +218,32 @@ type ResponseItemResponse struct {
 	XpEarned      int          `json:"xp_earned"`
 	QualityScore  float64      `json:"quality_score"`
 	Progress      float64      `json:"progress"`
-}
\ No newline at end of file
+}
+
+// RespondentResponse identifies who gave a response. It is never returned for
+// anonymous surveys.
+type RespondentResponse struct {
+	UserID        uint    `json:"user_id"`
+	WalletAddress string  `json:"wallet_address"`
+	Username      *string `json:"username"`
+	IPAddress     string  `json:"ip_address"`
+	UserAgent     string  `json:"user_agent"`
+}
+
+// CreatorResponseResponse represents a response as seen by the survey creator
+type CreatorResponseResponse struct {
+	SurveyResponseResponse
+	Language      string              `json:"language"`
+	Timezone      string              `json:"timezone"`
+	FlaggedReason *string             `json:"flagged_reason"`
+	Respondent    *RespondentResponse `json:"respondent,omitempty"`
+}
+
+// CreatorResponseListResponse for listing the responses of a survey
+type CreatorResponseListResponse struct {
+	Responses  []CreatorResponseResponse `json:"responses"`
+	Total      int64                     `json:"total"`
+	Page       int                       `json:"page"`
+	Limit      int                       `json:"limit"`
+	TotalPages int                       `json:"total_pages"`
+}
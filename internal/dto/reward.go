@@ -0,0 +1,45 @@
+// internal/dto/reward.go
+package dto
+
+import "time"
+
+// RewardBalanceResponse is a user's balance, summed from their reward
+// transactions. Withdrawals in progress are held back from the available
+// balance.
+type RewardBalanceResponse struct {
+	TotalEarned        float64 `json:"total_earned"`
+	TotalWithdrawn     float64 `json:"total_withdrawn"`
+	PendingWithdrawals float64 `json:"pending_withdrawals"`
+	AvailableBalance   float64 `json:"available_balance"`
+}
+
+// ListRewardTransactionsRequest for browsing a user's reward history
+type ListRewardTransactionsRequest struct {
+	Type   string `form:"type" binding:"omitempty,oneof=reward withdrawal refund fee"`
+	Status string `form:"status" binding:"omitempty,oneof=pending processing completed failed cancelled"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// RewardTransactionResponse represents a reward transaction
+type RewardTransactionResponse struct {
+	ID          uint       `json:"id"`
+	SurveyID    uint       `json:"survey_id"`
+	SurveyTitle string     `json:"survey_title"`
+	ResponseID  *uint      `json:"response_id"`
+	Type        string     `json:"type"`
+	Amount      float64    `json:"amount"`
+	Status      string     `json:"status"`
+	TxHash      *string    `json:"tx_hash"`
+	ProcessedAt *time.Time `json:"processed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// RewardTransactionListResponse for a page of reward transactions
+type RewardTransactionListResponse struct {
+	Transactions []RewardTransactionResponse `json:"transactions"`
+	Total        int64                       `json:"total"`
+	Page         int                         `json:"page"`
+	Limit        int                         `json:"limit"`
+	TotalPages   int                         `json:"total_pages"`
+}
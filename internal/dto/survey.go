@@ -10,11 +10,15 @@ type CreateSurveyRequest struct {
 	Title             string                   `json:"title" binding:"required,min=3,max=255"`
 	Description       string                   `json:"description" binding:"required"`
 	Category          string                   `json:"category" binding:"required"`
+	Language          string                   `json:"language"` // e.g. "en" or "pt-BR", defaults to "en"
 	EstimatedTime     string                   `json:"estimatedTime" binding:"required"`
+	TimeLimitMinutes  *int                     `json:"timeLimitMinutes" binding:"omitempty,gt=0"` // hard limit per response
 	RewardAmount      float64                  `json:"rewardAmount" binding:"required,gt=0"`
 	MaxParticipants   int                      `json:"maxParticipants" binding:"required,gt=0"`
 	XpReward          int                      `json:"xpReward" binding:"required,gt=0"`
-	Questions         []CreateQuestionRequest  `json:"questions" binding:"required,min=1"`
+	Questions         []CreateQuestionRequest  `json:"questions"`
+	BankQuestionIDs   []uint                   `json:"bankQuestionIds"` // appended after questions
+	Quotas            []QuotaRequest           `json:"quotas"`
 	IsAnonymous       bool                     `json:"isAnonymous"`
 	IsPublic          bool                     `json:"isPublic"`
 	RequireLogin      bool                     `json:"requireLogin"`
@@ -35,6 +39,25 @@ type CreateQuestionRequest struct {
 	MinValue    *float64                  `json:"minValue"`
 	MaxValue    *float64                  `json:"maxValue"`
 	Order       int                       `json:"order"`
+	Rows        []QuestionOptionRequest   `json:"rows"`       // matrix rows; options are the columns
+	SumTotal    *float64                  `json:"sumTotal"`   // constant-sum total
+	FileConfig  *FileUploadConfigRequest  `json:"fileConfig"` // file upload constraints
+	ShowIf      *ConditionalLogicRequest  `json:"showIf"`
+}
+
+// ConditionalLogicRequest shows a question only when an earlier question's
+// answer matches. The earlier question is referenced by its order.
+type ConditionalLogicRequest struct {
+	QuestionOrder int         `json:"questionOrder" binding:"required"`
+	Operator      string      `json:"operator" binding:"required"` // equals, not_equals, contains, greater_than, less_than
+	Value         interface{} `json:"value"`
+}
+
+// FileUploadConfigRequest represents file upload constraints for a question
+type FileUploadConfigRequest struct {
+	AllowedTypes []string `json:"allowedTypes"`
+	MaxSizeBytes int64    `json:"maxSizeBytes"`
+	MaxFiles     int      `json:"maxFiles"`
 }
 
 // QuestionOptionRequest represents question option
@@ -45,16 +68,30 @@ type QuestionOptionRequest struct {
 	Order int    `json:"order"`
 }
 
+// QuotaRequest represents a quota definition. Answer quotas reference their
+// screener question by its order, since question IDs are not known yet.
+type QuotaRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	QuestionOrder *int     `json:"questionOrder"`
+	MetadataField *string  `json:"metadataField"` // language, timezone
+	Value         string   `json:"value" binding:"required"`
+	Limit         *int     `json:"limit"`
+	Percentage    *float64 `json:"percentage"`
+}
+
 // UpdateSurveyRequest for updating draft surveys
 type UpdateSurveyRequest struct {
 	Title           *string                   `json:"title"`
 	Description     *string                   `json:"description"`
 	Category        *string                   `json:"category"`
+	Language        *string                   `json:"language"`
 	EstimatedTime   *string                   `json:"estimatedTime"`
+	TimeLimitMinutes *int                     `json:"timeLimitMinutes" binding:"omitempty,gte=0"` // 0 removes the limit
 	RewardAmount    *float64                  `json:"rewardAmount"`
 	MaxParticipants *int                      `json:"maxParticipants"`
 	XpReward        *int                      `json:"xpReward"`
 	Questions       []CreateQuestionRequest   `json:"questions"`
+	Quotas          []QuotaRequest            `json:"quotas"`
 	IsAnonymous     *bool                     `json:"isAnonymous"`
 	IsPublic        *bool                     `json:"isPublic"`
 	RequireLogin    *bool                     `json:"requireLogin"`
@@ -74,11 +111,13 @@ type SurveyResponse struct {
 	Title             string                   `json:"title"`
 	Description       string                   `json:"description"`
 	Category          string                   `json:"category"`
+	Language          string                   `json:"language"`
 	Status            string                   `json:"status"`
 	MaxResponses      int                      `json:"max_responses"`
 	RewardPerResponse float64                  `json:"reward_per_response"`
 	TotalRewardPool   float64                  `json:"total_reward_pool"`
 	EstimatedDuration int                      `json:"estimated_duration"`
+	TimeLimitMinutes  *int                     `json:"time_limit_minutes"`
 	ResponseCount     int                      `json:"response_count"`
 	CompletionRate    float64                  `json:"completion_rate"`
 	AverageRating     float64                  `json:"average_rating"`
@@ -91,6 +130,7 @@ type SurveyResponse struct {
 	CreatedAt         time.Time                `json:"created_at"`
 	UpdatedAt         time.Time                `json:"updated_at"`
 	Questions         []QuestionResponse       `json:"questions"`
+	Quotas            []QuotaResponse          `json:"quotas"`
 	Creator           UserResponse             `json:"creator"`
 }
 
@@ -107,6 +147,24 @@ type QuestionResponse struct {
 	MaxLength   *int                       `json:"max_length"`
 	MinValue    *float64                   `json:"min_value"`
 	MaxValue    *float64                   `json:"max_value"`
+	Rows        []QuestionOptionResponse   `json:"rows,omitempty"`
+	SumTotal    *float64                   `json:"sum_total,omitempty"`
+	FileConfig  *FileUploadConfigResponse  `json:"file_config,omitempty"`
+	ShowIf      *ConditionalLogicResponse  `json:"show_if,omitempty"`
+}
+
+// ConditionalLogicResponse represents a question's display condition in response
+type ConditionalLogicResponse struct {
+	QuestionID uint        `json:"question_id"`
+	Operator   string      `json:"operator"`
+	Value      interface{} `json:"value"`
+}
+
+// FileUploadConfigResponse represents file upload constraints in response
+type FileUploadConfigResponse struct {
+	AllowedTypes []string `json:"allowed_types"`
+	MaxSizeBytes int64    `json:"max_size_bytes"`
+	MaxFiles     int      `json:"max_files"`
 }
 
 // QuestionOptionResponse represents question option in response
@@ -117,6 +175,22 @@ type QuestionOptionResponse struct {
 	Order int    `json:"order"`
 }
 
+// QuotaResponse represents a quota with its live fill level
+type QuotaResponse struct {
+	ID            uint     `json:"id"`
+	Name          string   `json:"name"`
+	QuestionID    *uint    `json:"question_id"`
+	MetadataField *string  `json:"metadata_field"`
+	Value         string   `json:"value"`
+	Limit         *int     `json:"limit"`
+	Percentage    *float64 `json:"percentage"`
+	Capacity      int      `json:"capacity"`
+	CurrentCount  int      `json:"current_count"`
+	Remaining     int      `json:"remaining"`
+	FillRate      float64  `json:"fill_rate"` // percentage (0-100)
+	IsFull        bool     `json:"is_full"`
+}
+
 // UserResponse represents user in response
 type UserResponse struct {
 	ID              uint    `json:"id"`
@@ -125,13 +199,37 @@ type UserResponse struct {
 	ReputationScore float64 `json:"reputation_score"`
 }
 
-// SurveyListResponse for listing surveys
+// PublicSurveysRequest searches, filters and orders the public survey feed.
+// Pages follow each other through Cursor; Page still works for offsets.
+type PublicSurveysRequest struct {
+	Query       string   `form:"q"`
+	Lang        string   `form:"lang"` // language of q, defaults to Accept-Language
+	Category    string   `form:"category"`
+	Status      string   `form:"status"`
+	MinReward   *float64 `form:"min_reward" binding:"omitempty,min=0"`
+	MaxReward   *float64 `form:"max_reward" binding:"omitempty,min=0"`
+	MinDuration *int     `form:"min_duration" binding:"omitempty,min=0"` // minutes
+	MaxDuration *int     `form:"max_duration" binding:"omitempty,min=0"`
+	MinSlots    *int     `form:"min_slots" binding:"omitempty,min=1"`
+	EndsAfter   string   `form:"ends_after"`  // YYYY-MM-DD or RFC 3339
+	EndsBefore  string   `form:"ends_before"` // YYYY-MM-DD (inclusive) or RFC 3339
+	Sort        string   `form:"sort"`        // relevance, newest, ending_soon, reward_per_minute, popular
+	Cursor      string   `form:"cursor"`
+	Page        int      `form:"page"`
+	Limit       int      `form:"limit"`
+}
+
+// SurveyListResponse for listing surveys. The public feed adds the cursor of
+// the next page and how many of the matching surveys each category holds,
+// subcategories included.
 type SurveyListResponse struct {
-	Surveys    []SurveyItemResponse `json:"surveys"`
-	Total      int64                `json:"total"`
-	Page       int                  `json:"page"`
-	Limit      int                  `json:"limit"`
-	TotalPages int                  `json:"total_pages"`
+	Surveys        []SurveyItemResponse `json:"surveys"`
+	Total          int64                `json:"total"`
+	Page           int                  `json:"page"`
+	Limit          int                  `json:"limit"`
+	TotalPages     int                  `json:"total_pages"`
+	NextCursor     string               `json:"next_cursor,omitempty"`
+	CategoryCounts map[string]int64     `json:"category_counts,omitempty"`
 }
 
 // SurveyItemResponse for survey list item
@@ -140,12 +238,15 @@ type SurveyItemResponse struct {
 	Title             string       `json:"title"`
 	Description       string       `json:"description"`
 	Category          string       `json:"category"`
+	Language          string       `json:"language"`
 	Status            string       `json:"status"`
 	RewardPerResponse float64      `json:"reward_per_response"`
 	XpReward          int          `json:"xp_reward"`
 	EstimatedDuration int          `json:"estimated_duration"`
+	TimeLimitMinutes  *int         `json:"time_limit_minutes"`
 	ResponseCount     int          `json:"response_count"`
 	MaxResponses      int          `json:"max_responses"`
+	EndDate           *time.Time   `json:"end_date"`
 	CompletionRate    float64      `json:"completion_rate"`
 	AverageRating     float64      `json:"average_rating"`
 	CreatedAt         time.Time    `json:"created_at"`
@@ -0,0 +1,115 @@
+// internal/dto/template.go
+package dto
+
+import (
+	"time"
+)
+
+// CreateTemplateRequest represents the request to create a survey template.
+// Questions can be spelled out or pulled from the question bank.
+type CreateTemplateRequest struct {
+	Name            string                  `json:"name" binding:"required,min=3,max=255"`
+	Description     string                  `json:"description"`
+	Category        string                  `json:"category" binding:"required"`
+	EstimatedTime   string                  `json:"estimatedTime"`
+	Questions       []CreateQuestionRequest `json:"questions"`
+	BankQuestionIDs []uint                  `json:"bankQuestionIds"`
+}
+
+// UpdateTemplateRequest for updating a template
+type UpdateTemplateRequest struct {
+	Name          *string                 `json:"name"`
+	Description   *string                 `json:"description"`
+	Category      *string                 `json:"category"`
+	EstimatedTime *string                 `json:"estimatedTime"`
+	Questions     []CreateQuestionRequest `json:"questions"`
+	IsActive      *bool                   `json:"isActive"`
+}
+
+// CreateFromTemplateRequest represents the request to create a draft survey from a template
+type CreateFromTemplateRequest struct {
+	Title           *string    `json:"title"`
+	Description     *string    `json:"description"`
+	Category        *string    `json:"category"`
+	RewardAmount    float64    `json:"rewardAmount" binding:"required,gt=0"`
+	MaxParticipants int        `json:"maxParticipants" binding:"required,gt=0"`
+	XpReward        int        `json:"xpReward"`
+	IsAnonymous     bool       `json:"isAnonymous"`
+	IsPublic        bool       `json:"isPublic"`
+	RequireLogin    bool       `json:"requireLogin"`
+	AllowMultiple   bool       `json:"allowMultiple"`
+	StartDate       *time.Time `json:"startDate"`
+	EndDate         *time.Time `json:"endDate"`
+}
+
+// CloneSurveyRequest represents the request to clone an existing survey as a new draft
+type CloneSurveyRequest struct {
+	Title *string `json:"title"`
+}
+
+// ListTemplatesRequest for filtering templates
+type ListTemplatesRequest struct {
+	Category string `form:"category"`
+	Scope    string `form:"scope"` // all, platform, personal
+	Page     int    `form:"page" binding:"min=0"`
+	Limit    int    `form:"limit" binding:"min=0,max=100"`
+}
+
+// TemplateResponse represents a survey template in response
+type TemplateResponse struct {
+	ID                uint               `json:"id"`
+	Name              string             `json:"name"`
+	Description       string             `json:"description"`
+	Category          string             `json:"category"`
+	EstimatedDuration int                `json:"estimated_duration"`
+	IsPlatform        bool               `json:"is_platform"`
+	IsCurated         bool               `json:"is_curated"`
+	IsActive          bool               `json:"is_active"`
+	UsageCount        int                `json:"usage_count"`
+	Questions         []QuestionResponse `json:"questions"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+}
+
+// TemplateListResponse for listing templates
+type TemplateListResponse struct {
+	Templates  []TemplateResponse `json:"templates"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	TotalPages int                `json:"total_pages"`
+}
+
+// CreateBankQuestionRequest represents the request to add a question to the question bank
+type CreateBankQuestionRequest struct {
+	Category string                `json:"category"`
+	Question CreateQuestionRequest `json:"question" binding:"required"`
+}
+
+// ListBankQuestionsRequest for filtering the question bank
+type ListBankQuestionsRequest struct {
+	Category string `form:"category"`
+	Type     string `form:"type"`
+	Scope    string `form:"scope"` // all, platform, personal
+	Page     int    `form:"page" binding:"min=0"`
+	Limit    int    `form:"limit" binding:"min=0,max=100"`
+}
+
+// BankQuestionResponse represents a question bank entry in response
+type BankQuestionResponse struct {
+	ID         uint             `json:"id"`
+	Category   string           `json:"category"`
+	IsPlatform bool             `json:"is_platform"`
+	UsageCount int              `json:"usage_count"`
+	Question   QuestionResponse `json:"question"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// BankQuestionListResponse for listing question bank entries
+type BankQuestionListResponse struct {
+	Questions  []BankQuestionResponse `json:"questions"`
+	Total      int64                  `json:"total"`
+	Page       int                    `json:"page"`
+	Limit      int                    `json:"limit"`
+	TotalPages int                    `json:"total_pages"`
+}
@@ -0,0 +1,81 @@
+// internal/dto/webhook.go
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateWebhookRequest for registering a webhook endpoint. Without a survey
+// ID the webhook receives the events of all of the user's surveys.
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=2048"`
+	Events      []string `json:"events" binding:"required,min=1"`
+	SurveyID    *uint    `json:"surveyId"`
+	Description string   `json:"description" binding:"max=255"`
+}
+
+// UpdateWebhookRequest for changing a webhook; omitted fields are left unchanged
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url" binding:"omitempty,url,max=2048"`
+	Events      []string `json:"events" binding:"omitempty,min=1"`
+	Description *string  `json:"description" binding:"omitempty,max=255"`
+	IsActive    *bool    `json:"isActive"`
+}
+
+// ListWebhookDeliveriesRequest for browsing the delivery log of a webhook
+type ListWebhookDeliveriesRequest struct {
+	Status string `form:"status"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// WebhookResponse represents a webhook. The secret is only returned when the
+// webhook is created and when its secret is rotated.
+type WebhookResponse struct {
+	ID          uint      `json:"id"`
+	SurveyID    *uint     `json:"survey_id"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Description string    `json:"description"`
+	IsActive    bool      `json:"is_active"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse represents an entry of a webhook's delivery log
+type WebhookDeliveryResponse struct {
+	ID             uint            `json:"id"`
+	WebhookID      uint            `json:"webhook_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	ResponseStatus int             `json:"response_status"`
+	ResponseBody   string          `json:"response_body"`
+	Error          string          `json:"error"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// WebhookDeliveryListResponse for listing webhook deliveries
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	Total      int64                     `json:"total"`
+	Page       int                       `json:"page"`
+	Limit      int                       `json:"limit"`
+	TotalPages int                       `json:"total_pages"`
+}
+
+// WebhookPayload is the body of every webhook delivery
+type WebhookPayload struct {
+	ID        string      `json:"id"` // the same for every delivery of an event, including replays
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	SurveyID  *uint       `json:"survey_id,omitempty"`
+	Data      interface{} `json:"data"`
+}
@@ -0,0 +1,169 @@
+// internal/events/bus.go
+
+// Package events is an in-process bus pushing survey activity to live
+// subscribers, such as creators watching results stream in.
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBufferSize is the number of events a subscriber can fall behind by
+// before events are dropped for it
+const DefaultBufferSize = 64
+
+// Type identifies what happened
+type Type string
+
+const (
+	ResponseStarted     Type = "response.started"
+	ResponseCompleted   Type = "response.completed"
+	ResponseAbandoned   Type = "response.abandoned"
+	ResponseScreenedOut Type = "response.screened_out"
+	PoolUpdated         Type = "pool.updated"
+)
+
+// Event is something that happened to a survey
+type Event struct {
+	ID       uint64      `json:"id"`
+	Type     Type        `json:"type"`
+	SurveyID uint        `json:"survey_id"`
+	Data     interface{} `json:"data"`
+	Time     time.Time   `json:"time"`
+}
+
+// Publisher emits events
+type Publisher interface {
+	Publish(event Event)
+}
+
+// Bus fans events out to the subscribers of their survey. Publishing never
+// blocks: each subscriber has a bounded buffer and events that don't fit are
+// dropped and counted for that subscriber only, so one slow client can't
+// hold up the request that published the event or the other subscribers.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[uint]map[*Subscription]struct{}
+	closed      bool
+	bufferSize  int
+	lastID      atomic.Uint64
+}
+
+func NewBus(bufferSize int) *Bus {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Bus{
+		subscribers: make(map[uint]map[*Subscription]struct{}),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Publish sends an event to the current subscribers of its survey
+func (b *Bus) Publish(event Event) {
+	event.ID = b.lastID.Add(1)
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for subscription := range b.subscribers[event.SurveyID] {
+		select {
+		case subscription.events <- event:
+		default:
+			subscription.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribe starts receiving the events of a survey. The subscription must
+// be closed when no longer needed.
+func (b *Bus) Subscribe(surveyID uint) *Subscription {
+	subscription := &Subscription{
+		bus:      b,
+		surveyID: surveyID,
+		events:   make(chan Event, b.bufferSize),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(subscription.events)
+		subscription.closed = true
+		return subscription
+	}
+	if b.subscribers[surveyID] == nil {
+		b.subscribers[surveyID] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[surveyID][subscription] = struct{}{}
+
+	return subscription
+}
+
+// Subscribers returns the number of open subscriptions to a survey
+func (b *Bus) Subscribers(surveyID uint) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers[surveyID])
+}
+
+// Close ends every subscription, e.g. on shutdown so streaming requests return
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for surveyID, subscriptions := range b.subscribers {
+		for subscription := range subscriptions {
+			subscription.closed = true
+			close(subscription.events)
+		}
+		delete(b.subscribers, surveyID)
+	}
+}
+
+func (b *Bus) unsubscribe(subscription *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if subscription.closed {
+		return
+	}
+	subscription.closed = true
+	close(subscription.events)
+
+	subscriptions := b.subscribers[subscription.surveyID]
+	delete(subscriptions, subscription)
+	if len(subscriptions) == 0 {
+		delete(b.subscribers, subscription.surveyID)
+	}
+}
+
+// Subscription receives the events of one survey
+type Subscription struct {
+	bus      *Bus
+	surveyID uint
+	events   chan Event
+	dropped  atomic.Uint64
+	closed   bool // guarded by bus.mu
+}
+
+// Events is closed when the subscription or the bus is closed
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// TakeDropped returns the number of events dropped since the last call
+// because the subscriber fell too far behind
+func (s *Subscription) TakeDropped() uint64 {
+	return s.dropped.Swap(0)
+}
+
+func (s *Subscription) Close() {
+	s.bus.unsubscribe(s)
+}
@@ -0,0 +1,46 @@
+// internal/export/csv.go
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type csvWriter struct {
+	writer  *csv.Writer
+	columns []Column
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &csvWriter{
+		writer:  writer,
+		columns: columns,
+		record:  make([]string, len(columns)),
+	}, nil
+}
+
+func (w *csvWriter) WriteRow(values []interface{}) error {
+	if err := checkRowLength(w.columns, values); err != nil {
+		return err
+	}
+	for i, value := range values {
+		w.record[i] = formatValue(value)
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
@@ -0,0 +1,369 @@
+// internal/export/parquet.go
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// parquetRowGroupSize is the number of rows buffered before a row group is
+// flushed, bounding memory use for large exports
+const parquetRowGroupSize = 10000
+
+const parquetMagic = "PAR1"
+
+// Parquet physical and logical types, encodings and codecs used by the writer
+const (
+	parquetTypeDouble    = 5
+	parquetTypeByteArray = 6
+
+	parquetRepetitionOptional = 1
+
+	parquetConvertedUTF8 = 0
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetCodecUncompressed = 0
+	parquetPageTypeData      = 0
+)
+
+// parquetWriter writes a flat schema of optional columns. Every row group
+// holds one uncompressed, PLAIN encoded data page per column.
+type parquetWriter struct {
+	buffered  *bufio.Writer
+	w         *countingWriter
+	columns   []Column
+	pending   []parquetColumnBuffer
+	rowGroups []parquetRowGroup
+	rows      int
+	totalRows int64
+}
+
+type parquetColumnBuffer struct {
+	defined []bool
+	values  bytes.Buffer
+}
+
+type parquetRowGroup struct {
+	chunks   []parquetChunk
+	byteSize int64
+	numRows  int64
+}
+
+type parquetChunk struct {
+	offset    int64
+	size      int64
+	numValues int64
+}
+
+func newParquetWriter(w io.Writer, columns []Column) (*parquetWriter, error) {
+	buffered := bufio.NewWriter(w)
+	writer := &parquetWriter{
+		buffered: buffered,
+		w:        &countingWriter{w: buffered},
+		columns:  columns,
+		pending:  make([]parquetColumnBuffer, len(columns)),
+	}
+	if _, err := io.WriteString(writer.w, parquetMagic); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *parquetWriter) WriteRow(values []interface{}) error {
+	if err := checkRowLength(w.columns, values); err != nil {
+		return err
+	}
+
+	for i, column := range w.columns {
+		buffer := &w.pending[i]
+		value := values[i]
+		if value == nil {
+			buffer.defined = append(buffer.defined, false)
+			continue
+		}
+		buffer.defined = append(buffer.defined, true)
+
+		if column.Kind == KindNumber {
+			number, _ := value.(float64)
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(number))
+			buffer.values.Write(b[:])
+			continue
+		}
+		text := formatValue(value)
+		var length [4]byte
+		binary.LittleEndian.PutUint32(length[:], uint32(len(text)))
+		buffer.values.Write(length[:])
+		buffer.values.WriteString(text)
+	}
+
+	w.rows++
+	if w.rows >= parquetRowGroupSize {
+		return w.flushRowGroup()
+	}
+	return nil
+}
+
+func (w *parquetWriter) flushRowGroup() error {
+	if w.rows == 0 {
+		return nil
+	}
+
+	group := parquetRowGroup{numRows: int64(w.rows)}
+	for i := range w.columns {
+		buffer := &w.pending[i]
+
+		// Data page v1 body: length prefixed definition levels, then values
+		levels := encodeDefinitionLevels(buffer.defined)
+		var body bytes.Buffer
+		var length [4]byte
+		binary.LittleEndian.PutUint32(length[:], uint32(len(levels)))
+		body.Write(length[:])
+		body.Write(levels)
+		body.Write(buffer.values.Bytes())
+
+		header := &thriftWriter{}
+		header.fieldI32(1, parquetPageTypeData)
+		header.fieldI32(2, int32(body.Len()))
+		header.fieldI32(3, int32(body.Len()))
+		header.fieldStruct(5)
+		header.fieldI32(1, int32(len(buffer.defined)))
+		header.fieldI32(2, parquetEncodingPlain)
+		header.fieldI32(3, parquetEncodingRLE)
+		header.fieldI32(4, parquetEncodingRLE)
+		header.endStruct()
+		header.stop()
+
+		chunk := parquetChunk{
+			offset:    w.w.n,
+			size:      int64(header.buf.Len() + body.Len()),
+			numValues: int64(len(buffer.defined)),
+		}
+		if _, err := w.w.Write(header.buf.Bytes()); err != nil {
+			return err
+		}
+		if _, err := w.w.Write(body.Bytes()); err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, chunk)
+		group.byteSize += chunk.size
+
+		buffer.defined = buffer.defined[:0]
+		buffer.values.Reset()
+	}
+
+	w.rowGroups = append(w.rowGroups, group)
+	w.totalRows += int64(w.rows)
+	w.rows = 0
+	return nil
+}
+
+func (w *parquetWriter) Close() error {
+	if err := w.flushRowGroup(); err != nil {
+		return err
+	}
+
+	meta := &thriftWriter{}
+	meta.fieldI32(1, 1) // format version
+
+	// Schema: a root group followed by one optional leaf per column
+	meta.fieldList(2, thriftStruct, len(w.columns)+1)
+	meta.beginListStruct()
+	meta.fieldString(4, "schema")
+	meta.fieldI32(5, int32(len(w.columns)))
+	meta.endStruct()
+	for _, column := range w.columns {
+		meta.beginListStruct()
+		if column.Kind == KindNumber {
+			meta.fieldI32(1, parquetTypeDouble)
+		} else {
+			meta.fieldI32(1, parquetTypeByteArray)
+		}
+		meta.fieldI32(3, parquetRepetitionOptional)
+		meta.fieldString(4, column.Name)
+		if column.Kind == KindString {
+			meta.fieldI32(6, parquetConvertedUTF8)
+		}
+		meta.endStruct()
+	}
+
+	meta.fieldI64(3, w.totalRows)
+
+	meta.fieldList(4, thriftStruct, len(w.rowGroups))
+	for _, group := range w.rowGroups {
+		meta.beginListStruct()
+		meta.fieldList(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			column := w.columns[i]
+			meta.beginListStruct()
+			meta.fieldI64(2, chunk.offset)
+			meta.fieldStruct(3)
+			if column.Kind == KindNumber {
+				meta.fieldI32(1, parquetTypeDouble)
+			} else {
+				meta.fieldI32(1, parquetTypeByteArray)
+			}
+			meta.fieldList(2, thriftI32, 2)
+			meta.listI32(parquetEncodingPlain)
+			meta.listI32(parquetEncodingRLE)
+			meta.fieldList(3, thriftBinary, 1)
+			meta.listString(column.Name)
+			meta.fieldI32(4, parquetCodecUncompressed)
+			meta.fieldI64(5, chunk.numValues)
+			meta.fieldI64(6, chunk.size)
+			meta.fieldI64(7, chunk.size)
+			meta.fieldI64(9, chunk.offset)
+			meta.endStruct()
+			meta.endStruct()
+		}
+		meta.fieldI64(2, group.byteSize)
+		meta.fieldI64(3, group.numRows)
+		meta.endStruct()
+	}
+	meta.fieldString(6, "survey2earn")
+	meta.stop()
+
+	if _, err := w.w.Write(meta.buf.Bytes()); err != nil {
+		return err
+	}
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(meta.buf.Len()))
+	if _, err := w.w.Write(length[:]); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w.w, parquetMagic); err != nil {
+		return err
+	}
+	return w.buffered.Flush()
+}
+
+// encodeDefinitionLevels encodes 0/1 definition levels with the RLE/bit-packing
+// hybrid encoding, using RLE runs only
+func encodeDefinitionLevels(defined []bool) []byte {
+	var out bytes.Buffer
+	for i := 0; i < len(defined); {
+		j := i
+		for j < len(defined) && defined[j] == defined[i] {
+			j++
+		}
+		writeUvarint(&out, uint64(j-i)<<1)
+		if defined[i] {
+			out.WriteByte(1)
+		} else {
+			out.WriteByte(0)
+		}
+		i = j
+	}
+	return out.Bytes()
+}
+
+// Thrift compact protocol, just enough to write Parquet metadata
+
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftStruct = 12
+)
+
+type thriftWriter struct {
+	buf       bytes.Buffer
+	lastField []int16
+	current   int16
+}
+
+func (t *thriftWriter) fieldHeader(id int16, fieldType byte) {
+	delta := id - t.current
+	if delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.buf.WriteByte(fieldType)
+		writeVarint(&t.buf, int64(id))
+	}
+	t.current = id
+}
+
+func (t *thriftWriter) fieldI32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	writeVarint(&t.buf, int64(v))
+}
+
+func (t *thriftWriter) fieldI64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	writeVarint(&t.buf, v)
+}
+
+func (t *thriftWriter) fieldString(id int16, v string) {
+	t.fieldHeader(id, thriftBinary)
+	writeUvarint(&t.buf, uint64(len(v)))
+	t.buf.WriteString(v)
+}
+
+func (t *thriftWriter) fieldStruct(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.lastField = append(t.lastField, t.current)
+	t.current = 0
+}
+
+func (t *thriftWriter) fieldList(id int16, elemType byte, size int) {
+	t.fieldHeader(id, 9)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		t.buf.WriteByte(0xf0 | elemType)
+		writeUvarint(&t.buf, uint64(size))
+	}
+}
+
+// beginListStruct starts a struct element inside a list
+func (t *thriftWriter) beginListStruct() {
+	t.lastField = append(t.lastField, t.current)
+	t.current = 0
+}
+
+func (t *thriftWriter) endStruct() {
+	t.stop()
+	t.current = t.lastField[len(t.lastField)-1]
+	t.lastField = t.lastField[:len(t.lastField)-1]
+}
+
+func (t *thriftWriter) listI32(v int32) {
+	writeVarint(&t.buf, int64(v))
+}
+
+func (t *thriftWriter) listString(v string) {
+	writeUvarint(&t.buf, uint64(len(v)))
+	t.buf.WriteString(v)
+}
+
+func (t *thriftWriter) stop() {
+	t.buf.WriteByte(0)
+}
+
+// writeVarint writes a zigzag encoded varint
+func writeVarint(buf *bytes.Buffer, v int64) {
+	writeUvarint(buf, uint64((v<<1)^(v>>63)))
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	buf.Write(b[:n])
+}
+
+// countingWriter tracks the file offset for column chunk metadata
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
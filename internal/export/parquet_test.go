@@ -0,0 +1,297 @@
+// internal/export/parquet_test.go
+package export
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// thriftReader decodes the Thrift compact protocol into generic values:
+// structs become maps by field ID, lists slices, integers int64, binaries
+// strings
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) byte() byte {
+	if r.pos >= len(r.data) {
+		panic("thrift: unexpected end of data")
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		panic("thrift: invalid varint")
+	}
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(fieldType byte) interface{} {
+	switch fieldType {
+	case 1:
+		return true
+	case 2:
+		return false
+	case 3:
+		return int64(int8(r.byte()))
+	case 4, thriftI32, thriftI64:
+		return r.varint()
+	case 7:
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.data[r.pos:]))
+		r.pos += 8
+		return v
+	case thriftBinary:
+		n := int(r.uvarint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case 9:
+		header := r.byte()
+		size, elemType := int(header>>4), header&0x0f
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			if elemType == 1 || elemType == 2 {
+				list[i] = r.byte() == 1
+				continue
+			}
+			list[i] = r.value(elemType)
+		}
+		return list
+	case thriftStruct:
+		fields := map[int16]interface{}{}
+		var id int16
+		for {
+			header := r.byte()
+			if header == 0 {
+				return fields
+			}
+			if delta := int16(header >> 4); delta != 0 {
+				id += delta
+			} else {
+				id = int16(r.varint())
+			}
+			fields[id] = r.value(header & 0x0f)
+		}
+	}
+	panic(fmt.Sprintf("thrift: unsupported type %d", fieldType))
+}
+
+// readStruct decodes a struct, failing the test when it can't
+func (r *thriftReader) readStruct(t *testing.T) (fields map[int16]interface{}) {
+	t.Helper()
+	defer func() {
+		if err := recover(); err != nil {
+			t.Fatalf("decode Thrift struct at %d: %v", r.pos, err)
+		}
+	}()
+	return r.value(thriftStruct).(map[int16]interface{})
+}
+
+// parquetFile is what readParquet found in a file
+type parquetFile struct {
+	columns   []Column // names and kinds
+	rows      [][]interface{}
+	rowGroups int
+}
+
+// readParquet checks the layout of a file written by parquetWriter and reads
+// its rows back
+func readParquet(t *testing.T, data []byte) *parquetFile {
+	t.Helper()
+	if len(data) < 12 || string(data[:4]) != parquetMagic || string(data[len(data)-4:]) != parquetMagic {
+		t.Fatalf("file isn't framed by %s", parquetMagic)
+	}
+	footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footerStart := len(data) - 8 - footerLength
+	if footerStart < 4 {
+		t.Fatalf("footer length %d doesn't fit in a file of %d bytes", footerLength, len(data))
+	}
+	footer := &thriftReader{data: data[footerStart : len(data)-8]}
+	meta := footer.readStruct(t)
+	if footer.pos != footerLength {
+		t.Fatalf("file metadata is %d bytes, the footer length says %d", footer.pos, footerLength)
+	}
+	if meta[1] != int64(1) {
+		t.Errorf("format version = %v, want 1", meta[1])
+	}
+
+	// Schema: the root, then one optional leaf per column
+	schema := meta[2].([]interface{})
+	root := schema[0].(map[int16]interface{})
+	if root[4] != "schema" || root[5] != int64(len(schema)-1) {
+		t.Fatalf("schema root = %v, want %d children", root, len(schema)-1)
+	}
+	file := &parquetFile{}
+	for _, element := range schema[1:] {
+		leaf := element.(map[int16]interface{})
+		if leaf[3] != int64(parquetRepetitionOptional) {
+			t.Errorf("column %v is not optional", leaf[4])
+		}
+		column := Column{Name: leaf[4].(string)}
+		switch {
+		case leaf[1] == int64(parquetTypeDouble) && leaf[6] == nil:
+			column.Kind = KindNumber
+		case leaf[1] == int64(parquetTypeByteArray) && leaf[6] == int64(parquetConvertedUTF8):
+			column.Kind = KindString
+		default:
+			t.Fatalf("column %s has type %v, converted type %v", column.Name, leaf[1], leaf[6])
+		}
+		file.columns = append(file.columns, column)
+	}
+
+	numRows := meta[3].(int64)
+	for _, element := range meta[4].([]interface{}) {
+		group := element.(map[int16]interface{})
+		chunks := group[1].([]interface{})
+		if len(chunks) != len(file.columns) {
+			t.Fatalf("row group has %d column chunks, want %d", len(chunks), len(file.columns))
+		}
+		groupRows := int(group[3].(int64))
+		rows := make([][]interface{}, groupRows)
+		for i := range rows {
+			rows[i] = make([]interface{}, len(file.columns))
+		}
+
+		var byteSize int64
+		for i, chunk := range chunks {
+			column := file.columns[i]
+			chunkMeta := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+			if !reflect.DeepEqual(chunkMeta[3], []interface{}{column.Name}) {
+				t.Errorf("chunk path = %v, want [%s]", chunkMeta[3], column.Name)
+			}
+			if chunkMeta[4] != int64(parquetCodecUncompressed) || chunkMeta[5] != int64(groupRows) {
+				t.Errorf("chunk of %s has codec %v and %v values, want uncompressed and %d", column.Name, chunkMeta[4], chunkMeta[5], groupRows)
+			}
+			size := chunkMeta[6].(int64)
+			byteSize += size
+			values := readParquetPage(t, data, chunkMeta[9].(int64), size, column, groupRows)
+			for row, value := range values {
+				rows[row][i] = value
+			}
+		}
+		if group[2] != byteSize {
+			t.Errorf("row group size = %v, want %d", group[2], byteSize)
+		}
+		file.rows = append(file.rows, rows...)
+		file.rowGroups++
+	}
+	if int64(len(file.rows)) != numRows {
+		t.Errorf("file has %d rows, the metadata says %d", len(file.rows), numRows)
+	}
+	return file
+}
+
+// readParquetPage reads the values of the single data page of a chunk
+func readParquetPage(t *testing.T, data []byte, offset, size int64, column Column, numValues int) []interface{} {
+	t.Helper()
+	page := &thriftReader{data: data[offset : offset+size]}
+	header := page.readStruct(t)
+	body := page.data[page.pos:]
+	if header[1] != int64(parquetPageTypeData) || header[2] != int64(len(body)) || header[3] != int64(len(body)) {
+		t.Fatalf("page header of %s = %v, want a data page of %d bytes", column.Name, header, len(body))
+	}
+	dataHeader := header[5].(map[int16]interface{})
+	if dataHeader[1] != int64(numValues) || dataHeader[2] != int64(parquetEncodingPlain) || dataHeader[3] != int64(parquetEncodingRLE) {
+		t.Fatalf("data page header of %s = %v", column.Name, dataHeader)
+	}
+
+	// Definition levels, RLE runs of a bit width of 1
+	levelsLength := int(binary.LittleEndian.Uint32(body))
+	levels := &thriftReader{data: body[4 : 4+levelsLength]}
+	var defined []bool
+	for levels.pos < len(levels.data) {
+		run := levels.uvarint()
+		if run&1 != 0 {
+			t.Fatalf("definition levels of %s are bit-packed, want RLE runs", column.Name)
+		}
+		level := levels.byte()
+		for i := uint64(0); i < run>>1; i++ {
+			defined = append(defined, level == 1)
+		}
+	}
+	if len(defined) != numValues {
+		t.Fatalf("%s has %d definition levels, want %d", column.Name, len(defined), numValues)
+	}
+
+	// PLAIN values of the defined rows
+	plain := body[4+levelsLength:]
+	values := make([]interface{}, numValues)
+	for i := range values {
+		if !defined[i] {
+			continue
+		}
+		if column.Kind == KindNumber {
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(plain))
+			plain = plain[8:]
+			continue
+		}
+		n := binary.LittleEndian.Uint32(plain)
+		values[i] = string(plain[4 : 4+n])
+		plain = plain[4+n:]
+	}
+	if len(plain) != 0 {
+		t.Errorf("%d bytes are left after the values of %s", len(plain), column.Name)
+	}
+	return values
+}
+
+func TestParquet(t *testing.T) {
+	file := readParquet(t, writeExport(t, FormatParquet, "Survey", testColumns, testRows))
+
+	wantColumns := make([]Column, len(testColumns))
+	for i, column := range testColumns {
+		wantColumns[i] = Column{Name: column.Name, Kind: column.Kind}
+	}
+	if !reflect.DeepEqual(file.columns, wantColumns) {
+		t.Errorf("columns = %v, want %v", file.columns, wantColumns)
+	}
+	if file.rowGroups != 1 {
+		t.Errorf("file has %d row groups, want 1", file.rowGroups)
+	}
+	if !reflect.DeepEqual(file.rows, testRows) {
+		t.Errorf("rows = %v, want %v", file.rows, testRows)
+	}
+}
+
+func TestParquetRowGroups(t *testing.T) {
+	columns := []Column{{Name: "n", Kind: KindNumber}, {Name: "s", Kind: KindString}}
+	rows := make([][]interface{}, parquetRowGroupSize+1)
+	for i := range rows {
+		rows[i] = []interface{}{float64(i), nil}
+		if i%3 == 0 {
+			rows[i][1] = fmt.Sprint(i)
+		}
+	}
+
+	file := readParquet(t, writeExport(t, FormatParquet, "Survey", columns, rows))
+	if file.rowGroups != 2 {
+		t.Errorf("file has %d row groups, want 2", file.rowGroups)
+	}
+	if !reflect.DeepEqual(file.rows, rows) {
+		t.Error("rows read back differ from the rows written")
+	}
+}
+
+func TestParquetWithoutRows(t *testing.T) {
+	file := readParquet(t, writeExport(t, FormatParquet, "Survey", testColumns, nil))
+	if file.rowGroups != 0 || len(file.rows) != 0 || len(file.columns) != len(testColumns) {
+		t.Errorf("empty file = %+v, want %d columns and no row groups", file, len(testColumns))
+	}
+}
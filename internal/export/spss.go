@@ -0,0 +1,243 @@
+// internal/export/spss.go
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SPSS system file (.sav) limits and constants
+const (
+	spssStringWidth    = 255 // widest string that doesn't need very long string records
+	spssMaxLabelLength = 255
+	spssMaxNameLength  = 64
+	spssNumericFormat  = 5<<16 | 8<<8 | 2 // F8.2
+	spssStringFormat   = 1 << 16          // A, width added per variable
+	spssCodePageUTF8   = 65001
+)
+
+var spssSysmis = -math.MaxFloat64
+
+// spssReservedNames can't be used as variable names
+var spssReservedNames = map[string]bool{
+	"ALL": true, "AND": true, "BY": true, "EQ": true, "GE": true, "GT": true, "LE": true,
+	"LT": true, "NE": true, "NOT": true, "OR": true, "TO": true, "WITH": true,
+}
+
+// spssWriter writes an uncompressed SPSS system file. The number of cases is
+// written as unknown (-1), which SPSS and other readers accept, so rows can
+// be streamed without knowing the total up front.
+type spssWriter struct {
+	w       *bufio.Writer
+	columns []Column
+	buf     [8]byte
+}
+
+func newSPSSWriter(w io.Writer, title string, columns []Column) (*spssWriter, error) {
+	writer := &spssWriter{
+		w:       bufio.NewWriter(w),
+		columns: columns,
+	}
+	if err := writer.writeDictionary(title); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *spssWriter) writeDictionary(title string) error {
+	segments := 0
+	for _, column := range w.columns {
+		segments += spssSegments(column)
+	}
+
+	// File header record
+	now := time.Now()
+	w.writeBytes([]byte("$FL2"))
+	w.writeBytes(padRight("@(#) SPSS DATA FILE survey2earn", 60))
+	w.writeInt32(2)               // layout code
+	w.writeInt32(int32(segments)) // nominal case size in 8 byte units
+	w.writeInt32(0)               // uncompressed
+	w.writeInt32(0)               // no weight variable
+	w.writeInt32(-1)              // number of cases unknown
+	w.writeFloat64(100)           // compression bias
+	w.writeBytes(padRight(now.Format("02 Jan 06"), 9))
+	w.writeBytes(padRight(now.Format("15:04:05"), 8))
+	w.writeBytes(padRight(truncateUTF8(title, 64), 64))
+	w.writeBytes([]byte{0, 0, 0})
+
+	// Variable records, with short names V1, V2, ... and the real names in
+	// the long variable names record
+	longNames := make([]string, len(w.columns))
+	used := make(map[string]bool, len(w.columns))
+	for i, column := range w.columns {
+		shortName := fmt.Sprintf("V%d", i+1)
+		longNames[i] = shortName + "=" + spssVariableName(column.Name, i, used)
+
+		varType, format := int32(0), int32(spssNumericFormat)
+		if column.Kind == KindString {
+			varType, format = spssStringWidth, spssStringFormat|spssStringWidth<<8
+		}
+		label := truncateUTF8(column.Label, spssMaxLabelLength)
+
+		w.writeInt32(2)
+		w.writeInt32(varType)
+		if label != "" {
+			w.writeInt32(1)
+		} else {
+			w.writeInt32(0)
+		}
+		w.writeInt32(0) // no missing values
+		w.writeInt32(format)
+		w.writeInt32(format)
+		w.writeBytes(padRight(shortName, 8))
+		if label != "" {
+			w.writeInt32(int32(len(label)))
+			w.writeBytes(padRight(label, (len(label)+3)/4*4))
+		}
+
+		// Strings wider than 8 bytes continue in additional records
+		for j := 1; j < spssSegments(column); j++ {
+			w.writeInt32(2)
+			w.writeInt32(-1)
+			w.writeInt32(0)
+			w.writeInt32(0)
+			w.writeInt32(0)
+			w.writeInt32(0)
+			w.writeBytes(padRight("", 8))
+		}
+	}
+
+	// Machine integer info
+	w.writeInt32(7)
+	w.writeInt32(3)
+	w.writeInt32(4)
+	w.writeInt32(8)
+	for _, v := range []int32{1, 0, 0, -1, 1, 1, 2, spssCodePageUTF8} {
+		w.writeInt32(v)
+	}
+
+	// Machine floating point info
+	w.writeInt32(7)
+	w.writeInt32(4)
+	w.writeInt32(8)
+	w.writeInt32(3)
+	w.writeFloat64(spssSysmis)
+	w.writeFloat64(math.MaxFloat64)
+	w.writeFloat64(math.Nextafter(-math.MaxFloat64, 0))
+
+	w.writeTextExtension(13, strings.Join(longNames, "\t"))
+	w.writeTextExtension(20, "UTF-8")
+
+	// Dictionary termination
+	w.writeInt32(999)
+	w.writeInt32(0)
+
+	return nil
+}
+
+func (w *spssWriter) writeTextExtension(subtype int32, text string) {
+	w.writeInt32(7)
+	w.writeInt32(subtype)
+	w.writeInt32(1)
+	w.writeInt32(int32(len(text)))
+	w.writeBytes([]byte(text))
+}
+
+func (w *spssWriter) WriteRow(values []interface{}) error {
+	if err := checkRowLength(w.columns, values); err != nil {
+		return err
+	}
+	for i, column := range w.columns {
+		if column.Kind == KindNumber {
+			number, ok := values[i].(float64)
+			if !ok || math.IsNaN(number) {
+				number = spssSysmis
+			}
+			w.writeFloat64(number)
+			continue
+		}
+		text := truncateUTF8(formatValue(values[i]), spssStringWidth)
+		w.writeBytes(padRight(text, spssSegments(column)*8))
+	}
+	return nil
+}
+
+func (w *spssWriter) Close() error {
+	return w.w.Flush()
+}
+
+func (w *spssWriter) writeInt32(v int32) {
+	binary.LittleEndian.PutUint32(w.buf[:4], uint32(v))
+	w.w.Write(w.buf[:4])
+}
+
+func (w *spssWriter) writeFloat64(v float64) {
+	binary.LittleEndian.PutUint64(w.buf[:], math.Float64bits(v))
+	w.w.Write(w.buf[:8])
+}
+
+func (w *spssWriter) writeBytes(b []byte) {
+	w.w.Write(b)
+}
+
+// spssSegments returns the number of 8 byte segments a column occupies in a case
+func spssSegments(column Column) int {
+	if column.Kind == KindString {
+		return (spssStringWidth + 7) / 8
+	}
+	return 1
+}
+
+// spssVariableName turns a column name into a unique, valid SPSS variable name
+func spssVariableName(name string, index int, used map[string]bool) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	candidate := strings.TrimRight(b.String(), "._")
+	if candidate == "" || !(candidate[0] >= 'a' && candidate[0] <= 'z' || candidate[0] >= 'A' && candidate[0] <= 'Z') {
+		candidate = "v_" + candidate
+	}
+	if len(candidate) > spssMaxNameLength {
+		candidate = candidate[:spssMaxNameLength]
+	}
+	if spssReservedNames[strings.ToUpper(candidate)] || used[strings.ToUpper(candidate)] {
+		suffix := fmt.Sprintf("_%d", index+1)
+		if len(candidate)+len(suffix) > spssMaxNameLength {
+			candidate = candidate[:spssMaxNameLength-len(suffix)]
+		}
+		candidate += suffix
+	}
+	used[strings.ToUpper(candidate)] = true
+	return candidate
+}
+
+// padRight pads s with spaces to exactly n bytes, truncating longer strings
+func padRight(s string, n int) []byte {
+	b := bytes.Repeat([]byte{' '}, n)
+	copy(b, s)
+	return b
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a character
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
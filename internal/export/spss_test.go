@@ -0,0 +1,239 @@
+// internal/export/spss_test.go
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// spssTestFile is what readSPSS found in a system file
+type spssTestFile struct {
+	caseSize    int32
+	cases       int32
+	label       string
+	variables   []spssTestVariable
+	longNames   map[string]string // short name to long name
+	encoding    string
+	codePage    int32
+	sysmis      float64
+	rows        [][]interface{}
+	continuants int // string continuation records
+}
+
+type spssTestVariable struct {
+	name   string
+	width  int32 // 0 for numbers
+	label  string
+	format int32
+}
+
+// spssReader reads the little-endian fields of a system file
+type spssReader struct {
+	t    *testing.T
+	data []byte
+	pos  int
+}
+
+func (r *spssReader) bytes(n int) []byte {
+	r.t.Helper()
+	if r.pos+n > len(r.data) {
+		r.t.Fatalf("the file ends at %d, reading %d bytes at %d", len(r.data), n, r.pos)
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *spssReader) int32() int32 {
+	r.t.Helper()
+	return int32(binary.LittleEndian.Uint32(r.bytes(4)))
+}
+
+func (r *spssReader) float64() float64 {
+	r.t.Helper()
+	return math.Float64frombits(binary.LittleEndian.Uint64(r.bytes(8)))
+}
+
+// readSPSS parses an uncompressed system file the way readers of the format do
+func readSPSS(t *testing.T, data []byte) *spssTestFile {
+	t.Helper()
+	r := &spssReader{t: t, data: data}
+	file := &spssTestFile{longNames: map[string]string{}}
+
+	if magic := string(r.bytes(4)); magic != "$FL2" {
+		t.Fatalf("magic = %q, want $FL2", magic)
+	}
+	r.bytes(60) // product
+	if layout := r.int32(); layout != 2 {
+		t.Fatalf("layout code = %d, want 2 for little-endian", layout)
+	}
+	file.caseSize = r.int32()
+	if compression := r.int32(); compression != 0 {
+		t.Fatalf("compression = %d, want 0", compression)
+	}
+	if weight := r.int32(); weight != 0 {
+		t.Errorf("weight index = %d, want 0", weight)
+	}
+	file.cases = r.int32()
+	if bias := r.float64(); bias != 100 {
+		t.Errorf("bias = %v, want 100", bias)
+	}
+	r.bytes(9 + 8) // creation date and time
+	file.label = strings.TrimRight(string(r.bytes(64)), " ")
+	r.bytes(3)
+
+	// Dictionary records up to the termination record
+	for done := false; !done; {
+		switch recordType := r.int32(); recordType {
+		case 2:
+			width := r.int32()
+			hasLabel := r.int32()
+			if missing := r.int32(); missing != 0 {
+				t.Errorf("variable has %d missing values, want none", missing)
+			}
+			format := r.int32()
+			r.int32() // write format
+			name := strings.TrimRight(string(r.bytes(8)), " ")
+			variable := spssTestVariable{name: name, width: width, format: format}
+			if hasLabel == 1 {
+				length := int(r.int32())
+				variable.label = string(r.bytes((length + 3) / 4 * 4)[:length])
+			}
+			if width == -1 {
+				file.continuants++
+				continue
+			}
+			file.variables = append(file.variables, variable)
+		case 7:
+			subtype, size, count := r.int32(), r.int32(), r.int32()
+			content := r.bytes(int(size * count))
+			switch subtype {
+			case 3:
+				file.codePage = int32(binary.LittleEndian.Uint32(content[28:]))
+			case 4:
+				file.sysmis = math.Float64frombits(binary.LittleEndian.Uint64(content))
+			case 13:
+				for _, pair := range strings.Split(string(content), "\t") {
+					short, long, _ := strings.Cut(pair, "=")
+					file.longNames[short] = long
+				}
+			case 20:
+				file.encoding = string(content)
+			}
+		case 999:
+			r.int32()
+			done = true
+		default:
+			t.Fatalf("unknown record type %d at %d", recordType, r.pos-4)
+		}
+	}
+
+	// Cases, read to the end of the file since their number is unknown
+	segments := int32(0)
+	for _, variable := range file.variables {
+		if variable.width == 0 {
+			segments++
+		} else {
+			segments += (variable.width + 7) / 8
+		}
+	}
+	if segments != file.caseSize {
+		t.Errorf("variables take %d segments, the header says %d", segments, file.caseSize)
+	}
+	if (len(data)-r.pos)%int(segments*8) != 0 {
+		t.Fatalf("%d bytes of cases aren't whole cases of %d bytes", len(data)-r.pos, segments*8)
+	}
+	for r.pos < len(data) {
+		row := make([]interface{}, len(file.variables))
+		for i, variable := range file.variables {
+			if variable.width == 0 {
+				row[i] = r.float64()
+				continue
+			}
+			row[i] = strings.TrimRight(string(r.bytes(int((variable.width+7)/8*8))), " ")
+		}
+		file.rows = append(file.rows, row)
+	}
+	return file
+}
+
+func TestSPSS(t *testing.T) {
+	file := readSPSS(t, writeExport(t, FormatSPSS, "DeFi habits", testColumns, testRows))
+
+	if file.cases != -1 || file.label != "DeFi habits" {
+		t.Errorf("cases, label = %d, %q, want -1, DeFi habits", file.cases, file.label)
+	}
+	if file.encoding != "UTF-8" || file.codePage != spssCodePageUTF8 {
+		t.Errorf("encoding, code page = %q, %d, want UTF-8, %d", file.encoding, file.codePage, spssCodePageUTF8)
+	}
+	if file.sysmis != spssSysmis {
+		t.Errorf("sysmis = %v, want %v", file.sysmis, spssSysmis)
+	}
+
+	wantVariables := []spssTestVariable{
+		{name: "V1", label: "Response", format: spssNumericFormat},
+		{name: "V2", width: spssStringWidth, label: "How often do you use DeFi?", format: spssStringFormat | spssStringWidth<<8},
+		{name: "V3", format: spssNumericFormat},
+	}
+	if !reflect.DeepEqual(file.variables, wantVariables) {
+		t.Errorf("variables =\n%+v\nwant\n%+v", file.variables, wantVariables)
+	}
+	if want := spssSegments(testColumns[1]) - 1; file.continuants != want {
+		t.Errorf("%d continuation records, want %d", file.continuants, want)
+	}
+	wantNames := map[string]string{"V1": "response_id", "V2": "q1_usage", "V3": "q2_rating"}
+	if !reflect.DeepEqual(file.longNames, wantNames) {
+		t.Errorf("long names = %v, want %v", file.longNames, wantNames)
+	}
+
+	// Missing numbers are system missing, missing strings blank
+	wantRows := [][]interface{}{
+		{1.0, "daily", 4.5},
+		{2.0, "", spssSysmis},
+		{1234567890123.0, `Tom & "Jerry" <3> über`, -0.25},
+	}
+	if !reflect.DeepEqual(file.rows, wantRows) {
+		t.Errorf("rows = %v, want %v", file.rows, wantRows)
+	}
+}
+
+func TestSPSSTruncatesLongText(t *testing.T) {
+	columns := []Column{{Name: "answer", Label: strings.Repeat("é", 200), Kind: KindString}}
+	text := strings.Repeat("ü", 200) // 400 bytes
+	file := readSPSS(t, writeExport(t, FormatSPSS, strings.Repeat("t", 100), columns, [][]interface{}{{text}}))
+
+	if got := file.rows[0][0].(string); got != strings.Repeat("ü", 127) {
+		t.Errorf("text is cut to %d bytes, want 254 without splitting a character", len(got))
+	}
+	if got := file.variables[0].label; got != strings.Repeat("é", 127) {
+		t.Errorf("label is cut to %d bytes, want 254 without splitting a character", len(got))
+	}
+	if len(file.label) != 64 {
+		t.Errorf("file label is %d bytes, want 64", len(file.label))
+	}
+}
+
+func TestSPSSVariableName(t *testing.T) {
+	used := map[string]bool{}
+	tests := []struct{ name, want string }{
+		{"q1_usage", "q1_usage"},
+		{"Q1_USAGE", "Q1_USAGE_2"}, // names are case insensitive
+		{"1st question", "v_1st_question"},
+		{"and", "and_4"},
+		{"trailing.", "trailing"},
+		{"ünïcode", "v__n_code"},
+		{strings.Repeat("x", 70), strings.Repeat("x", 64)},
+	}
+	for i, test := range tests {
+		if got := spssVariableName(test.name, i, used); got != test.want {
+			t.Errorf("spssVariableName(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+	if !bytes.Equal(padRight("ab", 4), []byte("ab  ")) || !bytes.Equal(padRight("abcdef", 4), []byte("abcd")) {
+		t.Error("padRight doesn't pad and truncate to the width")
+	}
+}
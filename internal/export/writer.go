@@ -0,0 +1,113 @@
+// internal/export/writer.go
+//
+// Package export streams tabular data in the file formats offered for survey
+// response exports. Every writer takes the columns up front and then one row
+// at a time, so arbitrarily large exports never have to fit in memory.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Supported export formats
+const (
+	FormatCSV     = "csv"
+	FormatXLSX    = "xlsx"
+	FormatSPSS    = "sav"
+	FormatParquet = "parquet"
+)
+
+// Formats lists the supported export formats
+var Formats = []string{FormatCSV, FormatXLSX, FormatSPSS, FormatParquet}
+
+// ColumnKind is the data type of a column
+type ColumnKind int
+
+const (
+	KindString ColumnKind = iota
+	KindNumber
+)
+
+// Column describes a single column of the export
+type Column struct {
+	Name  string // machine friendly name, unique within the export
+	Label string // human readable description, e.g. the question text
+	Kind  ColumnKind
+}
+
+// Writer writes rows of values matching the columns it was created with.
+// Values are nil (missing), float64 for number columns and string for string
+// columns. Close must be called to flush the file trailer.
+type Writer interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// IsValidFormat checks if the format is a supported export format
+func IsValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatSPSS:
+		return "application/x-spss-sav"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// NewWriter creates a writer for the format. title is used where the format
+// has room for it (sheet name, file label).
+func NewWriter(format string, w io.Writer, title string, columns []Column) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatXLSX:
+		return newXLSXWriter(w, title, columns)
+	case FormatSPSS:
+		return newSPSSWriter(w, title, columns)
+	case FormatParquet:
+		return newParquetWriter(w, columns)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// formatValue renders a value as text for the text based formats
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return formatNumber(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatNumber avoids exponent notation so spreadsheets read numbers as-is
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func checkRowLength(columns []Column, values []interface{}) error {
+	if len(values) != len(columns) {
+		return fmt.Errorf("row has %d values, expected %d", len(values), len(columns))
+	}
+	return nil
+}
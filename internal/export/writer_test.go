@@ -0,0 +1,69 @@
+// internal/export/writer_test.go
+package export
+
+import (
+	"bytes"
+	"testing"
+)
+
+// testColumns and testRows are written in every format. The rows hold
+// missing values, markup and non-ASCII text.
+var testColumns = []Column{
+	{Name: "response_id", Label: "Response", Kind: KindNumber},
+	{Name: "q1_usage", Label: "How often do you use DeFi?", Kind: KindString},
+	{Name: "q2_rating", Kind: KindNumber},
+}
+
+var testRows = [][]interface{}{
+	{1.0, "daily", 4.5},
+	{2.0, nil, nil},
+	{1234567890123.0, `Tom & "Jerry" <3> über`, -0.25},
+}
+
+// writeExport writes rows in format and returns the file
+func writeExport(t *testing.T, format, title string, columns []Column, rows [][]interface{}) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, title, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestNewWriterRejectsUnknownFormats(t *testing.T) {
+	if _, err := NewWriter("pdf", &bytes.Buffer{}, "Survey", testColumns); err == nil {
+		t.Error("NewWriter(pdf) succeeded, want an error")
+	}
+}
+
+func TestWritersRejectRowsOfTheWrongLength(t *testing.T) {
+	for _, format := range Formats {
+		w, err := NewWriter(format, &bytes.Buffer{}, "Survey", testColumns)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if err := w.WriteRow([]interface{}{1.0}); err == nil {
+			t.Errorf("%s: a row of 1 value for 3 columns was written, want an error", format)
+		}
+	}
+}
+
+func TestCSV(t *testing.T) {
+	got := string(writeExport(t, FormatCSV, "Survey", testColumns, testRows))
+	want := "response_id,q1_usage,q2_rating\n" +
+		"1,daily,4.5\n" +
+		"2,,\n" +
+		"1234567890123,\"Tom & \"\"Jerry\"\" <3> über\",-0.25\n"
+	if got != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,170 @@
+// internal/export/xlsx.go
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxSheetNameLength is the longest worksheet name Excel accepts
+const maxSheetNameLength = 31
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+// xlsxWriter streams a single worksheet using inline strings, so no shared
+// string table has to be kept in memory
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	columns []Column
+	refs    []string
+	row     int
+}
+
+func newXLSXWriter(w io.Writer, title string, columns []Column) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, escapeXML(sheetName(title)))},
+	}
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// The worksheet is the last part, so rows can be streamed straight into it
+	f, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	writer := &xlsxWriter{
+		archive: archive,
+		sheet:   bufio.NewWriter(f),
+		columns: columns,
+		refs:    make([]string, len(columns)),
+	}
+	for i := range columns {
+		writer.refs[i] = columnLetters(i)
+	}
+
+	writer.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	writer.sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+	if err := writer.writeCells(header); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *xlsxWriter) WriteRow(values []interface{}) error {
+	if err := checkRowLength(w.columns, values); err != nil {
+		return err
+	}
+	return w.writeCells(values)
+}
+
+func (w *xlsxWriter) writeCells(values []interface{}) error {
+	w.row++
+	row := strconv.Itoa(w.row)
+
+	w.sheet.WriteString(`<row r="` + row + `">`)
+	for i, value := range values {
+		ref := w.refs[i] + row
+		switch v := value.(type) {
+		case nil:
+			continue
+		case float64:
+			w.sheet.WriteString(`<c r="` + ref + `"><v>` + formatNumber(v) + `</v></c>`)
+		default:
+			w.sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+			w.sheet.WriteString(escapeXML(formatValue(v)))
+			w.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (w *xlsxWriter) Close() error {
+	w.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
+
+// columnLetters converts a zero based column index to A, B, ..., Z, AA, ...
+func columnLetters(index int) string {
+	letters := ""
+	for index >= 0 {
+		letters = string(rune('A'+index%26)) + letters
+		index = index/26 - 1
+	}
+	return letters
+}
+
+// sheetName strips the characters Excel does not allow in worksheet names
+func sheetName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '\\', '/', '?', '*', '[', ']', ':':
+			return -1
+		}
+		return r
+	}, title)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "Responses"
+	}
+	if runes := []rune(name); len(runes) > maxSheetNameLength {
+		name = string(runes[:maxSheetNameLength])
+	}
+	return name
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
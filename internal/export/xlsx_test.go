@@ -0,0 +1,152 @@
+// internal/export/xlsx_test.go
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type xlsxTestSheet struct {
+	Rows []struct {
+		Ref   int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type xlsxTestWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxTestRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// readXLSXParts opens the zip package and decodes every part, failing the
+// test on a part that isn't well-formed XML
+func readXLSXParts(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("open the package: %v", err)
+	}
+	parts := map[string][]byte{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed: %v", f.Name, err)
+			}
+		}
+		parts[f.Name] = content
+	}
+	return parts
+}
+
+func TestXLSX(t *testing.T) {
+	parts := readXLSXParts(t, writeExport(t, FormatXLSX, "DeFi [habits]: 2026/Q1", testColumns, testRows))
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels", "xl/workbook.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Fatalf("the package has no %s", name)
+		}
+	}
+
+	// The workbook names the sheet after the title and links it
+	var workbook xlsxTestWorkbook
+	if err := xml.Unmarshal(parts["xl/workbook.xml"], &workbook); err != nil {
+		t.Fatal(err)
+	}
+	if len(workbook.Sheets) != 1 || workbook.Sheets[0].Name != "DeFi habits 2026Q1" {
+		t.Fatalf("sheets = %+v, want one named after the title", workbook.Sheets)
+	}
+	var rels xlsxTestRelationships
+	if err := xml.Unmarshal(parts["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		t.Fatal(err)
+	}
+	if len(rels.Relationships) != 1 || rels.Relationships[0].ID != workbook.Sheets[0].ID ||
+		rels.Relationships[0].Target != "worksheets/sheet1.xml" {
+		t.Errorf("workbook relationships = %+v, want the sheet's %s to target worksheets/sheet1.xml", rels.Relationships, workbook.Sheets[0].ID)
+	}
+
+	var sheet xlsxTestSheet
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatal(err)
+	}
+	type cell struct{ Ref, Type, Text string }
+	var got [][]cell
+	for i, row := range sheet.Rows {
+		if row.Ref != i+1 {
+			t.Errorf("row %d is numbered %d", i+1, row.Ref)
+		}
+		cells := []cell{}
+		for _, c := range row.Cells {
+			text := c.Value
+			if c.Type == "inlineStr" {
+				text = c.Inline
+			}
+			cells = append(cells, cell{c.Ref, c.Type, text})
+		}
+		got = append(got, cells)
+	}
+
+	// A header row, numbers as numbers, missing values as no cell at all
+	want := [][]cell{
+		{{"A1", "inlineStr", "response_id"}, {"B1", "inlineStr", "q1_usage"}, {"C1", "inlineStr", "q2_rating"}},
+		{{"A2", "", "1"}, {"B2", "inlineStr", "daily"}, {"C2", "", "4.5"}},
+		{{"A3", "", "2"}},
+		{{"A4", "", "1234567890123"}, {"B4", "inlineStr", `Tom & "Jerry" <3> über`}, {"C4", "", "-0.25"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cells =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestColumnLetters(t *testing.T) {
+	for index, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if got := columnLetters(index); got != want {
+			t.Errorf("columnLetters(%d) = %s, want %s", index, got, want)
+		}
+	}
+}
+
+func TestSheetName(t *testing.T) {
+	tests := map[string]string{
+		"Customer feedback": "Customer feedback",
+		" [*?] ":            "Responses",
+		"a/b\\c:d":          "abcd",
+		"A very long survey title that Excel would refuse": "A very long survey title that E",
+		"Ünïcödé survey title with accents, over 31 runes": "Ünïcödé survey title with accen",
+	}
+	for title, want := range tests {
+		if got := sheetName(title); got != want {
+			t.Errorf("sheetName(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// internal/handler/admin_handler.go
+package handler
+
+import (
+	"net/http"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	adminService service.AdminService
+}
+
+func NewAdminHandler(adminService service.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// ListSurveys godoc
+// @Summary List all surveys
+// @Description Browse every survey on the platform with its creator, newest first
+// @Tags admin
+// @Produce json
+// @Param status query string false "draft, published, paused, completed or cancelled"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} SuccessResponse{data=dto.SurveyListResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /admin/surveys [get]
+func (h *AdminHandler) ListSurveys(c *gin.Context) {
+	var req dto.AdminListSurveysRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+	req.Page, req.Limit = normalizePaging(req.Page, req.Limit)
+
+	surveys, err := h.adminService.ListSurveys(&req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    surveys,
+	})
+}
+
+// ListUsers godoc
+// @Summary List all users
+// @Description Browse the platform's users, newest first
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} SuccessResponse{data=dto.AdminUserListResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	var req dto.AdminListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+	req.Page, req.Limit = normalizePaging(req.Page, req.Limit)
+
+	users, err := h.adminService.ListUsers(&req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    users,
+	})
+}
+
+// GetAnalytics godoc
+// @Summary Get platform analytics
+// @Description Count the platform's users, surveys and responses by status, and the rewards funded and paid out
+// @Tags admin
+// @Produce json
+// @Success 200 {object} SuccessResponse{data=dto.PlatformAnalyticsResponse}
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /admin/analytics [get]
+func (h *AdminHandler) GetAnalytics(c *gin.Context) {
+	analytics, err := h.adminService.GetAnalytics()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    analytics,
+	})
+}
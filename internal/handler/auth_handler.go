@@ -0,0 +1,264 @@
+// internal/handler/auth_handler.go
+package handler
+
+import (
+	"net/http"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/middleware"
+	"survey2earn-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	authService service.AuthService
+}
+
+func NewAuthHandler(authService service.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+// Register godoc
+// @Summary Register a wallet
+// @Description Create a user for a wallet. The response message is what the wallet must sign to log in.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RegisterRequest true "Wallet"
+// @Success 201 {object} SuccessResponse{data=dto.RegisterResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /auth/register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req dto.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	result, err := h.authService.Register(&req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Data:    result,
+		Message: "Wallet registered successfully",
+	})
+}
+
+// GetNonce godoc
+// @Summary Get the sign-in message
+// @Description Get the message a registered wallet must sign to log in. It changes after every login.
+// @Tags auth
+// @Produce json
+// @Param wallet_address query string true "Wallet address"
+// @Success 200 {object} SuccessResponse{data=dto.NonceResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /auth/nonce [get]
+func (h *AuthHandler) GetNonce(c *gin.Context) {
+	result, err := h.authService.GetNonce(c.Query("wallet_address"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// Login godoc
+// @Summary Log in with a wallet signature
+// @Description Log in by signing the sign-in message with the wallet (personal_sign)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.LoginRequest true "Signed sign-in message"
+// @Success 200 {object} SuccessResponse{data=dto.LoginResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	result, err := h.authService.Login(&req, clientInfo(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    result,
+		Message: "Logged in successfully",
+	})
+}
+
+// RefreshToken godoc
+// @Summary Refresh tokens
+// @Description Trade a refresh token for a new access and refresh token. Each refresh token can be used once.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} SuccessResponse{data=dto.TokenResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Router /auth/refresh [post]
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	result, err := h.authService.RefreshToken(&req, clientInfo(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Revoke the refresh token of the current session
+// @Tags auth
+// @Produce json
+// @Success 200 {object} SuccessResponse
+// @Failure 401 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	if err := h.authService.Logout(userID, middleware.GetSessionID(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Logged out successfully",
+	})
+}
+
+// GetProfile godoc
+// @Summary Get the user's profile
+// @Tags user
+// @Produce json
+// @Success 200 {object} SuccessResponse{data=dto.UserProfileResponse}
+// @Failure 401 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /user/profile [get]
+func (h *AuthHandler) GetProfile(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	profile, err := h.authService.GetProfile(userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    profile,
+	})
+}
+
+// UpdateProfile godoc
+// @Summary Update the user's profile
+// @Description Update the username, email, bio or profile picture. An empty username or email clears it.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateProfileRequest true "Profile changes"
+// @Success 200 {object} SuccessResponse{data=dto.UserProfileResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /user/profile [put]
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	var req dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	profile, err := h.authService.UpdateProfile(userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    profile,
+		Message: "Profile updated successfully",
+	})
+}
+
+// GetUserStats godoc
+// @Summary Get the user's statistics
+// @Description Activity of the user as a survey creator and as a respondent
+// @Tags user
+// @Produce json
+// @Success 200 {object} SuccessResponse{data=dto.UserStatsResponse}
+// @Failure 401 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /user/stats [get]
+func (h *AuthHandler) GetUserStats(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	stats, err := h.authService.GetUserStats(userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+func clientInfo(c *gin.Context) service.ClientInfo {
+	return service.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
@@ -0,0 +1,224 @@
+// internal/handler/category_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	categoryService service.CategoryService
+}
+
+func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+// ListCategories godoc
+// @Summary List survey categories
+// @Description List the active categories as a tree, with how many public surveys each holds
+// @Tags categories
+// @Produce json
+// @Param lang query string false "Locale of the names, defaults to Accept-Language"
+// @Param status query string false "Only count surveys with this status"
+// @Success 200 {object} SuccessResponse{data=dto.CategoryListResponse}
+// @Failure 500 {object} apperror.Problem
+// @Router /categories [get]
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	h.listCategories(c, false)
+}
+
+// ListAllCategories godoc
+// @Summary List all survey categories
+// @Description List the categories as a tree, inactive ones included
+// @Tags admin
+// @Produce json
+// @Param lang query string false "Locale of the names, defaults to Accept-Language"
+// @Param status query string false "Only count surveys with this status"
+// @Success 200 {object} SuccessResponse{data=dto.CategoryListResponse}
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /admin/categories [get]
+func (h *CategoryHandler) ListAllCategories(c *gin.Context) {
+	h.listCategories(c, true)
+}
+
+func (h *CategoryHandler) listCategories(c *gin.Context, includeInactive bool) {
+	var req dto.ListCategoriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+	req.IncludeInactive = includeInactive
+
+	categories, err := h.categoryService.ListCategories(&req, requestLocale(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    categories,
+	})
+}
+
+// GetCategory godoc
+// @Summary Get a survey category
+// @Description Get an active category with its subcategories
+// @Tags categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Param lang query string false "Locale of the names, defaults to Accept-Language"
+// @Success 200 {object} SuccessResponse{data=dto.CategoryResponse}
+// @Failure 404 {object} apperror.Problem
+// @Router /categories/{slug} [get]
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	category, err := h.categoryService.GetCategory(c.Param("slug"), requestLocale(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    category,
+	})
+}
+
+// CreateCategory godoc
+// @Summary Create a survey category
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param category body dto.CreateCategoryRequest true "Category data"
+// @Success 201 {object} SuccessResponse{data=dto.CategoryResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /admin/categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req dto.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(&req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Data:    category,
+		Message: "Category created successfully",
+	})
+}
+
+// UpdateCategory godoc
+// @Summary Update a survey category
+// @Description Update the names, icon, parent, order or active flag of a category. The slug can't change.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param category body dto.UpdateCategoryRequest true "Category data"
+// @Success 200 {object} SuccessResponse{data=dto.CategoryResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /admin/categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	categoryID, ok := parseCategoryID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(categoryID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    category,
+		Message: "Category updated successfully",
+	})
+}
+
+// DeleteCategory godoc
+// @Summary Delete a survey category
+// @Description Delete a category without subcategories that no survey, template or bank question uses
+// @Tags admin
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /admin/categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	categoryID, ok := parseCategoryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(categoryID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Category deleted successfully",
+	})
+}
+
+func parseCategoryID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid category ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// requestLocale picks the locale of category names from the lang query
+// parameter, then the preferred language of Accept-Language
+func requestLocale(c *gin.Context) string {
+	locale := c.Query("lang")
+	if locale == "" {
+		preferred, _, _ := strings.Cut(c.GetHeader("Accept-Language"), ",")
+		locale, _, _ = strings.Cut(preferred, ";")
+	}
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if locale == "" || locale == "*" {
+		return models.DefaultLocale
+	}
+	return locale
+}
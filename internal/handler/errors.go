@@ -0,0 +1,134 @@
+// internal/handler/errors.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// errUnauthenticated is reported when a protected handler runs without a user
+var errUnauthenticated = apperror.New(apperror.Unauthorized, "User authentication required")
+
+func init() {
+	// Report invalid fields by the names clients send, not the Go names
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+// requestFieldName is the name of a request field in a query or in a JSON body
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"form", "json"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// invalidRequest describes a request that failed to bind, with a field
+// error for every invalid field when the fields are known
+func invalidRequest(err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]apperror.FieldError, len(validationErrs))
+		for i, fieldErr := range validationErrs {
+			fields[i] = apperror.FieldError{
+				Field:   requestFieldPath(fieldErr.Namespace()),
+				Code:    fieldErr.Tag(),
+				Message: validationMessage(fieldErr),
+			}
+		}
+		return apperror.Invalid("One or more fields are invalid", fields...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return apperror.Invalid("One or more fields are invalid", apperror.FieldError{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Message: "must be " + jsonTypeName(typeErr.Type),
+		})
+	}
+
+	// Decoder errors name Go types, so they are only kept for the logs
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return apperror.Wrap(err, apperror.InvalidRequest, "The request body is not valid JSON")
+	}
+	return apperror.Wrap(err, apperror.InvalidRequest, "The request could not be read")
+}
+
+// jsonTypeName names the JSON type a Go type is decoded from
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Pointer:
+		return jsonTypeName(t.Elem())
+	}
+	return "an object"
+}
+
+// requestFieldPath drops the request type from a validator namespace,
+// "CreateSurveyRequest.questions[0].title" giving "questions[0].title"
+func requestFieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+func validationMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	// min and max bound the length of strings and lists
+	atLeast, atMost, unit := "must be at least ", "must be at most ", ""
+	switch fieldErr.Kind() {
+	case reflect.String:
+		atLeast, atMost, unit = "must have at least ", "must have at most ", " characters"
+	case reflect.Slice, reflect.Map:
+		atLeast, atMost, unit = "must have at least ", "must have at most ", " items"
+	}
+
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return atLeast + param + unit
+	case "max", "lte":
+		return atMost + param + unit
+	case "gt":
+		return "must be greater than " + param
+	case "lt":
+		return "must be less than " + param
+	case "oneof":
+		return "must be one of " + param
+	case "url":
+		return "must be a URL"
+	case "email":
+		return "must be an email address"
+	}
+	if param != "" {
+		return "must satisfy " + fieldErr.Tag() + "=" + param
+	}
+	return "must satisfy " + fieldErr.Tag()
+}
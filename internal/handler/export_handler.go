@@ -0,0 +1,169 @@
+// internal/handler/export_handler.go
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/export"
+	"survey2earn-backend/internal/middleware"
+	"survey2earn-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportResponses godoc
+// @Summary Export survey responses
+// @Description Export all responses of a survey with one column per question. Small surveys are streamed directly; large ones (or async=true) start a background job that can be polled and downloaded when ready.
+// @Tags exports
+// @Produce csv,xlsx,sav,octet-stream
+// @Produce json
+// @Param id path int true "Survey ID"
+// @Param format query string false "csv, xlsx, sav or parquet" default(csv)
+// @Param async query bool false "Always export in the background"
+// @Success 200 {file} file
+// @Success 202 {object} SuccessResponse{data=dto.ExportJobResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/{id}/responses/export [get]
+func (h *ExportHandler) ExportResponses(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	surveyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid survey ID"))
+		return
+	}
+
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if !export.IsValidFormat(format) {
+		c.Error(apperror.New(apperror.InvalidFormat, "Format must be one of "+strings.Join(export.Formats, ", ")))
+		return
+	}
+	async := c.Query("async") == "true"
+
+	// Headers can only be set until the first byte of the file is written
+	streaming := false
+	open := func(fileName string) io.Writer {
+		streaming = true
+		c.Header("Content-Type", export.ContentType(format))
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+		c.Status(http.StatusOK)
+		return c.Writer
+	}
+
+	job, err := h.exportService.ExportResponses(userID, uint(surveyID), format, async, open)
+	if err != nil {
+		if streaming {
+			logrus.WithError(err).Error("Response export stream failed")
+			c.Abort()
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	if job != nil {
+		c.JSON(http.StatusAccepted, SuccessResponse{
+			Success: true,
+			Message: "Export started",
+			Data:    job,
+		})
+	}
+}
+
+// GetExportJob godoc
+// @Summary Get an export job
+// @Description Get the status of a background response export
+// @Tags exports
+// @Produce json
+// @Param id path int true "Export job ID"
+// @Success 200 {object} SuccessResponse{data=dto.ExportJobResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /exports/{id} [get]
+func (h *ExportHandler) GetExportJob(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid export job ID"))
+		return
+	}
+
+	job, err := h.exportService.GetExportJob(userID, uint(jobID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    job,
+	})
+}
+
+// DownloadExport godoc
+// @Summary Download an export
+// @Description Download the file produced by a completed export job
+// @Tags exports
+// @Produce csv,xlsx,sav,octet-stream
+// @Param id path int true "Export job ID"
+// @Success 200 {file} file
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /exports/{id}/download [get]
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid export job ID"))
+		return
+	}
+
+	job, err := h.exportService.GetExportFile(userID, uint(jobID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", export.ContentType(job.Format))
+	c.FileAttachment(job.FilePath, job.FileName)
+}
@@ -0,0 +1,707 @@
+// internal/handler/response_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/events"
+	"survey2earn-backend/internal/service"
+	"survey2earn-backend/internal/middleware"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResponseHandler struct {
+	responseService service.ResponseService
+}
+
+func NewResponseHandler(responseService service.ResponseService) *ResponseHandler {
+	return &ResponseHandler{
+		responseService: responseService,
+	}
+}
+
+// StartSurvey godoc
+// @Summary Start taking a survey
+// @Description Start a new survey response session
+// @Tags responses
+// @Accept json
+// @Produce json
+// @Param survey body dto.StartSurveyRequest true "Start survey data"
+// @Param Idempotency-Key header string false "Key that makes retries of the request return the first response"
+// @Success 201 {object} SuccessResponse{data=dto.ResponseStartResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Failure 422 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /responses/start [post]
+func (h *ResponseHandler) StartSurvey(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	var req dto.StartSurveyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	// Get IP address and User Agent from request
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.GetHeader("User-Agent")
+
+	response, err := h.responseService.StartSurvey(userID, req.SurveyID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Data:    response,
+		Message: "Survey started successfully",
+	})
+}
+
+// SubmitAnswers godoc
+// @Summary Submit answers for a survey
+// @Description Submit one or more answers for a survey response
+// @Tags responses
+// @Accept json
+// @Produce json
+// @Param id path int true "Response ID"
+// @Param answers body []dto.SubmitAnswerRequest true "Answers data"
+// @Success 200 {object} SuccessResponse "Answers saved"
+// @Success 200 {object} SuccessResponse{data=dto.ScreenOutResponse} "Screened out by a quota"
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Failure 410 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /responses/{id}/answers [post]
+func (h *ResponseHandler) SubmitAnswers(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	responseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid response ID"))
+		return
+	}
+
+	var answers []dto.SubmitAnswerRequest
+	if err := c.ShouldBindJSON(&answers); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	err = h.responseService.SubmitAnswers(userID, uint(responseID), answers)
+	if err != nil {
+		if h.respondScreenedOut(c, err) {
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Answers submitted successfully",
+	})
+}
+
+// CompleteSurvey godoc
+// @Summary Complete a survey
+// @Description Complete a survey and submit final answers. The duration is measured by the server.
+// @Tags responses
+// @Accept json
+// @Produce json
+// @Param complete body dto.CompleteSurveyRequest true "Complete survey data"
+// @Param Idempotency-Key header string false "Key that makes retries of the request return the first response"
+// @Success 200 {object} SuccessResponse{data=dto.CompletionResponse} "Survey completed"
+// @Success 200 {object} SuccessResponse{data=dto.ScreenOutResponse} "Screened out by a quota"
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Failure 410 {object} apperror.Problem
+// @Failure 422 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /responses/complete [post]
+func (h *ResponseHandler) CompleteSurvey(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	var req dto.CompleteSurveyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	completion, err := h.responseService.CompleteSurvey(userID, &req)
+	if err != nil {
+		if h.respondScreenedOut(c, err) {
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    completion,
+		Message: "Survey completed successfully",
+	})
+}
+
+// GetResponse godoc
+// @Summary Get a survey response
+// @Description Get details of a specific survey response
+// @Tags responses
+// @Accept json
+// @Produce json
+// @Param id path int true "Response ID"
+// @Success 200 {object} SuccessResponse{data=dto.SurveyResponseResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /responses/{id} [get]
+func (h *ResponseHandler) GetResponse(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	responseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid response ID"))
+		return
+	}
+
+	response, err := h.responseService.GetResponse(userID, uint(responseID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetUserResponses godoc
+// @Summary Get user's survey responses
+// @Description Get list of survey responses by the authenticated user
+// @Tags responses
+// @Accept json
+// @Produce json
+// @Param status query string false "Response status filter"
+// @Param survey_id query int false "Survey ID filter"
+// @Param start_date query string false "Start date filter (YYYY-MM-DD)"
+// @Param end_date query string false "End date filter (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} SuccessResponse{data=dto.ResponseListResponse}
+// @Failure 401 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /responses [get]
+func (h *ResponseHandler) GetUserResponses(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	var req dto.ListResponsesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	// Set defaults
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	responses, err := h.responseService.GetUserResponses(userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    responses,
+	})
+}
+
+// GetSurveyResponses godoc
+// @Summary List the responses of a survey
+// @Description List the responses of a survey owned by the authenticated user. Respondent identity is omitted for anonymous surveys.
+// @Tags responses
+// @Accept json
+// @Produce json
+// @Param id path int true "Survey ID"
+// @Param status query string false "Response status filter"
+// @Param is_valid query bool false "Validity filter"
+// @Param min_quality query number false "Minimum quality score"
+// @Param max_quality query number false "Maximum quality score"
+// @Param start_date query string false "Started on or after (YYYY-MM-DD or RFC 3339)"
+// @Param end_date query string false "Started on or before (YYYY-MM-DD or RFC 3339)"
+// @Param answer query []string false "Answer filter <question order>:<value>, repeatable" collectionFormat(multi)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} SuccessResponse{data=dto.CreatorResponseListResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/{id}/responses [get]
+func (h *ResponseHandler) GetSurveyResponses(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	surveyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid survey ID"))
+		return
+	}
+
+	var req dto.ListSurveyResponsesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	// Set defaults
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	responses, err := h.responseService.GetSurveyResponses(userID, uint(surveyID), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    responses,
+	})
+}
+
+// GetSurveyResponse godoc
+// @Summary Get a response to a survey
+// @Description Get a single response to a survey owned by the authenticated user
+// @Tags responses
+// @Accept json
+// @Produce json
+// @Param id path int true "Survey ID"
+// @Param response_id path int true "Response ID"
+// @Success 200 {object} SuccessResponse{data=dto.CreatorResponseResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/{id}/responses/{response_id} [get]
+func (h *ResponseHandler) GetSurveyResponse(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	surveyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid survey ID"))
+		return
+	}
+
+	responseID, err := strconv.ParseUint(c.Param("response_id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid response ID"))
+		return
+	}
+
+	response, err := h.responseService.GetSurveyResponse(userID, uint(surveyID), uint(responseID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// StreamSurveyEvents godoc
+// @Summary Stream live survey activity
+// @Description Server-Sent Events stream of a survey owned by the authenticated user: response.started, response.completed, response.abandoned, response.screened_out and pool.updated events. A lagged event reports events dropped because the client read too slowly; the client should then refetch what it displays.
+// @Tags responses
+// @Produce text/event-stream
+// @Param id path int true "Survey ID"
+// @Success 200 {object} events.Event
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/{id}/events [get]
+func (h *ResponseHandler) StreamSurveyEvents(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	surveyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid survey ID"))
+		return
+	}
+
+	subscription, err := h.responseService.SubscribeSurveyEvents(userID, uint(surveyID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer subscription.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // disable proxy buffering
+	c.Status(http.StatusOK)
+
+	// Tell the client to wait a few seconds before reconnecting
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetry.Milliseconds())
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return false
+			}
+			writeLagged(w, subscription)
+			return writeSSE(w, strconv.FormatUint(event.ID, 10), string(event.Type), event) == nil
+		case <-heartbeat.C:
+			writeLagged(w, subscription)
+			// Comments keep idle connections from being closed by proxies
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		}
+	})
+}
+
+// GetResponseProgress godoc
+// @Summary Get survey response progress
+// @Description Get progress information for an ongoing survey response
+// @Tags responses
+// @Accept json
+// @Produce json
+// @Param id path int true "Response ID"
+// @Success 200 {object} SuccessResponse{data=dto.SurveyProgressResponse}
+// @Header 200 {string} ETag "Version of the response, to send back in If-Match"
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /responses/{id}/progress [get]
+func (h *ResponseHandler) GetResponseProgress(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	responseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid response ID"))
+		return
+	}
+
+	progress, err := h.responseService.GetResponseProgress(userID, uint(responseID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", responseETag(progress.Version))
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    progress,
+	})
+}
+
+// ResumeResponse godoc
+// @Summary Resume a survey response
+// @Description Get the answers so far and the next question of a response in progress, to continue it on any device. The ETag header holds the response version for If-Match on the next answer.
+// @Tags responses
+// @Produce json
+// @Param id path int true "Response ID"
+// @Success 200 {object} SuccessResponse{data=dto.ResumeResponse}
+// @Header 200 {string} ETag "Version of the response, to send back in If-Match"
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 410 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /responses/{id}/resume [get]
+func (h *ResponseHandler) ResumeResponse(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	responseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid response ID"))
+		return
+	}
+
+	resume, err := h.responseService.ResumeResponse(userID, uint(responseID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", responseETag(resume.Version))
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    resume,
+	})
+}
+
+// UpdateAnswer godoc
+// @Summary Update a specific answer
+// @Description Autosave an answer for a specific question in a survey response. With If-Match, or a version in the body, the answer is only saved if the response is still at that version.
+// @Tags responses
+// @Accept json
+// @Produce json
+// @Param response_id path int true "Response ID"
+// @Param question_id path int true "Question ID"
+// @Param If-Match header string false "ETag of the response version the answer was made on"
+// @Param answer body dto.UpdateAnswerRequest true "Updated answer data"
+// @Success 200 {object} SuccessResponse{data=dto.AnswerSavedResponse} "Answer saved"
+// @Success 200 {object} SuccessResponse{data=dto.ScreenOutResponse} "Screened out by a quota"
+// @Header 200 {string} ETag "Version of the response, to send back in If-Match"
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Failure 410 {object} apperror.Problem
+// @Failure 412 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /responses/{response_id}/questions/{question_id} [put]
+func (h *ResponseHandler) UpdateAnswer(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	responseID, err := strconv.ParseUint(c.Param("response_id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid response ID"))
+		return
+	}
+
+	questionID, err := strconv.ParseUint(c.Param("question_id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid question ID"))
+		return
+	}
+
+	var req dto.UpdateAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		version, ok := parseResponseETag(ifMatch)
+		if !ok {
+			c.Error(apperror.New(apperror.InvalidRequest, "If-Match must be the ETag of the response"))
+			return
+		}
+		req.Version = version
+	}
+
+	saved, err := h.responseService.UpdateAnswer(userID, uint(responseID), uint(questionID), &req)
+	if err != nil {
+		if h.respondScreenedOut(c, err) {
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", responseETag(saved.Version))
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    saved,
+		Message: "Answer updated successfully",
+	})
+}
+
+// AbandonSurvey godoc
+// @Summary Abandon a survey
+// @Description Mark a survey response as abandoned
+// @Tags responses
+// @Accept json
+// @Produce json
+// @Param id path int true "Response ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /responses/{id}/abandon [post]
+func (h *ResponseHandler) AbandonSurvey(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	responseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid response ID"))
+		return
+	}
+
+	err = h.responseService.AbandonSurvey(userID, uint(responseID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Survey response abandoned",
+	})
+}
+
+// respondScreenedOut replies to a respondent screened out by a full quota.
+// Being screened out is an expected outcome, so it is not reported as an error.
+func (h *ResponseHandler) respondScreenedOut(c *gin.Context, err error) bool {
+	var screenedOut *service.ScreenedOutError
+	if !errors.As(err, &screenedOut) {
+		return false
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data: dto.ScreenOutResponse{
+			ResponseID: screenedOut.ResponseID,
+			Status:     "screened_out",
+			Quota:      screenedOut.Quota,
+			Message:    "Thank you for your time. This survey has enough responses from your group.",
+		},
+		Message: "Response screened out",
+	})
+	return true
+}
+
+// responseETag is the entity tag of a response at a version
+func responseETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// parseResponseETag reads the version out of an If-Match value. "*" matches
+// any version and gives 0.
+func parseResponseETag(value string) (int, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+	if value == "*" {
+		return 0, true
+	}
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return 0, false
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+const (
+	sseHeartbeatInterval = 15 * time.Second
+	sseRetry             = 3 * time.Second
+)
+
+// writeSSE writes a Server-Sent Event with a JSON payload
+func writeSSE(w io.Writer, id, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
+
+// writeLagged reports events dropped for a subscriber that fell behind
+func writeLagged(w io.Writer, subscription *events.Subscription) {
+	if dropped := subscription.TakeDropped(); dropped > 0 {
+		writeSSE(w, "", "lagged", gin.H{"dropped": dropped})
+	}
+}
@@ -0,0 +1,107 @@
+// internal/handler/reward_handler.go
+package handler
+
+import (
+	"net/http"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/middleware"
+	"survey2earn-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errWithdrawalsNotImplemented is reported by the route reserved for
+// withdrawals, which aren't available yet
+var errWithdrawalsNotImplemented = apperror.New(apperror.NotImplemented, "Reward withdrawals are not available yet")
+
+type RewardHandler struct {
+	rewardService service.RewardService
+}
+
+func NewRewardHandler(rewardService service.RewardService) *RewardHandler {
+	return &RewardHandler{
+		rewardService: rewardService,
+	}
+}
+
+// GetBalance godoc
+// @Summary Get the reward balance
+// @Description Get the authenticated user's reward balance, summed from their reward transactions. Withdrawals in progress are held back from the available balance.
+// @Tags rewards
+// @Produce json
+// @Success 200 {object} SuccessResponse{data=dto.RewardBalanceResponse}
+// @Failure 401 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /rewards/balance [get]
+func (h *RewardHandler) GetBalance(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	balance, err := h.rewardService.GetBalance(userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    balance,
+	})
+}
+
+// GetTransactions godoc
+// @Summary List reward transactions
+// @Description Browse the authenticated user's reward history, newest first
+// @Tags rewards
+// @Produce json
+// @Param type query string false "reward, withdrawal, refund or fee"
+// @Param status query string false "pending, processing, completed, failed or cancelled"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} SuccessResponse{data=dto.RewardTransactionListResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /rewards/transactions [get]
+func (h *RewardHandler) GetTransactions(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	var req dto.ListRewardTransactionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+	req.Page, req.Limit = normalizePaging(req.Page, req.Limit)
+
+	transactions, err := h.rewardService.GetTransactions(userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    transactions,
+	})
+}
+
+// Withdraw godoc
+// @Summary Withdraw rewards
+// @Description Reserved for withdrawing rewards to the user's wallet, which isn't available yet
+// @Tags rewards
+// @Produce json
+// @Failure 401 {object} apperror.Problem
+// @Failure 501 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /rewards/withdraw [post]
+func (h *RewardHandler) Withdraw(c *gin.Context) {
+	c.Error(errWithdrawalsNotImplemented)
+}
@@ -0,0 +1,624 @@
+// internal/handler/survey_handler.go
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/service"
+	"survey2earn-backend/internal/middleware"
+	"survey2earn-backend/internal/surveydef"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SurveyHandler struct {
+	surveyService service.SurveyService
+}
+
+func NewSurveyHandler(surveyService service.SurveyService) *SurveyHandler {
+	return &SurveyHandler{
+		surveyService: surveyService,
+	}
+}
+
+// CreateSurvey godoc
+// @Summary Create a new survey
+// @Description Create a new survey as a draft
+// @Tags surveys
+// @Accept json
+// @Produce json
+// @Param survey body dto.CreateSurveyRequest true "Survey data"
+// @Success 201 {object} SuccessResponse{data=dto.SurveyResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys [post]
+func (h *SurveyHandler) CreateSurvey(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	var req dto.CreateSurveyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	survey, err := h.surveyService.CreateSurvey(userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Data:    survey,
+		Message: "Survey created successfully",
+	})
+}
+
+// UpdateSurvey godoc
+// @Summary Update a survey
+// @Description Update a draft survey
+// @Tags surveys
+// @Accept json
+// @Produce json
+// @Param id path int true "Survey ID"
+// @Param survey body dto.UpdateSurveyRequest true "Survey update data"
+// @Success 200 {object} SuccessResponse{data=dto.SurveyResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/{id} [put]
+func (h *SurveyHandler) UpdateSurvey(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	surveyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid survey ID"))
+		return
+	}
+
+	var req dto.UpdateSurveyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	survey, err := h.surveyService.UpdateSurvey(userID, uint(surveyID), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    survey,
+		Message: "Survey updated successfully",
+	})
+}
+
+// PublishSurvey godoc
+// @Summary Publish a survey
+// @Description Publish a draft survey to make it available for responses
+// @Tags surveys
+// @Accept json
+// @Produce json
+// @Param id path int true "Survey ID"
+// @Param publish body dto.PublishSurveyRequest true "Publish data"
+// @Success 200 {object} SuccessResponse{data=dto.SurveyResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/{id}/publish [post]
+func (h *SurveyHandler) PublishSurvey(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	surveyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid survey ID"))
+		return
+	}
+
+	var req dto.PublishSurveyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	survey, err := h.surveyService.PublishSurvey(userID, uint(surveyID), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    survey,
+		Message: "Survey published successfully",
+	})
+}
+
+// GetSurvey godoc
+// @Summary Get a survey
+// @Description Get survey details by ID
+// @Tags surveys
+// @Accept json
+// @Produce json
+// @Param id path int true "Survey ID"
+// @Success 200 {object} SuccessResponse{data=dto.SurveyResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Router /surveys/{id} [get]
+func (h *SurveyHandler) GetSurvey(c *gin.Context) {
+	surveyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid survey ID"))
+		return
+	}
+
+	survey, err := h.surveyService.GetSurvey(uint(surveyID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    survey,
+	})
+}
+
+// GetUserSurveys godoc
+// @Summary Get user's surveys
+// @Description Get surveys created by the authenticated user
+// @Tags surveys
+// @Accept json
+// @Produce json
+// @Param status query string false "Survey status filter"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} SuccessResponse{data=dto.SurveyListResponse}
+// @Failure 401 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/my [get]
+func (h *SurveyHandler) GetUserSurveys(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	status := c.Query("status")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	surveys, err := h.surveyService.GetUserSurveys(userID, status, page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    surveys,
+	})
+}
+
+// GetPublicSurveys godoc
+// @Summary Get public surveys
+// @Description Search, filter and sort the public surveys available for participation. Follow next_cursor for stable paging.
+// @Tags surveys
+// @Accept json
+// @Produce json
+// @Param q query string false "Keywords over titles and descriptions; quoted phrases, -excluded words and or are supported"
+// @Param lang query string false "Language of q, defaults to Accept-Language"
+// @Param category query string false "Category slug, subcategories included"
+// @Param status query string false "Status filter"
+// @Param min_reward query number false "Minimum reward per response"
+// @Param max_reward query number false "Maximum reward per response"
+// @Param min_duration query int false "Minimum estimated minutes"
+// @Param max_duration query int false "Maximum estimated minutes"
+// @Param min_slots query int false "Minimum responses still open"
+// @Param ends_after query string false "Only surveys ending after this date, or without an end date (YYYY-MM-DD or RFC 3339)"
+// @Param ends_before query string false "Only surveys ending on or before this date (YYYY-MM-DD or RFC 3339)"
+// @Param sort query string false "relevance, newest, ending_soon, reward_per_minute or popular" default(newest)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param page query int false "Page number, ignored with a cursor" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} SuccessResponse{data=dto.SurveyListResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Router /surveys [get]
+func (h *SurveyHandler) GetPublicSurveys(c *gin.Context) {
+	var req dto.PublicSurveysRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+	if req.Lang == "" {
+		req.Lang = requestLocale(c)
+	}
+	req.Page, req.Limit = normalizePaging(req.Page, req.Limit)
+
+	surveys, err := h.surveyService.GetPublicSurveys(&req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    surveys,
+	})
+}
+
+// GetRecommendedSurveys godoc
+// @Summary Get recommended surveys
+// @Description Rank the open surveys the user can still take by their past categories, completion and quality record, reward per minute and remaining capacity, explaining each score
+// @Tags surveys
+// @Produce json
+// @Param limit query int false "Number of surveys" default(10)
+// @Param lang query string false "Respondent language matched against survey quotas, defaults to Accept-Language"
+// @Param timezone query string false "Respondent timezone matched against survey quotas"
+// @Success 200 {object} SuccessResponse{data=dto.RecommendationListResponse}
+// @Failure 401 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/recommended [get]
+func (h *SurveyHandler) GetRecommendedSurveys(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	var req dto.RecommendedSurveysRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+	if req.Language == "" {
+		req.Language = requestLocale(c)
+	}
+	_, req.Limit = normalizePaging(1, req.Limit)
+
+	recommendations, err := h.surveyService.GetRecommendedSurveys(userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    recommendations,
+	})
+}
+
+// DeleteSurvey godoc
+// @Summary Delete a survey
+// @Description Delete a draft survey
+// @Tags surveys
+// @Accept json
+// @Produce json
+// @Param id path int true "Survey ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/{id} [delete]
+func (h *SurveyHandler) DeleteSurvey(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	surveyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid survey ID"))
+		return
+	}
+
+	err = h.surveyService.DeleteSurvey(userID, uint(surveyID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Survey deleted successfully",
+	})
+}
+
+// GetSurveyAnalytics godoc
+// @Summary Get survey analytics
+// @Description Get response statistics and per-question answer distributions for a survey
+// @Tags surveys
+// @Accept json
+// @Produce json
+// @Param id path int true "Survey ID"
+// @Success 200 {object} SuccessResponse{data=dto.SurveyAnalyticsResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/{id}/analytics [get]
+func (h *SurveyHandler) GetSurveyAnalytics(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	surveyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid survey ID"))
+		return
+	}
+
+	analytics, err := h.surveyService.GetSurveyAnalytics(userID, uint(surveyID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    analytics,
+	})
+}
+
+// GetCrosstab godoc
+// @Summary Cross-tabulate two questions
+// @Description Count completed responses by their answers to a row and a column question, with row/column percentages, confidence intervals and a chi-square test. Accepts the same filters as the survey response list.
+// @Tags surveys
+// @Accept json
+// @Produce json
+// @Param id path int true "Survey ID"
+// @Param row query int true "Order of the row question"
+// @Param column query int true "Order of the column question"
+// @Param confidence query number false "Confidence level" default(0.95)
+// @Param status query string false "Response status filter" default(completed)
+// @Param is_valid query bool false "Validity filter"
+// @Param min_quality query number false "Minimum quality score"
+// @Param max_quality query number false "Maximum quality score"
+// @Param start_date query string false "Started on or after (YYYY-MM-DD or RFC 3339)"
+// @Param end_date query string false "Started on or before (YYYY-MM-DD or RFC 3339)"
+// @Param answer query []string false "Answer filter <question order>:<value>, repeatable" collectionFormat(multi)
+// @Success 200 {object} SuccessResponse{data=dto.CrosstabResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/{id}/analytics/crosstab [get]
+func (h *SurveyHandler) GetCrosstab(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	surveyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid survey ID"))
+		return
+	}
+
+	var req dto.CrosstabRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	crosstab, err := h.surveyService.GetCrosstab(userID, uint(surveyID), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    crosstab,
+	})
+}
+
+// CloneSurvey godoc
+// @Summary Clone a survey
+// @Description Copy an existing survey's questions, quotas and settings into a new draft
+// @Tags surveys
+// @Accept json
+// @Produce json
+// @Param id path int true "Survey ID"
+// @Param clone body dto.CloneSurveyRequest false "Clone options"
+// @Success 201 {object} SuccessResponse{data=dto.SurveyResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/{id}/clone [post]
+func (h *SurveyHandler) CloneSurvey(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	surveyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid survey ID"))
+		return
+	}
+
+	// The body is optional; an empty body clones with the default title
+	var req dto.CloneSurveyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(invalidRequest(err))
+			return
+		}
+	}
+
+	survey, err := h.surveyService.CloneSurvey(userID, uint(surveyID), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Data:    survey,
+		Message: "Survey cloned successfully",
+	})
+}
+
+// ExportSurvey godoc
+// @Summary Export a survey definition
+// @Description Download a survey with its questions, logic, quotas and settings in the portable definition format
+// @Tags surveys
+// @Produce json
+// @Produce application/yaml
+// @Param id path int true "Survey ID"
+// @Param format query string false "json or yaml" default(json)
+// @Success 200 {object} surveydef.SurveyDefinition
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/{id}/export [get]
+func (h *SurveyHandler) ExportSurvey(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	surveyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid survey ID"))
+		return
+	}
+
+	format := c.DefaultQuery("format", surveydef.FormatJSON)
+	if format != surveydef.FormatJSON && format != surveydef.FormatYAML {
+		c.Error(apperror.New(apperror.InvalidFormat, "Format must be json or yaml"))
+		return
+	}
+
+	definition, err := h.surveyService.ExportSurvey(userID, uint(surveyID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	data, err := surveydef.Encode(definition, format)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=survey-%d.%s", surveyID, format))
+	c.Data(http.StatusOK, surveydef.ContentType(format), data)
+}
+
+// ImportSurvey godoc
+// @Summary Import a survey definition
+// @Description Validate a survey definition (JSON or YAML) and create a draft survey from it
+// @Tags surveys
+// @Accept json
+// @Accept application/yaml
+// @Produce json
+// @Param definition body surveydef.SurveyDefinition true "Survey definition"
+// @Param format query string false "json or yaml; defaults to the Content-Type"
+// @Success 201 {object} SuccessResponse{data=dto.SurveyResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/import [post]
+func (h *SurveyHandler) ImportSurvey(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	data, err := c.GetRawData()
+	if err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = surveydef.FormatFromContentType(c.ContentType())
+	}
+
+	definition, err := surveydef.Decode(data, format)
+	if err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	survey, err := h.surveyService.ImportSurvey(userID, definition)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Data:    survey,
+		Message: "Survey imported as draft",
+	})
+}
+
+// SuccessResponse is the envelope of every successful JSON response. Errors
+// are answered as an apperror.Problem instead.
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
\ No newline at end of file
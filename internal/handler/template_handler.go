@@ -0,0 +1,564 @@
+// internal/handler/template_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/middleware"
+	"survey2earn-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TemplateHandler struct {
+	templateService service.TemplateService
+}
+
+func NewTemplateHandler(templateService service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// ListTemplates godoc
+// @Summary List survey templates
+// @Description List platform templates and the user's personal templates
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param category query string false "Category filter"
+// @Param scope query string false "all, platform or personal" default(all)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} SuccessResponse{data=dto.TemplateListResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /templates [get]
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	var req dto.ListTemplatesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+	req.Page, req.Limit = normalizePaging(req.Page, req.Limit)
+
+	templates, err := h.templateService.ListTemplates(userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    templates,
+	})
+}
+
+// GetTemplate godoc
+// @Summary Get a survey template
+// @Description Get a template with its question definitions
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} SuccessResponse{data=dto.TemplateResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /templates/{id} [get]
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(userID, templateID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    template,
+	})
+}
+
+// CreateTemplate godoc
+// @Summary Create a personal template
+// @Description Save a reusable set of questions as a personal template
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param template body dto.CreateTemplateRequest true "Template data"
+// @Success 201 {object} SuccessResponse{data=dto.TemplateResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /templates [post]
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	var req dto.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Data:    template,
+		Message: "Template created successfully",
+	})
+}
+
+// UpdateTemplate godoc
+// @Summary Update a personal template
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param template body dto.UpdateTemplateRequest true "Template update data"
+// @Success 200 {object} SuccessResponse{data=dto.TemplateResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /templates/{id} [put]
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(userID, templateID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    template,
+		Message: "Template updated successfully",
+	})
+}
+
+// DeleteTemplate godoc
+// @Summary Delete a personal template
+// @Tags templates
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(userID, templateID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Template deleted successfully",
+	})
+}
+
+// CreateSurveyFromTemplate godoc
+// @Summary Create a survey from a template
+// @Description Instantiate a template into a new draft survey
+// @Tags surveys
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param survey body dto.CreateFromTemplateRequest true "Survey settings"
+// @Success 201 {object} SuccessResponse{data=dto.SurveyResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /surveys/from-template/{id} [post]
+func (h *TemplateHandler) CreateSurveyFromTemplate(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateFromTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	survey, err := h.templateService.CreateSurveyFromTemplate(userID, templateID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Data:    survey,
+		Message: "Survey created from template",
+	})
+}
+
+// ListBankQuestions godoc
+// @Summary List question bank entries
+// @Description List platform and personal question bank entries
+// @Tags question-bank
+// @Accept json
+// @Produce json
+// @Param category query string false "Category filter"
+// @Param type query string false "Question type filter"
+// @Param scope query string false "all, platform or personal" default(all)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} SuccessResponse{data=dto.BankQuestionListResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /question-bank [get]
+func (h *TemplateHandler) ListBankQuestions(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	var req dto.ListBankQuestionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+	req.Page, req.Limit = normalizePaging(req.Page, req.Limit)
+
+	questions, err := h.templateService.ListBankQuestions(userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    questions,
+	})
+}
+
+// CreateBankQuestion godoc
+// @Summary Add a question to the personal question bank
+// @Tags question-bank
+// @Accept json
+// @Produce json
+// @Param question body dto.CreateBankQuestionRequest true "Question data"
+// @Success 201 {object} SuccessResponse{data=dto.BankQuestionResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /question-bank [post]
+func (h *TemplateHandler) CreateBankQuestion(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	var req dto.CreateBankQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	question, err := h.templateService.CreateBankQuestion(userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Data:    question,
+		Message: "Question added to question bank",
+	})
+}
+
+// DeleteBankQuestion godoc
+// @Summary Remove a question from the personal question bank
+// @Tags question-bank
+// @Produce json
+// @Param id path int true "Bank question ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /question-bank/{id} [delete]
+func (h *TemplateHandler) DeleteBankQuestion(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	questionID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.templateService.DeleteBankQuestion(userID, questionID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Question removed from question bank",
+	})
+}
+
+// CreatePlatformTemplate godoc
+// @Summary Create a curated platform template
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param template body dto.CreateTemplateRequest true "Template data"
+// @Success 201 {object} SuccessResponse{data=dto.TemplateResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /admin/templates [post]
+func (h *TemplateHandler) CreatePlatformTemplate(c *gin.Context) {
+	var req dto.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	template, err := h.templateService.CreatePlatformTemplate(&req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Data:    template,
+		Message: "Template created successfully",
+	})
+}
+
+// UpdatePlatformTemplate godoc
+// @Summary Update a curated platform template
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param template body dto.UpdateTemplateRequest true "Template update data"
+// @Success 200 {object} SuccessResponse{data=dto.TemplateResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /admin/templates/{id} [put]
+func (h *TemplateHandler) UpdatePlatformTemplate(c *gin.Context) {
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	template, err := h.templateService.UpdatePlatformTemplate(templateID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    template,
+		Message: "Template updated successfully",
+	})
+}
+
+// DeletePlatformTemplate godoc
+// @Summary Delete a curated platform template
+// @Tags admin
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /admin/templates/{id} [delete]
+func (h *TemplateHandler) DeletePlatformTemplate(c *gin.Context) {
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.templateService.DeletePlatformTemplate(templateID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Template deleted successfully",
+	})
+}
+
+// CreatePlatformBankQuestion godoc
+// @Summary Add a question to the platform question bank
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param question body dto.CreateBankQuestionRequest true "Question data"
+// @Success 201 {object} SuccessResponse{data=dto.BankQuestionResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /admin/question-bank [post]
+func (h *TemplateHandler) CreatePlatformBankQuestion(c *gin.Context) {
+	var req dto.CreateBankQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	question, err := h.templateService.CreatePlatformBankQuestion(&req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Data:    question,
+		Message: "Question added to question bank",
+	})
+}
+
+// DeletePlatformBankQuestion godoc
+// @Summary Remove a question from the platform question bank
+// @Tags admin
+// @Produce json
+// @Param id path int true "Bank question ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /admin/question-bank/{id} [delete]
+func (h *TemplateHandler) DeletePlatformBankQuestion(c *gin.Context) {
+	questionID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.templateService.DeletePlatformBankQuestion(questionID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Question removed from question bank",
+	})
+}
+
+// parseTemplateID parses the :id path parameter, writing a 400 response on failure
+func parseTemplateID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// normalizePaging applies the default page and limit used by list endpoints
+func normalizePaging(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	return page, limit
+}
@@ -0,0 +1,357 @@
+// internal/handler/webhook_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/middleware"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Register a webhook
+// @Description Register an endpoint to receive signed event notifications for one survey, or for all of the user's surveys when surveyId is omitted. The signing secret is only returned in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateWebhookRequest true "Webhook"
+// @Success 201 {object} SuccessResponse{data=dto.WebhookResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Success: true,
+		Data:    webhook,
+		Message: "Webhook registered. Store the secret now, it won't be shown again.",
+	})
+}
+
+// GetWebhooks godoc
+// @Summary List webhooks
+// @Description List the webhooks of the authenticated user
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} SuccessResponse{data=[]dto.WebhookResponse}
+// @Failure 401 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	webhooks, err := h.webhookService.GetWebhooks(userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    webhooks,
+	})
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} SuccessResponse{data=dto.WebhookResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	userID, webhookID, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhook(userID, webhookID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    webhook,
+	})
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook
+// @Description Change the URL, events or description of a webhook, or pause it with isActive=false
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param request body dto.UpdateWebhookRequest true "Changes"
+// @Success 200 {object} SuccessResponse{data=dto.WebhookResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	userID, webhookID, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(userID, webhookID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    webhook,
+	})
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook. Its pending deliveries are abandoned.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID, webhookID, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(userID, webhookID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "Webhook deleted",
+	})
+}
+
+// RotateWebhookSecret godoc
+// @Summary Rotate a webhook's signing secret
+// @Description Replace the signing secret of a webhook. The new secret is only returned in this response.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} SuccessResponse{data=dto.WebhookResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /webhooks/{id}/rotate-secret [post]
+func (h *WebhookHandler) RotateWebhookSecret(c *gin.Context) {
+	userID, webhookID, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.RotateWebhookSecret(userID, webhookID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    webhook,
+		Message: "Secret rotated. Store the new secret now, it won't be shown again.",
+	})
+}
+
+// TestWebhook godoc
+// @Summary Test-fire a webhook
+// @Description Send a webhook.test event to the webhook right away and return the delivery result
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} SuccessResponse{data=dto.WebhookDeliveryResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /webhooks/{id}/test [post]
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	userID, webhookID, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.TestWebhook(userID, webhookID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	message := "Test event delivered"
+	if delivery.Status != string(models.WebhookDeliveryStatusSucceeded) {
+		message = "Test event delivery failed: " + delivery.Error
+	}
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    delivery,
+		Message: message,
+	})
+}
+
+// GetDeliveries godoc
+// @Summary List webhook deliveries
+// @Description Browse the delivery log of a webhook, newest first
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param status query string false "pending, succeeded or failed"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} SuccessResponse{data=dto.WebhookDeliveryListResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	userID, webhookID, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ListWebhookDeliveriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+	req.Page, req.Limit = normalizePaging(req.Page, req.Limit)
+
+	deliveries, err := h.webhookService.GetDeliveries(userID, webhookID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Data:    deliveries,
+	})
+}
+
+// RedeliverDelivery godoc
+// @Summary Replay a webhook delivery
+// @Description Queue the event of a past delivery to be sent again with the same event ID
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 202 {object} SuccessResponse{data=dto.WebhookDeliveryResponse}
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Security BearerAuth
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) RedeliverDelivery(c *gin.Context) {
+	userID, webhookID, ok := webhookRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := strconv.ParseUint(c.Param("delivery_id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid delivery ID"))
+		return
+	}
+
+	delivery, err := h.webhookService.RedeliverDelivery(userID, webhookID, uint(deliveryID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, SuccessResponse{
+		Success: true,
+		Data:    delivery,
+		Message: "Delivery queued",
+	})
+}
+
+// webhookRequestIDs reads the authenticated user and the webhook ID of a
+// request, responding with an error if either is missing
+func webhookRequestIDs(c *gin.Context) (uint, uint, bool) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.Error(errUnauthenticated)
+		return 0, 0, false
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(apperror.New(apperror.InvalidID, "Invalid webhook ID"))
+		return 0, 0, false
+	}
+
+	return userID, uint(webhookID), true
+}
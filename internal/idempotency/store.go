@@ -0,0 +1,116 @@
+// internal/idempotency/store.go
+
+// Package idempotency lets clients retry writes safely. A write sent with an
+// Idempotency-Key is handled once per user and key; retries get the original
+// response back until the key expires.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// purgeInterval is how often expired keys are deleted
+const purgeInterval = time.Hour
+
+var (
+	// ErrKeyReused is returned when a key is sent again with another
+	// request than the one it was first used for
+	ErrKeyReused = errors.New("idempotency key was used for another request")
+
+	// ErrInProgress is returned when a key is sent again while the request
+	// it was first used for is still being handled
+	ErrInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// Store keeps the keys and the responses to replay for them
+type Store struct {
+	repo repository.IdempotencyRepository
+	ttl  time.Duration
+}
+
+func NewStore(repo repository.IdempotencyRepository, cfg config.IdempotencyConfig) *Store {
+	return &Store{
+		repo: repo,
+		ttl:  time.Duration(cfg.TTLHours) * time.Hour,
+	}
+}
+
+// Begin claims key for a request of the user. It returns the key's record
+// and whether the request is new and should be handled; otherwise the
+// record holds the response to replay. A key already used for a different
+// request fails with ErrKeyReused, and one whose request is still being
+// handled with ErrInProgress.
+func (s *Store) Begin(userID uint, key, method, path string, body []byte) (*models.IdempotencyKey, bool, error) {
+	now := time.Now()
+	record, claimed, err := s.repo.Claim(&models.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		Method:      method,
+		Path:        path,
+		Fingerprint: fingerprint(method, path, body),
+		ExpiresAt:   now.Add(s.ttl),
+		CreatedAt:   now,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if claimed {
+		return record, true, nil
+	}
+
+	if record.Fingerprint != fingerprint(method, path, body) {
+		return nil, false, ErrKeyReused
+	}
+	if !record.IsCompleted() {
+		return nil, false, ErrInProgress
+	}
+	return record, false, nil
+}
+
+// Finish stores the response to a request, to replay for its retries
+func (s *Store) Finish(record *models.IdempotencyKey, statusCode int, contentType string, body []byte) error {
+	return s.repo.Complete(record.ID, statusCode, contentType, body)
+}
+
+// Abandon frees the key of a request that failed without a response worth
+// replaying, so a retry is handled again
+func (s *Store) Abandon(record *models.IdempotencyKey) error {
+	return s.repo.Release(record.ID)
+}
+
+// Run deletes expired keys until ctx is done
+func (s *Store) Run(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if deleted, err := s.repo.DeleteExpired(time.Now()); err != nil {
+			logrus.WithError(err).Error("Failed to delete expired idempotency keys")
+		} else if deleted > 0 {
+			logrus.WithField("deleted", deleted).Info("Deleted expired idempotency keys")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fingerprint identifies a request by its method, path and body
+func fingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,79 @@
+// internal/middleware/auth.go
+package middleware
+
+import (
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthMiddleware validates JWT access tokens
+func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			abort(c, apperror.New(apperror.Unauthorized, "Authorization header required"))
+			return
+		}
+
+		// Extract Bearer token
+		tokenParts := strings.Split(authHeader, " ")
+		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+			abort(c, apperror.New(apperror.Unauthorized, "Invalid authorization header format"))
+			return
+		}
+
+		token := tokenParts[1]
+
+		claims, err := authService.ValidateToken(token)
+		if err != nil {
+			abort(c, apperror.New(apperror.Unauthorized, "Invalid or expired token"))
+			return
+		}
+
+		// Set user and session IDs in context
+		c.Set("user_id", claims.UserID)
+		c.Set("session_id", claims.SessionID)
+		c.Next()
+	})
+}
+
+// AdminMiddleware checks if user has admin privileges
+func AdminMiddleware(authService service.AuthService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		userID := GetUserID(c)
+		if userID == 0 {
+			abort(c, apperror.New(apperror.Unauthorized, "User authentication required"))
+			return
+		}
+
+		if !authService.IsAdmin(userID) {
+			abort(c, apperror.New(apperror.Forbidden, "Admin privileges required"))
+			return
+		}
+
+		c.Next()
+	})
+}
+
+// GetUserID extracts user ID from context
+func GetUserID(c *gin.Context) uint {
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(uint); ok {
+			return id
+		}
+	}
+	return 0
+}
+
+// GetSessionID extracts the session ID of the access token from context
+func GetSessionID(c *gin.Context) uint {
+	if sessionID, exists := c.Get("session_id"); exists {
+		if id, ok := sessionID.(uint); ok {
+			return id
+		}
+	}
+	return 0
+}
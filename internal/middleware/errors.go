@@ -0,0 +1,51 @@
+// internal/middleware/errors.go
+package middleware
+
+import (
+	"survey2earn-backend/internal/apperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Errors answers a request that failed with c.Error as an RFC 7807 problem,
+// the status given by the error's code. It is the only place errors are
+// mapped to responses; handlers and middleware only report them.
+func Errors() gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		c.Next()
+		WriteError(c)
+	})
+}
+
+// WriteError writes the last error of the request as a problem, unless a
+// response was already written. Middleware that records responses calls it
+// before reading the status.
+func WriteError(c *gin.Context) {
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+
+	err := c.Errors.Last().Err
+	problem := apperror.NewProblem(err, c.Request.URL.Path)
+	entry := logrus.WithError(err).WithFields(logrus.Fields{
+		"method": c.Request.Method,
+		"path":   c.Request.URL.Path,
+		"code":   problem.Code,
+	})
+	if problem.Status >= 500 {
+		entry.Error("Request failed")
+	} else {
+		entry.Debug("Request rejected")
+	}
+
+	// gin keeps a Content-Type that is already set
+	c.Header("Content-Type", apperror.ProblemContentType)
+	c.JSON(problem.Status, problem)
+}
+
+// abort stops the request with err, answered by Errors
+func abort(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
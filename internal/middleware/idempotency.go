@@ -0,0 +1,112 @@
+// internal/middleware/idempotency.go
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/idempotency"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxIdempotencyKeyLength is the longest Idempotency-Key accepted
+const maxIdempotencyKeyLength = 255
+
+// Idempotency handles a write sent with an Idempotency-Key header once per
+// user and key, and answers retries with the original response, marked with
+// an Idempotent-Replayed header. Requests without the header pass through.
+// Keys are per user, so it must run after AuthMiddleware. Responses with a
+// 5xx status aren't kept, so the retry is handled again.
+func Idempotency(store *idempotency.Store) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader("Idempotency-Key"))
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			abort(c, apperror.New(apperror.InvalidIdempotencyKey, "Idempotency-Key can't be longer than 255 characters"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abort(c, apperror.Wrap(err, apperror.InvalidRequest, "Failed to read the request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record, isNew, err := store.Begin(GetUserID(c), key, c.Request.Method, c.Request.URL.Path, body)
+		switch {
+		case errors.Is(err, idempotency.ErrKeyReused):
+			abort(c, apperror.Wrap(err, apperror.IdempotencyKeyReused, "This Idempotency-Key was used for a different request"))
+			return
+		case errors.Is(err, idempotency.ErrInProgress):
+			c.Header("Retry-After", "1")
+			abort(c, apperror.Wrap(err, apperror.IdempotencyKeyInProgress, "A request with this Idempotency-Key is still in progress"))
+			return
+		case err != nil:
+			// Handling the request without its key could repeat the write
+			// the key guards against
+			abort(c, apperror.Wrap(err, apperror.Internal, "Failed to check the Idempotency-Key, retry the request"))
+			return
+		}
+
+		if !isNew {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.StatusCode, record.ContentType, record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		finished := false
+		defer func() {
+			// Free the key of a request that panicked
+			if !finished {
+				if err := store.Abandon(record); err != nil {
+					logrus.WithError(err).Error("Failed to release idempotency key")
+				}
+			}
+		}()
+
+		c.Next()
+		// Write a failed request's problem now, so it is recorded
+		WriteError(c)
+		finished = true
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			if err := store.Abandon(record); err != nil {
+				logrus.WithError(err).Error("Failed to release idempotency key")
+			}
+			return
+		}
+		// Without its response the key stays in progress until it expires,
+		// which is safer than handling the write again
+		if err := store.Finish(record, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			logrus.WithError(err).Error("Failed to store idempotent response")
+		}
+	})
+}
+
+// responseRecorder keeps a copy of the response body while writing it
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
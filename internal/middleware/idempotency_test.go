@@ -0,0 +1,272 @@
+// internal/middleware/idempotency_test.go
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/idempotency"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryIdempotencyKeys keeps idempotency keys in memory, claimed and
+// released like the database does
+type memoryIdempotencyKeys struct {
+	repository.IdempotencyRepository
+	mu     sync.Mutex
+	keys   map[uint]models.IdempotencyKey
+	nextID uint
+}
+
+func (r *memoryIdempotencyKeys) Claim(key *models.IdempotencyKey) (*models.IdempotencyKey, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, existing := range r.keys {
+		if existing.UserID != key.UserID || existing.Key != key.Key {
+			continue
+		}
+		if existing.ExpiresAt.After(time.Now()) {
+			return &existing, false, nil
+		}
+		delete(r.keys, id)
+	}
+	r.nextID++
+	key.ID = r.nextID
+	r.keys[key.ID] = *key
+	return key, true, nil
+}
+
+func (r *memoryIdempotencyKeys) Complete(id uint, statusCode int, contentType string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := r.keys[id]
+	now := time.Now()
+	key.StatusCode, key.ContentType, key.ResponseBody, key.CompletedAt = statusCode, contentType, body, &now
+	r.keys[id] = key
+	return nil
+}
+
+func (r *memoryIdempotencyKeys) Release(id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key := r.keys[id]; !key.IsCompleted() {
+		delete(r.keys, id)
+	}
+	return nil
+}
+
+func (r *memoryIdempotencyKeys) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.keys)
+}
+
+// idempotencyTest serves POST /payouts behind Idempotency, for the user
+// named by the X-User header, with a handler counting its calls
+type idempotencyTest struct {
+	router  *gin.Engine
+	keys    *memoryIdempotencyKeys
+	store   *idempotency.Store
+	handled int
+	fail    func(c *gin.Context) bool // fails the request instead of handling it when true
+}
+
+func newIdempotencyTest(t *testing.T) *idempotencyTest {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	test := &idempotencyTest{keys: &memoryIdempotencyKeys{keys: map[uint]models.IdempotencyKey{}}}
+	test.store = idempotency.NewStore(test.keys, config.IdempotencyConfig{TTLHours: 24})
+	test.router = gin.New()
+	test.router.Use(gin.CustomRecovery(func(c *gin.Context, _ interface{}) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}), Errors(), func(c *gin.Context) {
+		id, _ := strconv.ParseUint(c.GetHeader("X-User"), 10, 32)
+		c.Set("user_id", uint(id))
+	})
+	test.router.POST("/payouts", Idempotency(test.store), func(c *gin.Context) {
+		if test.fail != nil && test.fail(c) {
+			return
+		}
+		test.handled++
+		c.JSON(http.StatusCreated, gin.H{"payout": test.handled})
+	})
+	return test
+}
+
+func (test *idempotencyTest) post(user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/payouts", strings.NewReader(body))
+	req.Header.Set("X-User", user)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	rec := httptest.NewRecorder()
+	test.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func problemCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var problem struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("response isn't a problem: %s", rec.Body)
+	}
+	return problem.Code
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	test := newIdempotencyTest(t)
+
+	first := test.post("1", "key-1", `{"amount": 5}`)
+	if first.Code != http.StatusCreated || first.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("first request = %d %v, want 201 not replayed", first.Code, first.Header())
+	}
+	retry := test.post("1", "key-1", `{"amount": 5}`)
+	if retry.Code != http.StatusCreated || retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retry = %d %v, want a replayed 201", retry.Code, retry.Header())
+	}
+	if retry.Body.String() != first.Body.String() || retry.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Errorf("retry = %s (%s), want %s (%s)", retry.Body, retry.Header().Get("Content-Type"), first.Body, first.Header().Get("Content-Type"))
+	}
+	if test.handled != 1 {
+		t.Errorf("handled %d times, want once", test.handled)
+	}
+
+	// Keys are per user, and requests without one are always handled
+	if rec := test.post("2", "key-1", `{"amount": 5}`); rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("another user's request = %d %v, want handled", rec.Code, rec.Header())
+	}
+	test.post("1", "", `{"amount": 5}`)
+	test.post("1", "", `{"amount": 5}`)
+	if test.handled != 4 {
+		t.Errorf("handled %d times, want 4", test.handled)
+	}
+}
+
+func TestIdempotencyRejectsReusedKey(t *testing.T) {
+	test := newIdempotencyTest(t)
+	test.post("1", "key-1", `{"amount": 5}`)
+
+	rec := test.post("1", "key-1", `{"amount": 500}`)
+	if rec.Code != http.StatusUnprocessableEntity || problemCode(t, rec) != string(apperror.IdempotencyKeyReused) {
+		t.Errorf("reused key = %d %s, want 422 %s", rec.Code, rec.Body, apperror.IdempotencyKeyReused)
+	}
+	if test.handled != 1 {
+		t.Errorf("handled %d times, want once", test.handled)
+	}
+}
+
+func TestIdempotencyKeyInProgress(t *testing.T) {
+	test := newIdempotencyTest(t)
+	// The first request with the key is still being handled
+	if _, claimed, err := test.store.Begin(1, "key-1", http.MethodPost, "/payouts", []byte(`{}`)); err != nil || !claimed {
+		t.Fatalf("Begin() = %v, %v", claimed, err)
+	}
+
+	rec := test.post("1", "key-1", `{}`)
+	if rec.Code != http.StatusConflict || rec.Header().Get("Retry-After") != "1" || problemCode(t, rec) != string(apperror.IdempotencyKeyInProgress) {
+		t.Errorf("retry in progress = %d %v %s, want 409 with Retry-After", rec.Code, rec.Header(), rec.Body)
+	}
+	if test.handled != 0 {
+		t.Errorf("handled %d times, want not at all", test.handled)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnServerError(t *testing.T) {
+	test := newIdempotencyTest(t)
+	failures := 1
+	test.fail = func(c *gin.Context) bool {
+		if failures == 0 {
+			return false
+		}
+		failures--
+		c.Error(apperror.New(apperror.Internal, "The database is down"))
+		return true
+	}
+
+	if rec := test.post("1", "key-1", `{}`); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("failed request = %d, want 500", rec.Code)
+	}
+	if n := test.keys.count(); n != 0 {
+		t.Fatalf("%d keys held after a server error, want the key released", n)
+	}
+
+	rec := test.post("1", "key-1", `{}`)
+	if rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "" || test.handled != 1 {
+		t.Errorf("retry = %d %v after %d calls, want handled", rec.Code, rec.Header(), test.handled)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnPanic(t *testing.T) {
+	test := newIdempotencyTest(t)
+	test.fail = func(c *gin.Context) bool { panic("handler bug") }
+
+	if rec := test.post("1", "key-1", `{}`); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("panicking request = %d, want 500", rec.Code)
+	}
+	if n := test.keys.count(); n != 0 {
+		t.Errorf("%d keys held after a panic, want the key released", n)
+	}
+}
+
+func TestIdempotencyReplaysClientErrors(t *testing.T) {
+	test := newIdempotencyTest(t)
+	calls := 0
+	test.fail = func(c *gin.Context) bool {
+		calls++
+		c.Error(apperror.New(apperror.PoolExhausted, "The reward pool is empty"))
+		return true
+	}
+
+	first := test.post("1", "key-1", `{}`)
+	retry := test.post("1", "key-1", `{}`)
+	if retry.Code != first.Code || retry.Body.String() != first.Body.String() || retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retry = %d %s, want the replayed %d %s", retry.Code, retry.Body, first.Code, first.Body)
+	}
+	if calls != 1 {
+		t.Errorf("handled %d times, want the error kept", calls)
+	}
+}
+
+func TestIdempotencyRejectsLongKeys(t *testing.T) {
+	test := newIdempotencyTest(t)
+	rec := test.post("1", strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)
+	if rec.Code != http.StatusBadRequest || problemCode(t, rec) != string(apperror.InvalidIdempotencyKey) {
+		t.Errorf("long key = %d %s, want 400", rec.Code, rec.Body)
+	}
+	if rec := test.post("1", strings.Repeat("k", maxIdempotencyKeyLength), `{}`); rec.Code != http.StatusCreated {
+		t.Errorf("key of %d characters = %d, want 201", maxIdempotencyKeyLength, rec.Code)
+	}
+}
+
+func TestIdempotencyKeysExpire(t *testing.T) {
+	test := newIdempotencyTest(t)
+	test.post("1", "key-1", `{}`)
+	for id, key := range test.keys.keys {
+		key.ExpiresAt = time.Now().Add(-time.Second)
+		test.keys.keys[id] = key
+	}
+
+	rec := test.post("1", "key-1", `{"amount": 1}`)
+	if rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("request with an expired key = %d %v, want handled anew", rec.Code, rec.Header())
+	}
+	if test.handled != 2 {
+		t.Errorf("handled %d times, want twice", test.handled)
+	}
+}
@@ -0,0 +1,76 @@
+// internal/middleware/rate_limit_test.go
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/ratelimit"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newRateLimitedRouter serves POST /login limited to a burst of two, with the
+// proxies trusted as main trusts them
+func newRateLimitedRouter(t *testing.T, trustedProxies []string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	limiter := ratelimit.NewMemory()
+	t.Cleanup(func() { limiter.Close() })
+
+	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		t.Fatal(err)
+	}
+	router.Use(Errors(), RateLimit(limiter, ratelimit.Rule{Name: "login", RequestsPerMinute: 1, Burst: 2}, nil))
+	router.POST("/login", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return router
+}
+
+// login sends a login from remoteAddr claiming to be forwardedFor
+func login(router *gin.Engine, remoteAddr, forwardedFor string) int {
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("X-Forwarded-For", forwardedFor)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "")
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Server.TrustedProxies) != 0 {
+		t.Fatalf("TrustedProxies = %v by default, want none", cfg.Server.TrustedProxies)
+	}
+	router := newRateLimitedRouter(t, cfg.Server.TrustedProxies)
+
+	// A new forwarded address on every request is still the same client
+	for i, want := range []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests} {
+		if got := login(router, "203.0.113.7:4000", fmt.Sprintf("198.51.100.%d", i)); got != want {
+			t.Fatalf("login %d = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestRateLimitTrustsConfiguredProxies(t *testing.T) {
+	router := newRateLimitedRouter(t, []string{"10.0.0.0/8"})
+
+	// Behind a trusted proxy every forwarded client has its own bucket
+	for i := 0; i < 3; i++ {
+		if got := login(router, "10.0.0.2:4000", fmt.Sprintf("198.51.100.%d", i)); got != http.StatusNoContent {
+			t.Fatalf("login from client %d = %d, want %d", i, got, http.StatusNoContent)
+		}
+	}
+	for i, want := range []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests} {
+		if got := login(router, "10.0.0.2:4000", "198.51.100.200"); got != want {
+			t.Fatalf("login %d from one client = %d, want %d", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"time"
+	"unicode/utf8"
+)
+
+// Answer validation error codes
+const (
+	AnswerErrorRequired        = "required"
+	AnswerErrorTypeMismatch    = "type_mismatch"
+	AnswerErrorUnsupportedType = "unsupported_type"
+	AnswerErrorUnknownQuestion = "unknown_question"
+	AnswerErrorTooShort        = "too_short"
+	AnswerErrorTooLong         = "too_long"
+	AnswerErrorInvalidOption   = "invalid_option"
+	AnswerErrorSelectionCount  = "invalid_selection_count"
+	AnswerErrorOutOfRange      = "out_of_range"
+	AnswerErrorInvalidValue    = "invalid_value"
+	AnswerErrorInvalidFormat   = "invalid_format"
+	AnswerErrorIncomplete      = "incomplete"
+	AnswerErrorInvalidFile     = "invalid_file"
+)
+
+// sumTolerance absorbs floating point noise when checking constant-sum totals
+const sumTolerance = 0.0001
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9 ().-]{7,20}$`)
+
+// AnswerValidationError describes why the answer to a question is invalid
+type AnswerValidationError struct {
+	QuestionID uint   `json:"question_id"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *AnswerValidationError) Error() string {
+	return fmt.Sprintf("question %d: %s", e.QuestionID, e.Message)
+}
+
+// AnswerValidationErrors collects the validation errors of several answers
+type AnswerValidationErrors []AnswerValidationError
+
+func (e AnswerValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "invalid answers: " + strings.Join(messages, "; ")
+}
+
+// AppError lists the invalid answers as field errors, each on the field
+// "questions.<question id>"
+func (e AnswerValidationErrors) AppError() *apperror.Error {
+	fields := make([]apperror.FieldError, len(e))
+	for i, err := range e {
+		fields[i] = apperror.FieldError{
+			Field:   "questions." + strconv.FormatUint(uint64(err.QuestionID), 10),
+			Code:    err.Code,
+			Message: err.Message,
+		}
+	}
+	return apperror.Invalid("One or more answers are invalid", fields...)
+}
+
+// NewUnknownQuestionError reports an answer to a question that is not part of the survey
+func NewUnknownQuestionError(questionID uint) *AnswerValidationError {
+	return &AnswerValidationError{
+		QuestionID: questionID,
+		Code:       AnswerErrorUnknownQuestion,
+		Message:    "question does not belong to this survey",
+	}
+}
+
+func newAnswerError(question *Question, code, message string) *AnswerValidationError {
+	return &AnswerValidationError{
+		QuestionID: question.ID,
+		Code:       code,
+		Message:    message,
+	}
+}
+
+// validateTextLength checks text answers against MinLength and MaxLength
+func (a *Answer) validateTextLength(question *Question) error {
+	length := utf8.RuneCountInString(a.AnswerText)
+	if question.MinLength != nil && length < *question.MinLength {
+		return newAnswerError(question, AnswerErrorTooShort, fmt.Sprintf("answer must be at least %d characters", *question.MinLength))
+	}
+	if question.MaxLength != nil && length > *question.MaxLength {
+		return newAnswerError(question, AnswerErrorTooLong, fmt.Sprintf("answer must be at most %d characters", *question.MaxLength))
+	}
+	return nil
+}
+
+// validateChoices checks that every selected option exists on the question
+// and that single choice questions have exactly one selection
+func (a *Answer) validateChoices(question *Question, single bool) error {
+	selected := a.AnswerValue.Options
+	if single && len(selected) != 1 {
+		return newAnswerError(question, AnswerErrorSelectionCount, "exactly one option must be selected")
+	}
+	if len(selected) == 0 {
+		return newAnswerError(question, AnswerErrorSelectionCount, "at least one option must be selected")
+	}
+
+	seen := make(map[string]bool, len(selected))
+	for _, value := range selected {
+		if !question.HasOption(value) {
+			return newAnswerError(question, AnswerErrorInvalidOption, fmt.Sprintf("unknown option %q", value))
+		}
+		if seen[value] {
+			return newAnswerError(question, AnswerErrorInvalidOption, fmt.Sprintf("option %q selected more than once", value))
+		}
+		seen[value] = true
+	}
+	return nil
+}
+
+// validateYesNo checks that the answer is a boolean or a yes/no option
+func (a *Answer) validateYesNo(question *Question) error {
+	if _, ok := a.AnswerValue.Content.(bool); ok {
+		return nil
+	}
+	if len(question.Options) > 0 {
+		return a.validateChoices(question, true)
+	}
+	if len(a.AnswerValue.Options) == 1 {
+		switch strings.ToLower(a.AnswerValue.Options[0]) {
+		case "yes", "no":
+			return nil
+		}
+	}
+	return newAnswerError(question, AnswerErrorInvalidValue, "answer must be yes or no")
+}
+
+// validateIntRange checks rating and scale answers against MinValue and MaxValue
+func (a *Answer) validateIntRange(question *Question, value *int, name string) error {
+	if value == nil {
+		return newAnswerError(question, AnswerErrorInvalidValue, name+" value is required")
+	}
+	return validateRange(question, float64(*value), name)
+}
+
+// validateNumber checks that number answers are numeric and within range
+func (a *Answer) validateNumber(question *Question) error {
+	var number float64
+	switch content := a.AnswerValue.Content.(type) {
+	case float64:
+		number = content
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(content), 64)
+		if err != nil {
+			return newAnswerError(question, AnswerErrorInvalidFormat, "answer must be a number")
+		}
+		number = parsed
+	default:
+		return newAnswerError(question, AnswerErrorInvalidFormat, "answer must be a number")
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return newAnswerError(question, AnswerErrorInvalidFormat, "answer must be a finite number")
+	}
+	return validateRange(question, number, "number")
+}
+
+// validateDate checks date answers against MinValue and MaxValue, which hold
+// Unix timestamps in seconds for date questions
+func (a *Answer) validateDate(question *Question) error {
+	if a.AnswerValue.Date == nil {
+		return newAnswerError(question, AnswerErrorInvalidFormat, "answer must be a date")
+	}
+	date := *a.AnswerValue.Date
+	if question.MinValue != nil && date.Before(time.Unix(int64(*question.MinValue), 0)) {
+		return newAnswerError(question, AnswerErrorOutOfRange, "date is before the earliest allowed date")
+	}
+	if question.MaxValue != nil && date.After(time.Unix(int64(*question.MaxValue), 0)) {
+		return newAnswerError(question, AnswerErrorOutOfRange, "date is after the latest allowed date")
+	}
+	return nil
+}
+
+// validateNPS checks that the NPS score is within 0-10
+func (a *Answer) validateNPS(question *Question) error {
+	if a.AnswerValue.Rating == nil {
+		return newAnswerError(question, AnswerErrorInvalidValue, "NPS score is required")
+	}
+	if *a.AnswerValue.Rating < NPSMinScore || *a.AnswerValue.Rating > NPSMaxScore {
+		return newAnswerError(question, AnswerErrorOutOfRange, fmt.Sprintf("NPS score must be between %d and %d", NPSMinScore, NPSMaxScore))
+	}
+	return nil
+}
+
+// validateContactText checks the format of email, URL and phone answers
+func (a *Answer) validateContactText(question *Question) error {
+	text := strings.TrimSpace(a.AnswerText)
+
+	switch question.Type {
+	case QuestionTypeEmail:
+		address, err := mail.ParseAddress(text)
+		if err != nil || address.Address != text {
+			return newAnswerError(question, AnswerErrorInvalidFormat, "invalid email address")
+		}
+	case QuestionTypeURL:
+		parsed, err := url.ParseRequestURI(text)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return newAnswerError(question, AnswerErrorInvalidFormat, "invalid URL")
+		}
+	case QuestionTypePhone:
+		digits := 0
+		for _, r := range text {
+			if r >= '0' && r <= '9' {
+				digits++
+			}
+		}
+		if !phonePattern.MatchString(text) || digits < 7 || digits > 15 {
+			return newAnswerError(question, AnswerErrorInvalidFormat, "invalid phone number")
+		}
+	}
+	return a.validateTextLength(question)
+}
+
+// validateMatrix checks that every answered row exists and picks a valid column
+func (a *Answer) validateMatrix(question *Question) error {
+	for rowID, column := range a.AnswerValue.Matrix {
+		if !question.HasRow(rowID) {
+			return newAnswerError(question, AnswerErrorInvalidOption, fmt.Sprintf("unknown matrix row %q", rowID))
+		}
+		if !question.HasOption(column) {
+			return newAnswerError(question, AnswerErrorInvalidOption, fmt.Sprintf("unknown matrix column %q for row %q", column, rowID))
+		}
+	}
+	if question.Required && len(a.AnswerValue.Matrix) < len(question.Rows) {
+		return newAnswerError(question, AnswerErrorIncomplete, "every matrix row must be answered")
+	}
+	return nil
+}
+
+// validateRanking checks that the ranking orders every option exactly once
+func (a *Answer) validateRanking(question *Question) error {
+	seen := make(map[string]bool, len(a.AnswerValue.Ranking))
+	for _, value := range a.AnswerValue.Ranking {
+		if !question.HasOption(value) {
+			return newAnswerError(question, AnswerErrorInvalidOption, fmt.Sprintf("unknown ranking option %q", value))
+		}
+		if seen[value] {
+			return newAnswerError(question, AnswerErrorInvalidOption, fmt.Sprintf("option %q ranked more than once", value))
+		}
+		seen[value] = true
+	}
+	if len(seen) != len(question.Options) {
+		return newAnswerError(question, AnswerErrorIncomplete, "every option must be ranked")
+	}
+	return nil
+}
+
+// validateConstantSum checks that allocations are non-negative and add up to the total
+func (a *Answer) validateConstantSum(question *Question) error {
+	total := 0.0
+	for value, amount := range a.AnswerValue.Allocations {
+		if !question.HasOption(value) {
+			return newAnswerError(question, AnswerErrorInvalidOption, fmt.Sprintf("unknown allocation option %q", value))
+		}
+		if amount < 0 {
+			return newAnswerError(question, AnswerErrorOutOfRange, fmt.Sprintf("allocation for %q cannot be negative", value))
+		}
+		total += amount
+	}
+	if question.SumTotal != nil && math.Abs(total-*question.SumTotal) > sumTolerance {
+		return newAnswerError(question, AnswerErrorOutOfRange, fmt.Sprintf("allocations must add up to %g", *question.SumTotal))
+	}
+	return nil
+}
+
+// validateFiles checks uploaded files against the question's file constraints
+func (a *Answer) validateFiles(question *Question) error {
+	for _, file := range a.AnswerValue.Files {
+		if file.URL == "" {
+			return newAnswerError(question, AnswerErrorInvalidFile, "uploaded file is missing its URL")
+		}
+	}
+
+	config := question.FileConfig
+	if config == nil {
+		return nil
+	}
+	if config.MaxFiles > 0 && len(a.AnswerValue.Files) > config.MaxFiles {
+		return newAnswerError(question, AnswerErrorInvalidFile, fmt.Sprintf("at most %d files can be uploaded", config.MaxFiles))
+	}
+	for _, file := range a.AnswerValue.Files {
+		if config.MaxSizeBytes > 0 && file.SizeBytes > config.MaxSizeBytes {
+			return newAnswerError(question, AnswerErrorInvalidFile, fmt.Sprintf("file %q exceeds the maximum size", file.Name))
+		}
+		if len(config.AllowedTypes) > 0 && !containsFold(config.AllowedTypes, file.ContentType) {
+			return newAnswerError(question, AnswerErrorInvalidFile, fmt.Sprintf("file type %q is not allowed", file.ContentType))
+		}
+	}
+	return nil
+}
+
+// validateRange checks a numeric answer against the question's MinValue and MaxValue
+func validateRange(question *Question, value float64, name string) error {
+	if question.MinValue != nil && value < *question.MinValue {
+		return newAnswerError(question, AnswerErrorOutOfRange, fmt.Sprintf("%s must be at least %g", name, *question.MinValue))
+	}
+	if question.MaxValue != nil && value > *question.MaxValue {
+		return newAnswerError(question, AnswerErrorOutOfRange, fmt.Sprintf("%s must be at most %g", name, *question.MaxValue))
+	}
+	return nil
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+
+func textAnswer(answerType, text string) Answer {
+	return Answer{AnswerText: text, AnswerValue: AnswerValue{Type: answerType, Content: text}}
+}
+
+func ratingAnswer(rating int) Answer {
+	return Answer{AnswerValue: AnswerValue{Type: string(QuestionTypeRating), Rating: intPtr(rating)}}
+}
+
+func optionsAnswer(answerType string, options ...string) Answer {
+	return Answer{AnswerValue: AnswerValue{Type: answerType, Options: options}}
+}
+
+func choiceOptions(values ...string) QuestionOptions {
+	options := make(QuestionOptions, len(values))
+	for i, value := range values {
+		options[i] = QuestionOption{ID: value, Label: value, Value: value, Order: i + 1}
+	}
+	return options
+}
+
+func TestValidateAnswer(t *testing.T) {
+	text := &Question{Type: QuestionTypeText, MinLength: intPtr(3), MaxLength: intPtr(5)}
+	required := &Question{Type: QuestionTypeText, Required: true}
+	single := &Question{Type: QuestionTypeSingleChoice, Options: choiceOptions("a", "b")}
+	multiple := &Question{Type: QuestionTypeMultipleChoice, Options: choiceOptions("a", "b")}
+	yesNo := &Question{Type: QuestionTypeYesNo}
+	rating := &Question{Type: QuestionTypeRating, MinValue: floatPtr(1), MaxValue: floatPtr(5)}
+	scale := &Question{Type: QuestionTypeScale, MinValue: floatPtr(1), MaxValue: floatPtr(10)}
+	number := &Question{Type: QuestionTypeNumber, MinValue: floatPtr(0), MaxValue: floatPtr(100)}
+	earliest := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	latest := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	date := &Question{Type: QuestionTypeDate, MinValue: floatPtr(float64(earliest.Unix())), MaxValue: floatPtr(float64(latest.Unix()))}
+	nps := &Question{Type: QuestionTypeNPS}
+	email := &Question{Type: QuestionTypeEmail}
+	link := &Question{Type: QuestionTypeURL}
+	phone := &Question{Type: QuestionTypePhone}
+	matrix := &Question{Type: QuestionTypeMatrix, Required: true, Options: choiceOptions("bad", "good"), Rows: choiceOptions("r1", "r2")}
+	ranking := &Question{Type: QuestionTypeRanking, Options: choiceOptions("a", "b")}
+	constantSum := &Question{Type: QuestionTypeConstantSum, Options: choiceOptions("a", "b"), SumTotal: floatPtr(100)}
+	upload := &Question{Type: QuestionTypeFileUpload, FileConfig: &FileUploadConfig{AllowedTypes: []string{"image/png"}, MaxSizeBytes: 1000, MaxFiles: 1}}
+
+	png := FileAnswer{URL: "https://files.example/a.png", Name: "a.png", ContentType: "IMAGE/PNG", SizeBytes: 1000}
+	files := func(files ...FileAnswer) Answer {
+		return Answer{AnswerValue: AnswerValue{Type: string(QuestionTypeFileUpload), Files: files}}
+	}
+	value := func(answerType string, set func(*AnswerValue)) Answer {
+		answer := Answer{AnswerValue: AnswerValue{Type: answerType}}
+		set(&answer.AnswerValue)
+		return answer
+	}
+
+	tests := []struct {
+		name     string
+		question *Question
+		answer   Answer
+		want     string // error code, empty when the answer is valid
+	}{
+		{"optional and empty", text, textAnswer("text", " "), ""},
+		{"required and empty", required, textAnswer("text", " "), AnswerErrorRequired},
+		{"required and skipped", required, Answer{IsSkipped: true, AnswerValue: AnswerValue{Type: "text", Content: "x"}}, AnswerErrorRequired},
+		{"wrong answer type", text, textAnswer("number", "abcd"), AnswerErrorTypeMismatch},
+		{"unsupported question type", &Question{Type: "slider"}, textAnswer("slider", "3"), AnswerErrorUnsupportedType},
+
+		{"text at the minimum length", text, textAnswer("text", "abc"), ""},
+		{"text length in characters", text, textAnswer("textarea", "ééééé"), ""},
+		{"text too short", text, textAnswer("text", "ab"), AnswerErrorTooShort},
+		{"text too long", text, textAnswer("text", "abcdef"), AnswerErrorTooLong},
+
+		{"single choice", single, optionsAnswer("single_choice", "a"), ""},
+		{"single choice as an array", single, optionsAnswer("array", "b"), ""},
+		{"single choice of two options", single, optionsAnswer("single_choice", "a", "b"), AnswerErrorSelectionCount},
+		{"single choice of an unknown option", single, optionsAnswer("single_choice", "c"), AnswerErrorInvalidOption},
+		{"multiple choice", multiple, optionsAnswer("multiple_choice", "a", "b"), ""},
+		{"multiple choice without options", multiple, value("multiple_choice", func(v *AnswerValue) { v.Content = "a" }), AnswerErrorSelectionCount},
+		{"multiple choice repeating an option", multiple, optionsAnswer("multiple_choice", "a", "a"), AnswerErrorInvalidOption},
+
+		{"yes/no as a boolean", yesNo, value("boolean", func(v *AnswerValue) { v.Content = false }), ""},
+		{"yes/no as an option", yesNo, optionsAnswer("yes_no", "Yes"), ""},
+		{"yes/no as another option", yesNo, optionsAnswer("yes_no", "maybe"), AnswerErrorInvalidValue},
+
+		{"rating at the maximum", rating, value("rating", func(v *AnswerValue) { v.Rating = intPtr(5) }), ""},
+		{"rating below the minimum", rating, value("rating", func(v *AnswerValue) { v.Rating = intPtr(0) }), AnswerErrorOutOfRange},
+		{"rating above the maximum", rating, value("rating", func(v *AnswerValue) { v.Rating = intPtr(6) }), AnswerErrorOutOfRange},
+		{"rating without a rating", rating, value("rating", func(v *AnswerValue) { v.Content = "5" }), AnswerErrorInvalidValue},
+		{"scale given as a rating", scale, value("scale", func(v *AnswerValue) { v.Rating = intPtr(10) }), ""},
+		{"scale above the maximum", scale, value("scale", func(v *AnswerValue) { v.Scale = intPtr(11) }), AnswerErrorOutOfRange},
+
+		{"number at the maximum", number, value("number", func(v *AnswerValue) { v.Content = 100.0 }), ""},
+		{"number as text", number, value("number", func(v *AnswerValue) { v.Content = " 42.5 " }), ""},
+		{"number above the maximum", number, value("number", func(v *AnswerValue) { v.Content = 100.5 }), AnswerErrorOutOfRange},
+		{"number below the minimum", number, value("number", func(v *AnswerValue) { v.Content = "-1" }), AnswerErrorOutOfRange},
+		{"number that isn't one", number, value("number", func(v *AnswerValue) { v.Content = "many" }), AnswerErrorInvalidFormat},
+		{"number that isn't finite", number, value("number", func(v *AnswerValue) { v.Content = "NaN" }), AnswerErrorInvalidFormat},
+
+		{"date at the earliest", date, value("date", func(v *AnswerValue) { v.Date = &earliest }), ""},
+		{"date before the earliest", date, value("date", func(v *AnswerValue) { d := earliest.Add(-time.Second); v.Date = &d }), AnswerErrorOutOfRange},
+		{"date after the latest", date, value("date", func(v *AnswerValue) { d := latest.Add(time.Second); v.Date = &d }), AnswerErrorOutOfRange},
+		{"date without a date", date, value("date", func(v *AnswerValue) { v.Content = "tomorrow" }), AnswerErrorInvalidFormat},
+
+		{"NPS of 0", nps, value("nps", func(v *AnswerValue) { v.Rating = intPtr(0) }), ""},
+		{"NPS of 10", nps, value("nps", func(v *AnswerValue) { v.Rating = intPtr(10) }), ""},
+		{"NPS of 11", nps, value("nps", func(v *AnswerValue) { v.Rating = intPtr(11) }), AnswerErrorOutOfRange},
+
+		{"email", email, textAnswer("text", "ada@example.com"), ""},
+		{"email with a display name", email, textAnswer("email", "Ada <ada@example.com>"), AnswerErrorInvalidFormat},
+		{"URL", link, textAnswer("url", "https://example.com/a"), ""},
+		{"URL of another scheme", link, textAnswer("url", "ftp://example.com"), AnswerErrorInvalidFormat},
+		{"phone number", phone, textAnswer("phone", "+1 (555) 123-4567"), ""},
+		{"phone number too short", phone, textAnswer("phone", "123456"), AnswerErrorInvalidFormat},
+
+		{"matrix", matrix, value("matrix", func(v *AnswerValue) { v.Matrix = map[string]string{"r1": "good", "r2": "bad"} }), ""},
+		{"matrix missing a row", matrix, value("matrix", func(v *AnswerValue) { v.Matrix = map[string]string{"r1": "good"} }), AnswerErrorIncomplete},
+		{"matrix with an unknown row", matrix, value("matrix", func(v *AnswerValue) { v.Matrix = map[string]string{"r3": "good"} }), AnswerErrorInvalidOption},
+		{"matrix with an unknown column", matrix, value("matrix", func(v *AnswerValue) { v.Matrix = map[string]string{"r1": "great"} }), AnswerErrorInvalidOption},
+
+		{"ranking", ranking, value("ranking", func(v *AnswerValue) { v.Ranking = []string{"b", "a"} }), ""},
+		{"ranking missing an option", ranking, value("ranking", func(v *AnswerValue) { v.Ranking = []string{"a"} }), AnswerErrorIncomplete},
+		{"ranking repeating an option", ranking, value("ranking", func(v *AnswerValue) { v.Ranking = []string{"a", "a"} }), AnswerErrorInvalidOption},
+
+		{"constant sum", constantSum, value("constant_sum", func(v *AnswerValue) { v.Allocations = map[string]float64{"a": 60.00001, "b": 40} }), ""},
+		{"constant sum under the total", constantSum, value("constant_sum", func(v *AnswerValue) { v.Allocations = map[string]float64{"a": 60, "b": 30} }), AnswerErrorOutOfRange},
+		{"constant sum with a negative amount", constantSum, value("constant_sum", func(v *AnswerValue) { v.Allocations = map[string]float64{"a": -10, "b": 110} }), AnswerErrorOutOfRange},
+		{"constant sum of an unknown option", constantSum, value("constant_sum", func(v *AnswerValue) { v.Allocations = map[string]float64{"c": 100} }), AnswerErrorInvalidOption},
+
+		{"file at the maximum size", upload, files(png), ""},
+		{"too many files", upload, files(png, png), AnswerErrorInvalidFile},
+		{"file too large", upload, files(FileAnswer{URL: png.URL, ContentType: "image/png", SizeBytes: 1001}), AnswerErrorInvalidFile},
+		{"file of another type", upload, files(FileAnswer{URL: png.URL, ContentType: "application/pdf"}), AnswerErrorInvalidFile},
+		{"file without a URL", upload, files(FileAnswer{ContentType: "image/png"}), AnswerErrorInvalidFile},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.answer.ValidateAnswer(test.question)
+			if test.want == "" {
+				if err != nil {
+					t.Fatalf("ValidateAnswer() = %v, want no error", err)
+				}
+				return
+			}
+			var answerErr *AnswerValidationError
+			if !errors.As(err, &answerErr) {
+				t.Fatalf("ValidateAnswer() = %v, want a %s error", err, test.want)
+			}
+			if answerErr.Code != test.want {
+				t.Errorf("ValidateAnswer() code = %s (%s), want %s", answerErr.Code, answerErr.Message, test.want)
+			}
+		})
+	}
+}
+
+// skipLogicSurvey has a required follow-up shown after "yes", and a required
+// question depending on the follow-up. The questions are out of order to
+// check that conditions are settled in question order.
+func skipLogicSurvey() *Survey {
+	return &Survey{Questions: []Question{
+		{BaseModel: BaseModel{ID: 3}, Order: 3, Type: QuestionTypeRating, Required: true,
+			ShowIf: &ConditionalLogic{QuestionID: "2", Operator: ConditionContains, Value: "defi"}},
+		{BaseModel: BaseModel{ID: 1}, Order: 1, Type: QuestionTypeYesNo, Required: true},
+		{BaseModel: BaseModel{ID: 2}, Order: 2, Type: QuestionTypeText, Required: true,
+			ShowIf: &ConditionalLogic{QuestionID: "1", Operator: ConditionEquals, Value: "yes"}},
+		{BaseModel: BaseModel{ID: 4}, Order: 4, Type: QuestionTypeText},
+	}}
+}
+
+func TestMissingRequiredAnswers(t *testing.T) {
+	answer := func(questionID uint, a Answer) Answer {
+		a.QuestionID = questionID
+		return a
+	}
+	yes := answer(1, optionsAnswer("yes_no", "yes"))
+	no := answer(1, optionsAnswer("yes_no", "No"))
+
+	tests := []struct {
+		name    string
+		answers []Answer
+		want    []uint
+	}{
+		{"no answers", nil, []uint{1}},
+		{"follow-up hidden", []Answer{no}, nil},
+		{"follow-up shown", []Answer{yes}, []uint{2}},
+		{"follow-up empty", []Answer{yes, answer(2, textAnswer("text", "  "))}, []uint{2}},
+		{"follow-up skipped", []Answer{yes, {QuestionID: 2, IsSkipped: true, AnswerValue: AnswerValue{Content: "defi"}}}, []uint{2}},
+		{"chained question shown", []Answer{yes, answer(2, textAnswer("text", "I use DeFi"))}, []uint{3}},
+		{"chained question hidden", []Answer{yes, answer(2, textAnswer("text", "I use CeFi"))}, nil},
+		{"skipped question hides its follow-up", []Answer{{QuestionID: 1, IsSkipped: true, AnswerValue: yes.AnswerValue}}, []uint{1}},
+		{"answers to hidden questions don't show them", []Answer{no, answer(2, textAnswer("text", "defi"))}, nil},
+		{"all answered", []Answer{yes, answer(2, textAnswer("text", "defi")), answer(3, ratingAnswer(3))}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			missing := skipLogicSurvey().MissingRequiredAnswers(test.answers)
+			var got []uint
+			for _, err := range missing {
+				if err.Code != AnswerErrorRequired {
+					t.Errorf("question %d has code %s, want %s", err.QuestionID, err.Code, AnswerErrorRequired)
+				}
+				got = append(got, err.QuestionID)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("missing answers to %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAnswerValidationErrorsAppError(t *testing.T) {
+	appErr := skipLogicSurvey().MissingRequiredAnswers(nil).AppError()
+	if len(appErr.Fields) != 1 || appErr.Fields[0].Field != "questions.1" || appErr.Fields[0].Code != AnswerErrorRequired {
+		t.Errorf("fields = %+v, want questions.1 required", appErr.Fields)
+	}
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultLocale is the locale category names fall back to
+const DefaultLocale = "en"
+
+// categorySlugPattern is lowercase words joined by single hyphens
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Category is a survey category managed by admins. Surveys, templates and
+// bank questions refer to a category by its slug, which never changes once
+// created. Categories nest through ParentID.
+type Category struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Slug      string         `json:"slug" gorm:"not null;size:100;uniqueIndex"`
+	Names     LocalizedNames `json:"names" gorm:"type:json;not null"`
+	Icon      string         `json:"icon" gorm:"size:255"`
+	ParentID  *uint          `json:"parent_id" gorm:"index"`
+	SortOrder int            `json:"sort_order" gorm:"default:0"`
+	IsActive  bool           `json:"is_active" gorm:"default:true"`
+}
+
+// LocalizedNames maps a locale such as "en" or "pt-br" to a display name
+type LocalizedNames map[string]string
+
+// IsValidCategorySlug checks if the slug is lowercase letters and digits in
+// hyphen separated words
+func IsValidCategorySlug(slug string) bool {
+	return len(slug) <= 100 && categorySlugPattern.MatchString(slug)
+}
+
+// Name returns the category name in the locale, falling back to the base
+// language, the default locale and then the slug
+func (c *Category) Name(locale string) string {
+	if name := c.Names.Get(locale); name != "" {
+		return name
+	}
+	return c.Slug
+}
+
+// Get returns the name in the locale, its base language ("pt" for "pt-br")
+// or the default locale, and otherwise the name of the first locale in
+// alphabetical order
+func (n LocalizedNames) Get(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if name := n[locale]; name != "" {
+		return name
+	}
+	if base, _, found := strings.Cut(locale, "-"); found {
+		if name := n[base]; name != "" {
+			return name
+		}
+	}
+	if name := n[DefaultLocale]; name != "" {
+		return name
+	}
+
+	locales := make([]string, 0, len(n))
+	for l := range n {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	for _, l := range locales {
+		if n[l] != "" {
+			return n[l]
+		}
+	}
+	return ""
+}
+
+// Value implements driver.Valuer interface for LocalizedNames
+func (n LocalizedNames) Value() (driver.Value, error) {
+	if n == nil {
+		return json.Marshal(map[string]string{})
+	}
+	return json.Marshal(map[string]string(n))
+}
+
+// Scan implements sql.Scanner interface for LocalizedNames
+func (n *LocalizedNames) Scan(value interface{}) error {
+	if value == nil {
+		*n = LocalizedNames{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("cannot scan non-bytes into LocalizedNames")
+	}
+
+	return json.Unmarshal(bytes, n)
+}
+
+// TableName returns the table name for Category
+func (Category) TableName() string {
+	return "categories"
+}
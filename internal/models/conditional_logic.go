@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VisibleQuestions returns the questions, in order, whose display conditions
+// are met by the answers. A condition on a hidden or unanswered question is
+// not met, so questions depending on a hidden question are hidden as well.
+func (s *Survey) VisibleQuestions(answers []Answer) []Question {
+	questions := make([]Question, len(s.Questions))
+	copy(questions, s.Questions)
+	sort.SliceStable(questions, func(i, j int) bool {
+		return questions[i].Order < questions[j].Order
+	})
+
+	answerByQuestion := make(map[uint]*Answer, len(answers))
+	for i := range answers {
+		answerByQuestion[answers[i].QuestionID] = &answers[i]
+	}
+
+	// Conditions only refer to earlier questions, so one pass in order
+	// settles every question
+	visible := make(map[uint]bool, len(questions))
+	result := make([]Question, 0, len(questions))
+	for _, question := range questions {
+		if question.ShowIf != nil {
+			id, err := strconv.ParseUint(question.ShowIf.QuestionID, 10, 32)
+			if err != nil || !visible[uint(id)] || !question.ShowIf.IsMetBy(answerByQuestion[uint(id)]) {
+				continue
+			}
+		}
+		visible[question.ID] = true
+		result = append(result, question)
+	}
+	return result
+}
+
+// IsMetBy checks if the answer to the condition's question meets it. Choice
+// answers match on their selected options, numeric answers compare as
+// numbers and everything else compares as text, ignoring case.
+func (c *ConditionalLogic) IsMetBy(answer *Answer) bool {
+	if answer == nil || answer.IsSkipped || !answer.AnswerValue.HasValue() {
+		return false
+	}
+	expected := strings.TrimSpace(fmt.Sprint(c.Value))
+
+	switch c.Operator {
+	case ConditionEquals:
+		return answerEquals(answer, expected)
+	case ConditionNotEquals:
+		return !answerEquals(answer, expected)
+	case ConditionContains:
+		for _, option := range answer.AnswerValue.Options {
+			if strings.EqualFold(option, expected) {
+				return true
+			}
+		}
+		return strings.Contains(strings.ToLower(answer.AnswerText), strings.ToLower(expected))
+	case ConditionGreaterThan, ConditionLessThan:
+		value, ok := answerNumber(answer)
+		threshold, err := strconv.ParseFloat(expected, 64)
+		if !ok || err != nil {
+			return false
+		}
+		if c.Operator == ConditionGreaterThan {
+			return value > threshold
+		}
+		return value < threshold
+	}
+	return false
+}
+
+func answerEquals(answer *Answer, expected string) bool {
+	if options := answer.AnswerValue.Options; len(options) > 0 {
+		return len(options) == 1 && strings.EqualFold(options[0], expected)
+	}
+	if value, ok := answerNumber(answer); ok {
+		if threshold, err := strconv.ParseFloat(expected, 64); err == nil {
+			return value == threshold
+		}
+	}
+	return strings.EqualFold(strings.TrimSpace(answer.AnswerText), expected)
+}
+
+// answerNumber returns the rating, scale or number of an answer
+func answerNumber(answer *Answer) (float64, bool) {
+	value := answer.AnswerValue
+	switch {
+	case value.Rating != nil:
+		return float64(*value.Rating), true
+	case value.Scale != nil:
+		return float64(*value.Scale), true
+	}
+	switch content := value.Content.(type) {
+	case float64:
+		return content, true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(content), 64)
+		return number, err == nil
+	}
+	return 0, false
+}
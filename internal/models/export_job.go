@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+// ExportJobStatus represents the status of a background response export
+type ExportJobStatus string
+
+const (
+	ExportJobStatusPending    ExportJobStatus = "pending"
+	ExportJobStatusProcessing ExportJobStatus = "processing"
+	ExportJobStatusCompleted  ExportJobStatus = "completed"
+	ExportJobStatusFailed     ExportJobStatus = "failed"
+)
+
+// ExportJob tracks a response export that is too large to stream inline.
+// The finished file is kept on disk until it is downloaded by the requester.
+type ExportJob struct {
+	BaseModel
+	SurveyID    uint            `json:"survey_id" gorm:"not null;index"`
+	RequestedBy uint            `json:"requested_by" gorm:"not null;index"`
+	Format      string          `json:"format" gorm:"not null;size:20"`
+	Status      ExportJobStatus `json:"status" gorm:"default:'pending';index"`
+
+	// Result
+	FileName    string     `json:"file_name" gorm:"size:255"`
+	FilePath    string     `json:"-" gorm:"size:500"`
+	RowCount    int        `json:"row_count" gorm:"default:0"`
+	SizeBytes   int64      `json:"size_bytes" gorm:"default:0"`
+	Error       *string    `json:"error"`
+	CompletedAt *time.Time `json:"completed_at"`
+
+	// Relationships
+	Survey Survey `json:"-" gorm:"foreignKey:SurveyID"`
+}
+
+// MarkAsCompleted records the produced file
+func (j *ExportJob) MarkAsCompleted(rowCount int, sizeBytes int64) {
+	now := time.Now()
+	j.Status = ExportJobStatusCompleted
+	j.RowCount = rowCount
+	j.SizeBytes = sizeBytes
+	j.Error = nil
+	j.CompletedAt = &now
+}
+
+// MarkAsFailed records why the export failed
+func (j *ExportJob) MarkAsFailed(reason string) {
+	now := time.Now()
+	j.Status = ExportJobStatusFailed
+	j.Error = &reason
+	j.CompletedAt = &now
+}
+
+// TableName returns the table name for ExportJob
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
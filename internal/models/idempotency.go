@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// IdempotencyKey is a write a user made with an Idempotency-Key header, so
+// retries with the same key get its response back instead of repeating it.
+// The response is empty until the original request has been handled.
+type IdempotencyKey struct {
+	ID           uint       `json:"-" gorm:"primaryKey"`
+	UserID       uint       `json:"user_id" gorm:"not null;uniqueIndex:idx_idempotency_keys_user_key"`
+	Key          string     `json:"key" gorm:"not null;size:255;uniqueIndex:idx_idempotency_keys_user_key"`
+	Method       string     `json:"method" gorm:"not null;size:10"`
+	Path         string     `json:"path" gorm:"not null;size:255"`
+	Fingerprint  string     `json:"fingerprint" gorm:"not null;size:64"` // SHA-256 of the method, path and body
+	StatusCode   int        `json:"status_code"`
+	ContentType  string     `json:"content_type" gorm:"size:100"`
+	ResponseBody []byte     `json:"-"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null;index"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// IsCompleted checks if the original request has been handled
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.CompletedAt != nil
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Outbox event types
+const (
+	OutboxEventSurveyPublished     = "survey.published"
+	OutboxEventSurveyUpdated       = "survey.updated"
+	OutboxEventSurveyDeleted       = "survey.deleted"
+	OutboxEventResponseStarted     = "response.started"
+	OutboxEventResponseCompleted   = "response.completed"
+	OutboxEventResponseAbandoned   = "response.abandoned"
+	OutboxEventResponseScreenedOut = "response.screened_out"
+	OutboxEventRewardPaid          = "reward.paid"
+	OutboxEventPoolUpdated         = "pool.updated"
+	OutboxEventPoolExhausted       = "pool.exhausted"
+)
+
+// Aggregates outbox events are about
+const (
+	OutboxAggregateSurvey      = "survey"
+	OutboxAggregateResponse    = "response"
+	OutboxAggregateTransaction = "reward_transaction"
+	OutboxAggregatePool        = "reward_pool"
+)
+
+// OutboxEvent is a domain event, recorded in the same database transaction
+// as the state change it describes. Events are numbered in commit order once
+// committed, and relayed to consumers in that order.
+type OutboxEvent struct {
+	ID            uint      `json:"-" gorm:"primaryKey"`
+	EventID       string    `json:"id" gorm:"not null;size:40;uniqueIndex"`
+	Sequence      *uint64   `json:"sequence" gorm:"uniqueIndex"` // nil until the dispatcher numbers the event
+	Type          string    `json:"type" gorm:"not null;size:50;index"`
+	AggregateType string    `json:"aggregate_type" gorm:"not null;size:50"`
+	AggregateID   uint      `json:"aggregate_id" gorm:"not null"`
+	SurveyID      uint      `json:"survey_id" gorm:"not null;index"`
+	Payload       string    `json:"-" gorm:"type:text;not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"index"`
+}
+
+// NewOutboxEvent builds an event with a new event ID and data as its JSON payload
+func NewOutboxEvent(eventType, aggregateType string, aggregateID, surveyID uint, data interface{}) (OutboxEvent, error) {
+	eventID, err := NewEventID()
+	if err != nil {
+		return OutboxEvent{}, err
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return OutboxEvent{}, err
+	}
+
+	return OutboxEvent{
+		EventID:       eventID,
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		SurveyID:      surveyID,
+		Payload:       string(payload),
+	}, nil
+}
+
+// TableName returns the table name for OutboxEvent
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// OutboxOffset is the sequence of the last event a durable outbox consumer
+// has processed
+type OutboxOffset struct {
+	Consumer  string    `json:"consumer" gorm:"primaryKey;size:100"`
+	Sequence  uint64    `json:"sequence" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for OutboxOffset
+func (OutboxOffset) TableName() string {
+	return "outbox_offsets"
+}
+
+// NewEventID generates a random event ID. Consumers can use it to ignore
+// events they have already seen.
+func NewEventID() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "evt_" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// QuotaMetadataField represents a response metadata field a quota can be keyed by
+type QuotaMetadataField string
+
+const (
+	QuotaMetadataLanguage QuotaMetadataField = "language"
+	QuotaMetadataTimezone QuotaMetadataField = "timezone"
+)
+
+// ErrQuotaFull is returned when a quota has no remaining capacity
+var ErrQuotaFull = errors.New("quota is full")
+
+// SurveyQuota caps the number of completed responses matching a condition.
+// A quota is keyed either by a screener answer (QuestionID + Value) or by a
+// response metadata field (MetadataField + Value).
+type SurveyQuota struct {
+	BaseModel
+	SurveyID uint   `json:"survey_id" gorm:"not null;index"`
+	Name     string `json:"name" gorm:"not null;size:100"`
+
+	// Quota Condition
+	QuestionID    *uint               `json:"question_id" gorm:"index"`
+	MetadataField *QuotaMetadataField `json:"metadata_field" gorm:"size:50"`
+	Value         string              `json:"value" gorm:"not null"`
+
+	// Quota Limits (Limit takes precedence over Percentage)
+	Limit        *int     `json:"limit"`
+	Percentage   *float64 `json:"percentage"` // share of Survey.MaxResponses
+	CurrentCount int      `json:"current_count" gorm:"default:0"`
+	IsActive     bool     `json:"is_active" gorm:"default:true"`
+
+	// Relationships
+	Survey Survey `json:"-" gorm:"foreignKey:SurveyID"`
+}
+
+// Validate checks that the quota has exactly one condition and one limit
+func (q *SurveyQuota) Validate() error {
+	if (q.QuestionID == nil) == (q.MetadataField == nil) {
+		return errors.New("quota must be keyed by either a question or a metadata field")
+	}
+	if q.MetadataField != nil {
+		switch *q.MetadataField {
+		case QuotaMetadataLanguage, QuotaMetadataTimezone:
+		default:
+			return errors.New("unsupported quota metadata field")
+		}
+	}
+	if q.Value == "" {
+		return errors.New("quota value is required")
+	}
+	if q.Limit == nil && q.Percentage == nil {
+		return errors.New("quota must define a limit or a percentage")
+	}
+	if q.Limit != nil && *q.Limit < 0 {
+		return errors.New("quota limit cannot be negative")
+	}
+	if q.Percentage != nil && (*q.Percentage <= 0 || *q.Percentage > 100) {
+		return errors.New("quota percentage must be between 0 and 100")
+	}
+	return nil
+}
+
+// Capacity returns the maximum number of completed responses allowed by the quota
+func (q *SurveyQuota) Capacity(maxResponses int) int {
+	if q.Limit != nil {
+		return *q.Limit
+	}
+	if q.Percentage != nil {
+		// Dividing last keeps whole shares exact: 7 / 100 * 100 rounds up to 8
+		return int(math.Ceil(*q.Percentage * float64(maxResponses) / 100))
+	}
+	return maxResponses
+}
+
+// IsFull checks if the quota has no remaining capacity
+func (q *SurveyQuota) IsFull(maxResponses int) bool {
+	return q.IsActive && q.CurrentCount >= q.Capacity(maxResponses)
+}
+
+// FillRate returns the quota fill level as a percentage (0-100)
+func (q *SurveyQuota) FillRate(maxResponses int) float64 {
+	capacity := q.Capacity(maxResponses)
+	if capacity == 0 {
+		return 100
+	}
+	rate := float64(q.CurrentCount) / float64(capacity) * 100
+	if rate > 100 {
+		rate = 100
+	}
+	return rate
+}
+
+// IsAnswerQuota checks if the quota is keyed by a screener answer
+func (q *SurveyQuota) IsAnswerQuota() bool {
+	return q.QuestionID != nil
+}
+
+// Matches checks if the response falls into the quota. For answer quotas the
+// answers given so far are used; a quota whose question is still unanswered
+// does not match.
+func (q *SurveyQuota) Matches(response *Response, answers []Answer) bool {
+	if !q.IsActive {
+		return false
+	}
+
+	if q.MetadataField != nil {
+		switch *q.MetadataField {
+		case QuotaMetadataLanguage:
+			return strings.EqualFold(response.Language, q.Value)
+		case QuotaMetadataTimezone:
+			return strings.EqualFold(response.Timezone, q.Value)
+		}
+		return false
+	}
+
+	for _, answer := range answers {
+		if answer.QuestionID != *q.QuestionID || answer.IsSkipped {
+			continue
+		}
+		for _, option := range answer.AnswerValue.Options {
+			if option == q.Value {
+				return true
+			}
+		}
+		return answer.AnswerText == q.Value
+	}
+	return false
+}
+
+// TableName returns the table name for SurveyQuota
+func (SurveyQuota) TableName() string {
+	return "survey_quotas"
+}
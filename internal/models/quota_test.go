@@ -0,0 +1,168 @@
+package models
+
+import "testing"
+
+func uintPtr(v uint) *uint { return &v }
+
+func metadataField(field QuotaMetadataField) *QuotaMetadataField { return &field }
+
+func TestQuotaCapacity(t *testing.T) {
+	tests := []struct {
+		name         string
+		quota        SurveyQuota
+		maxResponses int
+		want         int
+	}{
+		{"limit", SurveyQuota{Limit: intPtr(50)}, 100, 50},
+		{"limit over a percentage", SurveyQuota{Limit: intPtr(50), Percentage: floatPtr(10)}, 100, 50},
+		{"zero limit", SurveyQuota{Limit: intPtr(0)}, 100, 0},
+		{"whole percentage", SurveyQuota{Percentage: floatPtr(7)}, 100, 7},
+		{"whole percentage of more responses", SurveyQuota{Percentage: floatPtr(29)}, 300, 87},
+		{"percentage rounded up", SurveyQuota{Percentage: floatPtr(10)}, 95, 10},
+		{"fractional percentage", SurveyQuota{Percentage: floatPtr(12.5)}, 8, 1},
+		{"tiny percentage", SurveyQuota{Percentage: floatPtr(0.1)}, 10, 1},
+		{"all responses", SurveyQuota{Percentage: floatPtr(100)}, 200, 200},
+		{"no limit", SurveyQuota{}, 100, 100},
+	}
+	for _, test := range tests {
+		if got := test.quota.Capacity(test.maxResponses); got != test.want {
+			t.Errorf("%s: Capacity(%d) = %d, want %d", test.name, test.maxResponses, got, test.want)
+		}
+	}
+
+	// Whole percentages of whole responses are never rounded up past the share
+	for percentage := 1; percentage <= 100; percentage++ {
+		quota := SurveyQuota{Percentage: floatPtr(float64(percentage))}
+		for maxResponses := 1; maxResponses <= 1000; maxResponses++ {
+			if got, want := quota.Capacity(maxResponses), (percentage*maxResponses+99)/100; got != want {
+				t.Fatalf("%d%% of %d responses: Capacity = %d, want %d", percentage, maxResponses, got, want)
+			}
+		}
+	}
+}
+
+func TestQuotaIsFull(t *testing.T) {
+	tests := []struct {
+		name  string
+		quota SurveyQuota
+		want  bool
+	}{
+		{"below the limit", SurveyQuota{IsActive: true, Limit: intPtr(3), CurrentCount: 2}, false},
+		{"at the limit", SurveyQuota{IsActive: true, Limit: intPtr(3), CurrentCount: 3}, true},
+		{"over the limit", SurveyQuota{IsActive: true, Limit: intPtr(3), CurrentCount: 4}, true},
+		{"zero limit", SurveyQuota{IsActive: true, Limit: intPtr(0)}, true},
+		{"below the percentage", SurveyQuota{IsActive: true, Percentage: floatPtr(7), CurrentCount: 6}, false},
+		{"at the percentage", SurveyQuota{IsActive: true, Percentage: floatPtr(7), CurrentCount: 7}, true},
+		{"inactive at the limit", SurveyQuota{Limit: intPtr(3), CurrentCount: 3}, false},
+	}
+	for _, test := range tests {
+		if got := test.quota.IsFull(100); got != test.want {
+			t.Errorf("%s: IsFull() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestQuotaFillRate(t *testing.T) {
+	tests := []struct {
+		quota SurveyQuota
+		want  float64
+	}{
+		{SurveyQuota{Limit: intPtr(4), CurrentCount: 3}, 75},
+		{SurveyQuota{Limit: intPtr(4), CurrentCount: 6}, 100},
+		{SurveyQuota{Limit: intPtr(0)}, 100},
+		{SurveyQuota{Percentage: floatPtr(50)}, 0},
+	}
+	for _, test := range tests {
+		if got := test.quota.FillRate(10); got != test.want {
+			t.Errorf("FillRate() of %d/%d = %v, want %v", test.quota.CurrentCount, test.quota.Capacity(10), got, test.want)
+		}
+	}
+}
+
+func TestQuotaValidate(t *testing.T) {
+	valid := SurveyQuota{QuestionID: uintPtr(1), Value: "daily", Limit: intPtr(10)}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() = %v for a valid quota", err)
+	}
+
+	tests := map[string]func(q *SurveyQuota){
+		"no condition":          func(q *SurveyQuota) { q.QuestionID = nil },
+		"two conditions":        func(q *SurveyQuota) { q.MetadataField = metadataField(QuotaMetadataLanguage) },
+		"unknown metadata":      func(q *SurveyQuota) { q.QuestionID, q.MetadataField = nil, metadataField("country") },
+		"no value":              func(q *SurveyQuota) { q.Value = "" },
+		"no limit":              func(q *SurveyQuota) { q.Limit = nil },
+		"negative limit":        func(q *SurveyQuota) { q.Limit = intPtr(-1) },
+		"zero percentage":       func(q *SurveyQuota) { q.Limit, q.Percentage = nil, floatPtr(0) },
+		"percentage over a 100": func(q *SurveyQuota) { q.Limit, q.Percentage = nil, floatPtr(100.5) },
+	}
+	for name, invalidate := range tests {
+		quota := valid
+		invalidate(&quota)
+		if err := quota.Validate(); err == nil {
+			t.Errorf("%s: Validate() = nil, want an error", name)
+		}
+	}
+}
+
+func TestQuotaMatches(t *testing.T) {
+	response := &Response{Language: "PT", Timezone: "Europe/Lisbon"}
+	answerQuota := SurveyQuota{IsActive: true, QuestionID: uintPtr(5), Value: "daily"}
+	withOption := func(questionID uint, options ...string) Answer {
+		return withQuestion(questionID, optionsAnswer("single_choice", options...))
+	}
+
+	tests := []struct {
+		name    string
+		quota   SurveyQuota
+		answers []Answer
+		want    bool
+	}{
+		{"language, ignoring case", SurveyQuota{IsActive: true, MetadataField: metadataField(QuotaMetadataLanguage), Value: "pt"}, nil, true},
+		{"another language", SurveyQuota{IsActive: true, MetadataField: metadataField(QuotaMetadataLanguage), Value: "en"}, nil, false},
+		{"timezone", SurveyQuota{IsActive: true, MetadataField: metadataField(QuotaMetadataTimezone), Value: "europe/lisbon"}, nil, true},
+		{"unknown metadata", SurveyQuota{IsActive: true, MetadataField: metadataField("country"), Value: "PT"}, nil, false},
+		{"inactive", SurveyQuota{MetadataField: metadataField(QuotaMetadataLanguage), Value: "pt"}, nil, false},
+
+		{"selected option", answerQuota, []Answer{withOption(4, "daily"), withOption(5, "weekly", "daily")}, true},
+		{"other option", answerQuota, []Answer{withOption(5, "weekly")}, false},
+		{"option of another question", answerQuota, []Answer{withOption(4, "daily")}, false},
+		{"text answer", answerQuota, []Answer{{QuestionID: 5, AnswerText: "daily"}}, true},
+		{"text answer in another case", answerQuota, []Answer{{QuestionID: 5, AnswerText: "Daily"}}, false},
+		{"skipped", answerQuota, []Answer{{QuestionID: 5, IsSkipped: true, AnswerText: "daily"}}, false},
+		{"unanswered", answerQuota, nil, false},
+	}
+	for _, test := range tests {
+		if got := test.quota.Matches(response, test.answers); got != test.want {
+			t.Errorf("%s: Matches() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSurveyFullQuota(t *testing.T) {
+	survey := &Survey{MaxResponses: 100, Quotas: []SurveyQuota{
+		{BaseModel: BaseModel{ID: 1}, IsActive: true, MetadataField: metadataField(QuotaMetadataLanguage), Value: "en", Limit: intPtr(10), CurrentCount: 9},
+		{BaseModel: BaseModel{ID: 2}, IsActive: true, MetadataField: metadataField(QuotaMetadataLanguage), Value: "fr", Percentage: floatPtr(5), CurrentCount: 5},
+		{BaseModel: BaseModel{ID: 3}, IsActive: true, QuestionID: uintPtr(1), Value: "daily", Limit: intPtr(50)},
+	}}
+	daily := withQuestion(1, optionsAnswer("single_choice", "daily"))
+
+	if full := survey.FullQuota(&Response{Language: "en"}, []Answer{daily}); full != nil {
+		t.Errorf("FullQuota() = %d with one place left, want none", full.ID)
+	}
+	if matched := survey.MatchingQuotas(&Response{Language: "en"}, []Answer{daily}); len(matched) != 2 {
+		t.Errorf("%d quotas match, want the language and answer quotas", len(matched))
+	}
+	if full := survey.FullQuota(&Response{Language: "fr"}, nil); full == nil || full.ID != 2 {
+		t.Errorf("FullQuota() = %v, want the full French quota", full)
+	}
+
+	survey.Quotas[0].CurrentCount = 10
+	if full := survey.FullQuota(&Response{Language: "en"}, nil); full == nil || full.ID != 1 {
+		t.Errorf("FullQuota() = %v at the limit, want the English quota", full)
+	}
+}
+
+func withQuestion(questionID uint, answer Answer) Answer {
+	answer.QuestionID = questionID
+	return answer
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -14,6 +16,7 @@ const (
 	ResponseStatusStarted   ResponseStatus = "started"
 	ResponseStatusCompleted ResponseStatus = "completed"
 	ResponseStatusAbandoned ResponseStatus = "abandoned"
+	ResponseStatusScreenedOut ResponseStatus = "screened_out"
 )
 
 // Response represents a user's response to a survey
@@ -27,6 +30,7 @@ type Response struct {
 	StartedAt     time.Time        `json:"started_at" gorm:"not null"`
 	CompletedAt   *time.Time       `json:"completed_at"`
 	Duration      int              `json:"duration"` // in seconds
+	Deadline      *time.Time       `json:"deadline"`  // end of the survey's time limit, nil without one
 	
 	// Response Metadata
 	IPAddress     string           `json:"ip_address"`
@@ -34,6 +38,11 @@ type Response struct {
 	Timezone      string           `json:"timezone"`
 	Language      string           `json:"language" gorm:"default:'en'"`
 	
+	// Autosave: Version moves on with every change of the answers, so
+	// clients editing the same response from two places notice each other
+	Version        int             `json:"version" gorm:"not null;default:1"`
+	LastActivityAt *time.Time      `json:"last_activity_at"`
+	
 	// Quality Metrics
 	QualityScore  float64          `json:"quality_score" gorm:"default:0"`
 	IsValid       bool             `json:"is_valid" gorm:"default:true"`
@@ -73,6 +82,20 @@ type AnswerValue struct {
 	Rating     *int        `json:"rating"`   // Rating value
 	Scale      *int        `json:"scale"`    // Scale value
 	Date       *time.Time  `json:"date"`     // Date value
+	
+	// Rich answer structures
+	Matrix      map[string]string  `json:"matrix,omitempty"`      // matrix row ID -> selected column value
+	Ranking     []string           `json:"ranking,omitempty"`     // option values, most preferred first
+	Allocations map[string]float64 `json:"allocations,omitempty"` // constant-sum option value -> amount
+	Files       []FileAnswer       `json:"files,omitempty"`       // uploaded files
+}
+
+// FileAnswer represents an uploaded file referenced by an answer
+type FileAnswer struct {
+	URL         string `json:"url"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
 }
 
 // ResponseSummary represents a summary of responses for analytics
@@ -115,17 +138,51 @@ func (r *Response) IsCompleted() bool {
 	return r.Status == ResponseStatusCompleted && r.CompletedAt != nil
 }
 
-// CalculateDuration calculates the duration of the response
+// CalculateDuration calculates the duration of the response. Time past the
+// deadline doesn't count.
 func (r *Response) CalculateDuration() int {
 	if r.CompletedAt != nil {
 		return int(r.CompletedAt.Sub(r.StartedAt).Seconds())
 	}
-	return int(time.Since(r.StartedAt).Seconds())
+	end := time.Now()
+	if r.Deadline != nil && end.After(*r.Deadline) {
+		end = *r.Deadline
+	}
+	return int(end.Sub(r.StartedAt).Seconds())
+}
+
+// PastDeadline checks if the survey's time limit is up for the response
+func (r *Response) PastDeadline() bool {
+	return r.Deadline != nil && !time.Now().Before(*r.Deadline)
+}
+
+// LastActivity returns when the respondent last changed the response, or
+// started it
+func (r *Response) LastActivity() time.Time {
+	if r.LastActivityAt != nil && r.LastActivityAt.After(r.StartedAt) {
+		return *r.LastActivityAt
+	}
+	return r.StartedAt
+}
+
+// LastAnsweredAt returns when an answer was last saved, or nil without answers
+func (r *Response) LastAnsweredAt() *time.Time {
+	var last *time.Time
+	for i := range r.Answers {
+		if last == nil || r.Answers[i].UpdatedAt.After(*last) {
+			last = &r.Answers[i].UpdatedAt
+		}
+	}
+	return last
 }
 
-// MarkAsCompleted marks the response as completed
+// MarkAsCompleted marks the response as completed, at the deadline at the
+// latest
 func (r *Response) MarkAsCompleted() {
 	now := time.Now()
+	if r.Deadline != nil && now.After(*r.Deadline) {
+		now = *r.Deadline
+	}
 	r.Status = ResponseStatusCompleted
 	r.CompletedAt = &now
 	r.Duration = r.CalculateDuration()
@@ -137,6 +194,13 @@ func (r *Response) MarkAsAbandoned() {
 	r.Duration = r.CalculateDuration()
 }
 
+// MarkAsScreenedOut marks the response as screened out by a full quota
+func (r *Response) MarkAsScreenedOut(reason string) {
+	r.Status = ResponseStatusScreenedOut
+	r.FlaggedReason = &reason
+	r.Duration = r.CalculateDuration()
+}
+
 // GetAnswerByQuestionID finds an answer by question ID
 func (r *Response) GetAnswerByQuestionID(questionID uint) (*Answer, error) {
 	for _, answer := range r.Answers {
@@ -147,33 +211,71 @@ func (r *Response) GetAnswerByQuestionID(questionID uint) (*Answer, error) {
 	return nil, errors.New("answer not found")
 }
 
-// ValidateAnswer validates an answer based on question requirements
+// ValidateAnswer validates an answer based on question requirements.
+// Any returned error is an *AnswerValidationError.
 func (a *Answer) ValidateAnswer(question *Question) error {
-	if question.Required && (a.IsSkipped || a.AnswerText == "") {
-		return errors.New("answer is required")
+	if a.IsSkipped || !a.AnswerValue.HasValue() {
+		if question.Required {
+			return newAnswerError(question, AnswerErrorRequired, "answer is required")
+		}
+		return nil
+	}
+	
+	if !question.AcceptsAnswerType(a.AnswerValue.Type) {
+		return newAnswerError(question, AnswerErrorTypeMismatch,
+			fmt.Sprintf("answer type %q does not match question type %q", a.AnswerValue.Type, question.Type))
 	}
 	
 	// Additional validation based on question type
 	switch question.Type {
 	case QuestionTypeText, QuestionTypeTextArea:
-		if question.MinLength != nil && len(a.AnswerText) < *question.MinLength {
-			return errors.New("answer too short")
-		}
-		if question.MaxLength != nil && len(a.AnswerText) > *question.MaxLength {
-			return errors.New("answer too long")
-		}
-	case QuestionTypeRating, QuestionTypeScale:
-		if a.AnswerValue.Rating != nil {
-			if question.MinValue != nil && float64(*a.AnswerValue.Rating) < *question.MinValue {
-				return errors.New("rating below minimum")
-			}
-			if question.MaxValue != nil && float64(*a.AnswerValue.Rating) > *question.MaxValue {
-				return errors.New("rating above maximum")
-			}
+		return a.validateTextLength(question)
+	case QuestionTypeSingleChoice:
+		return a.validateChoices(question, true)
+	case QuestionTypeMultipleChoice:
+		return a.validateChoices(question, false)
+	case QuestionTypeYesNo:
+		return a.validateYesNo(question)
+	case QuestionTypeRating:
+		return a.validateIntRange(question, a.AnswerValue.Rating, "rating")
+	case QuestionTypeScale:
+		scale := a.AnswerValue.Scale
+		if scale == nil {
+			scale = a.AnswerValue.Rating
 		}
+		return a.validateIntRange(question, scale, "scale")
+	case QuestionTypeNumber:
+		return a.validateNumber(question)
+	case QuestionTypeDate:
+		return a.validateDate(question)
+	case QuestionTypeNPS:
+		return a.validateNPS(question)
+	case QuestionTypeEmail, QuestionTypeURL, QuestionTypePhone:
+		return a.validateContactText(question)
+	case QuestionTypeMatrix:
+		return a.validateMatrix(question)
+	case QuestionTypeRanking:
+		return a.validateRanking(question)
+	case QuestionTypeConstantSum:
+		return a.validateConstantSum(question)
+	case QuestionTypeFileUpload:
+		return a.validateFiles(question)
 	}
 	
-	return nil
+	return newAnswerError(question, AnswerErrorUnsupportedType, fmt.Sprintf("unsupported question type %q", question.Type))
+}
+
+// HasValue checks if the answer value carries any content
+func (av AnswerValue) HasValue() bool {
+	if str, ok := av.Content.(string); ok {
+		if strings.TrimSpace(str) != "" {
+			return true
+		}
+	} else if av.Content != nil {
+		return true
+	}
+	return len(av.Options) > 0 || av.Rating != nil || av.Scale != nil || av.Date != nil ||
+		len(av.Matrix) > 0 || len(av.Ranking) > 0 || len(av.Allocations) > 0 || len(av.Files) > 0
 }
 
 // TableName returns the table name for Response
@@ -1,7 +1,7 @@
 package models
 
 import (
-	"errors"
+	"survey2earn-backend/internal/apperror"
 	"time"
 )
 
@@ -16,6 +16,12 @@ const (
 	TransactionStatusCancelled TransactionStatus = "cancelled"
 )
 
+// MaxTransactionRetries is how many times a failed transaction is retried
+const MaxTransactionRetries = 3
+
+// ErrPoolExhausted is returned when a pool can't pay another reward
+var ErrPoolExhausted = apperror.New(apperror.PoolExhausted, "The survey's reward pool is exhausted")
+
 // TransactionType represents the type of transaction
 type TransactionType string
 
@@ -110,7 +116,7 @@ func (rp *RewardPool) CanProcessReward() bool {
 
 func (rp *RewardPool) ProcessReward() error {
 	if !rp.CanProcessReward() {
-		return errors.New("cannot process reward: insufficient funds or pool inactive")
+		return ErrPoolExhausted
 	}
 	
 	rp.CurrentResponses++
@@ -152,7 +158,7 @@ func (rt *RewardTransaction) MarkAsFailed(reason string) {
 
 // CanRetry checks if the transaction can be retried
 func (rt *RewardTransaction) CanRetry() bool {
-	return rt.Status == TransactionStatusFailed && rt.RetryCount < 3
+	return rt.Status == TransactionStatusFailed && rt.RetryCount < MaxTransactionRetries
 }
 
 // UpdateBalance updates the user balance
@@ -4,6 +4,8 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -31,6 +33,33 @@ const (
 	QuestionTypeScale          QuestionType = "scale"
 	QuestionTypeDate           QuestionType = "date"
 	QuestionTypeNumber         QuestionType = "number"
+	QuestionTypeMatrix         QuestionType = "matrix"
+	QuestionTypeRanking        QuestionType = "ranking"
+	QuestionTypeConstantSum    QuestionType = "constant_sum"
+	QuestionTypeNPS            QuestionType = "nps"
+	QuestionTypeEmail          QuestionType = "email"
+	QuestionTypeURL            QuestionType = "url"
+	QuestionTypePhone          QuestionType = "phone"
+	QuestionTypeFileUpload     QuestionType = "file_upload"
+)
+
+// answerTypeAliases lists the generic answer types accepted for a question
+// type in addition to the question type itself
+var answerTypeAliases = map[QuestionType][]string{
+	QuestionTypeSingleChoice:   {"array"},
+	QuestionTypeMultipleChoice: {"array"},
+	QuestionTypeText:           {"textarea"},
+	QuestionTypeTextArea:       {"text"},
+	QuestionTypeYesNo:          {"boolean"},
+	QuestionTypeEmail:          {"text"},
+	QuestionTypeURL:            {"text"},
+	QuestionTypePhone:          {"text"},
+}
+
+// NPS scores always range from 0 to 10
+const (
+	NPSMinScore = 0
+	NPSMaxScore = 10
 )
 
 // Survey represents a survey
@@ -40,6 +69,7 @@ type Survey struct {
 	Title             string         `json:"title" gorm:"not null;size:255"`
 	Description       string         `json:"description" gorm:"type:text"`
 	Category          string         `json:"category" gorm:"not null;size:100;index"`
+	Language          string         `json:"language" gorm:"not null;size:10;default:'en'"`
 	Status            SurveyStatus   `json:"status" gorm:"default:'draft';index"`
 	
 	// Survey Configuration
@@ -52,6 +82,7 @@ type Survey struct {
 	StartDate         *time.Time     `json:"start_date"`
 	EndDate           *time.Time     `json:"end_date"`
 	EstimatedDuration int            `json:"estimated_duration"` // in minutes
+	TimeLimitMinutes  *int           `json:"time_limit_minutes"` // hard limit per response, nil without one
 	
 	// Survey Settings
 	IsAnonymous       bool           `json:"is_anonymous" gorm:"default:true"`
@@ -64,11 +95,33 @@ type Survey struct {
 	CompletionRate    float64        `json:"completion_rate" gorm:"default:0"`
 	AverageRating     float64        `json:"average_rating" gorm:"default:0"`
 	
+	// Full-text search document the database generates from the title and
+	// description, stemmed for the survey's language. Never read or written.
+	SearchVector      string         `json:"-" gorm:"type:tsvector;->:false;index:idx_surveys_search_vector,type:gin"`
+	
 	// Relationships
 	Creator           User           `json:"creator" gorm:"foreignKey:CreatorID"`
 	Questions         []Question     `json:"questions" gorm:"foreignKey:SurveyID;constraint:OnDelete:CASCADE"`
 	Responses         []Response     `json:"responses,omitempty" gorm:"foreignKey:SurveyID"`
 	RewardPool        *RewardPool    `json:"reward_pool,omitempty" gorm:"foreignKey:SurveyID"`
+	Quotas            []SurveyQuota  `json:"quotas,omitempty" gorm:"foreignKey:SurveyID;constraint:OnDelete:CASCADE"`
+}
+
+// surveyLanguagePattern matches a base language code such as "en" or "pt"
+var surveyLanguagePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// NormalizeSurveyLanguage reduces a language tag such as "pt-BR" to its base
+// language, DefaultLocale when the tag is empty. It reports false for tags
+// that don't start with a language code.
+func NormalizeSurveyLanguage(tag string) (string, bool) {
+	base := strings.ToLower(strings.TrimSpace(tag))
+	if base == "" {
+		return DefaultLocale, true
+	}
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	return base, surveyLanguagePattern.MatchString(base)
 }
 
 // Question represents a question in a survey
@@ -88,8 +141,13 @@ type Question struct {
 	MinValue     *float64           `json:"min_value"`
 	MaxValue     *float64           `json:"max_value"`
 	
+	// Rich Question Configuration
+	Rows         QuestionOptions    `json:"rows" gorm:"type:json"`        // matrix rows; Options are the columns
+	SumTotal     *float64           `json:"sum_total"`                    // constant-sum total to allocate
+	FileConfig   *FileUploadConfig  `json:"file_config" gorm:"type:json"` // file upload constraints
+	
 	// Conditional Logic
-	ShowIf       *ConditionalLogic  `json:"show_if" gorm:"type:json"`
+	ShowIf       *ConditionalLogic  `json:"show_if" gorm:"type:json;serializer:json"`
 	
 	// Relationships
 	Survey       Survey             `json:"survey" gorm:"foreignKey:SurveyID"`
@@ -106,6 +164,13 @@ type QuestionOption struct {
 	Order int    `json:"order"`
 }
 
+// FileUploadConfig represents the constraints for a file upload question
+type FileUploadConfig struct {
+	AllowedTypes []string `json:"allowed_types"` // MIME types, e.g. image/png
+	MaxSizeBytes int64    `json:"max_size_bytes"`
+	MaxFiles     int      `json:"max_files"`
+}
+
 // ConditionalLogic represents conditional logic for showing questions
 type ConditionalLogic struct {
 	QuestionID string      `json:"question_id"`
@@ -113,6 +178,24 @@ type ConditionalLogic struct {
 	Value      interface{} `json:"value"`
 }
 
+// Conditional logic operators
+const (
+	ConditionEquals      = "equals"
+	ConditionNotEquals   = "not_equals"
+	ConditionContains    = "contains"
+	ConditionGreaterThan = "greater_than"
+	ConditionLessThan    = "less_than"
+)
+
+// IsValidConditionOperator checks if the operator is supported by conditional logic
+func IsValidConditionOperator(operator string) bool {
+	switch operator {
+	case ConditionEquals, ConditionNotEquals, ConditionContains, ConditionGreaterThan, ConditionLessThan:
+		return true
+	}
+	return false
+}
+
 // QuestionOptionsValue implements driver.Valuer interface for QuestionOptions
 func (qo QuestionOptions) Value() (driver.Value, error) {
 	return json.Marshal(qo)
@@ -133,25 +216,75 @@ func (qo *QuestionOptions) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, qo)
 }
 
-// ConditionalLogicValue implements driver.Valuer interface for ConditionalLogic
-// func (cl ConditionalLogic) Value() (driver.Value, error) {
-// 	return json.Marshal(cl)
-// }
+// Value implements driver.Valuer interface for FileUploadConfig
+func (fc FileUploadConfig) Value() (driver.Value, error) {
+	return json.Marshal(fc)
+}
 
-// Scan implements sql.Scanner interface for ConditionalLogic
-func (cl *ConditionalLogic) Scan(value interface{}) error {
+// Scan implements sql.Scanner interface for FileUploadConfig
+func (fc *FileUploadConfig) Scan(value interface{}) error {
 	if value == nil {
 		return nil
 	}
 	
 	bytes, ok := value.([]byte)
 	if !ok {
-		return errors.New("cannot scan non-bytes into ConditionalLogic")
+		return errors.New("cannot scan non-bytes into FileUploadConfig")
 	}
 	
-	return json.Unmarshal(bytes, cl)
+	return json.Unmarshal(bytes, fc)
 }
 
+// IsValid checks if the type is one of the supported question types
+func (qt QuestionType) IsValid() bool {
+	switch qt {
+	case QuestionTypeMultipleChoice, QuestionTypeSingleChoice, QuestionTypeText,
+		QuestionTypeTextArea, QuestionTypeRating, QuestionTypeYesNo,
+		QuestionTypeScale, QuestionTypeDate, QuestionTypeNumber,
+		QuestionTypeMatrix, QuestionTypeRanking, QuestionTypeConstantSum,
+		QuestionTypeNPS, QuestionTypeEmail, QuestionTypeURL,
+		QuestionTypePhone, QuestionTypeFileUpload:
+		return true
+	}
+	return false
+}
+
+// AcceptsAnswerType checks if an answer of the given type can answer the question
+func (q *Question) AcceptsAnswerType(answerType string) bool {
+	if answerType == string(q.Type) {
+		return true
+	}
+	for _, alias := range answerTypeAliases[q.Type] {
+		if answerType == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// HasOption checks if the question defines an option with the given value
+func (q *Question) HasOption(value string) bool {
+	for _, option := range q.Options {
+		if option.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRow checks if the matrix question defines a row with the given ID
+func (q *Question) HasRow(rowID string) bool {
+	for _, row := range q.Rows {
+		if row.ID == rowID {
+			return true
+		}
+	}
+	return false
+}
+
+// ConditionalLogic cannot implement driver.Valuer because of its Value field,
+// so Question.ShowIf is stored with GORM's json serializer instead
+
 // IsActive checks if the survey is currently active
 func (s *Survey) IsActive() bool {
 	now := time.Now()
@@ -195,6 +328,47 @@ func (s *Survey) GetQuestionByID(questionID uint) (*Question, error) {
 	return nil, errors.New("question not found")
 }
 
+// MatchingQuotas returns the active quotas the response falls into
+func (s *Survey) MatchingQuotas(response *Response, answers []Answer) []SurveyQuota {
+	var matched []SurveyQuota
+	for _, quota := range s.Quotas {
+		if quota.Matches(response, answers) {
+			matched = append(matched, quota)
+		}
+	}
+	return matched
+}
+
+// FullQuota returns the first full quota the response falls into, if any
+func (s *Survey) FullQuota(response *Response, answers []Answer) *SurveyQuota {
+	for _, quota := range s.MatchingQuotas(response, answers) {
+		if quota.IsFull(s.MaxResponses) {
+			return &quota
+		}
+	}
+	return nil
+}
+
+// MissingRequiredAnswers reports the required questions the answers make
+// visible that have no answer, or only a skipped or empty one. A response
+// can't be completed while any are missing.
+func (s *Survey) MissingRequiredAnswers(answers []Answer) AnswerValidationErrors {
+	answered := make(map[uint]bool, len(answers))
+	for _, answer := range answers {
+		if !answer.IsSkipped && answer.AnswerValue.HasValue() {
+			answered[answer.QuestionID] = true
+		}
+	}
+
+	var missing AnswerValidationErrors
+	for _, question := range s.VisibleQuestions(answers) {
+		if question.Required && !answered[question.ID] {
+			missing = append(missing, *newAnswerError(&question, AnswerErrorRequired, "answer is required"))
+		}
+	}
+	return missing
+}
+
 // TableName returns the table name for Survey
 func (Survey) TableName() string {
 	return "surveys"
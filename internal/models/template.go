@@ -0,0 +1,149 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// QuestionDefinition is a survey-independent question definition shared by
+// the question bank and survey templates
+type QuestionDefinition struct {
+	Type        QuestionType      `json:"type"`
+	Text        string            `json:"text"`
+	Description string            `json:"description"`
+	Options     QuestionOptions   `json:"options"`
+	Required    bool              `json:"required"`
+	Order       int               `json:"order"`
+	MinLength   *int              `json:"min_length,omitempty"`
+	MaxLength   *int              `json:"max_length,omitempty"`
+	MinValue    *float64          `json:"min_value,omitempty"`
+	MaxValue    *float64          `json:"max_value,omitempty"`
+	Rows        QuestionOptions   `json:"rows,omitempty"`
+	SumTotal    *float64          `json:"sum_total,omitempty"`
+	FileConfig  *FileUploadConfig `json:"file_config,omitempty"`
+}
+
+// QuestionDefinitions represents an ordered list of question definitions
+type QuestionDefinitions []QuestionDefinition
+
+// BankQuestion is a reusable question in a creator's personal question bank,
+// or in the platform-wide bank when OwnerID is nil
+type BankQuestion struct {
+	BaseModel
+	OwnerID    *uint              `json:"owner_id" gorm:"index"`
+	Category   string             `json:"category" gorm:"size:100;index"`
+	Definition QuestionDefinition `json:"definition" gorm:"type:json;not null"`
+	UsageCount int                `json:"usage_count" gorm:"default:0"`
+
+	Owner *User `json:"-" gorm:"foreignKey:OwnerID"`
+}
+
+// SurveyTemplate is a reusable set of questions a survey can be created from.
+// Templates without an owner are platform templates curated by admins.
+type SurveyTemplate struct {
+	BaseModel
+	OwnerID           *uint               `json:"owner_id" gorm:"index"`
+	Name              string              `json:"name" gorm:"not null;size:255"`
+	Description       string              `json:"description" gorm:"type:text"`
+	Category          string              `json:"category" gorm:"not null;size:100;index"`
+	EstimatedDuration int                 `json:"estimated_duration"` // in minutes
+	Questions         QuestionDefinitions `json:"questions" gorm:"type:json;not null"`
+	IsCurated         bool                `json:"is_curated" gorm:"default:false;index"`
+	IsActive          bool                `json:"is_active" gorm:"default:true"`
+	UsageCount        int                 `json:"usage_count" gorm:"default:0"`
+
+	Owner *User `json:"-" gorm:"foreignKey:OwnerID"`
+}
+
+// NewQuestionDefinition builds a definition from an existing survey question
+func NewQuestionDefinition(q *Question) QuestionDefinition {
+	return QuestionDefinition{
+		Type:        q.Type,
+		Text:        q.Text,
+		Description: q.Description,
+		Options:     q.Options,
+		Required:    q.Required,
+		Order:       q.Order,
+		MinLength:   q.MinLength,
+		MaxLength:   q.MaxLength,
+		MinValue:    q.MinValue,
+		MaxValue:    q.MaxValue,
+		Rows:        q.Rows,
+		SumTotal:    q.SumTotal,
+		FileConfig:  q.FileConfig,
+	}
+}
+
+// IsPlatform checks if the question belongs to the platform-wide bank
+func (bq *BankQuestion) IsPlatform() bool {
+	return bq.OwnerID == nil
+}
+
+// IsAccessibleBy checks if the user can use the bank question
+func (bq *BankQuestion) IsAccessibleBy(userID uint) bool {
+	return bq.IsPlatform() || *bq.OwnerID == userID
+}
+
+// IsPlatform checks if the template is a platform template
+func (st *SurveyTemplate) IsPlatform() bool {
+	return st.OwnerID == nil
+}
+
+// IsAccessibleBy checks if the user can instantiate the template
+func (st *SurveyTemplate) IsAccessibleBy(userID uint) bool {
+	if st.IsPlatform() {
+		return st.IsActive
+	}
+	return *st.OwnerID == userID
+}
+
+// Value implements driver.Valuer interface for QuestionDefinition
+func (qd QuestionDefinition) Value() (driver.Value, error) {
+	return json.Marshal(qd)
+}
+
+// Scan implements sql.Scanner interface for QuestionDefinition
+func (qd *QuestionDefinition) Scan(value interface{}) error {
+	if value == nil {
+		*qd = QuestionDefinition{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("cannot scan non-bytes into QuestionDefinition")
+	}
+
+	return json.Unmarshal(bytes, qd)
+}
+
+// Value implements driver.Valuer interface for QuestionDefinitions
+func (qd QuestionDefinitions) Value() (driver.Value, error) {
+	return json.Marshal(qd)
+}
+
+// Scan implements sql.Scanner interface for QuestionDefinitions
+func (qd *QuestionDefinitions) Scan(value interface{}) error {
+	if value == nil {
+		*qd = QuestionDefinitions{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("cannot scan non-bytes into QuestionDefinitions")
+	}
+
+	return json.Unmarshal(bytes, qd)
+}
+
+// TableName returns the table name for BankQuestion
+func (BankQuestion) TableName() string {
+	return "bank_questions"
+}
+
+// TableName returns the table name for SurveyTemplate
+func (SurveyTemplate) TableName() string {
+	return "survey_templates"
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"survey2earn-backend/internal/textanalysis"
+)
+
+// TextAnalysis is the running analysis of the answers to a text question:
+// keywords, sentiment and clusters of similar answers. It is updated as
+// responses complete rather than recomputed from every answer.
+type TextAnalysis struct {
+	BaseModel
+	SurveyID   uint               `json:"survey_id" gorm:"not null;index"`
+	QuestionID uint               `json:"question_id" gorm:"not null;uniqueIndex"`
+	State      textanalysis.State `json:"state" gorm:"type:json;serializer:json"`
+
+	// Relationships
+	Survey   Survey   `json:"-" gorm:"foreignKey:SurveyID"`
+	Question Question `json:"-" gorm:"foreignKey:QuestionID"`
+}
@@ -11,6 +11,7 @@ type User struct {
 	WalletAddress  string    `json:"wallet_address" gorm:"unique;not null;index"`
 	Nonce          string    `json:"-" gorm:"not null"`
 	IsActive       bool      `json:"is_active" gorm:"default:true"`
+	IsAdmin        bool      `json:"is_admin" gorm:"default:false"`
 	LastLoginAt    *time.Time `json:"last_login_at"`
 	
 	Username       *string   `json:"username" gorm:"unique"`
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"time"
+)
+
+// Webhook event types, relayed from the outbox events of the same name
+const (
+	WebhookEventResponseCompleted = OutboxEventResponseCompleted
+	WebhookEventSurveyPublished   = OutboxEventSurveyPublished
+	WebhookEventPoolExhausted     = OutboxEventPoolExhausted
+	WebhookEventRewardPaid        = OutboxEventRewardPaid
+
+	// WebhookEventTest is sent by test-firing a webhook. It can't be subscribed to.
+	WebhookEventTest = "webhook.test"
+)
+
+// WebhookEventTypes are the events a webhook can subscribe to
+var WebhookEventTypes = []string{
+	WebhookEventResponseCompleted,
+	WebhookEventSurveyPublished,
+	WebhookEventPoolExhausted,
+	WebhookEventRewardPaid,
+}
+
+// IsWebhookEventType reports whether a webhook can subscribe to an event type
+func IsWebhookEventType(eventType string) bool {
+	for _, known := range WebhookEventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus represents the status of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+// Webhook is an endpoint a creator registered to be notified of events,
+// either for one survey or, without a SurveyID, for all of their surveys
+type Webhook struct {
+	BaseModel
+	UserID      uint     `json:"user_id" gorm:"not null;index"`
+	SurveyID    *uint    `json:"survey_id" gorm:"index"`
+	URL         string   `json:"url" gorm:"not null;size:2048"`
+	Secret      string   `json:"-" gorm:"not null;size:100"`
+	Events      []string `json:"events" gorm:"type:json;serializer:json;not null"`
+	Description string   `json:"description" gorm:"size:255"`
+	IsActive    bool     `json:"is_active" gorm:"default:true"`
+
+	// Relationships
+	User   User    `json:"-" gorm:"foreignKey:UserID"`
+	Survey *Survey `json:"-" gorm:"foreignKey:SurveyID"`
+}
+
+// Subscribes reports whether the webhook wants an event type
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, event := range w.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event sent, or to be sent, to a webhook. Failed
+// attempts are retried until the delivery succeeds or runs out of attempts.
+type WebhookDelivery struct {
+	BaseModel
+	WebhookID uint                  `json:"webhook_id" gorm:"not null;index"`
+	EventID   string                `json:"event_id" gorm:"not null;size:40;index"`
+	EventType string                `json:"event_type" gorm:"not null;size:50"`
+	Payload   string                `json:"payload" gorm:"type:text;not null"`
+	Status    WebhookDeliveryStatus `json:"status" gorm:"default:'pending';index"`
+
+	// Attempts
+	Attempts       int        `json:"attempts" gorm:"default:0"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at" gorm:"index"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at"`
+	ResponseStatus int        `json:"response_status"`
+	ResponseBody   string     `json:"response_body" gorm:"type:text"`
+	Error          string     `json:"error" gorm:"type:text"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+
+	// Relationships
+	Webhook Webhook `json:"-" gorm:"foreignKey:WebhookID"`
+}
+
+// MarkAsSucceeded records a successful attempt
+func (d *WebhookDelivery) MarkAsSucceeded(statusCode int, body string) {
+	now := time.Now()
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.ResponseStatus = statusCode
+	d.ResponseBody = body
+	d.Error = ""
+	d.Status = WebhookDeliveryStatusSucceeded
+	d.DeliveredAt = &now
+	d.NextAttemptAt = nil
+}
+
+// MarkAttemptFailed records a failed attempt and schedules the next one, or
+// fails the delivery when nextAttemptAt is nil
+func (d *WebhookDelivery) MarkAttemptFailed(statusCode int, body, reason string, nextAttemptAt *time.Time) {
+	now := time.Now()
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.ResponseStatus = statusCode
+	d.ResponseBody = body
+	d.Error = reason
+	d.NextAttemptAt = nextAttemptAt
+	if nextAttemptAt == nil {
+		d.Status = WebhookDeliveryStatusFailed
+	}
+}
+
+// TableName returns the table name for WebhookDelivery
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
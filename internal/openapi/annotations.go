@@ -0,0 +1,348 @@
+// internal/openapi/annotations.go
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// annotations are the swag-style godoc annotations of a handler:
+//
+//	// @Summary Get survey by ID
+//	// @Tags surveys
+//	// @Param id path int true "Survey ID"
+//	// @Success 200 {object} SuccessResponse{data=dto.SurveyResponse}
+//	// @Failure 404 {object} apperror.Problem
+//	// @Security BearerAuth
+//	// @Router /surveys/{id} [get]
+type annotations struct {
+	summary     string
+	description string
+	tags        []string
+	accept      []string
+	produce     []string
+	params      []paramAnnotation
+	responses   []responseAnnotation
+	headers     []headerAnnotation
+	security    []string
+	path        string
+	method      string
+}
+
+// paramAnnotation is "@Param name in type required "description" attrs..."
+type paramAnnotation struct {
+	name        string
+	in          string
+	typ         typeExpr
+	required    bool
+	description string
+	attrs       map[string]string
+}
+
+// responseAnnotation is "@Success status {kind} type "description"", or the
+// same with @Failure. kind is object, array or file.
+type responseAnnotation struct {
+	status      int
+	kind        string
+	typ         typeExpr
+	description string
+}
+
+// headerAnnotation is "@Header status {type} name "description""
+type headerAnnotation struct {
+	status      int
+	typ         string
+	name        string
+	description string
+}
+
+// typeExpr is a type named in an annotation: "dto.SurveyResponse",
+// "[]dto.WebhookResponse", or a composition that replaces fields of a type,
+// "SuccessResponse{data=dto.SurveyResponse}"
+type typeExpr struct {
+	name   string
+	array  bool
+	fields map[string]typeExpr
+	order  []string
+}
+
+func (t typeExpr) String() string {
+	s := t.name
+	if len(t.fields) > 0 {
+		parts := make([]string, len(t.order))
+		for i, field := range t.order {
+			parts[i] = field + "=" + t.fields[field].String()
+		}
+		s += "{" + strings.Join(parts, ",") + "}"
+	}
+	if t.array {
+		s = "[]" + s
+	}
+	return s
+}
+
+// parseAnnotations reads the annotations out of a doc comment. Lines that
+// aren't annotations are ignored, so the comment can also document the code.
+func parseAnnotations(doc string) (*annotations, error) {
+	a := &annotations{}
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		name, value, _ := strings.Cut(line, " ")
+		value = strings.TrimSpace(value)
+
+		var err error
+		switch name {
+		case "@Summary":
+			a.summary = value
+		case "@Description":
+			a.description = joinLines(a.description, value)
+		case "@Tags":
+			a.tags = append(a.tags, splitList(value)...)
+		case "@Accept":
+			a.accept = append(a.accept, splitList(value)...)
+		case "@Produce":
+			a.produce = append(a.produce, splitList(value)...)
+		case "@Param":
+			err = a.parseParam(value)
+		case "@Success", "@Failure":
+			err = a.parseResponse(value)
+		case "@Header":
+			err = a.parseHeader(value)
+		case "@Security":
+			a.security = append(a.security, value)
+		case "@Router":
+			err = a.parseRouter(value)
+		default:
+			err = fmt.Errorf("unknown annotation")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", line, err)
+		}
+	}
+	return a, nil
+}
+
+func (a *annotations) parseParam(value string) error {
+	fields, err := splitFields(value)
+	if err != nil {
+		return err
+	}
+	if len(fields) < 4 {
+		return fmt.Errorf("want name, in, type and required")
+	}
+
+	param := paramAnnotation{name: fields[0], in: fields[1], attrs: map[string]string{}}
+	switch param.in {
+	case "path", "query", "header", "body":
+	default:
+		return fmt.Errorf("unknown parameter location %q", param.in)
+	}
+	if param.typ, err = parseTypeExpr(fields[2]); err != nil {
+		return err
+	}
+	if param.required, err = strconv.ParseBool(fields[3]); err != nil {
+		return fmt.Errorf("required must be true or false")
+	}
+
+	for _, field := range fields[4:] {
+		if unquoted, ok := unquote(field); ok {
+			param.description = unquoted
+			continue
+		}
+		// Attributes are written as default(1) or collectionFormat(multi)
+		name, arg, ok := strings.Cut(field, "(")
+		if !ok || !strings.HasSuffix(arg, ")") {
+			return fmt.Errorf("unknown attribute %q", field)
+		}
+		param.attrs[name] = strings.TrimSuffix(arg, ")")
+	}
+
+	a.params = append(a.params, param)
+	return nil
+}
+
+func (a *annotations) parseResponse(value string) error {
+	fields, err := splitFields(value)
+	if err != nil {
+		return err
+	}
+	if len(fields) < 3 {
+		return fmt.Errorf("want status, {kind} and type")
+	}
+
+	response := responseAnnotation{}
+	if response.status, err = strconv.Atoi(fields[0]); err != nil {
+		return fmt.Errorf("invalid status %q", fields[0])
+	}
+	response.kind = strings.Trim(fields[1], "{}")
+	switch response.kind {
+	case "object", "array", "file":
+	default:
+		return fmt.Errorf("unknown kind %q", fields[1])
+	}
+	if response.typ, err = parseTypeExpr(fields[2]); err != nil {
+		return err
+	}
+	if response.kind == "array" {
+		response.typ.array = true
+	}
+	if len(fields) > 3 {
+		description, ok := unquote(fields[3])
+		if !ok || len(fields) > 4 {
+			return fmt.Errorf("unexpected %q", strings.Join(fields[3:], " "))
+		}
+		response.description = description
+	}
+
+	a.responses = append(a.responses, response)
+	return nil
+}
+
+func (a *annotations) parseHeader(value string) error {
+	fields, err := splitFields(value)
+	if err != nil {
+		return err
+	}
+	if len(fields) != 4 {
+		return fmt.Errorf("want status, {type}, name and description")
+	}
+
+	header := headerAnnotation{typ: strings.Trim(fields[1], "{}"), name: fields[2]}
+	if header.status, err = strconv.Atoi(fields[0]); err != nil {
+		return fmt.Errorf("invalid status %q", fields[0])
+	}
+	var ok bool
+	if header.description, ok = unquote(fields[3]); !ok {
+		return fmt.Errorf("the description must be quoted")
+	}
+
+	a.headers = append(a.headers, header)
+	return nil
+}
+
+func (a *annotations) parseRouter(value string) error {
+	path, method, ok := strings.Cut(value, " ")
+	method = strings.TrimSpace(method)
+	if !ok || !strings.HasPrefix(method, "[") || !strings.HasSuffix(method, "]") {
+		return fmt.Errorf("want /path [method]")
+	}
+	a.path = path
+	a.method = strings.ToLower(strings.Trim(method, "[]"))
+	return nil
+}
+
+// parseTypeExpr parses a type named in an annotation
+func parseTypeExpr(s string) (typeExpr, error) {
+	t := typeExpr{}
+	if strings.HasPrefix(s, "[]") {
+		t.array = true
+		s = s[2:]
+	}
+
+	name, fields, composed := strings.Cut(s, "{")
+	t.name = name
+	if !composed {
+		return t, nil
+	}
+	if !strings.HasSuffix(fields, "}") {
+		return t, fmt.Errorf("unterminated composition in %q", s)
+	}
+
+	t.fields = map[string]typeExpr{}
+	for _, field := range splitTopLevel(strings.TrimSuffix(fields, "}")) {
+		fieldName, fieldType, ok := strings.Cut(field, "=")
+		if !ok {
+			return t, fmt.Errorf("want field=type in %q", s)
+		}
+		parsed, err := parseTypeExpr(fieldType)
+		if err != nil {
+			return t, err
+		}
+		t.fields[fieldName] = parsed
+		t.order = append(t.order, fieldName)
+	}
+	return t, nil
+}
+
+// splitFields splits an annotation on spaces, keeping quoted strings and
+// parenthesized attributes whole
+func splitFields(s string) ([]string, error) {
+	var fields []string
+	var current strings.Builder
+	quoted, depth := false, 0
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case r == '(' || r == '{':
+			depth++
+		case r == ')' || r == '}':
+			depth--
+		case r == ' ' || r == '\t':
+			if depth == 0 {
+				if current.Len() > 0 {
+					fields = append(fields, current.String())
+					current.Reset()
+				}
+				continue
+			}
+		}
+		current.WriteRune(r)
+	}
+	if quoted || depth != 0 {
+		return nil, fmt.Errorf("unbalanced quotes or brackets")
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields, nil
+}
+
+// splitTopLevel splits a composition's fields on commas outside brackets
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func unquote(s string) (string, bool) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", false
+	}
+	return s[1 : len(s)-1], true
+}
+
+func joinLines(text, line string) string {
+	if text == "" {
+		return line
+	}
+	return text + "\n" + line
+}
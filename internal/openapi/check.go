@@ -0,0 +1,312 @@
+// internal/openapi/check.go
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Methods of gin.Context a handler reads its input with
+var (
+	queryReaders = map[string]bool{"Query": true, "DefaultQuery": true, "GetQuery": true, "QueryArray": true, "GetQueryArray": true}
+	pathReaders  = map[string]bool{"Param": true}
+	bodyBinders  = map[string]bool{"ShouldBindJSON": true, "BindJSON": true, "ShouldBind": true, "Bind": true}
+	queryBinders = map[string]bool{"ShouldBindQuery": true, "BindQuery": true}
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// usage is what a handler does, itself or through the functions of the
+// handler package it calls
+type usage struct {
+	// envelopes are the statuses and data types of the SuccessResponse
+	// bodies written, "200 dto.SurveyResponse" or "200" without data
+	envelopes map[string]bool
+	query     map[string]bool
+	path      map[string]bool
+	bodies    []types.Type
+}
+
+// check compares a handler's annotations with its code: the success
+// envelopes it writes, the body it binds, and the path and query parameters
+// it reads
+func (g *generator) check(h *handlerFunc) error {
+	a := h.annotations
+	u := &usage{envelopes: map[string]bool{}, query: map[string]bool{}, path: map[string]bool{}}
+	var errs []error
+	g.collect(h.decl, u, map[*ast.FuncDecl]bool{}, &errs)
+
+	annotated := map[string]bool{}
+	for _, r := range a.responses {
+		if r.typ.name != envelopeType || r.typ.array {
+			continue
+		}
+		key, err := g.envelopeKey(r)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		annotated[key] = true
+	}
+	for _, key := range difference(u.envelopes, annotated) {
+		errs = append(errs, fmt.Errorf("writes %s, which isn't annotated", describeEnvelope(key)))
+	}
+	for _, key := range difference(annotated, u.envelopes) {
+		errs = append(errs, fmt.Errorf("is annotated with %s, which it never writes", describeEnvelope(key)))
+	}
+
+	params := map[string]map[string]bool{"path": {}, "query": {}}
+	var body *paramAnnotation
+	for i, param := range a.params {
+		if param.in == "body" {
+			body = &a.params[i]
+			continue
+		}
+		if params[param.in] != nil {
+			params[param.in][param.name] = true
+		}
+	}
+
+	routeParams := map[string]bool{}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(a.path, -1) {
+		routeParams[match[1]] = true
+	}
+	for _, name := range difference(routeParams, params["path"]) {
+		errs = append(errs, fmt.Errorf("path parameter %s isn't annotated", name))
+	}
+	for _, name := range difference(params["path"], routeParams) {
+		errs = append(errs, fmt.Errorf("path parameter %s isn't in the route %s", name, a.path))
+	}
+	for _, name := range difference(u.path, routeParams) {
+		errs = append(errs, fmt.Errorf("reads path parameter %s, which isn't in the route %s", name, a.path))
+	}
+	for _, name := range difference(u.query, params["query"]) {
+		errs = append(errs, fmt.Errorf("reads query parameter %s, which isn't annotated", name))
+	}
+	for _, name := range difference(params["query"], u.query) {
+		errs = append(errs, fmt.Errorf("is annotated with query parameter %s, which it never reads", name))
+	}
+
+	for _, bound := range u.bodies {
+		if body == nil {
+			errs = append(errs, fmt.Errorf("binds a %s body, which isn't annotated", typeString(bound)))
+			continue
+		}
+		annotatedBody, err := g.resolve(body.typ)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !types.Identical(deref(bound), annotatedBody) {
+			errs = append(errs, fmt.Errorf("binds a %s body, but is annotated with %s", typeString(bound), body.typ))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// collect records what fn does, following the calls to functions and
+// methods of the handler package
+func (g *generator) collect(fn *ast.FuncDecl, u *usage, seen map[*ast.FuncDecl]bool, errs *[]error) {
+	if seen[fn] || fn.Body == nil {
+		return
+	}
+	seen[fn] = true
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		callee := g.callee(call)
+		if callee == nil {
+			return true
+		}
+
+		if isContextMethod(callee) {
+			g.collectContextCall(call, callee.Name(), u, errs)
+			return true
+		}
+		if callee.Pkg() == g.pkg {
+			if decl, ok := g.funcs[callee]; ok {
+				g.collect(decl, u, seen, errs)
+			}
+		}
+		return true
+	})
+}
+
+func (g *generator) collectContextCall(call *ast.CallExpr, method string, u *usage, errs *[]error) {
+	position := g.fset.Position(call.Pos())
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, fmt.Errorf("%s:%d: %s", position.Filename, position.Line, fmt.Sprintf(format, args...)))
+	}
+
+	switch {
+	case method == "JSON":
+		status, ok := g.constantInt(call.Args[0])
+		if !ok {
+			fail("can't tell the status of a JSON response")
+			return
+		}
+		key, err := g.envelopeWritten(status, call.Args[1])
+		if err != nil {
+			fail("%v", err)
+			return
+		}
+		u.envelopes[key] = true
+	case queryReaders[method], pathReaders[method]:
+		name, ok := g.constantString(call.Args[0])
+		if !ok {
+			fail("can't tell which parameter %s reads", method)
+			return
+		}
+		if pathReaders[method] {
+			u.path[name] = true
+		} else {
+			u.query[name] = true
+		}
+	case bodyBinders[method]:
+		u.bodies = append(u.bodies, g.info.Types[call.Args[0]].Type)
+	case queryBinders[method]:
+		for _, name := range formFields(deref(g.info.Types[call.Args[0]].Type)) {
+			u.query[name] = true
+		}
+	}
+}
+
+// envelopeWritten is the envelope key of a JSON body, which must be a
+// SuccessResponse literal
+func (g *generator) envelopeWritten(status int, body ast.Expr) (string, error) {
+	lit, ok := body.(*ast.CompositeLit)
+	t := g.info.Types[body].Type
+	if named, isNamed := t.(*types.Named); !isNamed || named.Obj().Pkg() != g.pkg || named.Obj().Name() != envelopeType {
+		return "", fmt.Errorf("writes a %s body instead of a %s", typeString(t), envelopeType)
+	}
+	if !ok {
+		return "", fmt.Errorf("can't tell the data of a %s that isn't a literal", envelopeType)
+	}
+
+	key := fmt.Sprint(status)
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return "", fmt.Errorf("write %s literals with field names", envelopeType)
+		}
+		if ident, ok := kv.Key.(*ast.Ident); ok && ident.Name == "Data" {
+			key += " " + typeString(deref(g.info.Types[kv.Value].Type))
+		}
+	}
+	return key, nil
+}
+
+// envelopeKey is the envelope key of an annotated response
+func (g *generator) envelopeKey(r responseAnnotation) (string, error) {
+	key := fmt.Sprint(r.status)
+	data, ok := r.typ.fields["data"]
+	if !ok {
+		return key, nil
+	}
+	t, err := g.resolve(data)
+	if err != nil {
+		return "", err
+	}
+	return key + " " + typeString(t), nil
+}
+
+func describeEnvelope(key string) string {
+	status, data, ok := strings.Cut(key, " ")
+	if !ok {
+		return status + " " + envelopeType
+	}
+	return status + " " + envelopeType + "{data=" + data + "}"
+}
+
+func (g *generator) callee(call *ast.CallExpr) *types.Func {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		f, _ := g.info.Uses[fun].(*types.Func)
+		return f
+	case *ast.SelectorExpr:
+		if selection, ok := g.info.Selections[fun]; ok {
+			f, _ := selection.Obj().(*types.Func)
+			return f
+		}
+		f, _ := g.info.Uses[fun.Sel].(*types.Func)
+		return f
+	}
+	return nil
+}
+
+func isContextMethod(f *types.Func) bool {
+	sig, ok := f.Type().(*types.Signature)
+	return ok && sig.Recv() != nil && isGinContext(sig.Recv().Type())
+}
+
+func (g *generator) constantInt(expr ast.Expr) (int, bool) {
+	value := g.info.Types[expr].Value
+	if value == nil || value.Kind() != constant.Int {
+		return 0, false
+	}
+	n, ok := constant.Int64Val(value)
+	return int(n), ok
+}
+
+func (g *generator) constantString(expr ast.Expr) (string, bool) {
+	value := g.info.Types[expr].Value
+	if value == nil || value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(value), true
+}
+
+// formFields are the query parameters a struct binds. Like gin, it binds
+// the fields of untagged struct fields, embedded ones included.
+func formFields(t types.Type) []string {
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for i := 0; i < st.NumFields(); i++ {
+		name, _, _ := strings.Cut(reflect.StructTag(st.Tag(i)).Get("form"), ",")
+		switch name {
+		case "-":
+		case "":
+			names = append(names, formFields(deref(st.Field(i).Type()))...)
+		default:
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func deref(t types.Type) types.Type {
+	if pointer, ok := t.(*types.Pointer); ok {
+		return pointer.Elem()
+	}
+	return t
+}
+
+// typeString names a type by its package name, "[]dto.WebhookResponse"
+func typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string { return pkg.Name() })
+}
+
+// difference lists the keys of a missing from b, sorted
+func difference(a, b map[string]bool) []string {
+	var keys []string
+	for key := range a {
+		if !b[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
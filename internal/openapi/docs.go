@@ -0,0 +1,92 @@
+// internal/openapi/docs.go
+package openapi
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+)
+
+// docIndex finds the doc comments of the module's types and fields, which
+// describe their schemas. go/types doesn't keep comments, so the packages
+// are parsed again, once each.
+type docIndex struct {
+	root       string
+	modulePath string
+	docs       map[string]string
+	parsed     map[string]bool
+}
+
+func newDocIndex(root, modulePath string) *docIndex {
+	return &docIndex{root: root, modulePath: modulePath, docs: map[string]string{}, parsed: map[string]bool{}}
+}
+
+func (d *docIndex) typeDoc(obj *types.TypeName) string {
+	d.parse(obj.Pkg().Path())
+	return d.docs[obj.Pkg().Path()+"."+obj.Name()]
+}
+
+func (d *docIndex) fieldDoc(obj *types.TypeName, field string) string {
+	d.parse(obj.Pkg().Path())
+	return d.docs[obj.Pkg().Path()+"."+obj.Name()+"."+field]
+}
+
+// parse indexes the comments of a package of the module. Comments are only
+// descriptions, so packages that can't be parsed are left undescribed.
+func (d *docIndex) parse(pkgPath string) {
+	if d.parsed[pkgPath] {
+		return
+	}
+	d.parsed[pkgPath] = true
+
+	rel, ok := strings.CutPrefix(pkgPath, d.modulePath+"/")
+	if !ok {
+		return
+	}
+	pkgs, err := parser.ParseDir(token.NewFileSet(), filepath.Join(d.root, filepath.FromSlash(rel)), nil, parser.ParseComments)
+	if err != nil {
+		return
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					doc := typeSpec.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+					key := pkgPath + "." + typeSpec.Name.Name
+					d.docs[key] = commentText(doc)
+
+					st, ok := typeSpec.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+					for _, field := range st.Fields.List {
+						text := commentText(field.Doc)
+						if text == "" {
+							text = commentText(field.Comment)
+						}
+						for _, name := range field.Names {
+							d.docs[key+"."+name.Name] = text
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// commentText is a comment as a single paragraph
+func commentText(group *ast.CommentGroup) string {
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
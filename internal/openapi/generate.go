@@ -0,0 +1,586 @@
+// internal/openapi/generate.go
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Where the generator finds the API and what it calls it
+const (
+	handlerDir      = "internal/handler"
+	envelopeType    = "SuccessResponse"
+	apiTitle        = "Survey2Earn API"
+	apiVersion      = "v1"
+	apiServerURL    = "/api/" + apiVersion
+	bearerAuthName  = "BearerAuth"
+	problemMimeType = "application/problem+json"
+)
+
+const apiDescription = `Create surveys, answer them and earn rewards for it.
+
+Successful responses wrap their payload in an envelope, {"success": true, "data": ..., "message": ...}. ` +
+	`Errors are RFC 7807 problem details (application/problem+json) with a stable "code" and, for invalid fields, "errors". ` +
+	`Every route is rate limited and may answer 429 with the code "rate_limited".`
+
+// mimeTypes expands the short names annotations use for media types
+var mimeTypes = map[string]string{
+	"json":         "application/json",
+	"yaml":         "application/yaml",
+	"plain":        "text/plain",
+	"octet-stream": "application/octet-stream",
+	"csv":          "text/csv",
+	"xlsx":         "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"sav":          "application/x-spss-sav",
+}
+
+// fileMimeTypes are the media types only {file} responses are served in. A
+// handler producing both files and JSON serves its files in these and
+// everything else as JSON.
+var fileMimeTypes = map[string]bool{
+	"octet-stream": true,
+	"csv":          true,
+	"xlsx":         true,
+	"sav":          true,
+}
+
+// Generate builds the OpenAPI document of the handlers of the module at
+// root. It fails when an annotation doesn't match the handler's code, listing
+// every mismatch.
+func Generate(root string) (*Document, error) {
+	modulePath, err := readModulePath(root)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := loadHandlers(root, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	g.schemas = newSchemaBuilder(newDocIndex(root, modulePath))
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: apiTitle, Description: apiDescription, Version: apiVersion},
+		Servers: []Server{{URL: apiServerURL}},
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerAuthName: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	// Request types first, so their schemas are built as inputs
+	for _, h := range g.handlers {
+		for _, param := range h.annotations.params {
+			if param.in == "body" {
+				if t, err := g.resolve(param.typ); err == nil {
+					g.schemas.markInput(t)
+				}
+			}
+		}
+	}
+
+	var errs []error
+	tags := map[string]bool{}
+	for _, h := range g.handlers {
+		op, err := g.operation(h)
+		if err != nil {
+			for _, line := range strings.Split(err.Error(), "\n") {
+				errs = append(errs, fmt.Errorf("%s: %s", h.name, line))
+			}
+			continue
+		}
+
+		a := h.annotations
+		item, ok := doc.Paths[a.path]
+		if !ok {
+			item = PathItem{}
+			doc.Paths[a.path] = item
+		}
+		if _, ok := item[a.method]; ok {
+			errs = append(errs, fmt.Errorf("%s: %s %s is annotated on another handler too", h.name, strings.ToUpper(a.method), a.path))
+			continue
+		}
+		item[a.method] = op
+		for _, tag := range op.Tags {
+			tags[tag] = true
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	doc.Components.Schemas = g.schemas.schemas
+	return doc, nil
+}
+
+// Marshal encodes a document the way it is committed: indented, ending in a
+// newline, and without escaping HTML characters in descriptions
+func Marshal(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// generator holds the type-checked handler package
+type generator struct {
+	fset     *token.FileSet
+	pkg      *types.Package
+	info     *types.Info
+	funcs    map[*types.Func]*ast.FuncDecl
+	handlers []*handlerFunc
+	schemas  *schemaBuilder
+}
+
+// handlerFunc is an annotated handler
+type handlerFunc struct {
+	name        string
+	decl        *ast.FuncDecl
+	annotations *annotations
+}
+
+// loadHandlers parses and type-checks the handler package, and finds the
+// annotated handlers in it
+func loadHandlers(root, modulePath string) (*generator, error) {
+	fset := token.NewFileSet()
+	dir := filepath.Join(root, filepath.FromSlash(handlerDir))
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("want a single package in %s, found %d", dir, len(pkgs))
+	}
+	var files []*ast.File
+	for _, pkg := range pkgs {
+		names := make([]string, 0, len(pkg.Files))
+		for name := range pkg.Files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			files = append(files, pkg.Files[name])
+		}
+	}
+
+	g := &generator{
+		fset: fset,
+		info: &types.Info{
+			Types:      map[ast.Expr]types.TypeAndValue{},
+			Defs:       map[*ast.Ident]types.Object{},
+			Uses:       map[*ast.Ident]types.Object{},
+			Selections: map[*ast.SelectorExpr]*types.Selection{},
+		},
+		funcs: map[*types.Func]*ast.FuncDecl{},
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if g.pkg, err = conf.Check(modulePath+"/"+handlerDir, fset, files, g.info); err != nil {
+		return nil, fmt.Errorf("type-check handlers: %w", err)
+	}
+
+	var errs []error
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			if obj, ok := g.info.Defs[fn.Name].(*types.Func); ok {
+				g.funcs[obj] = fn
+			}
+			if fn.Recv == nil || !isHandler(g.info, fn) {
+				continue
+			}
+
+			name := receiverName(fn) + "." + fn.Name.Name
+			a, err := parseAnnotations(fn.Doc.Text())
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				continue
+			}
+			if a.path == "" {
+				if fn.Name.IsExported() {
+					errs = append(errs, fmt.Errorf("%s: handler has no @Router annotation", name))
+				}
+				continue
+			}
+			g.handlers = append(g.handlers, &handlerFunc{name: name, decl: fn, annotations: a})
+		}
+	}
+	return g, errors.Join(errs...)
+}
+
+// isHandler reports whether fn is a gin handler, taking only a *gin.Context
+func isHandler(info *types.Info, fn *ast.FuncDecl) bool {
+	obj, ok := info.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := obj.Type().(*types.Signature)
+	return sig.Params().Len() == 1 && sig.Results().Len() == 0 && isGinContext(sig.Params().At(0).Type())
+}
+
+func isGinContext(t types.Type) bool {
+	pointer, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := pointer.Elem().(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "github.com/gin-gonic/gin" && named.Obj().Name() == "Context"
+}
+
+func receiverName(fn *ast.FuncDecl) string {
+	t := fn.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	if ident, ok := t.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return "?"
+}
+
+// resolve finds the Go type an annotation names, in the handler package or
+// a package it imports
+func (g *generator) resolve(expr typeExpr) (types.Type, error) {
+	var obj types.Object
+	if pkgName, typeName, ok := strings.Cut(expr.name, "."); ok {
+		for _, imported := range g.pkg.Imports() {
+			if imported.Name() == pkgName {
+				obj = imported.Scope().Lookup(typeName)
+				break
+			}
+		}
+	} else {
+		obj = g.pkg.Scope().Lookup(expr.name)
+	}
+
+	typeName, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("unknown type %s", expr.name)
+	}
+	t := typeName.Type()
+	if expr.array {
+		t = types.NewSlice(t)
+	}
+	return t, nil
+}
+
+// schemaOfExpr describes a type named in an annotation. Compositions replace
+// the listed fields of the type, and make them required.
+func (g *generator) schemaOfExpr(expr typeExpr) (*Schema, error) {
+	if schema := primitiveSchema(expr.name); schema != nil && len(expr.fields) == 0 {
+		if expr.array {
+			return &Schema{Type: "array", Items: schema}, nil
+		}
+		return schema, nil
+	}
+
+	base := expr
+	base.array, base.fields, base.order = false, nil, nil
+	t, err := g.resolve(base)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := g.schemas.schemaOf(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(expr.fields) > 0 {
+		overrides := &Schema{Type: "object", Properties: map[string]*Schema{}, Required: expr.order}
+		for _, field := range expr.order {
+			fieldSchema, err := g.schemaOfExpr(expr.fields[field])
+			if err != nil {
+				return nil, err
+			}
+			overrides.Properties[field] = fieldSchema
+		}
+		schema = &Schema{AllOf: []*Schema{schema, overrides}}
+	}
+	if expr.array {
+		schema = &Schema{Type: "array", Items: schema}
+	}
+	return schema, nil
+}
+
+// primitiveSchema describes the types parameters are annotated with, nil for
+// other types
+func primitiveSchema(name string) *Schema {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}
+	case "int", "integer":
+		return &Schema{Type: "integer"}
+	case "number":
+		return &Schema{Type: "number"}
+	case "bool", "boolean":
+		return &Schema{Type: "boolean"}
+	case "file":
+		return &Schema{Type: "string", Format: "binary"}
+	}
+	return nil
+}
+
+// operation builds the operation of a handler, after checking its
+// annotations against its code
+func (g *generator) operation(h *handlerFunc) (*Operation, error) {
+	a := h.annotations
+	if err := g.check(h); err != nil {
+		return nil, err
+	}
+
+	op := &Operation{
+		OperationID: h.decl.Name.Name,
+		Summary:     a.summary,
+		Description: a.description,
+		Tags:        a.tags,
+		Responses:   map[string]*Response{},
+	}
+	for _, name := range a.security {
+		if name != bearerAuthName {
+			return nil, fmt.Errorf("unknown security scheme %q", name)
+		}
+		op.Security = append(op.Security, map[string][]string{name: {}})
+	}
+
+	for _, param := range a.params {
+		if param.in == "body" {
+			body, err := g.requestBody(param, a.accept)
+			if err != nil {
+				return nil, err
+			}
+			op.RequestBody = body
+			continue
+		}
+
+		p, err := g.parameter(param)
+		if err != nil {
+			return nil, err
+		}
+		op.Parameters = append(op.Parameters, p)
+	}
+
+	for _, r := range a.responses {
+		response, err := g.response(r, a.produce)
+		if err != nil {
+			return nil, err
+		}
+		status := strconv.Itoa(r.status)
+		op.Responses[status] = mergeResponses(op.Responses[status], response, r.status)
+	}
+	for _, header := range a.headers {
+		response, ok := op.Responses[strconv.Itoa(header.status)]
+		if !ok {
+			return nil, fmt.Errorf("header %s is annotated on %d, which has no response", header.name, header.status)
+		}
+		if response.Headers == nil {
+			response.Headers = map[string]*Header{}
+		}
+		response.Headers[header.name] = &Header{Description: header.description, Schema: primitiveSchema(header.typ)}
+	}
+	return op, nil
+}
+
+func (g *generator) parameter(param paramAnnotation) (*Parameter, error) {
+	schema, err := g.schemaOfExpr(param.typ)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %s: %w", param.name, err)
+	}
+
+	valueSchema := schema
+	if schema.Items != nil {
+		valueSchema = schema.Items
+	}
+	for name, value := range param.attrs {
+		switch name {
+		case "default":
+			schema.Default = parseValue(valueSchema, value)
+		case "Enums":
+			for _, item := range strings.Split(value, ",") {
+				valueSchema.Enum = append(valueSchema.Enum, parseValue(valueSchema, strings.TrimSpace(item)))
+			}
+		case "minimum", "maximum":
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parameter %s: invalid %s %q", param.name, name, value)
+			}
+			if name == "minimum" {
+				valueSchema.Minimum = &f
+			} else {
+				valueSchema.Maximum = &f
+			}
+		case "collectionFormat":
+			// Repeated query parameters are the default of OpenAPI
+			if value != "multi" {
+				return nil, fmt.Errorf("parameter %s: unsupported collectionFormat %q", param.name, value)
+			}
+		default:
+			return nil, fmt.Errorf("parameter %s: unknown attribute %s", param.name, name)
+		}
+	}
+
+	return &Parameter{
+		Name:        param.name,
+		In:          param.in,
+		Description: param.description,
+		Required:    param.required || param.in == "path",
+		Schema:      schema,
+	}, nil
+}
+
+// parseValue reads a default or enum value as the schema's type
+func parseValue(schema *Schema, value string) interface{} {
+	switch schema.Type {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+func (g *generator) requestBody(param paramAnnotation, accept []string) (*RequestBody, error) {
+	schema, err := g.schemaOfExpr(param.typ)
+	if err != nil {
+		return nil, fmt.Errorf("body: %w", err)
+	}
+
+	content, err := contentOf(schema, accept)
+	if err != nil {
+		return nil, err
+	}
+	return &RequestBody{Description: param.description, Required: param.required, Content: content}, nil
+}
+
+func (g *generator) response(r responseAnnotation, produce []string) (*Response, error) {
+	description := r.description
+	if description == "" {
+		description = http.StatusText(r.status)
+	}
+	response := &Response{Description: description}
+
+	// Errors are problems whatever the handler produces
+	if r.status >= 400 {
+		produce = []string{problemMimeType}
+	}
+
+	var schema *Schema
+	var err error
+	if r.kind == "file" {
+		schema = primitiveSchema("file")
+	} else if schema, err = g.schemaOfExpr(r.typ); err != nil {
+		return nil, fmt.Errorf("response %d: %w", r.status, err)
+	}
+	produce = producedAs(produce, r.kind == "file")
+
+	if response.Content, err = contentOf(schema, produce); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// producedAs narrows what a handler produces to the media types of a file
+// response, or of any other response, when it produces both
+func producedAs(produce []string, file bool) []string {
+	var matching []string
+	for _, mime := range produce {
+		if fileMimeTypes[mime] == file {
+			matching = append(matching, mime)
+		}
+	}
+	if len(matching) == 0 {
+		return produce
+	}
+	return matching
+}
+
+// contentOf gives the same schema to each media type, JSON by default
+func contentOf(schema *Schema, mimes []string) (map[string]*MediaType, error) {
+	if len(mimes) == 0 {
+		mimes = []string{"json"}
+	}
+	content := map[string]*MediaType{}
+	for _, mime := range mimes {
+		if full, ok := mimeTypes[mime]; ok {
+			mime = full
+		} else if !strings.Contains(mime, "/") {
+			return nil, fmt.Errorf("unknown media type %q", mime)
+		}
+		content[mime] = &MediaType{Schema: schema}
+	}
+	return content, nil
+}
+
+// mergeResponses combines two responses annotated with the same status,
+// which hold either schema
+func mergeResponses(existing, added *Response, status int) *Response {
+	if existing == nil {
+		return added
+	}
+	for mime, media := range added.Content {
+		current, ok := existing.Content[mime]
+		if !ok {
+			existing.Content[mime] = media
+			continue
+		}
+		if len(current.Schema.AnyOf) > 0 {
+			current.Schema.AnyOf = append(current.Schema.AnyOf, media.Schema)
+		} else {
+			current.Schema = &Schema{AnyOf: []*Schema{current.Schema, media.Schema}}
+		}
+	}
+	if added.Description != http.StatusText(status) && added.Description != existing.Description {
+		existing.Description += "; " + added.Description
+	}
+	return existing
+}
+
+// readModulePath reads the module path out of root's go.mod
+func readModulePath(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(path), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("no module path in %s", filepath.Join(root, "go.mod"))
+}
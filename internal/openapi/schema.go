@@ -0,0 +1,462 @@
+// internal/openapi/schema.go
+package openapi
+
+import (
+	"fmt"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemaBuilder describes Go types as schemas the way encoding/json
+// marshals them. Named struct types become schemas in the components,
+// named after their package and type, "dto.SurveyResponse".
+type schemaBuilder struct {
+	schemas map[string]*Schema
+	owners  map[string]string
+	docs    *docIndex
+	// inputs are the types reached from request bodies. Their required
+	// fields are the ones their binding tags require, while the required
+	// fields of other types are the ones always marshaled.
+	inputs map[*types.Named]bool
+}
+
+func newSchemaBuilder(docs *docIndex) *schemaBuilder {
+	return &schemaBuilder{
+		schemas: map[string]*Schema{},
+		owners:  map[string]string{},
+		docs:    docs,
+		inputs:  map[*types.Named]bool{},
+	}
+}
+
+// markInput records t, and every struct type it contains, as request input
+func (b *schemaBuilder) markInput(t types.Type) {
+	switch t := t.(type) {
+	case *types.Named:
+		if b.inputs[t] {
+			return
+		}
+		b.inputs[t] = true
+		b.markInput(t.Underlying())
+	case *types.Pointer:
+		b.markInput(t.Elem())
+	case *types.Slice:
+		b.markInput(t.Elem())
+	case *types.Array:
+		b.markInput(t.Elem())
+	case *types.Map:
+		b.markInput(t.Elem())
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			b.markInput(t.Field(i).Type())
+		}
+	}
+}
+
+// schemaOf describes values of type t
+func (b *schemaBuilder) schemaOf(t types.Type) (*Schema, error) {
+	switch t := t.(type) {
+	case *types.Named:
+		return b.namedSchema(t)
+	case *types.Alias:
+		if obj := t.Obj(); obj.Pkg() != nil && obj.Pkg().Path() == "encoding/json" && obj.Name() == "RawMessage" {
+			return &Schema{}, nil
+		}
+		return b.schemaOf(types.Unalias(t))
+	case *types.Pointer:
+		return b.schemaOf(t.Elem())
+	case *types.Basic:
+		return basicSchema(t)
+	case *types.Slice:
+		if basic, ok := t.Elem().(*types.Basic); ok && basic.Kind() == types.Byte {
+			// encoding/json writes byte slices in base64
+			return &Schema{Type: "string", Format: "byte"}, nil
+		}
+		items, err := b.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case *types.Array:
+		items, err := b.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items, MinItems: intPtr(int(t.Len())), MaxItems: intPtr(int(t.Len()))}, nil
+	case *types.Map:
+		if key, ok := t.Key().Underlying().(*types.Basic); !ok || key.Info()&(types.IsString|types.IsInteger) == 0 {
+			return nil, fmt.Errorf("map keys of %s can't be marshaled", t)
+		}
+		values, err := b.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case *types.Interface:
+		// Any value
+		return &Schema{}, nil
+	case *types.Struct:
+		return b.structSchema(t, nil)
+	}
+	return nil, fmt.Errorf("no schema for %s", t)
+}
+
+func (b *schemaBuilder) namedSchema(t *types.Named) (*Schema, error) {
+	obj := t.Obj()
+	if obj.Pkg() == nil {
+		// error, the only predeclared named type
+		return &Schema{Type: "string"}, nil
+	}
+
+	switch obj.Pkg().Path() + "." + obj.Name() {
+	case "time.Time":
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case "time.Duration":
+		return &Schema{Type: "integer", Format: "int64", Description: "Duration in nanoseconds"}, nil
+	case "encoding/json.RawMessage", "encoding/json/jsontext.Value":
+		return &Schema{}, nil
+	}
+	if marshalsItself(t) {
+		return nil, fmt.Errorf("%s marshals itself to JSON and has no known schema", t)
+	}
+
+	if _, ok := t.Underlying().(*types.Struct); !ok {
+		schema, err := b.schemaOf(t.Underlying())
+		if err != nil {
+			return nil, err
+		}
+		schema.Description = b.docs.typeDoc(obj)
+		schema.Enum = enumValues(t)
+		return schema, nil
+	}
+
+	name := obj.Pkg().Name() + "." + obj.Name()
+	if owner, ok := b.owners[name]; ok {
+		if owner != obj.Pkg().Path() {
+			return nil, fmt.Errorf("schema %s is both in %s and %s", name, owner, obj.Pkg().Path())
+		}
+		return refTo(name), nil
+	}
+	b.owners[name] = obj.Pkg().Path()
+
+	// Register the schema before building it, for types that contain themselves
+	schema := &Schema{}
+	b.schemas[name] = schema
+	built, err := b.structSchema(t.Underlying().(*types.Struct), t)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	*schema = *built
+	schema.Description = b.docs.typeDoc(obj)
+	return refTo(name), nil
+}
+
+// structSchema describes a struct. owner is the named type of the struct,
+// nil for struct literals.
+func (b *schemaBuilder) structSchema(st *types.Struct, owner *types.Named) (*Schema, error) {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	input := owner != nil && b.inputs[owner]
+	if err := b.addFields(schema, st, owner, input); err != nil {
+		return nil, err
+	}
+	if len(schema.Properties) == 0 {
+		schema.Properties = nil
+	}
+	return schema, nil
+}
+
+// addFields adds the fields of st to schema, with the fields of embedded
+// structs as encoding/json promotes them
+func (b *schemaBuilder) addFields(schema *Schema, st *types.Struct, owner *types.Named, input bool) error {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		jsonTag := tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(jsonTag, ",")
+		omitEmpty := hasOption(options, "omitempty")
+
+		if field.Embedded() && name == "" {
+			embedded := field.Type()
+			if pointer, ok := embedded.(*types.Pointer); ok {
+				embedded = pointer.Elem()
+			}
+			if named, ok := embedded.(*types.Named); ok {
+				if embeddedStruct, ok := named.Underlying().(*types.Struct); ok {
+					if err := b.addFields(schema, embeddedStruct, named, input); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name()
+		}
+
+		fieldSchema, err := b.schemaOf(field.Type())
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name(), err)
+		}
+		if hasOption(options, "string") {
+			fieldSchema = &Schema{Type: "string"}
+		}
+		if owner != nil {
+			if doc := b.docs.fieldDoc(owner.Obj(), field.Name()); doc != "" {
+				fieldSchema.Description = doc
+			}
+		}
+
+		required := !input && !omitEmpty
+		if binding := tag.Get("binding"); binding != "" {
+			bindingRequired := applyBinding(fieldSchema, field.Type(), binding)
+			if input {
+				required = bindingRequired
+			}
+		}
+
+		// Nil pointers, slices and maps are marshaled as null, and requests
+		// may send null
+		if marshalsNil(field.Type()) && (input || !omitEmpty) {
+			fieldSchema = nullable(fieldSchema)
+		}
+
+		schema.Properties[name] = fieldSchema
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return nil
+}
+
+// marshalsNil reports whether values of type t can be marshaled as null
+func marshalsNil(t types.Type) bool {
+	if _, ok := t.(*types.Pointer); ok {
+		return true
+	}
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Map:
+		return true
+	}
+	return false
+}
+
+// applyBinding adds the constraints of a binding tag to the schema of a
+// field of type t, and reports whether the field is required. Rules after
+// "dive" apply to the items of a list.
+func applyBinding(schema *Schema, t types.Type, binding string) bool {
+	required := false
+	target, targetType := schema, t
+	for _, rule := range strings.Split(binding, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		if name == "dive" {
+			target, targetType = target.Items, elemType(targetType)
+			if target == nil {
+				return required
+			}
+			continue
+		}
+		if name == "required" && target == schema {
+			required = true
+			continue
+		}
+		applyRule(target, targetType, name, param)
+	}
+	return required
+}
+
+func applyRule(schema *Schema, t types.Type, name, param string) {
+	kind := kindOf(t)
+	switch name {
+	case "min", "max", "len":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+		switch kind {
+		case "string":
+			if name != "max" {
+				schema.MinLength = intPtr(n)
+			}
+			if name != "min" {
+				schema.MaxLength = intPtr(n)
+			}
+		case "array":
+			if name != "max" {
+				schema.MinItems = intPtr(n)
+			}
+			if name != "min" {
+				schema.MaxItems = intPtr(n)
+			}
+		case "number":
+			if name != "max" {
+				schema.Minimum = floatPtr(float64(n))
+			}
+			if name != "min" {
+				schema.Maximum = floatPtr(float64(n))
+			}
+		}
+	case "gte", "lte", "gt", "lt":
+		f, err := strconv.ParseFloat(param, 64)
+		if err != nil || kind != "number" {
+			return
+		}
+		switch name {
+		case "gte":
+			schema.Minimum = floatPtr(f)
+		case "lte":
+			schema.Maximum = floatPtr(f)
+		case "gt":
+			schema.ExclusiveMinimum = floatPtr(f)
+		case "lt":
+			schema.ExclusiveMaximum = floatPtr(f)
+		}
+	case "oneof":
+		schema.Enum = nil
+		for _, value := range strings.Fields(param) {
+			if kind == "number" {
+				if f, err := strconv.ParseFloat(value, 64); err == nil {
+					schema.Enum = append(schema.Enum, f)
+				}
+				continue
+			}
+			schema.Enum = append(schema.Enum, value)
+		}
+	case "email":
+		schema.Format = "email"
+	case "url", "uri":
+		schema.Format = "uri"
+	case "uuid":
+		schema.Format = "uuid"
+	case "eth_addr":
+		schema.Pattern = "^0x[0-9a-fA-F]{40}$"
+	}
+}
+
+// kindOf is how the validator measures a value of type t: the length of a
+// string, the number of items of a list, or the value of a number
+func kindOf(t types.Type) string {
+	if pointer, ok := t.Underlying().(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsString != 0:
+			return "string"
+		case u.Info()&types.IsNumeric != 0:
+			return "number"
+		}
+	case *types.Slice, *types.Array, *types.Map:
+		return "array"
+	}
+	return ""
+}
+
+func elemType(t types.Type) types.Type {
+	if pointer, ok := t.Underlying().(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		return u.Elem()
+	case *types.Array:
+		return u.Elem()
+	case *types.Map:
+		return u.Elem()
+	}
+	return nil
+}
+
+func basicSchema(t *types.Basic) (*Schema, error) {
+	switch t.Kind() {
+	case types.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case types.String:
+		return &Schema{Type: "string"}, nil
+	case types.Int, types.Int8, types.Int16:
+		return &Schema{Type: "integer"}, nil
+	case types.Int32:
+		return &Schema{Type: "integer", Format: "int32"}, nil
+	case types.Int64:
+		return &Schema{Type: "integer", Format: "int64"}, nil
+	case types.Uint, types.Uint8, types.Uint16:
+		return &Schema{Type: "integer", Minimum: floatPtr(0)}, nil
+	case types.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Minimum: floatPtr(0)}, nil
+	case types.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Minimum: floatPtr(0)}, nil
+	case types.Float32:
+		return &Schema{Type: "number", Format: "float"}, nil
+	case types.Float64:
+		return &Schema{Type: "number", Format: "double"}, nil
+	}
+	return nil, fmt.Errorf("no schema for %s", t)
+}
+
+// enumValues are the values of the constants declared with type t, in the
+// order they are declared
+func enumValues(t *types.Named) []interface{} {
+	pkg := t.Obj().Pkg()
+	var consts []*types.Const
+	for _, name := range pkg.Scope().Names() {
+		if c, ok := pkg.Scope().Lookup(name).(*types.Const); ok && types.Identical(c.Type(), t) {
+			consts = append(consts, c)
+		}
+	}
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+
+	var values []interface{}
+	for _, c := range consts {
+		switch c.Val().Kind() {
+		case constant.String:
+			values = append(values, constant.StringVal(c.Val()))
+		case constant.Int:
+			n, _ := constant.Int64Val(c.Val())
+			values = append(values, n)
+		case constant.Float:
+			f, _ := constant.Float64Val(c.Val())
+			values = append(values, f)
+		}
+	}
+	return values
+}
+
+// marshalsItself reports whether t, or a pointer to it, implements
+// json.Marshaler or encoding.TextMarshaler
+func marshalsItself(t *types.Named) bool {
+	for _, method := range []string{"MarshalJSON", "MarshalText"} {
+		if obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, method); obj != nil {
+			if _, ok := obj.(*types.Func); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasOption(options, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,225 @@
+// internal/outbox/dispatcher.go
+
+// Package outbox relays the domain events repositories record in the outbox
+// table, in commit order, to durable consumers, external brokers and live
+// in-process subscribers.
+package outbox
+
+import (
+	"context"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// consumerRetryDelay is the wait before a consumer retries a failed batch
+	consumerRetryDelay = 10 * time.Second
+
+	// sinkPublishTimeout bounds publishing one batch to a sink
+	sinkPublishTimeout = 30 * time.Second
+
+	// pruneInterval is how often events past their retention are deleted
+	pruneInterval = time.Hour
+)
+
+// ConsumerFunc processes a batch of events for a durable consumer. It runs in
+// the transaction that moves the consumer's offset past the batch: writes
+// made through tx are committed exactly once together with the offset, and
+// an error rolls both back so the batch is retried.
+type ConsumerFunc func(tx *gorm.DB, events []models.OutboxEvent) error
+
+// SubscriberFunc is called with every event committed while the dispatcher
+// runs, on every instance. Subscribers don't resume after a restart, so they
+// suit live updates only.
+type SubscriberFunc func(event models.OutboxEvent)
+
+type consumer struct {
+	name   string
+	handle ConsumerFunc
+}
+
+// Dispatcher numbers committed outbox events and relays them. Durable
+// consumers keep their offset in the database and only run on one instance
+// at a time, resuming where they left off.
+type Dispatcher struct {
+	outboxRepo   repository.OutboxRepository
+	pollInterval time.Duration
+	batchSize    int
+	retention    time.Duration
+
+	consumers   []consumer
+	subscribers []SubscriberFunc
+	sinks       []Sink
+}
+
+func NewDispatcher(outboxRepo repository.OutboxRepository, config config.OutboxConfig) *Dispatcher {
+	pollInterval := time.Duration(config.PollIntervalMs) * time.Millisecond
+	if pollInterval <= 0 {
+		pollInterval = 500 * time.Millisecond
+	}
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &Dispatcher{
+		outboxRepo:   outboxRepo,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		retention:    time.Duration(config.RetentionHours) * time.Hour,
+	}
+}
+
+// Consume registers a durable consumer. A new consumer starts from the
+// oldest retained event. Consumers must be registered before Run.
+func (d *Dispatcher) Consume(name string, handle ConsumerFunc) {
+	d.consumers = append(d.consumers, consumer{name: name, handle: handle})
+}
+
+// Subscribe registers a live subscriber. Subscribers must be registered before Run.
+func (d *Dispatcher) Subscribe(fn SubscriberFunc) {
+	d.subscribers = append(d.subscribers, fn)
+}
+
+// AddSink registers a durable consumer publishing to sink, named after it.
+// The sink is closed when Run returns.
+func (d *Dispatcher) AddSink(sink Sink) {
+	d.sinks = append(d.sinks, sink)
+	d.Consume("sink:"+sink.Name(), func(tx *gorm.DB, events []models.OutboxEvent) error {
+		ctx, cancel := context.WithTimeout(context.Background(), sinkPublishTimeout)
+		defer cancel()
+		return sink.Publish(ctx, events)
+	})
+}
+
+// Run relays events until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, c := range d.consumers {
+		wg.Add(1)
+		go func(c consumer) {
+			defer wg.Done()
+			d.runConsumer(ctx, c)
+		}(c)
+	}
+
+	d.relay(ctx)
+	wg.Wait()
+
+	for _, sink := range d.sinks {
+		if err := sink.Close(); err != nil {
+			logrus.WithError(err).WithField("sink", sink.Name()).Warn("Failed to close outbox sink")
+		}
+	}
+}
+
+// relay numbers new events, passes them to the live subscribers and prunes
+// old events
+func (d *Dispatcher) relay(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	var position uint64
+	started := false
+	var lastPruned time.Time
+
+	for {
+		d.assignSequences()
+
+		// Subscribers only see events committed after the dispatcher started
+		if len(d.subscribers) > 0 && !started {
+			sequence, err := d.outboxRepo.LastSequence()
+			if err != nil {
+				logrus.WithError(err).Error("Failed to read the outbox position")
+			} else {
+				position, started = sequence, true
+			}
+		} else if started {
+			position = d.publish(position)
+		}
+
+		if d.retention > 0 && time.Since(lastPruned) >= pruneInterval {
+			if deleted, err := d.outboxRepo.DeleteBefore(time.Now().Add(-d.retention)); err != nil {
+				logrus.WithError(err).Error("Failed to prune outbox events")
+			} else if deleted > 0 {
+				logrus.WithField("deleted", deleted).Info("Pruned outbox events")
+			}
+			lastPruned = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) assignSequences() {
+	for {
+		assigned, err := d.outboxRepo.AssignSequences(d.batchSize)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to number outbox events")
+			return
+		}
+		if assigned < int64(d.batchSize) {
+			return
+		}
+	}
+}
+
+// publish passes the events after position to the live subscribers and
+// returns the new position
+func (d *Dispatcher) publish(position uint64) uint64 {
+	for {
+		events, err := d.outboxRepo.GetAfter(position, d.batchSize)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to read outbox events")
+			return position
+		}
+
+		for _, event := range events {
+			for _, subscriber := range d.subscribers {
+				subscriber(event)
+			}
+			position = *event.Sequence
+		}
+
+		if len(events) < d.batchSize {
+			return position
+		}
+	}
+}
+
+// runConsumer feeds a durable consumer until ctx is done
+func (d *Dispatcher) runConsumer(ctx context.Context, c consumer) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		wait := ticker.C
+		for ctx.Err() == nil {
+			consumed, err := d.outboxRepo.Consume(c.name, d.batchSize, c.handle)
+			if err != nil {
+				logrus.WithError(err).WithField("consumer", c.name).Error("Outbox consumer failed, retrying")
+				wait = time.After(consumerRetryDelay)
+				break
+			}
+			if consumed < d.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-wait:
+		}
+	}
+}
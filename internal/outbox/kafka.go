@@ -0,0 +1,56 @@
+// internal/outbox/kafka.go
+package outbox
+
+import (
+	"context"
+	"strconv"
+	"survey2earn-backend/internal/models"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type kafkaSink struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaSink publishes events to a Kafka topic, keyed by survey so the
+// events of a survey stay in order on one partition. Kafka doesn't drop
+// duplicates here: consumers should skip event IDs they have already seen,
+// which are sent in the event-id header.
+func NewKafkaSink(brokers []string, topic string) Sink {
+	return &kafkaSink{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+	}
+}
+
+func (s *kafkaSink) Name() string {
+	return "kafka"
+}
+
+func (s *kafkaSink) Publish(ctx context.Context, events []models.OutboxEvent) error {
+	messages := make([]kafka.Message, len(events))
+	for i, event := range events {
+		body, err := encodeMessage(event)
+		if err != nil {
+			return err
+		}
+		messages[i] = kafka.Message{
+			Key:   []byte(strconv.FormatUint(uint64(event.SurveyID), 10)),
+			Value: body,
+			Headers: []kafka.Header{
+				{Key: "event-id", Value: []byte(event.EventID)},
+				{Key: "event-type", Value: []byte(event.Type)},
+			},
+		}
+	}
+	return s.writer.WriteMessages(ctx, messages...)
+}
+
+func (s *kafkaSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,64 @@
+// internal/outbox/nats.go
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"survey2earn-backend/internal/models"
+
+	"github.com/nats-io/nats.go"
+)
+
+type natsSink struct {
+	conn   *nats.Conn
+	js     nats.JetStreamContext
+	prefix string
+}
+
+// NewNATSSink publishes events to NATS JetStream on "<prefix>.<event type>"
+// subjects, which a stream must be configured to capture. Events are sent
+// with their event ID as Nats-Msg-Id, so JetStream drops events published
+// again within the stream's duplicate window.
+func NewNATSSink(url, prefix string) (Sink, error) {
+	conn, err := nats.Connect(url,
+		nats.Name("survey2earn-outbox"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open JetStream: %w", err)
+	}
+
+	return &natsSink{
+		conn:   conn,
+		js:     js,
+		prefix: prefix,
+	}, nil
+}
+
+func (s *natsSink) Name() string {
+	return "nats"
+}
+
+func (s *natsSink) Publish(ctx context.Context, events []models.OutboxEvent) error {
+	for _, event := range events {
+		body, err := encodeMessage(event)
+		if err != nil {
+			return err
+		}
+		if _, err := s.js.Publish(s.prefix+"."+event.Type, body, nats.MsgId(event.EventID), nats.Context(ctx)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *natsSink) Close() error {
+	return s.conn.Drain()
+}
@@ -0,0 +1,68 @@
+// internal/outbox/redis.go
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"survey2earn-backend/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type redisSink struct {
+	client *redis.Client
+	stream string
+}
+
+// NewRedisSink publishes events to a Redis stream. Entries are added with
+// the event's sequence as their ID, so Redis itself rejects an event that
+// was already published. The stream must not be written to by anything else.
+func NewRedisSink(url, stream string) (Sink, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outbox Redis URL: %w", err)
+	}
+	return &redisSink{
+		client: redis.NewClient(options),
+		stream: stream,
+	}, nil
+}
+
+func (s *redisSink) Name() string {
+	return "redis"
+}
+
+func (s *redisSink) Publish(ctx context.Context, events []models.OutboxEvent) error {
+	for _, event := range events {
+		body, err := encodeMessage(event)
+		if err != nil {
+			return err
+		}
+
+		err = s.client.XAdd(ctx, &redis.XAddArgs{
+			Stream: s.stream,
+			ID:     fmt.Sprintf("%d-0", *event.Sequence),
+			Values: map[string]interface{}{
+				"id":        event.EventID,
+				"type":      event.Type,
+				"survey_id": event.SurveyID,
+				"message":   body,
+			},
+		}).Err()
+		if err != nil && !isPublishedBefore(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *redisSink) Close() error {
+	return s.client.Close()
+}
+
+// isPublishedBefore reports whether XADD refused an entry because the stream
+// already has one with the same or a later ID
+func isPublishedBefore(err error) bool {
+	return strings.Contains(err.Error(), "equal or smaller than the target stream top item")
+}
@@ -0,0 +1,82 @@
+// internal/outbox/sink.go
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/models"
+	"time"
+)
+
+// Sink publishes outbox events to an external broker, in order. A batch is
+// published again if the consumer's offset can't be committed after it, so
+// sinks pass the event ID along for the broker or its consumers to drop
+// duplicates.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, events []models.OutboxEvent) error
+	Close() error
+}
+
+// Message is the JSON body sinks publish for an event
+type Message struct {
+	ID            string          `json:"id"`
+	Sequence      uint64          `json:"sequence"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint            `json:"aggregate_id"`
+	SurveyID      uint            `json:"survey_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+func encodeMessage(event models.OutboxEvent) ([]byte, error) {
+	message := Message{
+		ID:            event.EventID,
+		Type:          event.Type,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		SurveyID:      event.SurveyID,
+		CreatedAt:     event.CreatedAt.UTC(),
+		Data:          json.RawMessage(event.Payload),
+	}
+	if event.Sequence != nil {
+		message.Sequence = *event.Sequence
+	}
+	return json.Marshal(message)
+}
+
+// NewSinks creates the sinks enabled in config
+func NewSinks(config config.OutboxConfig) ([]Sink, error) {
+	var sinks []Sink
+
+	if config.RedisURL != "" {
+		sink, err := NewRedisSink(config.RedisURL, config.RedisStream)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if config.NATSURL != "" {
+		sink, err := NewNATSSink(config.NATSURL, config.NATSSubjectPrefix)
+		if err != nil {
+			closeSinks(sinks)
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if len(config.KafkaBrokers) > 0 {
+		sinks = append(sinks, NewKafkaSink(config.KafkaBrokers, config.KafkaTopic))
+	}
+
+	return sinks, nil
+}
+
+func closeSinks(sinks []Sink) {
+	for _, sink := range sinks {
+		sink.Close()
+	}
+}
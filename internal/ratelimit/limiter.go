@@ -0,0 +1,106 @@
+// internal/ratelimit/limiter.go
+
+// Package ratelimit limits request rates with token buckets, counted in
+// Redis when the API server runs as several instances.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"survey2earn-backend/internal/config"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Rule is a named token bucket holding up to Burst requests, refilled at
+// RequestsPerMinute. Every rule counts requests separately.
+type Rule struct {
+	Name              string
+	RequestsPerMinute int
+	Burst             int
+}
+
+// NewRule names a configured rule
+func NewRule(name string, rule config.RateLimitRule) Rule {
+	return Rule{Name: name, RequestsPerMinute: rule.RequestsPerMinute, Burst: rule.Burst}
+}
+
+// Enabled reports whether the rule limits anything
+func (r Rule) Enabled() bool {
+	return r.RequestsPerMinute > 0
+}
+
+// capacity is the bucket size; without a burst it holds one request
+func (r Rule) capacity() float64 {
+	if r.Burst < 1 {
+		return 1
+	}
+	return float64(r.Burst)
+}
+
+// ratePerMs is how many tokens the bucket gains per millisecond
+func (r Rule) ratePerMs() float64 {
+	return float64(r.RequestsPerMinute) / float64(time.Minute/time.Millisecond)
+}
+
+// Result is the outcome of taking a request from a bucket
+type Result struct {
+	Allowed    bool
+	Limit      int           // bucket size
+	Remaining  int           // whole requests left in the bucket
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next request is allowed, if this one wasn't
+}
+
+// newResult describes a bucket left with tokens after a request was, or
+// wasn't, taken from it
+func newResult(rule Rule, allowed bool, tokens float64) Result {
+	rate := rule.ratePerMs()
+	result := Result{
+		Allowed:   allowed,
+		Limit:     int(rule.capacity()),
+		Remaining: int(math.Floor(tokens)),
+		Reset:     msDuration((rule.capacity() - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = msDuration((1 - tokens) / rate)
+	}
+	return result
+}
+
+func msDuration(ms float64) time.Duration {
+	if ms <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(ms)) * time.Millisecond
+}
+
+// Limiter takes requests from the token buckets of clients
+type Limiter interface {
+	// Allow takes a request from the bucket of rule for key
+	Allow(ctx context.Context, rule Rule, key string) (Result, error)
+
+	Close() error
+}
+
+// New returns the limiter configured by cfg. The Redis driver falls back to
+// counting in this process when Redis can't be reached, so the server still
+// starts; every instance then allows the full rate.
+func New(redisConfig config.RedisConfig, cfg config.RateLimitConfig) Limiter {
+	switch cfg.Driver {
+	case "memory":
+		return NewMemory()
+	case "redis", "":
+	default:
+		logrus.WithField("driver", cfg.Driver).Warn("Unknown rate limit driver, counting in memory")
+		return NewMemory()
+	}
+
+	limiter, err := NewRedis(redisConfig, cfg.KeyPrefix)
+	if err != nil {
+		logrus.WithError(err).Warn("Redis is unavailable, counting rate limits in memory")
+		return NewMemory()
+	}
+	return limiter
+}
@@ -0,0 +1,93 @@
+// internal/ratelimit/memory.go
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is how often buckets that have filled up again are
+// dropped
+const memorySweepInterval = time.Minute
+
+type memoryLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+	done    chan struct{}
+	closed  sync.Once
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // when the bucket will be full again
+}
+
+// NewMemory returns a limiter counting in this process only
+func NewMemory() Limiter {
+	l := &memoryLimiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+		done:    make(chan struct{}),
+	}
+	go l.sweep()
+	return l
+}
+
+func (l *memoryLimiter) Allow(ctx context.Context, rule Rule, key string) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	capacity := rule.capacity()
+	rate := rule.ratePerMs()
+
+	id := rule.Name + ":" + key
+	b := l.buckets[id]
+	if b == nil {
+		b = &bucket{tokens: capacity, updated: now}
+		l.buckets[id] = b
+	}
+
+	elapsed := float64(now.Sub(b.updated)) / float64(time.Millisecond)
+	b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	result := newResult(rule, allowed, b.tokens)
+	b.full = now.Add(result.Reset)
+	return result, nil
+}
+
+func (l *memoryLimiter) Close() error {
+	l.closed.Do(func() { close(l.done) })
+	return nil
+}
+
+// sweep drops full buckets, which are the same as missing ones, until the
+// limiter is closed
+func (l *memoryLimiter) sweep() {
+	ticker := time.NewTicker(memorySweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case now := <-ticker.C:
+			l.mu.Lock()
+			for id, b := range l.buckets {
+				if !now.Before(b.full) {
+					delete(l.buckets, id)
+				}
+			}
+			l.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// internal/ratelimit/memory_test.go
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock tests move by hand
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestMemory(t *testing.T) (*memoryLimiter, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{now: time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)}
+	l := NewMemory().(*memoryLimiter)
+	l.now = clock.Now
+	t.Cleanup(func() { l.Close() })
+	return l, clock
+}
+
+// take takes a request from the bucket of key, failing the test on errors
+func take(t *testing.T, l Limiter, rule Rule, key string) Result {
+	t.Helper()
+	result, err := l.Allow(context.Background(), rule, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestMemoryBurst(t *testing.T) {
+	l, _ := newTestMemory(t)
+	rule := Rule{Name: "login", RequestsPerMinute: 6, Burst: 3}
+
+	// A full bucket lets the burst through at once
+	for i, remaining := range []int{2, 1, 0} {
+		result := take(t, l, rule, "ip:203.0.113.7")
+		if !result.Allowed || result.Limit != 3 || result.Remaining != remaining {
+			t.Fatalf("request %d = %+v, want allowed with %d remaining of 3", i, result, remaining)
+		}
+	}
+
+	// The next token comes after a tenth of a minute
+	result := take(t, l, rule, "ip:203.0.113.7")
+	if result.Allowed {
+		t.Fatalf("request over the burst = %+v, want refused", result)
+	}
+	if result.RetryAfter != 10*time.Second || result.Reset != 30*time.Second {
+		t.Errorf("RetryAfter, Reset = %v, %v, want 10s, 30s", result.RetryAfter, result.Reset)
+	}
+
+	// Other clients and rules have buckets of their own
+	if result := take(t, l, rule, "ip:198.51.100.1"); !result.Allowed {
+		t.Errorf("another client = %+v, want allowed", result)
+	}
+	if result := take(t, l, Rule{Name: "default", RequestsPerMinute: 6, Burst: 3}, "ip:203.0.113.7"); !result.Allowed {
+		t.Errorf("another rule = %+v, want allowed", result)
+	}
+}
+
+func TestMemoryRefill(t *testing.T) {
+	l, clock := newTestMemory(t)
+	rule := Rule{Name: "login", RequestsPerMinute: 6, Burst: 3}
+	for i := 0; i < 3; i++ {
+		take(t, l, rule, "user:1")
+	}
+
+	// Part of a token isn't enough
+	clock.Advance(9 * time.Second)
+	if result := take(t, l, rule, "user:1"); result.Allowed || result.RetryAfter != time.Second {
+		t.Fatalf("after 9s = %+v, want refused for another second", result)
+	}
+
+	// A refused request takes nothing, so the token is whole a second later
+	clock.Advance(time.Second)
+	if result := take(t, l, rule, "user:1"); !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("after 10s = %+v, want allowed with none remaining", result)
+	}
+
+	// The bucket never holds more than the burst
+	clock.Advance(time.Hour)
+	for i, remaining := range []int{2, 1, 0} {
+		if result := take(t, l, rule, "user:1"); !result.Allowed || result.Remaining != remaining {
+			t.Fatalf("request %d after an hour = %+v, want allowed with %d remaining", i, result, remaining)
+		}
+	}
+	if result := take(t, l, rule, "user:1"); result.Allowed {
+		t.Fatalf("request over the burst after an hour = %+v, want refused", result)
+	}
+}
+
+func TestMemoryWithoutBurst(t *testing.T) {
+	l, clock := newTestMemory(t)
+	rule := Rule{Name: "start_response", RequestsPerMinute: 2}
+
+	// Without a burst the bucket holds one request
+	if result := take(t, l, rule, "user:1"); !result.Allowed || result.Limit != 1 {
+		t.Fatalf("first request = %+v, want allowed with a limit of 1", result)
+	}
+	if result := take(t, l, rule, "user:1"); result.Allowed || result.RetryAfter != 30*time.Second {
+		t.Fatalf("second request = %+v, want refused for 30s", result)
+	}
+	clock.Advance(30 * time.Second)
+	if result := take(t, l, rule, "user:1"); !result.Allowed {
+		t.Fatalf("request after 30s = %+v, want allowed", result)
+	}
+}
+
+func TestRuleEnabled(t *testing.T) {
+	if (Rule{Burst: 5}).Enabled() {
+		t.Error("a rule without a rate is enabled, want disabled")
+	}
+	if !(Rule{RequestsPerMinute: 1}).Enabled() {
+		t.Error("a rule with a rate is disabled, want enabled")
+	}
+}
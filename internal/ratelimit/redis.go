@@ -0,0 +1,106 @@
+// internal/ratelimit/redis.go
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"survey2earn-backend/internal/config"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis timeouts are short: when Redis is slow requests are let through
+// rather than held up
+const (
+	redisDialTimeout = 2 * time.Second
+	redisIOTimeout   = 500 * time.Millisecond
+)
+
+// takeScript refills a bucket for the time since it was last used, takes a
+// token if there is one and returns whether it did with the tokens left. It
+// uses the Redis clock so instances with skewed clocks agree. Buckets expire
+// once they would be full again.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil or updated == nil then
+	tokens = capacity
+	updated = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - updated) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((capacity - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+type redisLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedis connects to Redis and checks that it is reachable. Buckets are
+// stored under prefix.
+func NewRedis(cfg config.RedisConfig, prefix string) (Limiter, error) {
+	options, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	if cfg.Password != "" {
+		options.Password = cfg.Password
+	}
+	if cfg.DB != 0 {
+		options.DB = cfg.DB
+	}
+	options.DialTimeout = redisDialTimeout
+	options.ReadTimeout = redisIOTimeout
+	options.WriteTimeout = redisIOTimeout
+
+	client := redis.NewClient(options)
+	ctx, cancel := context.WithTimeout(context.Background(), redisDialTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &redisLimiter{client: client, prefix: prefix}, nil
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, rule Rule, key string) (Result, error) {
+	values, err := takeScript.Run(ctx, l.client, []string{l.prefix + rule.Name + ":" + key},
+		rule.capacity(), rule.ratePerMs()).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(values) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result %v", values)
+	}
+
+	allowed, _ := values[0].(int64)
+	left, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(left, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("unexpected rate limit script result %v", values)
+	}
+	return newResult(rule, allowed == 1, tokens), nil
+}
+
+func (l *redisLimiter) Close() error {
+	return l.client.Close()
+}
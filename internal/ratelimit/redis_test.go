@@ -0,0 +1,76 @@
+// internal/ratelimit/redis_test.go
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"survey2earn-backend/internal/config"
+	"testing"
+	"time"
+)
+
+// testRedisEnv names the Redis the script tests run against; they are
+// skipped without it
+const testRedisEnv = "TEST_REDIS_URL"
+
+func newTestRedis(t *testing.T) *redisLimiter {
+	t.Helper()
+	url := os.Getenv(testRedisEnv)
+	if url == "" {
+		t.Skipf("%s is not set", testRedisEnv)
+	}
+
+	// A prefix of its own keeps the test's buckets apart from other runs
+	l, err := NewRedis(config.RedisConfig{URL: url}, fmt.Sprintf("test:ratelimit:%d:", time.Now().UnixNano()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l.(*redisLimiter)
+}
+
+func TestRedisBurst(t *testing.T) {
+	l := newTestRedis(t)
+	rule := Rule{Name: "login", RequestsPerMinute: 1, Burst: 3}
+
+	for i, remaining := range []int{2, 1, 0} {
+		result := take(t, l, rule, "ip:203.0.113.7")
+		if !result.Allowed || result.Limit != 3 || result.Remaining != remaining {
+			t.Fatalf("request %d = %+v, want allowed with %d remaining of 3", i, result, remaining)
+		}
+	}
+	result := take(t, l, rule, "ip:203.0.113.7")
+	if result.Allowed || result.RetryAfter <= 55*time.Second || result.RetryAfter > time.Minute {
+		t.Fatalf("request over the burst = %+v, want refused for about a minute", result)
+	}
+	if result := take(t, l, rule, "ip:198.51.100.1"); !result.Allowed {
+		t.Errorf("another client = %+v, want allowed", result)
+	}
+
+	// Buckets expire once they would be full again
+	ttl, err := l.client.PTTL(context.Background(), l.prefix+"login:ip:203.0.113.7").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl <= 3*time.Minute || ttl > 3*time.Minute+time.Second {
+		t.Errorf("bucket expires in %v, want 3m plus a second", ttl)
+	}
+}
+
+func TestRedisRefill(t *testing.T) {
+	l := newTestRedis(t)
+	// A token every 100ms
+	rule := Rule{Name: "login", RequestsPerMinute: 600, Burst: 2}
+
+	take(t, l, rule, "user:1")
+	take(t, l, rule, "user:1")
+	if result := take(t, l, rule, "user:1"); result.Allowed {
+		t.Fatalf("request over the burst = %+v, want refused", result)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if result := take(t, l, rule, "user:1"); !result.Allowed {
+		t.Fatalf("request after a refill = %+v, want allowed", result)
+	}
+}
@@ -0,0 +1,69 @@
+// internal/repository/auth_session_repository.go
+package repository
+
+import (
+	"survey2earn-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AuthSessionRepository stores sign-in sessions. A session's Token is the
+// hash of its refresh token, never the token itself.
+type AuthSessionRepository interface {
+	Create(session *models.AuthSession) error
+	GetByToken(tokenHash string) (*models.AuthSession, error)
+	Rotate(old, next *models.AuthSession) error
+	Deactivate(userID, sessionID uint) error
+	DeactivateAllForUser(userID uint) error
+}
+
+type authSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthSessionRepository(db *gorm.DB) AuthSessionRepository {
+	return &authSessionRepository{db: db}
+}
+
+func (r *authSessionRepository) Create(session *models.AuthSession) error {
+	return r.db.Create(session).Error
+}
+
+func (r *authSessionRepository) GetByToken(tokenHash string) (*models.AuthSession, error) {
+	var session models.AuthSession
+	err := r.db.Where("token = ?", tokenHash).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Rotate replaces an active session with next. It fails with
+// gorm.ErrRecordNotFound if old was already rotated or revoked, so a refresh
+// token can only be used once even by concurrent requests.
+func (r *authSessionRepository) Rotate(old, next *models.AuthSession) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AuthSession{}).
+			Where("id = ? AND is_active = ?", old.ID, true).
+			Update("is_active", false)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(next).Error
+	})
+}
+
+func (r *authSessionRepository) Deactivate(userID, sessionID uint) error {
+	return r.db.Model(&models.AuthSession{}).
+		Where("id = ? AND user_id = ?", sessionID, userID).
+		Update("is_active", false).Error
+}
+
+func (r *authSessionRepository) DeactivateAllForUser(userID uint) error {
+	return r.db.Model(&models.AuthSession{}).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Update("is_active", false).Error
+}
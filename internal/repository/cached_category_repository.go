@@ -0,0 +1,74 @@
+// internal/repository/cached_category_repository.go
+package repository
+
+import (
+	"context"
+	"survey2earn-backend/internal/cache"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/models"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// categoriesCacheKey holds the cached category list
+const categoriesCacheKey = "categories"
+
+type cachedCategoryRepository struct {
+	CategoryRepository
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedCategoryRepository caches the category list, which every public
+// survey listing reads, in front of categoryRepo. Writes through it drop the
+// cached list.
+func NewCachedCategoryRepository(categoryRepo CategoryRepository, c cache.Cache, config config.CacheConfig) CategoryRepository {
+	return &cachedCategoryRepository{
+		CategoryRepository: categoryRepo,
+		cache:              c,
+		ttl:                time.Duration(config.CategoriesTTLSeconds) * time.Second,
+	}
+}
+
+func (r *cachedCategoryRepository) List() ([]models.Category, error) {
+	if r.ttl <= 0 {
+		return r.CategoryRepository.List()
+	}
+
+	var categories []models.Category
+	err := cache.Fetch(context.Background(), r.cache, categoriesCacheKey, r.ttl, &categories, func() (interface{}, error) {
+		return r.CategoryRepository.List()
+	})
+	return categories, err
+}
+
+func (r *cachedCategoryRepository) Create(category *models.Category) error {
+	if err := r.CategoryRepository.Create(category); err != nil {
+		return err
+	}
+	r.dropList()
+	return nil
+}
+
+func (r *cachedCategoryRepository) Update(category *models.Category) error {
+	if err := r.CategoryRepository.Update(category); err != nil {
+		return err
+	}
+	r.dropList()
+	return nil
+}
+
+func (r *cachedCategoryRepository) Delete(id uint) error {
+	if err := r.CategoryRepository.Delete(id); err != nil {
+		return err
+	}
+	r.dropList()
+	return nil
+}
+
+func (r *cachedCategoryRepository) dropList() {
+	if err := r.cache.Delete(context.Background(), categoriesCacheKey); err != nil {
+		logrus.WithError(err).Warn("Failed to drop the cached categories")
+	}
+}
@@ -0,0 +1,193 @@
+// internal/repository/cached_survey_repository.go
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"survey2earn-backend/internal/cache"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/models"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// publicSurveysGenerationKey is the generation counter of the cached public
+// survey pages
+const publicSurveysGenerationKey = "surveys:public:generation"
+
+type cachedSurveyRepository struct {
+	SurveyRepository
+	cache            cache.Cache
+	surveyTTL        time.Duration
+	publicSurveysTTL time.Duration
+}
+
+// NewCachedSurveyRepository caches surveys with their questions, creator and
+// quotas, and the pages of public surveys, in front of surveyRepo. Writes
+// through it drop the affected entries right away; changes made elsewhere
+// reach the cache through InvalidateSurveyCache.
+func NewCachedSurveyRepository(surveyRepo SurveyRepository, c cache.Cache, config config.CacheConfig) SurveyRepository {
+	return &cachedSurveyRepository{
+		SurveyRepository: surveyRepo,
+		cache:            c,
+		surveyTTL:        time.Duration(config.SurveyTTLSeconds) * time.Second,
+		publicSurveysTTL: time.Duration(config.PublicSurveysTTLSeconds) * time.Second,
+	}
+}
+
+func (r *cachedSurveyRepository) GetByID(id uint) (*models.Survey, error) {
+	if r.surveyTTL <= 0 {
+		return r.SurveyRepository.GetByID(id)
+	}
+
+	var survey models.Survey
+	err := cache.Fetch(context.Background(), r.cache, surveyCacheKey(id), r.surveyTTL, &survey, func() (interface{}, error) {
+		return r.SurveyRepository.GetByID(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &survey, nil
+}
+
+func (r *cachedSurveyRepository) GetPublicSurveys(filter *PublicSurveyFilter) (*PublicSurveyPage, error) {
+	if r.publicSurveysTTL <= 0 {
+		return r.SurveyRepository.GetPublicSurveys(filter)
+	}
+
+	ctx := context.Background()
+	generation := cache.Generation(ctx, r.cache, publicSurveysGenerationKey)
+	key := fmt.Sprintf("surveys:public:%d:%s", generation, filter.cacheKey())
+
+	var page PublicSurveyPage
+	err := cache.Fetch(ctx, r.cache, key, r.publicSurveysTTL, &page, func() (interface{}, error) {
+		return r.SurveyRepository.GetPublicSurveys(filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// CountPublicSurveysByCategory is cached with the public survey pages, so it
+// changes whenever they do
+func (r *cachedSurveyRepository) CountPublicSurveysByCategory(filter *PublicSurveyFilter) (map[string]int64, error) {
+	if r.publicSurveysTTL <= 0 {
+		return r.SurveyRepository.CountPublicSurveysByCategory(filter)
+	}
+
+	// Counts ignore the categories, the page and the order
+	countFilter := *filter
+	countFilter.Categories, countFilter.Sort, countFilter.After, countFilter.Offset, countFilter.Limit = nil, "", nil, 0, 0
+
+	ctx := context.Background()
+	generation := cache.Generation(ctx, r.cache, publicSurveysGenerationKey)
+	key := fmt.Sprintf("surveys:public:%d:category-counts:%s", generation, countFilter.cacheKey())
+
+	var counts map[string]int64
+	err := cache.Fetch(ctx, r.cache, key, r.publicSurveysTTL, &counts, func() (interface{}, error) {
+		return r.SurveyRepository.CountPublicSurveysByCategory(filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *cachedSurveyRepository) Create(survey *models.Survey) error {
+	if err := r.SurveyRepository.Create(survey); err != nil {
+		return err
+	}
+	InvalidatePublicSurveysCache(r.cache)
+	return nil
+}
+
+func (r *cachedSurveyRepository) Update(survey *models.Survey) error {
+	if err := r.SurveyRepository.Update(survey); err != nil {
+		return err
+	}
+	InvalidateSurveyCache(r.cache, survey.ID)
+	return nil
+}
+
+func (r *cachedSurveyRepository) Delete(id uint) error {
+	if err := r.SurveyRepository.Delete(id); err != nil {
+		return err
+	}
+	InvalidateSurveyCache(r.cache, id)
+	return nil
+}
+
+func (r *cachedSurveyRepository) DeleteQuestions(surveyID uint) error {
+	if err := r.SurveyRepository.DeleteQuestions(surveyID); err != nil {
+		return err
+	}
+	DropCachedSurvey(r.cache, surveyID)
+	return nil
+}
+
+func (r *cachedSurveyRepository) UpdateQuestionConditions(questions []models.Question) error {
+	if err := r.SurveyRepository.UpdateQuestionConditions(questions); err != nil {
+		return err
+	}
+	dropped := make(map[uint]bool)
+	for _, question := range questions {
+		if !dropped[question.SurveyID] {
+			DropCachedSurvey(r.cache, question.SurveyID)
+			dropped[question.SurveyID] = true
+		}
+	}
+	return nil
+}
+
+func (r *cachedSurveyRepository) PublishWithRewardPool(survey *models.Survey, pool *models.RewardPool) error {
+	if err := r.SurveyRepository.PublishWithRewardPool(survey, pool); err != nil {
+		return err
+	}
+	InvalidateSurveyCache(r.cache, survey.ID)
+	return nil
+}
+
+func (r *cachedSurveyRepository) UpdateStatistics(surveyID uint) error {
+	if err := r.SurveyRepository.UpdateStatistics(surveyID); err != nil {
+		return err
+	}
+	DropCachedSurvey(r.cache, surveyID)
+	return nil
+}
+
+// InvalidateSurveyCache drops a cached survey and the cached public survey
+// pages, which may list it
+func InvalidateSurveyCache(c cache.Cache, surveyID uint) {
+	DropCachedSurvey(c, surveyID)
+	InvalidatePublicSurveysCache(c)
+}
+
+// InvalidatePublicSurveysCache drops the cached public survey pages
+func InvalidatePublicSurveysCache(c cache.Cache) {
+	cache.InvalidateGeneration(context.Background(), c, publicSurveysGenerationKey)
+}
+
+// DropCachedSurvey drops a cached survey only, for changes that don't show
+// in survey lists
+func DropCachedSurvey(c cache.Cache, surveyID uint) {
+	if err := c.Delete(context.Background(), surveyCacheKey(surveyID)); err != nil {
+		logrus.WithError(err).WithField("survey_id", surveyID).Warn("Failed to drop cached survey")
+	}
+}
+
+// cacheKey identifies the filter in cache keys: a hash of all its fields,
+// which keeps keys short whatever the search query
+func (f *PublicSurveyFilter) cacheKey() string {
+	data, _ := json.Marshal(f)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+func surveyCacheKey(surveyID uint) string {
+	return fmt.Sprintf("survey:%d", surveyID)
+}
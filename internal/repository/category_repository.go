@@ -0,0 +1,72 @@
+// internal/repository/category_repository.go
+package repository
+
+import (
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type CategoryRepository interface {
+	Create(category *models.Category) error
+	Update(category *models.Category) error
+	GetByID(id uint) (*models.Category, error)
+	GetBySlug(slug string) (*models.Category, error)
+	List() ([]models.Category, error)
+	Delete(id uint) error
+	CountUsage(slug string) (int64, error)
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(category *models.Category) error {
+	return r.db.Create(category).Error
+}
+
+func (r *categoryRepository) Update(category *models.Category) error {
+	return r.db.Save(category).Error
+}
+
+func (r *categoryRepository) GetByID(id uint) (*models.Category, error) {
+	var category models.Category
+	err := r.db.First(&category, id).Error
+	return &category, apperror.NotFoundAs(err, "Category not found")
+}
+
+func (r *categoryRepository) GetBySlug(slug string) (*models.Category, error) {
+	var category models.Category
+	err := r.db.Where("slug = ?", slug).First(&category).Error
+	return &category, apperror.NotFoundAs(err, "Category not found")
+}
+
+// List returns every category, active or not, in display order. There are
+// few enough categories to build the hierarchy in memory.
+func (r *categoryRepository) List() ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Order("sort_order, id").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Category{}, id).Error
+}
+
+// CountUsage counts the surveys, templates and bank questions in the
+// category. Soft-deleted surveys and templates count, since their rows
+// still refer to it.
+func (r *categoryRepository) CountUsage(slug string) (int64, error) {
+	var usage int64
+	err := r.db.Raw(`SELECT
+			(SELECT COUNT(*) FROM surveys WHERE category = @slug) +
+			(SELECT COUNT(*) FROM survey_templates WHERE category = @slug) +
+			(SELECT COUNT(*) FROM bank_questions WHERE category = @slug AND deleted_at IS NULL)`,
+		map[string]interface{}{"slug": slug}).Scan(&usage).Error
+	return usage, err
+}
@@ -0,0 +1,83 @@
+// internal/repository/export_job_repository.go
+package repository
+
+import (
+	"errors"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExportJobRepository interface {
+	Create(job *models.ExportJob) error
+	Update(job *models.ExportJob) error
+	GetByID(id uint) (*models.ExportJob, error)
+	ClaimPending() (*models.ExportJob, error)
+	Touch(id uint) error
+	RequeueStale(before time.Time) (int64, error)
+}
+
+type exportJobRepository struct {
+	db *gorm.DB
+}
+
+func NewExportJobRepository(db *gorm.DB) ExportJobRepository {
+	return &exportJobRepository{db: db}
+}
+
+func (r *exportJobRepository) Create(job *models.ExportJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *exportJobRepository) Update(job *models.ExportJob) error {
+	return r.db.Save(job).Error
+}
+
+func (r *exportJobRepository) GetByID(id uint) (*models.ExportJob, error) {
+	var job models.ExportJob
+	err := r.db.First(&job, id).Error
+	return &job, apperror.NotFoundAs(err, "Export job not found")
+}
+
+// ClaimPending marks the oldest pending job as processing and returns it, or
+// nil when none is pending. Jobs locked by another worker are skipped rather
+// than waited for.
+func (r *exportJobRepository) ClaimPending() (*models.ExportJob, error) {
+	var job models.ExportJob
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", models.ExportJobStatusPending).
+			Order("id").Take(&job).Error
+		if err != nil {
+			return err
+		}
+
+		job.Status = models.ExportJobStatusProcessing
+		return tx.Model(&job).Update("status", job.Status).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Touch records that a processing job is still being worked on
+func (r *exportJobRepository) Touch(id uint) error {
+	return r.db.Model(&models.ExportJob{}).Where("id = ?", id).Update("updated_at", time.Now()).Error
+}
+
+// RequeueStale puts processing jobs that haven't been touched since before
+// back in the pending queue, such as those of a server that stopped without
+// finishing them. It returns how many were requeued.
+func (r *exportJobRepository) RequeueStale(before time.Time) (int64, error) {
+	result := r.db.Model(&models.ExportJob{}).
+		Where("status = ? AND updated_at < ?", models.ExportJobStatusProcessing, before).
+		Update("status", models.ExportJobStatusPending)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,89 @@
+// internal/repository/idempotency_repository.go
+package repository
+
+import (
+	"errors"
+	"survey2earn-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository interface {
+	Claim(key *models.IdempotencyKey) (*models.IdempotencyKey, bool, error)
+	Complete(id uint, statusCode int, contentType string, body []byte) error
+	Release(id uint) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Claim saves key unless the user already holds the same key and it hasn't
+// expired. It returns the record holding the key, and whether that is key.
+func (r *idempotencyRepository) Claim(key *models.IdempotencyKey) (*models.IdempotencyKey, bool, error) {
+	var existing models.IdempotencyKey
+	claimed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(key).Error; err != nil {
+			return err
+		}
+		if key.ID != 0 {
+			claimed = true
+			return nil
+		}
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND key = ?", key.UserID, key.Key).Take(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Released since, so free again
+			claimed = true
+			return tx.Create(key).Error
+		}
+		if err != nil || existing.ExpiresAt.After(time.Now()) {
+			return err
+		}
+
+		// An expired key is free again
+		if err := tx.Delete(&existing).Error; err != nil {
+			return err
+		}
+		claimed = true
+		return tx.Create(key).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if claimed {
+		return key, true, nil
+	}
+	return &existing, false, nil
+}
+
+// Complete stores the response to the request a key was claimed for
+func (r *idempotencyRepository) Complete(id uint, statusCode int, contentType string, body []byte) error {
+	return r.db.Model(&models.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status_code":   statusCode,
+		"content_type":  contentType,
+		"response_body": body,
+		"completed_at":  time.Now(),
+	}).Error
+}
+
+// Release frees a key whose request failed before it completed, so it can
+// be retried
+func (r *idempotencyRepository) Release(id uint) error {
+	return r.db.Where("id = ? AND completed_at IS NULL", id).Delete(&models.IdempotencyKey{}).Error
+}
+
+// DeleteExpired deletes the keys that expired before now
+func (r *idempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,64 @@
+// internal/repository/interfaces.go
+package repository
+
+import (
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"time"
+)
+
+type UserRepository interface {
+	Create(user *models.User) error
+	GetByID(id uint) (*models.User, error)
+	GetByWalletAddress(address string) (*models.User, error)
+	GetByUsername(username string) (*models.User, error)
+	GetByEmail(email string) (*models.User, error)
+	Update(user *models.User) error
+	GetStats(userID uint) (*models.UserStats, error)
+	List(page, limit int) ([]models.User, int64, error)
+}
+
+type SurveyRepository interface {
+	Create(survey *models.Survey) error
+	Update(survey *models.Survey) error
+	GetByID(id uint) (*models.Survey, error)
+	GetByUserID(userID uint, status string, page, limit int) ([]models.Survey, int64, error)
+	List(status string, page, limit int) ([]models.Survey, int64, error)
+	GetPublicSurveys(filter *PublicSurveyFilter) (*PublicSurveyPage, error)
+	CountPublicSurveysByCategory(filter *PublicSurveyFilter) (map[string]int64, error)
+	GetRecommendationCandidates(limit int) ([]RecommendationCandidate, error)
+	Delete(id uint) error
+	DeleteQuestions(surveyID uint) error
+	UpdateQuestionConditions(questions []models.Question) error
+	PublishWithRewardPool(survey *models.Survey, pool *models.RewardPool) error
+	UpdateStatistics(surveyID uint) error
+}
+
+type ResponseRepository interface {
+	Create(response *models.Response) error
+	Update(response *models.Response) error
+	GetByID(id uint) (*models.Response, error)
+	GetWithAnswers(id uint) (*models.Response, error)
+	GetByUserID(userID uint, req *dto.ListResponsesRequest) ([]models.Response, int64, error)
+	GetBySurveyID(surveyID uint, filter *ResponseFilter) ([]models.Response, int64, error)
+	GetCrosstabCounts(surveyID, rowQuestionID, columnQuestionID uint, filter *ResponseFilter) ([]CrosstabCount, error)
+	GetAllBySurveyID(surveyID uint) ([]models.Response, error)
+	CountBySurveyID(surveyID uint) (int64, error)
+	FindInBatchesBySurveyID(surveyID uint, batchSize int, fn func(responses []models.Response) error) error
+	HasUserResponded(userID, surveyID uint) (bool, error)
+	GetCategoryHistory(userID uint) ([]CategoryHistory, error)
+	SaveAnswers(responseID uint, expectedVersion int, answers []*models.Answer) (int, error)
+	AbandonStale(idleSince time.Time, batchSize int) (int, error)
+	GetPastDeadline(now time.Time, afterID uint, limit int) ([]uint, error)
+}
+
+type RewardRepository interface {
+	GetPoolBySurveyID(surveyID uint) (*models.RewardPool, error)
+	ProcessReward(pool *models.RewardPool, transaction *models.RewardTransaction, events ...models.OutboxEvent) error
+	ProcessRewardWithQuotas(response *models.Response, pool *models.RewardPool, transaction *models.RewardTransaction, quotas []models.SurveyQuota, maxResponses int, events ...models.OutboxEvent) error
+	CreateTransaction(transaction *models.RewardTransaction) error
+	UpdatePool(pool *models.RewardPool) error
+	RequeueFailedTransactions() (requeued, exhausted int64, err error)
+	GetLedger(userID uint) (*RewardLedger, error)
+	GetTransactionsByUserID(userID uint, txType, status string, page, limit int) ([]models.RewardTransaction, int64, error)
+}
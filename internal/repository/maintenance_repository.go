@@ -0,0 +1,193 @@
+// internal/repository/maintenance_repository.go
+package repository
+
+import (
+	"survey2earn-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BalanceLedger is a user's balance as the reward ledger has it, next to the
+// balance recorded for them
+type BalanceLedger struct {
+	UserID uint
+
+	// From reward_transactions: rewards that weren't failed or cancelled,
+	// completed withdrawals and withdrawals in progress
+	Earned    float64
+	Withdrawn float64
+	Pending   float64
+
+	// Recorded on the user and in user_balances
+	UserTotalEarned  float64
+	HasBalance       bool
+	BalanceEarned    float64
+	BalanceWithdrawn float64
+	BalancePending   float64
+	BalanceAvailable float64
+}
+
+// MaintenanceRepository backs the operator tasks that rebuild derived data
+// across many rows
+type MaintenanceRepository interface {
+	RecomputeSurveyStatistics(surveyID uint) (int64, error)
+	RecomputeUserStats(userID uint) (int64, error)
+	FindBalanceLedgersInBatches(batchSize int, fn func(ledgers []BalanceLedger) error) error
+	SaveBalances(balances []models.UserBalance) error
+}
+
+type maintenanceRepository struct {
+	db *gorm.DB
+}
+
+func NewMaintenanceRepository(db *gorm.DB) MaintenanceRepository {
+	return &maintenanceRepository{db: db}
+}
+
+// RecomputeSurveyStatistics rebuilds the response summary, response count and
+// completion rate of a survey, or of every survey when surveyID is 0
+func (r *maintenanceRepository) RecomputeSurveyStatistics(surveyID uint) (int64, error) {
+	return recomputeSurveyStatistics(r.db, surveyID)
+}
+
+// RecomputeUserStats rebuilds the user_stats row and the survey and response
+// counters of a user, or of every user when userID is 0, with the figures
+// UserRepository.GetStats computes
+func (r *maintenanceRepository) RecomputeUserStats(userID uint) (int64, error) {
+	scope, scopeArgs := "", []interface{}{}
+	if userID != 0 {
+		scope, scopeArgs = " AND u.id = ?", []interface{}{userID}
+	}
+
+	var updated int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		args := append([]interface{}{models.ResponseStatusCompleted}, scopeArgs...)
+		err := tx.Exec(`INSERT INTO user_stats
+			(user_id, total_surveys_created, total_surveys_answered, total_earned, total_spent, average_rating, last_activity_at)
+			SELECT u.id,
+				(SELECT COUNT(*) FROM surveys s WHERE s.creator_id = u.id AND s.deleted_at IS NULL),
+				(SELECT COUNT(*) FROM responses r WHERE r.user_id = u.id AND r.status = ? AND r.deleted_at IS NULL),
+				u.total_earned,
+				(SELECT COALESCE(SUM(p.paid_out), 0) FROM reward_pools p
+					JOIN surveys s ON s.id = p.survey_id
+					WHERE s.creator_id = u.id AND p.deleted_at IS NULL),
+				(SELECT COALESCE(AVG(NULLIF(s.average_rating, 0)), 0) FROM surveys s
+					WHERE s.creator_id = u.id AND s.deleted_at IS NULL),
+				GREATEST(
+					(SELECT MAX(s.updated_at) FROM surveys s WHERE s.creator_id = u.id AND s.deleted_at IS NULL),
+					(SELECT MAX(r.updated_at) FROM responses r WHERE r.user_id = u.id AND r.deleted_at IS NULL))
+			FROM users u
+			WHERE u.deleted_at IS NULL`+scope+`
+			ON CONFLICT (user_id) DO UPDATE SET
+				total_surveys_created = EXCLUDED.total_surveys_created,
+				total_surveys_answered = EXCLUDED.total_surveys_answered,
+				total_earned = EXCLUDED.total_earned,
+				total_spent = EXCLUDED.total_spent,
+				average_rating = EXCLUDED.average_rating,
+				last_activity_at = EXCLUDED.last_activity_at`, args...).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Exec(`UPDATE users u SET
+				total_surveys = us.total_surveys_created,
+				total_responses = us.total_surveys_answered
+			FROM user_stats us
+			WHERE us.user_id = u.id AND u.deleted_at IS NULL`+scope, scopeArgs...)
+		updated = result.RowsAffected
+		return result.Error
+	})
+	return updated, err
+}
+
+// FindBalanceLedgersInBatches walks the users in ID order with their ledger
+// and recorded balances
+func (r *maintenanceRepository) FindBalanceLedgersInBatches(batchSize int, fn func(ledgers []BalanceLedger) error) error {
+	var lastID uint
+	for {
+		var ledgers []BalanceLedger
+		err := r.db.Raw(`SELECT u.id AS user_id,
+				COALESCE(l.earned, 0) AS earned,
+				COALESCE(l.withdrawn, 0) AS withdrawn,
+				COALESCE(l.pending, 0) AS pending,
+				u.total_earned AS user_total_earned,
+				b.user_id IS NOT NULL AS has_balance,
+				COALESCE(b.total_earned, 0) AS balance_earned,
+				COALESCE(b.total_withdrawn, 0) AS balance_withdrawn,
+				COALESCE(b.pending_balance, 0) AS balance_pending,
+				COALESCE(b.available_balance, 0) AS balance_available
+			FROM users u
+			LEFT JOIN (
+				SELECT user_id,
+					SUM(amount) FILTER (WHERE type = @reward AND status IN @earning) AS earned,
+					SUM(amount) FILTER (WHERE type = @withdrawal AND status = @completed) AS withdrawn,
+					SUM(amount) FILTER (WHERE type = @withdrawal AND status IN @inProgress) AS pending
+				FROM reward_transactions
+				WHERE deleted_at IS NULL
+				GROUP BY user_id
+			) l ON l.user_id = u.id
+			LEFT JOIN user_balances b ON b.user_id = u.id
+			WHERE u.deleted_at IS NULL AND u.id > @lastID
+			ORDER BY u.id
+			LIMIT @limit`, map[string]interface{}{
+			"reward":     models.TransactionTypeReward,
+			"withdrawal": models.TransactionTypeWithdrawal,
+			"completed":  models.TransactionStatusCompleted,
+			"earning": []models.TransactionStatus{
+				models.TransactionStatusPending, models.TransactionStatusProcessing, models.TransactionStatusCompleted,
+			},
+			"inProgress": []models.TransactionStatus{
+				models.TransactionStatusPending, models.TransactionStatusProcessing,
+			},
+			"lastID": lastID,
+			"limit":  batchSize,
+		}).Scan(&ledgers).Error
+		if err != nil {
+			return err
+		}
+		if len(ledgers) == 0 {
+			return nil
+		}
+
+		if err := fn(ledgers); err != nil {
+			return err
+		}
+		if len(ledgers) < batchSize {
+			return nil
+		}
+		lastID = ledgers[len(ledgers)-1].UserID
+	}
+}
+
+// SaveBalances writes balances to user_balances and their total earned onto
+// the users
+func (r *maintenanceRepository) SaveBalances(balances []models.UserBalance) error {
+	if len(balances) == 0 {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for i := range balances {
+			balances[i].LastUpdatedAt = now
+		}
+		err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"total_earned", "total_withdrawn", "available_balance", "pending_balance", "last_updated_at"}),
+		}).Create(&balances).Error
+		if err != nil {
+			return err
+		}
+
+		for _, balance := range balances {
+			err := tx.Model(&models.User{}).Where("id = ?", balance.UserID).
+				Update("total_earned", balance.TotalEarned).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,134 @@
+// internal/repository/outbox_repository.go
+package repository
+
+import (
+	"errors"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// outboxSequenceLock is the advisory lock that serializes numbering outbox events
+const outboxSequenceLock = 0x5e2e0b0c
+
+type OutboxRepository interface {
+	AssignSequences(limit int) (int64, error)
+	LastSequence() (uint64, error)
+	GetAfter(sequence uint64, limit int) ([]models.OutboxEvent, error)
+	Consume(consumer string, limit int, fn func(tx *gorm.DB, events []models.OutboxEvent) error) (int, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// AssignSequences numbers committed events that don't have a sequence yet,
+// in ID order, after the highest sequence so far. Events only become visible
+// here once their transaction has committed, so sequences follow commit order
+// even when a transaction that started earlier commits later.
+func (r *outboxRepository) AssignSequences(limit int) (int64, error) {
+	var assigned int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", outboxSequenceLock).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`
+			UPDATE outbox_events SET sequence = numbered.sequence
+			FROM (
+				SELECT id, (SELECT COALESCE(MAX(sequence), 0) FROM outbox_events) + ROW_NUMBER() OVER (ORDER BY id) AS sequence
+				FROM outbox_events
+				WHERE sequence IS NULL
+				ORDER BY id
+				LIMIT ?
+			) AS numbered
+			WHERE outbox_events.id = numbered.id`, limit)
+		assigned = result.RowsAffected
+		return result.Error
+	})
+	return assigned, err
+}
+
+func (r *outboxRepository) LastSequence() (uint64, error) {
+	var sequence uint64
+	err := r.db.Model(&models.OutboxEvent{}).Select("COALESCE(MAX(sequence), 0)").Scan(&sequence).Error
+	return sequence, err
+}
+
+func (r *outboxRepository) GetAfter(sequence uint64, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.Where("sequence > ?", sequence).Order("sequence").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// Consume passes the next events after a consumer's offset to fn and moves
+// the offset past them in the same transaction, so database writes fn makes
+// through tx happen exactly once per event. Nothing is consumed while
+// another instance holds the consumer's offset.
+func (r *outboxRepository) Consume(consumer string, limit int, fn func(tx *gorm.DB, events []models.OutboxEvent) error) (int, error) {
+	var consumed int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		offset := models.OutboxOffset{Consumer: consumer}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&offset).Error; err != nil {
+			return err
+		}
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("consumer = ?", consumer).Take(&offset).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var events []models.OutboxEvent
+		err = tx.Where("sequence > ?", offset.Sequence).Order("sequence").Limit(limit).Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		if err := fn(tx, events); err != nil {
+			return err
+		}
+
+		consumed = len(events)
+		return tx.Model(&offset).Update("sequence", *events[len(events)-1].Sequence).Error
+	})
+	return consumed, err
+}
+
+// DeleteBefore removes numbered events created before a time
+func (r *outboxRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("sequence IS NOT NULL AND created_at < ?", before).Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// RecordOutboxEvents adds events to the outbox inside tx, so they are
+// committed together with the state change they describe
+func RecordOutboxEvents(tx *gorm.DB, events ...models.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return tx.Create(&events).Error
+}
+
+// responseOutboxEvent describes the status a response has been saved with
+func responseOutboxEvent(response *models.Response) (models.OutboxEvent, error) {
+	data := dto.ResponseEventData{
+		ResponseID: response.ID,
+		Status:     string(response.Status),
+	}
+	if response.Status == models.ResponseStatusScreenedOut && response.FlaggedReason != nil {
+		data.Reason = *response.FlaggedReason
+	}
+	return models.NewOutboxEvent("response."+string(response.Status), models.OutboxAggregateResponse, response.ID, response.SurveyID, data)
+}
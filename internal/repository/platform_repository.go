@@ -0,0 +1,85 @@
+// internal/repository/platform_repository.go
+package repository
+
+import (
+	"survey2earn-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PlatformStats sums up the platform for its admins
+type PlatformStats struct {
+	TotalUsers        int64
+	ActiveUsers       int64
+	SurveysByStatus   map[string]int64
+	ResponsesByStatus map[string]int64
+	RewardsFunded     float64 // put in the reward pools of published surveys
+	RewardsPaid       float64 // paid out of them
+}
+
+// PlatformRepository reads figures across the whole platform
+type PlatformRepository interface {
+	GetStats() (*PlatformStats, error)
+}
+
+type platformRepository struct {
+	db *gorm.DB
+}
+
+func NewPlatformRepository(db *gorm.DB) PlatformRepository {
+	return &platformRepository{db: db}
+}
+
+func (r *platformRepository) GetStats() (*PlatformStats, error) {
+	stats := &PlatformStats{}
+
+	var users struct {
+		Total  int64
+		Active int64
+	}
+	err := r.db.Model(&models.User{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE is_active) AS active").
+		Scan(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.TotalUsers, stats.ActiveUsers = users.Total, users.Active
+
+	if stats.SurveysByStatus, err = countByStatus(r.db.Model(&models.Survey{})); err != nil {
+		return nil, err
+	}
+	if stats.ResponsesByStatus, err = countByStatus(r.db.Model(&models.Response{})); err != nil {
+		return nil, err
+	}
+
+	var rewards struct {
+		Funded float64
+		Paid   float64
+	}
+	err = r.db.Model(&models.RewardPool{}).
+		Select("COALESCE(SUM(total_amount), 0) AS funded, COALESCE(SUM(paid_out), 0) AS paid").
+		Scan(&rewards).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.RewardsFunded, stats.RewardsPaid = rewards.Funded, rewards.Paid
+
+	return stats, nil
+}
+
+// countByStatus counts the rows of a model by their status column
+func countByStatus(query *gorm.DB) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := query.Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
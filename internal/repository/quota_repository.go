@@ -0,0 +1,61 @@
+// internal/repository/quota_repository.go
+package repository
+
+import (
+	"survey2earn-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type QuotaRepository interface {
+	GetBySurveyID(surveyID uint) ([]models.SurveyQuota, error)
+	ReplaceForSurvey(surveyID uint, quotas []models.SurveyQuota) error
+}
+
+type quotaRepository struct {
+	db *gorm.DB
+}
+
+func NewQuotaRepository(db *gorm.DB) QuotaRepository {
+	return &quotaRepository{db: db}
+}
+
+func (r *quotaRepository) GetBySurveyID(surveyID uint) ([]models.SurveyQuota, error) {
+	var quotas []models.SurveyQuota
+	err := r.db.Where("survey_id = ?", surveyID).Order("id").Find(&quotas).Error
+	return quotas, err
+}
+
+func (r *quotaRepository) ReplaceForSurvey(surveyID uint, quotas []models.SurveyQuota) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("survey_id = ?", surveyID).Delete(&models.SurveyQuota{}).Error; err != nil {
+			return err
+		}
+		if len(quotas) == 0 {
+			return nil
+		}
+		for i := range quotas {
+			quotas[i].SurveyID = surveyID
+		}
+		return tx.Create(&quotas).Error
+	})
+}
+
+// ReserveQuotas counts a completed response against each quota inside tx.
+// The increment is conditional on remaining capacity so concurrent
+// completions can never overfill a quota; models.ErrQuotaFull is returned
+// when any of them is already full.
+func ReserveQuotas(tx *gorm.DB, quotas []models.SurveyQuota, maxResponses int) error {
+	for _, quota := range quotas {
+		result := tx.Model(&models.SurveyQuota{}).
+			Where("id = ? AND is_active = ? AND current_count < ?", quota.ID, true, quota.Capacity(maxResponses)).
+			Update("current_count", gorm.Expr("current_count + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrQuotaFull
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+// internal/repository/quota_repository_test.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"survey2earn-backend/internal/database"
+	"survey2earn-backend/internal/models"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The repository tests run against the Postgres database at
+// TEST_DATABASE_URL and are skipped without it. The database is wiped
+// before each test, so never point it at one holding data you need.
+const testDatabaseEnv = "TEST_DATABASE_URL"
+
+// newTestDB migrates a wiped database
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv(testDatabaseEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseEnv)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connect to %s: %v", testDatabaseEnv, err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if err := db.Exec("DROP SCHEMA public CASCADE; CREATE SCHEMA public").Error; err != nil {
+		t.Fatalf("wipe the test database: %v", err)
+	}
+	migrator, err := (&database.Database{DB: db}).Migrator()
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// createQuotaSurvey saves a survey of maxResponses with the quotas
+func createQuotaSurvey(t *testing.T, db *gorm.DB, maxResponses int, quotas ...models.SurveyQuota) *models.Survey {
+	t.Helper()
+	creator := &models.User{WalletAddress: "0x00000000000000000000000000000000000000aa", Nonce: "nonce"}
+	if err := db.Create(creator).Error; err != nil {
+		t.Fatal(err)
+	}
+	survey := &models.Survey{
+		CreatorID:         creator.ID,
+		Title:             "Quotas",
+		Category:          "finance",
+		MaxResponses:      maxResponses,
+		RewardPerResponse: 1,
+		TotalRewardPool:   float64(maxResponses),
+		Quotas:            quotas,
+	}
+	if err := db.Create(survey).Error; err != nil {
+		t.Fatal(err)
+	}
+	return survey
+}
+
+func quotaCounts(t *testing.T, db *gorm.DB, survey *models.Survey) []int {
+	t.Helper()
+	quotas, err := NewQuotaRepository(db).GetBySurveyID(survey.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make([]int, len(quotas))
+	for i, quota := range quotas {
+		counts[i] = quota.CurrentCount
+	}
+	return counts
+}
+
+func TestReserveQuotas(t *testing.T) {
+	db := newTestDB(t)
+	limit, percentage := 2, 30.0
+	survey := createQuotaSurvey(t, db, 10,
+		models.SurveyQuota{Name: "percentage", MetadataField: quotaField(models.QuotaMetadataTimezone), Value: "UTC", Percentage: &percentage, IsActive: true},
+		models.SurveyQuota{Name: "limit", MetadataField: quotaField(models.QuotaMetadataLanguage), Value: "en", Limit: &limit, IsActive: true},
+	)
+	reserve := func() error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			return ReserveQuotas(tx, survey.Quotas, survey.MaxResponses)
+		})
+	}
+
+	for i := 0; i < limit; i++ {
+		if err := reserve(); err != nil {
+			t.Fatalf("reservation %d: %v", i+1, err)
+		}
+	}
+	// The limit quota is full, so the transaction rolls back the place the
+	// percentage quota had just reserved
+	if err := reserve(); !errors.Is(err, models.ErrQuotaFull) {
+		t.Fatalf("reservation past the limit = %v, want ErrQuotaFull", err)
+	}
+	if counts := quotaCounts(t, db, survey); counts[0] != 2 || counts[1] != 2 {
+		t.Errorf("counts = %v, want [2 2]", counts)
+	}
+
+	// 30% of 10 responses takes 3
+	percentageOnly := survey.Quotas[:1]
+	if err := ReserveQuotas(db, percentageOnly, survey.MaxResponses); err != nil {
+		t.Fatalf("third reservation of 3: %v", err)
+	}
+	if err := ReserveQuotas(db, percentageOnly, survey.MaxResponses); !errors.Is(err, models.ErrQuotaFull) {
+		t.Errorf("fourth reservation of 3 = %v, want ErrQuotaFull", err)
+	}
+
+	// Inactive quotas take no reservations, even with room left
+	if err := db.Model(&models.SurveyQuota{}).Where("id = ?", survey.Quotas[1].ID).Update("is_active", false).Error; err != nil {
+		t.Fatal(err)
+	}
+	limit = 100 // the quota's Limit
+	if err := ReserveQuotas(db, survey.Quotas[1:], survey.MaxResponses); !errors.Is(err, models.ErrQuotaFull) {
+		t.Errorf("reservation of an inactive quota = %v, want ErrQuotaFull", err)
+	}
+}
+
+func TestReserveQuotasConcurrently(t *testing.T) {
+	db := newTestDB(t)
+	limit := 3
+	survey := createQuotaSurvey(t, db, 100,
+		models.SurveyQuota{Name: "limit", MetadataField: quotaField(models.QuotaMetadataLanguage), Value: "en", Limit: &limit, IsActive: true},
+	)
+
+	const completions = 10
+	var wg sync.WaitGroup
+	results := make(chan error, completions)
+	for i := 0; i < completions; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- db.Transaction(func(tx *gorm.DB) error {
+				return ReserveQuotas(tx, survey.Quotas, survey.MaxResponses)
+			})
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	reserved := 0
+	for err := range results {
+		switch {
+		case err == nil:
+			reserved++
+		case !errors.Is(err, models.ErrQuotaFull):
+			t.Errorf("reservation: %v", err)
+		}
+	}
+	if reserved != limit {
+		t.Errorf("%d of %d concurrent completions reserved a place, want %d", reserved, completions, limit)
+	}
+	if counts := quotaCounts(t, db, survey); counts[0] != limit {
+		t.Errorf("count = %d, want %d", counts[0], limit)
+	}
+}
+
+func quotaField(field models.QuotaMetadataField) *models.QuotaMetadataField {
+	return &field
+}
@@ -0,0 +1,432 @@
+// internal/repository/response_repository.go
+package repository
+
+import (
+	"errors"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ResponseFilter narrows the responses of a survey listed for its creator
+type ResponseFilter struct {
+	Status     string
+	IsValid    *bool
+	MinQuality *float64
+	MaxQuality *float64
+	From       *time.Time // started at or after
+	To         *time.Time // started before
+	Answers    []AnswerFilter
+	Page       int // used by paginated queries only
+	Limit      int
+}
+
+// AnswerFilter matches responses that answered a question with a value,
+// either as the answer text or as one of the selected options
+type AnswerFilter struct {
+	QuestionID uint
+	Value      string
+}
+
+// ErrVersionConflict is returned when saving answers to a response that has
+// changed since the version the client read
+var ErrVersionConflict = apperror.New(apperror.VersionConflict, "The response changed since this version, resume it to get the latest answers")
+
+// ErrResponseNotInProgress is returned when answering or closing a response
+// that was already completed, abandoned or screened out
+var ErrResponseNotInProgress = apperror.New(apperror.ResponseNotActive, "The response is no longer in progress")
+
+// CrosstabCount is the number of responses with a pair of answer categories
+type CrosstabCount struct {
+	RowValue    string
+	ColumnValue string
+	Count       int
+}
+
+// CategoryHistory sums up a respondent's responses to the surveys of a
+// category. AverageQuality is over the completed responses, counting
+// flagged ones as 0.
+type CategoryHistory struct {
+	Category       string
+	Completed      int
+	Abandoned      int
+	AverageQuality float64
+}
+
+// answerCategoriesSQL expands the answers to a question into one row per
+// category: every selected option for choice questions, the answer text
+// otherwise (ratings, scales and yes/no answers are stored as text)
+const answerCategoriesSQL = `SELECT answers.response_id, category.value AS category
+	FROM answers
+	CROSS JOIN LATERAL jsonb_array_elements_text(
+		CASE WHEN jsonb_typeof(answers.answer_value::jsonb -> 'options') = 'array'
+			AND jsonb_array_length(answers.answer_value::jsonb -> 'options') > 0
+		THEN answers.answer_value::jsonb -> 'options'
+		ELSE jsonb_build_array(answers.answer_text) END
+	) AS category(value)
+	WHERE answers.question_id = ?
+	AND answers.is_skipped = false
+	AND answers.deleted_at IS NULL
+	AND category.value <> ''`
+
+type responseRepository struct {
+	db *gorm.DB
+}
+
+func NewResponseRepository(db *gorm.DB) ResponseRepository {
+	return &responseRepository{db: db}
+}
+
+// Create saves a new response and records its response.started or
+// response.screened_out event
+func (r *responseRepository) Create(response *models.Response) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(response).Error; err != nil {
+			return err
+		}
+		event, err := responseOutboxEvent(response)
+		if err != nil {
+			return err
+		}
+		return RecordOutboxEvents(tx, event)
+	})
+}
+
+// Update saves a response and records an event when its status changed.
+// Completions are saved by the reward transaction that settles them
+// instead, see RewardRepository.ProcessRewardWithQuotas. The version and
+// last activity are left alone, SaveAnswers keeps them.
+//
+// A response leaves "started" once, so closing it twice, say by two
+// concurrent completions, fails with ErrResponseNotInProgress.
+func (r *responseRepository) Update(response *models.Response) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveInProgress(tx, response); err != nil {
+			return err
+		}
+
+		if response.Status == models.ResponseStatusStarted || response.Status == models.ResponseStatusCompleted {
+			return nil
+		}
+		event, err := responseOutboxEvent(response)
+		if err != nil {
+			return err
+		}
+		return RecordOutboxEvents(tx, event)
+	})
+}
+
+// saveInProgress saves a response inside tx if it is still in progress,
+// locking it so concurrent closes can't both succeed, or fails with
+// ErrResponseNotInProgress
+func saveInProgress(tx *gorm.DB, response *models.Response) error {
+	var previous models.Response
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("status").Take(&previous, response.ID).Error
+	if err != nil {
+		return err
+	}
+	if previous.Status != models.ResponseStatusStarted {
+		return ErrResponseNotInProgress
+	}
+	return tx.Omit(clause.Associations, "Version", "LastActivityAt").Save(response).Error
+}
+
+func (r *responseRepository) GetByID(id uint) (*models.Response, error) {
+	var response models.Response
+	err := r.db.First(&response, id).Error
+	return &response, apperror.NotFoundAs(err, "Response not found")
+}
+
+func (r *responseRepository) GetWithAnswers(id uint) (*models.Response, error) {
+	var response models.Response
+	err := r.db.Preload("Answers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("updated_at")
+	}).Preload("Survey").Preload("Transaction").First(&response, id).Error
+	return &response, apperror.NotFoundAs(err, "Response not found")
+}
+
+func (r *responseRepository) GetByUserID(userID uint, req *dto.ListResponsesRequest) ([]models.Response, int64, error) {
+	var responses []models.Response
+	var total int64
+
+	query := r.db.Model(&models.Response{}).Where("user_id = ?", userID)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.SurveyID != 0 {
+		query = query.Where("survey_id = ?", req.SurveyID)
+	}
+	if startDate, err := time.Parse("2006-01-02", req.StartDate); err == nil {
+		query = query.Where("started_at >= ?", startDate)
+	}
+	if endDate, err := time.Parse("2006-01-02", req.EndDate); err == nil {
+		query = query.Where("started_at < ?", endDate.AddDate(0, 0, 1))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	err := query.Preload("Survey.Questions").Preload("Answers").Preload("Transaction").
+		Order("started_at DESC").Offset(offset).Limit(req.Limit).Find(&responses).Error
+
+	return responses, total, err
+}
+
+func (r *responseRepository) GetBySurveyID(surveyID uint, filter *ResponseFilter) ([]models.Response, int64, error) {
+	var responses []models.Response
+	var total int64
+
+	query := applyResponseFilter(r.db.Model(&models.Response{}).Where("survey_id = ?", surveyID), filter)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	err := query.Preload("Answers").Preload("User").Preload("Survey").Preload("Transaction").
+		Order("started_at DESC, id DESC").Offset(offset).Limit(filter.Limit).Find(&responses).Error
+
+	return responses, total, err
+}
+
+// GetCrosstabCounts counts the filtered responses of a survey by their
+// answers to two questions. A response with several options selected is
+// counted once for every option.
+func (r *responseRepository) GetCrosstabCounts(surveyID, rowQuestionID, columnQuestionID uint, filter *ResponseFilter) ([]CrosstabCount, error) {
+	var counts []CrosstabCount
+
+	query := r.db.Model(&models.Response{}).
+		Select("row_answers.category AS row_value, column_answers.category AS column_value, COUNT(*) AS count").
+		Joins("JOIN ("+answerCategoriesSQL+") AS row_answers ON row_answers.response_id = responses.id", rowQuestionID).
+		Joins("JOIN ("+answerCategoriesSQL+") AS column_answers ON column_answers.response_id = responses.id", columnQuestionID).
+		Where("responses.survey_id = ?", surveyID)
+	err := applyResponseFilter(query, filter).
+		Group("row_answers.category, column_answers.category").
+		Scan(&counts).Error
+
+	return counts, err
+}
+
+func (r *responseRepository) GetAllBySurveyID(surveyID uint) ([]models.Response, error) {
+	var responses []models.Response
+	err := r.db.Preload("Answers").Where("survey_id = ?", surveyID).Order("id").Find(&responses).Error
+	return responses, err
+}
+
+func (r *responseRepository) CountBySurveyID(surveyID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Response{}).Where("survey_id = ?", surveyID).Count(&count).Error
+	return count, err
+}
+
+// FindInBatchesBySurveyID walks the responses of a survey in ID order, with
+// answers and respondent loaded, without holding more than one batch in memory
+func (r *responseRepository) FindInBatchesBySurveyID(surveyID uint, batchSize int, fn func(responses []models.Response) error) error {
+	var lastID uint
+	for {
+		var responses []models.Response
+		err := r.db.Preload("Answers").Preload("User").
+			Where("survey_id = ? AND id > ?", surveyID, lastID).
+			Order("id").Limit(batchSize).Find(&responses).Error
+		if err != nil {
+			return err
+		}
+		if len(responses) == 0 {
+			return nil
+		}
+
+		if err := fn(responses); err != nil {
+			return err
+		}
+		if len(responses) < batchSize {
+			return nil
+		}
+		lastID = responses[len(responses)-1].ID
+	}
+}
+
+func (r *responseRepository) HasUserResponded(userID, surveyID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Response{}).
+		Where("user_id = ? AND survey_id = ? AND status <> ?", userID, surveyID, models.ResponseStatusAbandoned).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetCategoryHistory sums up the user's responses by survey category
+func (r *responseRepository) GetCategoryHistory(userID uint) ([]CategoryHistory, error) {
+	var history []CategoryHistory
+	completed, abandoned := models.ResponseStatusCompleted, models.ResponseStatusAbandoned
+	err := r.db.Model(&models.Response{}).
+		Select(`surveys.category,
+			COUNT(*) FILTER (WHERE responses.status = ?) AS completed,
+			COUNT(*) FILTER (WHERE responses.status = ?) AS abandoned,
+			COALESCE(AVG(CASE WHEN responses.is_valid THEN responses.quality_score ELSE 0 END)
+				FILTER (WHERE responses.status = ?), 0) AS average_quality`,
+			completed, abandoned, completed).
+		Joins("JOIN surveys ON surveys.id = responses.survey_id").
+		Where("responses.user_id = ?", userID).
+		Group("surveys.category").
+		Scan(&history).Error
+	return history, err
+}
+
+// AbandonStale marks responses in progress without any activity since
+// idleSince as abandoned, recording their response.abandoned events, and
+// returns how many it abandoned. Responses that finish or move on meanwhile
+// are left alone.
+func (r *responseRepository) AbandonStale(idleSince time.Time, batchSize int) (int, error) {
+	abandoned := 0
+	var lastID uint
+	for {
+		var ids []uint
+		err := r.db.Model(&models.Response{}).
+			Where("status = ? AND COALESCE(last_activity_at, started_at) < ? AND id > ?", models.ResponseStatusStarted, idleSince, lastID).
+			Order("id").Limit(batchSize).Pluck("id", &ids).Error
+		if err != nil {
+			return abandoned, err
+		}
+
+		for _, id := range ids {
+			changed := false
+			err := r.db.Transaction(func(tx *gorm.DB) error {
+				var response models.Response
+				err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Take(&response, id).Error
+				if err != nil || response.Status != models.ResponseStatusStarted || !response.LastActivity().Before(idleSince) {
+					return err
+				}
+
+				response.MarkAsAbandoned()
+				if err := tx.Omit(clause.Associations).Save(&response).Error; err != nil {
+					return err
+				}
+				event, err := responseOutboxEvent(&response)
+				if err != nil {
+					return err
+				}
+				changed = true
+				return RecordOutboxEvents(tx, event)
+			})
+			if err != nil {
+				return abandoned, err
+			}
+			if changed {
+				abandoned++
+			}
+		}
+
+		if len(ids) < batchSize {
+			return abandoned, nil
+		}
+		lastID = ids[len(ids)-1]
+	}
+}
+
+// GetPastDeadline returns the IDs of responses in progress whose deadline
+// has passed, after afterID in ID order
+func (r *responseRepository) GetPastDeadline(now time.Time, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Response{}).
+		Where("status = ? AND deadline IS NOT NULL AND deadline <= ? AND id > ?", models.ResponseStatusStarted, now, afterID).
+		Order("id").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// SaveAnswers saves the answers of a response in progress, replacing the
+// ones it already has for the same questions, and moves the response's
+// version on. When expectedVersion isn't 0 the response must still be at
+// that version, or nothing is saved and ErrVersionConflict is returned.
+// ErrResponseNotInProgress is returned once the response is closed. It
+// returns the new version.
+func (r *responseRepository) SaveAnswers(responseID uint, expectedVersion int, answers []*models.Answer) (int, error) {
+	var version int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.Response{}).
+			Where("id = ? AND status = ?", responseID, models.ResponseStatusStarted)
+		if expectedVersion != 0 {
+			query = query.Where("version = ?", expectedVersion)
+		}
+		result := query.Updates(map[string]interface{}{
+			"version":          gorm.Expr("version + 1"),
+			"last_activity_at": time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var current models.Response
+			if err := tx.Select("status").Take(&current, responseID).Error; err != nil {
+				return apperror.NotFoundAs(err, "Response not found")
+			}
+			if current.Status != models.ResponseStatusStarted {
+				return ErrResponseNotInProgress
+			}
+			return ErrVersionConflict
+		}
+
+		for _, answer := range answers {
+			if err := upsertAnswer(tx, answer); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Response{}).Where("id = ?", responseID).Pluck("version", &version).Error
+	})
+	return version, err
+}
+
+// upsertAnswer replaces the answer to a question if the response already has one
+func upsertAnswer(tx *gorm.DB, answer *models.Answer) error {
+	var existing models.Answer
+	err := tx.Where("response_id = ? AND question_id = ?", answer.ResponseID, answer.QuestionID).
+		First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return tx.Omit(clause.Associations).Create(answer).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	answer.ID = existing.ID
+	answer.CreatedAt = existing.CreatedAt
+	return tx.Omit(clause.Associations).Save(answer).Error
+}
+
+// applyResponseFilter adds the conditions of a response filter to a query on responses
+func applyResponseFilter(query *gorm.DB, filter *ResponseFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("responses.status = ?", filter.Status)
+	}
+	if filter.IsValid != nil {
+		query = query.Where("responses.is_valid = ?", *filter.IsValid)
+	}
+	if filter.MinQuality != nil {
+		query = query.Where("responses.quality_score >= ?", *filter.MinQuality)
+	}
+	if filter.MaxQuality != nil {
+		query = query.Where("responses.quality_score <= ?", *filter.MaxQuality)
+	}
+	if filter.From != nil {
+		query = query.Where("responses.started_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("responses.started_at < ?", *filter.To)
+	}
+	for _, answer := range filter.Answers {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM answers
+			WHERE answers.response_id = responses.id
+			AND answers.question_id = ?
+			AND answers.is_skipped = false
+			AND answers.deleted_at IS NULL
+			AND (LOWER(answers.answer_text) = LOWER(?)
+				OR (answers.answer_value::jsonb -> 'options') @> jsonb_build_array(?::text))
+		)`, answer.QuestionID, answer.Value, answer.Value)
+	}
+	return query
+}
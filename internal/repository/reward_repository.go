@@ -0,0 +1,225 @@
+// internal/repository/reward_repository.go
+package repository
+
+import (
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RewardLedger sums a user's reward transactions: rewards that weren't
+// failed or cancelled, completed withdrawals and withdrawals in progress
+type RewardLedger struct {
+	Earned    float64
+	Withdrawn float64
+	Pending   float64
+}
+
+type rewardRepository struct {
+	db *gorm.DB
+}
+
+func NewRewardRepository(db *gorm.DB) RewardRepository {
+	return &rewardRepository{db: db}
+}
+
+func (r *rewardRepository) GetPoolBySurveyID(surveyID uint) (*models.RewardPool, error) {
+	var pool models.RewardPool
+	err := r.db.Where("survey_id = ?", surveyID).First(&pool).Error
+	return &pool, apperror.NotFoundAs(err, "Reward pool not found")
+}
+
+func (r *rewardRepository) ProcessReward(pool *models.RewardPool, transaction *models.RewardTransaction, events ...models.OutboxEvent) error {
+	return r.ProcessRewardWithQuotas(nil, pool, transaction, nil, 0, events...)
+}
+
+// ProcessRewardWithQuotas pays a reward out of a pool, credits it to the
+// user's total earned and counts the response against its quotas in one
+// transaction. A completed response, if given, is
+// saved in the same transaction, so it is only completed once it is paid;
+// it must still be in progress, or ErrResponseNotInProgress is returned.
+// models.ErrPoolExhausted and models.ErrQuotaFull are returned when the
+// locked pool or a quota can't take the response. It records reward.paid
+// and pool.updated, pool.exhausted when the reward empties the pool, and the
+// caller's events, such as the completion the reward settles. On success pool
+// holds the new balance.
+func (r *rewardRepository) ProcessRewardWithQuotas(response *models.Response, pool *models.RewardPool, transaction *models.RewardTransaction, quotas []models.SurveyQuota, maxResponses int, events ...models.OutboxEvent) error {
+	var updated models.RewardPool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if response != nil {
+			if err := saveInProgress(tx, response); err != nil {
+				return err
+			}
+		}
+
+		// Lock the pool so concurrent rewards are paid from the latest balance
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&updated, pool.ID).Error; err != nil {
+			return err
+		}
+		if err := updated.ProcessReward(); err != nil {
+			return err
+		}
+		if err := ReserveQuotas(tx, quotas, maxResponses); err != nil {
+			return err
+		}
+
+		if err := tx.Omit(clause.Associations).Create(transaction).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&models.User{}).Where("id = ?", transaction.UserID).
+			Update("total_earned", gorm.Expr("total_earned + ?", transaction.Amount)).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(&updated).Error; err != nil {
+			return err
+		}
+
+		ledgerEvents, err := r.rewardOutboxEvents(tx, &updated, transaction)
+		if err != nil {
+			return err
+		}
+		return RecordOutboxEvents(tx, append(events, ledgerEvents...)...)
+	})
+	if err != nil {
+		return err
+	}
+
+	*pool = updated
+	return nil
+}
+
+func (r *rewardRepository) CreateTransaction(transaction *models.RewardTransaction) error {
+	return r.db.Create(transaction).Error
+}
+
+func (r *rewardRepository) UpdatePool(pool *models.RewardPool) error {
+	return r.db.Omit(clause.Associations).Save(pool).Error
+}
+
+// RequeueFailedTransactions puts failed transactions that have retries left
+// back in the pending queue. It returns how many were requeued and how many
+// failed transactions have no retries left.
+func (r *rewardRepository) RequeueFailedTransactions() (requeued, exhausted int64, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RewardTransaction{}).
+			Where("status = ? AND retry_count < ?", models.TransactionStatusFailed, models.MaxTransactionRetries).
+			Updates(map[string]interface{}{
+				"status":         models.TransactionStatusPending,
+				"failure_reason": nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		requeued = result.RowsAffected
+
+		return tx.Model(&models.RewardTransaction{}).
+			Where("status = ?", models.TransactionStatusFailed).
+			Count(&exhausted).Error
+	})
+	return requeued, exhausted, err
+}
+
+// GetLedger sums the reward transactions of a user the way the balance
+// rebuild does
+func (r *rewardRepository) GetLedger(userID uint) (*RewardLedger, error) {
+	var ledger RewardLedger
+	err := r.db.Raw(`SELECT
+			COALESCE(SUM(amount) FILTER (WHERE type = @reward AND status IN @earning), 0) AS earned,
+			COALESCE(SUM(amount) FILTER (WHERE type = @withdrawal AND status = @completed), 0) AS withdrawn,
+			COALESCE(SUM(amount) FILTER (WHERE type = @withdrawal AND status IN @inProgress), 0) AS pending
+		FROM reward_transactions
+		WHERE user_id = @userID AND deleted_at IS NULL`, map[string]interface{}{
+		"reward":     models.TransactionTypeReward,
+		"withdrawal": models.TransactionTypeWithdrawal,
+		"completed":  models.TransactionStatusCompleted,
+		"earning": []models.TransactionStatus{
+			models.TransactionStatusPending, models.TransactionStatusProcessing, models.TransactionStatusCompleted,
+		},
+		"inProgress": []models.TransactionStatus{
+			models.TransactionStatusPending, models.TransactionStatusProcessing,
+		},
+		"userID": userID,
+	}).Scan(&ledger).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ledger, nil
+}
+
+// GetTransactionsByUserID pages through a user's reward transactions, newest
+// first, with the title of their survey
+func (r *rewardRepository) GetTransactionsByUserID(userID uint, txType, status string, page, limit int) ([]models.RewardTransaction, int64, error) {
+	var transactions []models.RewardTransaction
+	var total int64
+
+	query := r.db.Model(&models.RewardTransaction{}).Where("user_id = ?", userID)
+	if txType != "" {
+		query = query.Where("type = ?", txType)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Preload("Survey", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Select("id", "title")
+	}).Order("id DESC").Offset(offset).Limit(limit).Find(&transactions).Error
+
+	return transactions, total, err
+}
+
+// rewardOutboxEvents describes a reward that was just paid out of a pool
+func (r *rewardRepository) rewardOutboxEvents(tx *gorm.DB, pool *models.RewardPool, transaction *models.RewardTransaction) ([]models.OutboxEvent, error) {
+	var survey models.Survey
+	if err := tx.Select("id", "is_anonymous").Take(&survey, pool.SurveyID).Error; err != nil {
+		return nil, err
+	}
+
+	paid := dto.RewardPaidEventData{
+		TransactionID: transaction.ID,
+		SurveyID:      pool.SurveyID,
+		Amount:        transaction.Amount,
+	}
+	if transaction.ResponseID != nil {
+		paid.ResponseID = *transaction.ResponseID
+	}
+	if !survey.IsAnonymous {
+		userID := transaction.UserID
+		paid.UserID = &userID
+	}
+	paidEvent, err := models.NewOutboxEvent(models.OutboxEventRewardPaid, models.OutboxAggregateTransaction, transaction.ID, pool.SurveyID, paid)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := dto.PoolEventData{
+		PoolID:           pool.ID,
+		SurveyID:         pool.SurveyID,
+		TotalAmount:      pool.TotalAmount,
+		PaidOut:          pool.PaidOut,
+		RemainingAmount:  pool.RemainingAmount,
+		CurrentResponses: pool.CurrentResponses,
+		MaxResponses:     pool.MaxResponses,
+	}
+	poolEvent, err := models.NewOutboxEvent(models.OutboxEventPoolUpdated, models.OutboxAggregatePool, pool.ID, pool.SurveyID, balance)
+	if err != nil {
+		return nil, err
+	}
+	events := []models.OutboxEvent{paidEvent, poolEvent}
+
+	// ProcessReward deactivates the pool when it can't pay another reward
+	if !pool.IsActive {
+		exhaustedEvent, err := models.NewOutboxEvent(models.OutboxEventPoolExhausted, models.OutboxAggregatePool, pool.ID, pool.SurveyID, balance)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, exhaustedEvent)
+	}
+	return events, nil
+}
@@ -0,0 +1,467 @@
+// internal/repository/survey_repository.go
+package repository
+
+import (
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type surveyRepository struct {
+	db *gorm.DB
+}
+
+func NewSurveyRepository(db *gorm.DB) SurveyRepository {
+	return &surveyRepository{db: db}
+}
+
+func (r *surveyRepository) Create(survey *models.Survey) error {
+	return r.db.Create(survey).Error
+}
+
+// Update saves a survey and records its survey.updated event
+func (r *surveyRepository) Update(survey *models.Survey) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(survey).Error; err != nil {
+			return err
+		}
+		return recordSurveyEvent(tx, models.OutboxEventSurveyUpdated, survey)
+	})
+}
+
+func (r *surveyRepository) GetByID(id uint) (*models.Survey, error) {
+	var survey models.Survey
+	err := r.db.Preload("Questions").Preload("Creator").Preload("Quotas").First(&survey, id).Error
+	return &survey, apperror.NotFoundAs(err, "Survey not found")
+}
+
+func (r *surveyRepository) GetByUserID(userID uint, status string, page, limit int) ([]models.Survey, int64, error) {
+	var surveys []models.Survey
+	var total int64
+
+	query := r.db.Model(&models.Survey{}).Where("creator_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Preload("Creator").Offset(offset).Limit(limit).Find(&surveys).Error
+
+	return surveys, total, err
+}
+
+// List pages through every survey on the platform, newest first
+func (r *surveyRepository) List(status string, page, limit int) ([]models.Survey, int64, error) {
+	var surveys []models.Survey
+	var total int64
+
+	query := r.db.Model(&models.Survey{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Preload("Creator").Order("id DESC").Offset(offset).Limit(limit).Find(&surveys).Error
+
+	return surveys, total, err
+}
+
+// Orders of the public survey feed
+const (
+	SurveySortNewest          = "newest"
+	SurveySortEndingSoon      = "ending_soon"
+	SurveySortRewardPerMinute = "reward_per_minute"
+	SurveySortPopular         = "popular"
+	SurveySortRelevance       = "relevance" // needs a Query
+)
+
+// PublicSurveyFilter selects and orders the surveys of the public feed
+type PublicSurveyFilter struct {
+	Categories []string // any of these, or every category when empty
+	Status     string
+
+	// Query is a web search style query ("defi -nft", quoted phrases, or)
+	// over titles and descriptions, stemmed for Language
+	Query    string
+	Language string
+
+	MinReward   *float64
+	MaxReward   *float64
+	MinDuration *int // estimated minutes
+	MaxDuration *int
+	MinSlots    *int       // responses still open
+	EndsAfter   *time.Time // surveys without an end date always match
+	EndsBefore  *time.Time
+
+	Sort   string
+	After  *SurveyCursor // continue after this survey, instead of Offset
+	Offset int
+	Limit  int
+}
+
+// SurveyCursor is a position in the public feed: the sort key of a survey,
+// as Postgres prints it, and its ID to break ties
+type SurveyCursor struct {
+	Key string `json:"k"`
+	ID  uint   `json:"id"`
+}
+
+// PublicSurveyPage is a page of the public feed. Next is set when more
+// surveys follow.
+type PublicSurveyPage struct {
+	Surveys []models.Survey `json:"surveys"`
+	Total   int64           `json:"total"`
+	Next    *SurveyCursor   `json:"next,omitempty"`
+}
+
+// feedOrder is how a feed sort orders surveys: by an SQL expression of the
+// given type, then by ID in the same direction
+type feedOrder struct {
+	expr string
+	vars []interface{}
+	cast string
+	desc bool
+}
+
+// searchQuerySQL matches the query stemmed for the language, or word for
+// word, against the survey's search document
+const searchQuerySQL = "(websearch_to_tsquery(survey_search_config(?), ?) || websearch_to_tsquery('simple', ?))"
+
+func (f *PublicSurveyFilter) order() feedOrder {
+	switch f.Sort {
+	case SurveySortEndingSoon:
+		return feedOrder{expr: "COALESCE(surveys.end_date, 'infinity'::timestamptz)", cast: "timestamptz"}
+	case SurveySortRewardPerMinute:
+		return feedOrder{expr: "surveys.reward_per_response / GREATEST(surveys.estimated_duration, 1)", cast: "numeric", desc: true}
+	case SurveySortPopular:
+		return feedOrder{expr: "COALESCE(surveys.response_count, 0)", cast: "bigint", desc: true}
+	case SurveySortRelevance:
+		return feedOrder{
+			expr: "ts_rank(surveys.search_vector, " + searchQuerySQL + ")",
+			vars: []interface{}{f.Language, f.Query, f.Query},
+			cast: "real",
+			desc: true,
+		}
+	default:
+		return feedOrder{expr: "surveys.created_at", cast: "timestamptz", desc: true}
+	}
+}
+
+// scope applies the filters, except the categories when withCategories is
+// false
+func (f *PublicSurveyFilter) scope(withCategories bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("surveys.is_public = ?", true)
+		if withCategories && len(f.Categories) > 0 {
+			db = db.Where("surveys.category IN ?", f.Categories)
+		}
+		if f.Status != "" {
+			db = db.Where("surveys.status = ?", f.Status)
+		}
+		if f.Query != "" {
+			db = db.Where("surveys.search_vector @@ "+searchQuerySQL, f.Language, f.Query, f.Query)
+		}
+		if f.MinReward != nil {
+			db = db.Where("surveys.reward_per_response >= ?", *f.MinReward)
+		}
+		if f.MaxReward != nil {
+			db = db.Where("surveys.reward_per_response <= ?", *f.MaxReward)
+		}
+		if f.MinDuration != nil {
+			db = db.Where("surveys.estimated_duration >= ?", *f.MinDuration)
+		}
+		if f.MaxDuration != nil {
+			db = db.Where("surveys.estimated_duration <= ?", *f.MaxDuration)
+		}
+		if f.MinSlots != nil {
+			db = db.Where("COALESCE(surveys.max_responses, 0) - COALESCE(surveys.response_count, 0) >= ?", *f.MinSlots)
+		}
+		if f.EndsAfter != nil {
+			db = db.Where("(surveys.end_date IS NULL OR surveys.end_date > ?)", *f.EndsAfter)
+		}
+		if f.EndsBefore != nil {
+			db = db.Where("surveys.end_date <= ?", *f.EndsBefore)
+		}
+		return db
+	}
+}
+
+// GetPublicSurveys returns a page of the public feed. Pages continued from a
+// cursor stay stable while surveys are published: new surveys only show up
+// before the cursor, never twice or in place of others.
+func (r *surveyRepository) GetPublicSurveys(filter *PublicSurveyFilter) (*PublicSurveyPage, error) {
+	page := &PublicSurveyPage{}
+	if err := r.db.Model(&models.Survey{}).Scopes(filter.scope(true)).Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+
+	// Find the page's surveys and their sort keys first, then load them
+	order := filter.order()
+	direction, after := "ASC", ">"
+	if order.desc {
+		direction, after = "DESC", "<"
+	}
+
+	query := r.db.Model(&models.Survey{}).Scopes(filter.scope(true)).
+		Select("surveys.id, ("+order.expr+")::text AS sort_key", order.vars...)
+	if filter.After != nil {
+		vars := append(append([]interface{}{}, order.vars...), filter.After.Key, filter.After.ID)
+		query = query.Where("(("+order.expr+"), surveys.id) "+after+" (CAST(? AS "+order.cast+"), ?)", vars...)
+	} else if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var keys []struct {
+		ID      uint
+		SortKey string
+	}
+	err := query.Clauses(clause.OrderBy{Expression: clause.Expr{
+		SQL:                "(" + order.expr + ") " + direction + ", surveys.id " + direction,
+		Vars:               order.vars,
+		WithoutParentheses: true,
+	}}).Limit(filter.Limit + 1).Scan(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) > filter.Limit {
+		keys = keys[:filter.Limit]
+		last := keys[len(keys)-1]
+		page.Next = &SurveyCursor{Key: last.SortKey, ID: last.ID}
+	}
+	if len(keys) == 0 {
+		page.Surveys = []models.Survey{}
+		return page, nil
+	}
+
+	ids := make([]uint, len(keys))
+	for i, key := range keys {
+		ids[i] = key.ID
+	}
+	var surveys []models.Survey
+	if err := r.db.Preload("Creator").Where("id IN ?", ids).Find(&surveys).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]models.Survey, len(surveys))
+	for _, survey := range surveys {
+		byID[survey.ID] = survey
+	}
+	page.Surveys = make([]models.Survey, 0, len(ids))
+	for _, id := range ids {
+		// A survey deleted in between is left out
+		if survey, ok := byID[id]; ok {
+			page.Surveys = append(page.Surveys, survey)
+		}
+	}
+	return page, nil
+}
+
+// CountPublicSurveysByCategory counts the public surveys of each category
+// that match the filter's other conditions
+func (r *surveyRepository) CountPublicSurveysByCategory(filter *PublicSurveyFilter) (map[string]int64, error) {
+	var rows []struct {
+		Category string
+		Count    int64
+	}
+
+	err := r.db.Model(&models.Survey{}).Scopes(filter.scope(false)).
+		Select("surveys.category, COUNT(*) AS count").
+		Group("surveys.category").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Category] = row.Count
+	}
+	return counts, nil
+}
+
+// RecommendationCandidate is an open public survey with what is left of
+// its reward pool, as ranked by the recommendation engine
+type RecommendationCandidate struct {
+	Survey        models.Survey
+	PoolRemaining float64
+}
+
+// GetRecommendationCandidates returns up to limit of the newest published
+// public surveys that are running, have responses left and can still pay
+// a reward, with their creator and quotas
+func (r *surveyRepository) GetRecommendationCandidates(limit int) ([]RecommendationCandidate, error) {
+	now := time.Now()
+	var pools []struct {
+		SurveyID        uint
+		RemainingAmount float64
+	}
+	err := r.db.Model(&models.Survey{}).
+		Select("surveys.id AS survey_id, reward_pools.remaining_amount").
+		Joins("JOIN reward_pools ON reward_pools.survey_id = surveys.id AND reward_pools.deleted_at IS NULL AND reward_pools.is_active").
+		Where("surveys.is_public = ? AND surveys.status = ?", true, models.SurveyStatusPublished).
+		Where("surveys.start_date IS NULL OR surveys.start_date <= ?", now).
+		Where("surveys.end_date IS NULL OR surveys.end_date > ?", now).
+		Where("COALESCE(surveys.response_count, 0) < surveys.max_responses").
+		Where("reward_pools.remaining_amount >= surveys.reward_per_response").
+		Order("surveys.created_at DESC, surveys.id DESC").
+		Limit(limit).
+		Scan(&pools).Error
+	if err != nil || len(pools) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uint, len(pools))
+	for i, pool := range pools {
+		ids[i] = pool.SurveyID
+	}
+	var surveys []models.Survey
+	if err := r.db.Preload("Creator").Preload("Quotas").Where("id IN ?", ids).Find(&surveys).Error; err != nil {
+		return nil, err
+	}
+
+	remaining := make(map[uint]float64, len(pools))
+	for _, pool := range pools {
+		remaining[pool.SurveyID] = pool.RemainingAmount
+	}
+	candidates := make([]RecommendationCandidate, len(surveys))
+	for i, survey := range surveys {
+		candidates[i] = RecommendationCandidate{Survey: survey, PoolRemaining: remaining[survey.ID]}
+	}
+	return candidates, nil
+}
+
+// Delete soft deletes a survey and records its survey.deleted event
+func (r *surveyRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var survey models.Survey
+		if err := tx.First(&survey, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&survey).Error; err != nil {
+			return err
+		}
+		return recordSurveyEvent(tx, models.OutboxEventSurveyDeleted, &survey)
+	})
+}
+
+func (r *surveyRepository) DeleteQuestions(surveyID uint) error {
+	return r.db.Where("survey_id = ?", surveyID).Delete(&models.Question{}).Error
+}
+
+func (r *surveyRepository) UpdateQuestionConditions(questions []models.Question) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range questions {
+			if err := tx.Model(&questions[i]).Select("show_if").Updates(&questions[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PublishWithRewardPool saves a published survey with its reward pool and
+// records its survey.published event
+func (r *surveyRepository) PublishWithRewardPool(survey *models.Survey, pool *models.RewardPool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(survey).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(pool).Error; err != nil {
+			return err
+		}
+
+		event, err := models.NewOutboxEvent(models.OutboxEventSurveyPublished, models.OutboxAggregateSurvey, survey.ID, survey.ID, dto.SurveyPublishedEventData{
+			SurveyID:          survey.ID,
+			Title:             survey.Title,
+			Category:          survey.Category,
+			StartDate:         survey.StartDate,
+			EndDate:           survey.EndDate,
+			MaxResponses:      survey.MaxResponses,
+			RewardPerResponse: survey.RewardPerResponse,
+			TotalRewardPool:   pool.TotalAmount,
+		})
+		if err != nil {
+			return err
+		}
+		return RecordOutboxEvents(tx, event)
+	})
+}
+
+func recordSurveyEvent(tx *gorm.DB, eventType string, survey *models.Survey) error {
+	event, err := models.NewOutboxEvent(eventType, models.OutboxAggregateSurvey, survey.ID, survey.ID, dto.SurveyEventData{
+		SurveyID: survey.ID,
+		Title:    survey.Title,
+		Status:   string(survey.Status),
+	})
+	if err != nil {
+		return err
+	}
+	return RecordOutboxEvents(tx, event)
+}
+
+// UpdateStatistics recomputes the survey's response summary, response count
+// and completion rate from its responses
+func (r *surveyRepository) UpdateStatistics(surveyID uint) error {
+	_, err := recomputeSurveyStatistics(r.db, surveyID)
+	return err
+}
+
+// recomputeSurveyStatistics rebuilds the response summaries of one survey, or
+// of every survey when surveyID is 0, and copies the response count and
+// completion rate onto the surveys. It returns the number of surveys updated.
+func recomputeSurveyStatistics(db *gorm.DB, surveyID uint) (int64, error) {
+	var updated int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		scope, scopeArgs := "", []interface{}{}
+		if surveyID != 0 {
+			scope, scopeArgs = " AND s.id = ?", []interface{}{surveyID}
+		}
+		completed, abandoned := models.ResponseStatusCompleted, models.ResponseStatusAbandoned
+		args := append([]interface{}{completed, abandoned, completed, completed, completed}, scopeArgs...)
+
+		err := tx.Exec(`INSERT INTO response_summaries
+			(survey_id, total_responses, completed_count, abandoned_count, average_duration, completion_rate, average_quality, last_response_at)
+			SELECT s.id,
+				COUNT(r.id),
+				COUNT(r.id) FILTER (WHERE r.status = ?),
+				COUNT(r.id) FILTER (WHERE r.status = ?),
+				COALESCE(AVG(r.duration) FILTER (WHERE r.status = ?), 0),
+				CASE WHEN COUNT(r.id) = 0 THEN 0
+					ELSE COUNT(r.id) FILTER (WHERE r.status = ?) * 100.0 / COUNT(r.id) END,
+				COALESCE(AVG(r.quality_score) FILTER (WHERE r.status = ?), 0),
+				MAX(r.created_at)
+			FROM surveys s
+			LEFT JOIN responses r ON r.survey_id = s.id AND r.deleted_at IS NULL
+			WHERE s.deleted_at IS NULL`+scope+`
+			GROUP BY s.id
+			ON CONFLICT (survey_id) DO UPDATE SET
+				total_responses = EXCLUDED.total_responses,
+				completed_count = EXCLUDED.completed_count,
+				abandoned_count = EXCLUDED.abandoned_count,
+				average_duration = EXCLUDED.average_duration,
+				completion_rate = EXCLUDED.completion_rate,
+				average_quality = EXCLUDED.average_quality,
+				last_response_at = EXCLUDED.last_response_at`, args...).Error
+		if err != nil {
+			return err
+		}
+
+		query := tx.Exec(`UPDATE surveys s SET
+				response_count = rs.completed_count,
+				completion_rate = rs.completion_rate
+			FROM response_summaries rs
+			WHERE rs.survey_id = s.id AND s.deleted_at IS NULL`+scope, scopeArgs...)
+		updated = query.RowsAffected
+		return query.Error
+	})
+	return updated, err
+}
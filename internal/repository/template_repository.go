@@ -0,0 +1,139 @@
+// internal/repository/template_repository.go
+package repository
+
+import (
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Template and question bank listing scopes
+const (
+	ScopeAll      = "all"
+	ScopePlatform = "platform"
+	ScopePersonal = "personal"
+)
+
+type TemplateRepository interface {
+	CreateTemplate(template *models.SurveyTemplate) error
+	UpdateTemplate(template *models.SurveyTemplate) error
+	GetTemplateByID(id uint) (*models.SurveyTemplate, error)
+	ListTemplates(userID uint, scope, category string, page, limit int) ([]models.SurveyTemplate, int64, error)
+	DeleteTemplate(id uint) error
+	IncrementTemplateUsage(id uint) error
+
+	CreateBankQuestion(question *models.BankQuestion) error
+	GetBankQuestionsByIDs(ids []uint) ([]models.BankQuestion, error)
+	ListBankQuestions(userID uint, scope, category, questionType string, page, limit int) ([]models.BankQuestion, int64, error)
+	DeleteBankQuestion(id uint) error
+	IncrementBankQuestionUsage(ids []uint) error
+}
+
+type templateRepository struct {
+	db *gorm.DB
+}
+
+func NewTemplateRepository(db *gorm.DB) TemplateRepository {
+	return &templateRepository{db: db}
+}
+
+func (r *templateRepository) CreateTemplate(template *models.SurveyTemplate) error {
+	return r.db.Create(template).Error
+}
+
+func (r *templateRepository) UpdateTemplate(template *models.SurveyTemplate) error {
+	return r.db.Save(template).Error
+}
+
+func (r *templateRepository) GetTemplateByID(id uint) (*models.SurveyTemplate, error) {
+	var template models.SurveyTemplate
+	err := r.db.First(&template, id).Error
+	return &template, apperror.NotFoundAs(err, "Template not found")
+}
+
+func (r *templateRepository) ListTemplates(userID uint, scope, category string, page, limit int) ([]models.SurveyTemplate, int64, error) {
+	var templates []models.SurveyTemplate
+	var total int64
+
+	query := r.scoped(r.db.Model(&models.SurveyTemplate{}), userID, scope).
+		Where("owner_id IS NOT NULL OR is_active = ?", true)
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Order("is_curated DESC, usage_count DESC, id").Offset(offset).Limit(limit).Find(&templates).Error
+
+	return templates, total, err
+}
+
+func (r *templateRepository) DeleteTemplate(id uint) error {
+	return r.db.Delete(&models.SurveyTemplate{}, id).Error
+}
+
+func (r *templateRepository) IncrementTemplateUsage(id uint) error {
+	return r.db.Model(&models.SurveyTemplate{}).
+		Where("id = ?", id).
+		Update("usage_count", gorm.Expr("usage_count + 1")).Error
+}
+
+func (r *templateRepository) CreateBankQuestion(question *models.BankQuestion) error {
+	return r.db.Create(question).Error
+}
+
+func (r *templateRepository) GetBankQuestionsByIDs(ids []uint) ([]models.BankQuestion, error) {
+	var questions []models.BankQuestion
+	if len(ids) == 0 {
+		return questions, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&questions).Error
+	return questions, err
+}
+
+func (r *templateRepository) ListBankQuestions(userID uint, scope, category, questionType string, page, limit int) ([]models.BankQuestion, int64, error) {
+	var questions []models.BankQuestion
+	var total int64
+
+	query := r.scoped(r.db.Model(&models.BankQuestion{}), userID, scope)
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if questionType != "" {
+		query = query.Where("definition->>'type' = ?", questionType)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Order("usage_count DESC, id").Offset(offset).Limit(limit).Find(&questions).Error
+
+	return questions, total, err
+}
+
+func (r *templateRepository) DeleteBankQuestion(id uint) error {
+	return r.db.Delete(&models.BankQuestion{}, id).Error
+}
+
+func (r *templateRepository) IncrementBankQuestionUsage(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.BankQuestion{}).
+		Where("id IN ?", ids).
+		Update("usage_count", gorm.Expr("usage_count + 1")).Error
+}
+
+// scoped restricts a query to platform entries, the user's own entries, or both
+func (r *templateRepository) scoped(query *gorm.DB, userID uint, scope string) *gorm.DB {
+	switch scope {
+	case ScopePlatform:
+		return query.Where("owner_id IS NULL")
+	case ScopePersonal:
+		return query.Where("owner_id = ?", userID)
+	default:
+		return query.Where("owner_id IS NULL OR owner_id = ?", userID)
+	}
+}
@@ -0,0 +1,52 @@
+// internal/repository/text_analysis_repository.go
+package repository
+
+import (
+	"survey2earn-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TextAnalysisRepository interface {
+	GetBySurveyID(surveyID uint) ([]models.TextAnalysis, error)
+	Update(surveyID, questionID uint, fn func(analysis *models.TextAnalysis) error) error
+}
+
+type textAnalysisRepository struct {
+	db *gorm.DB
+}
+
+func NewTextAnalysisRepository(db *gorm.DB) TextAnalysisRepository {
+	return &textAnalysisRepository{db: db}
+}
+
+func (r *textAnalysisRepository) GetBySurveyID(surveyID uint) ([]models.TextAnalysis, error) {
+	var analyses []models.TextAnalysis
+	err := r.db.Where("survey_id = ?", surveyID).Order("question_id").Find(&analyses).Error
+	return analyses, err
+}
+
+// Update applies fn to the analysis of a question and saves it, creating the
+// analysis first if needed. The row is locked while fn runs so concurrent
+// completions don't overwrite each other's answers.
+func (r *textAnalysisRepository) Update(surveyID, questionID uint, fn func(analysis *models.TextAnalysis) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		analysis := models.TextAnalysis{SurveyID: surveyID, QuestionID: questionID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&analysis).Error; err != nil {
+			return err
+		}
+
+		analysis = models.TextAnalysis{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("question_id = ?", questionID).First(&analysis).Error
+		if err != nil {
+			return err
+		}
+
+		if err := fn(&analysis); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Save(&analysis).Error
+	})
+}
@@ -0,0 +1,119 @@
+// internal/repository/user_repository.go
+package repository
+
+import (
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) Create(user *models.User) error {
+	return r.db.Create(user).Error
+}
+
+func (r *userRepository) GetByID(id uint) (*models.User, error) {
+	var user models.User
+	err := r.db.First(&user, id).Error
+	return &user, apperror.NotFoundAs(err, "User not found")
+}
+
+func (r *userRepository) GetByWalletAddress(address string) (*models.User, error) {
+	var user models.User
+	err := r.db.Where("wallet_address = ?", address).First(&user).Error
+	return &user, apperror.NotFoundAs(err, "User not found")
+}
+
+func (r *userRepository) GetByUsername(username string) (*models.User, error) {
+	var user models.User
+	err := r.db.Where("username = ?", username).First(&user).Error
+	return &user, err
+}
+
+func (r *userRepository) GetByEmail(email string) (*models.User, error) {
+	var user models.User
+	err := r.db.Where("email = ?", email).First(&user).Error
+	return &user, err
+}
+
+func (r *userRepository) Update(user *models.User) error {
+	return r.db.Save(user).Error
+}
+
+// GetStats aggregates a user's activity as a creator and as a respondent
+func (r *userRepository) GetStats(userID uint) (*models.UserStats, error) {
+	user, err := r.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	stats := &models.UserStats{
+		UserID:      userID,
+		TotalEarned: user.TotalEarned,
+	}
+
+	var created struct {
+		Count         int
+		AverageRating float64
+		LastActivity  *time.Time
+	}
+	err = r.db.Model(&models.Survey{}).
+		Select("COUNT(*) AS count, COALESCE(AVG(NULLIF(average_rating, 0)), 0) AS average_rating, MAX(updated_at) AS last_activity").
+		Where("creator_id = ?", userID).
+		Scan(&created).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.TotalSurveysCreated = created.Count
+	stats.AverageRating = created.AverageRating
+
+	err = r.db.Model(&models.RewardPool{}).
+		Select("COALESCE(SUM(reward_pools.paid_out), 0)").
+		Joins("JOIN surveys ON surveys.id = reward_pools.survey_id").
+		Where("surveys.creator_id = ?", userID).
+		Scan(&stats.TotalSpent).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var answered struct {
+		Count        int
+		LastActivity *time.Time
+	}
+	err = r.db.Model(&models.Response{}).
+		Select("COUNT(*) FILTER (WHERE status = ?) AS count, MAX(updated_at) AS last_activity", models.ResponseStatusCompleted).
+		Where("user_id = ?", userID).
+		Scan(&answered).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.TotalSurveysAnswered = answered.Count
+
+	stats.LastActivityAt = created.LastActivity
+	if answered.LastActivity != nil && (stats.LastActivityAt == nil || answered.LastActivity.After(*stats.LastActivityAt)) {
+		stats.LastActivityAt = answered.LastActivity
+	}
+	return stats, nil
+}
+
+// List pages through every user, newest first
+func (r *userRepository) List(page, limit int) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	query := r.db.Model(&models.User{})
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&users).Error
+
+	return users, total, err
+}
@@ -0,0 +1,156 @@
+// internal/repository/webhook_repository.go
+package repository
+
+import (
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	Create(webhook *models.Webhook) error
+	Update(webhook *models.Webhook) error
+	Delete(id uint) error
+	GetByID(id uint) (*models.Webhook, error)
+	GetByUserID(userID uint) ([]models.Webhook, error)
+	GetSubscribed(surveyID uint, eventType string) ([]models.Webhook, error)
+
+	CreateDeliveries(deliveries []models.WebhookDelivery) error
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+	GetDelivery(webhookID, deliveryID uint) (*models.WebhookDelivery, error)
+	GetDeliveries(webhookID uint, status string, page, limit int) ([]models.WebhookDelivery, int64, error)
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+
+	// WithTx returns a repository that works inside tx
+	WithTx(tx *gorm.DB) WebhookRepository
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(webhook *models.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+func (r *webhookRepository) Update(webhook *models.Webhook) error {
+	return r.db.Omit(clause.Associations).Save(webhook).Error
+}
+
+// Delete removes a webhook and gives up on its pending deliveries
+func (r *webhookRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.WebhookDelivery{}).
+			Where("webhook_id = ? AND status = ?", id, models.WebhookDeliveryStatusPending).
+			Updates(map[string]interface{}{
+				"status":          models.WebhookDeliveryStatusFailed,
+				"error":           "webhook deleted",
+				"next_attempt_at": nil,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&models.Webhook{}, id).Error
+	})
+}
+
+func (r *webhookRepository) GetByID(id uint) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := r.db.First(&webhook, id).Error
+	return &webhook, apperror.NotFoundAs(err, "Webhook not found")
+}
+
+func (r *webhookRepository) GetByUserID(userID uint) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	err := r.db.Where("user_id = ?", userID).Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// GetSubscribed returns the active webhooks of a survey's creator that want
+// an event of the survey: those registered for the survey and those for all
+// of the creator's surveys
+func (r *webhookRepository) GetSubscribed(surveyID uint, eventType string) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	err := r.db.Where("user_id = (?)", r.db.Model(&models.Survey{}).Select("creator_id").Where("id = ?", surveyID)).
+		Where("is_active = ?", true).
+		Where("survey_id IS NULL OR survey_id = ?", surveyID).
+		Where("events::jsonb @> jsonb_build_array(?::text)", eventType).
+		Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepository) CreateDeliveries(deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Omit(clause.Associations).Create(&deliveries).Error
+}
+
+func (r *webhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Omit(clause.Associations).Save(delivery).Error
+}
+
+func (r *webhookRepository) GetDelivery(webhookID, deliveryID uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.Where("webhook_id = ?", webhookID).First(&delivery, deliveryID).Error
+	return &delivery, apperror.NotFoundAs(err, "Delivery not found")
+}
+
+func (r *webhookRepository) GetDeliveries(webhookID uint, status string, page, limit int) ([]models.WebhookDelivery, int64, error) {
+	var deliveries []models.WebhookDelivery
+	var total int64
+
+	query := r.db.Model(&models.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&deliveries).Error
+
+	return deliveries, total, err
+}
+
+// ClaimDueDeliveries picks pending deliveries whose next attempt is due and
+// pushes their next attempt back by lease, so other workers skip them while
+// they are being sent. Rows locked by another worker are skipped rather than
+// waited for. Deliveries are returned with their webhook.
+func (r *webhookRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	var ids []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var due []models.WebhookDelivery
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("id").
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryStatusPending, now).
+			Order("next_attempt_at").Limit(limit).Find(&due).Error
+		if err != nil || len(due) == 0 {
+			return err
+		}
+
+		for _, delivery := range due {
+			ids = append(ids, delivery.ID)
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	err = r.db.Preload("Webhook").Where("id IN ?", ids).Order("id").Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookRepository) WithTx(tx *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: tx}
+}
@@ -0,0 +1,369 @@
+// internal/routes/contract_test.go
+package routes
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/events"
+	"survey2earn-backend/internal/handler"
+	"survey2earn-backend/internal/openapi"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// openAPIDocumentPath is the committed document, served at /docs/openapi.json
+const openAPIDocumentPath = "../apidocs/openapi.json"
+
+// contract checks exchanges against an OpenAPI document
+type contract struct {
+	doc *openapi.Document
+}
+
+func loadContract(t *testing.T) *contract {
+	t.Helper()
+	data, err := os.ReadFile(openAPIDocumentPath)
+	if err != nil {
+		t.Fatalf("read the OpenAPI document: %v", err)
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("decode the OpenAPI document: %v", err)
+	}
+	return &contract{doc: &doc}
+}
+
+var routeParam = regexp.MustCompile(`:(\w+)`)
+
+// documentedPath turns a route's path pattern into the path the document
+// lists it under, relative to the API's base URL
+func documentedPath(route string) string {
+	path := strings.TrimPrefix(route, "/api/v1")
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return routeParam.ReplaceAllString(path, "{$1}")
+}
+
+// check lists how an exchange disagrees with the document
+func (c *contract) check(ex exchange) []string {
+	path := documentedPath(ex.Route)
+	operation, ok := c.doc.Paths[path][strings.ToLower(ex.Method)]
+	if !ok {
+		return []string{fmt.Sprintf("%s %s is not documented", ex.Method, path)}
+	}
+	response, ok := operation.Responses[strconv.Itoa(ex.Status)]
+	if !ok {
+		return []string{fmt.Sprintf("status %d is not documented: %s", ex.Status, ex.Body)}
+	}
+	if len(response.Content) == 0 {
+		if len(ex.Body) > 0 {
+			return []string{fmt.Sprintf("status %d is documented without a body, got %s", ex.Status, ex.Body)}
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(ex.ContentType)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid Content-Type %q", ex.ContentType)}
+	}
+	media, ok := response.Content[mediaType]
+	if !ok {
+		documented := make([]string, 0, len(response.Content))
+		for documentedType := range response.Content {
+			documented = append(documented, documentedType)
+		}
+		sort.Strings(documented)
+		return []string{fmt.Sprintf("status %d is served as %s, documented as %s", ex.Status, mediaType, strings.Join(documented, ", "))}
+	}
+
+	switch {
+	case media.Schema != nil && media.Schema.Format == "binary":
+		// Files are opaque
+		return nil
+	case mediaType == "text/event-stream":
+		// Each event's data is documented
+		var problems []string
+		scanner := bufio.NewScanner(bytes.NewReader(ex.Body))
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				problems = append(problems, c.validateJSON(media.Schema, []byte(data))...)
+			}
+		}
+		return problems
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return c.validateJSON(media.Schema, ex.Body)
+	}
+	return nil
+}
+
+// validateJSON lists how data disagrees with schema
+func (c *contract) validateJSON(schema *openapi.Schema, data []byte) []string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return []string{fmt.Sprintf("invalid JSON: %v: %s", err, data)}
+	}
+	return c.validate(schema, value, "body")
+}
+
+// validate lists how value, found at path, disagrees with schema. It
+// understands the JSON Schema keywords the generated document uses.
+func (c *contract) validate(schema *openapi.Schema, value interface{}, path string) []string {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		referenced, ok := c.doc.Components.Schemas[name]
+		if !ok {
+			return []string{fmt.Sprintf("%s: unknown schema %s", path, schema.Ref)}
+		}
+		return c.validate(referenced, value, path)
+	}
+
+	var problems []string
+	for _, sub := range schema.AllOf {
+		problems = append(problems, c.validate(sub, value, path)...)
+	}
+	if len(schema.AnyOf) > 0 && c.matching(schema.AnyOf, value, path) == 0 {
+		problems = append(problems, fmt.Sprintf("%s: %s matches none of the allowed schemas", path, describe(value)))
+	}
+	if len(schema.OneOf) > 0 && c.matching(schema.OneOf, value, path) != 1 {
+		problems = append(problems, fmt.Sprintf("%s: %s doesn't match exactly one of the allowed schemas", path, describe(value)))
+	}
+
+	if types := schemaTypes(schema); len(types) > 0 && !hasType(types, value) {
+		return append(problems, fmt.Sprintf("%s: got %s, want %s", path, describe(value), strings.Join(types, " or ")))
+	}
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		problems = append(problems, fmt.Sprintf("%s: %s is not one of %v", path, describe(value), schema.Enum))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := schema.Properties[name]; ok {
+				problems = append(problems, c.validate(property, v[name], path+"."+name)...)
+			} else if schema.AdditionalProperties != nil {
+				problems = append(problems, c.validate(schema.AdditionalProperties, v[name], path+"."+name)...)
+			}
+		}
+	case []interface{}:
+		if schema.MinItems != nil && len(v) < *schema.MinItems {
+			problems = append(problems, fmt.Sprintf("%s: %d items, want at least %d", path, len(v), *schema.MinItems))
+		}
+		if schema.MaxItems != nil && len(v) > *schema.MaxItems {
+			problems = append(problems, fmt.Sprintf("%s: %d items, want at most %d", path, len(v), *schema.MaxItems))
+		}
+		for i, item := range v {
+			problems = append(problems, c.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			problems = append(problems, fmt.Sprintf("%s: %q is shorter than %d", path, v, *schema.MinLength))
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			problems = append(problems, fmt.Sprintf("%s: %q is longer than %d", path, v, *schema.MaxLength))
+		}
+		if schema.Pattern != "" {
+			if pattern, err := regexp.Compile(schema.Pattern); err == nil && !pattern.MatchString(v) {
+				problems = append(problems, fmt.Sprintf("%s: %q doesn't match %s", path, v, schema.Pattern))
+			}
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %q is not a date-time", path, v))
+			}
+		}
+	case json.Number:
+		n, _ := v.Float64()
+		if schema.Minimum != nil && n < *schema.Minimum {
+			problems = append(problems, fmt.Sprintf("%s: %s is below the minimum %v", path, v, *schema.Minimum))
+		}
+		if schema.Maximum != nil && n > *schema.Maximum {
+			problems = append(problems, fmt.Sprintf("%s: %s is above the maximum %v", path, v, *schema.Maximum))
+		}
+		if schema.ExclusiveMinimum != nil && n <= *schema.ExclusiveMinimum {
+			problems = append(problems, fmt.Sprintf("%s: %s is not above %v", path, v, *schema.ExclusiveMinimum))
+		}
+		if schema.ExclusiveMaximum != nil && n >= *schema.ExclusiveMaximum {
+			problems = append(problems, fmt.Sprintf("%s: %s is not below %v", path, v, *schema.ExclusiveMaximum))
+		}
+	}
+	return problems
+}
+
+// matching counts the schemas value matches
+func (c *contract) matching(schemas []*openapi.Schema, value interface{}, path string) int {
+	count := 0
+	for _, schema := range schemas {
+		if len(c.validate(schema, value, path)) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// schemaTypes lists the types a schema allows, none for any type
+func schemaTypes(schema *openapi.Schema) []string {
+	switch t := schema.Type.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, name := range t {
+			if s, ok := name.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func hasType(types []string, value interface{}) bool {
+	for _, t := range types {
+		switch v := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case json.Number:
+			if t == "number" {
+				return true
+			}
+			if n, err := v.Float64(); t == "integer" && err == nil && n == math.Trunc(n) {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inEnum compares values as JSON, since the document's numbers decode as
+// float64 and the body's as json.Number
+func inEnum(enum []interface{}, value interface{}) bool {
+	encoded, _ := json.Marshal(value)
+	for _, allowed := range enum {
+		if allowedEncoded, _ := json.Marshal(allowed); bytes.Equal(allowedEncoded, encoded) {
+			return true
+		}
+	}
+	return false
+}
+
+func describe(value interface{}) string {
+	encoded, _ := json.Marshal(value)
+	if len(encoded) > 80 {
+		return string(encoded[:77]) + "..."
+	}
+	return string(encoded)
+}
+
+// TestAPIContract checks every response of the end-to-end scenario against
+// the committed OpenAPI document
+func TestAPIContract(t *testing.T) {
+	contract := loadContract(t)
+	api := runScenario(t)
+
+	for _, ex := range api.recorded() {
+		// The documentation routes serve the document rather than being in it
+		if ex.Route == "" || strings.HasPrefix(ex.Route, "/api/v1/docs") {
+			continue
+		}
+		for _, problem := range contract.check(ex) {
+			t.Errorf("%s %s: %s", ex.Method, ex.Path, problem)
+		}
+	}
+}
+
+func TestContractCheckFindsDisagreements(t *testing.T) {
+	contract := loadContract(t)
+	marshal := func(v interface{}) []byte {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	surveyExchange := func(status int, contentType string, body []byte) exchange {
+		return exchange{Method: "GET", Route: "/api/v1/surveys/:id", Path: "/api/v1/surveys/1", Status: status, ContentType: contentType, Body: body}
+	}
+	notFound := marshal(apperror.Problem{Type: "about:blank", Title: "Not Found", Status: 404, Code: apperror.NotFound, Detail: "survey not found"})
+
+	agreeing := map[string]exchange{
+		// Nil slices and pointers of an empty survey are marshaled as null
+		"survey":  surveyExchange(200, "application/json; charset=utf-8", marshal(handler.SuccessResponse{Success: true, Data: dto.SurveyResponse{}})),
+		"problem": surveyExchange(404, "application/problem+json", notFound),
+		"list":    {Method: "GET", Route: "/api/v1/webhooks/", Status: 200, ContentType: "application/json", Body: marshal(handler.SuccessResponse{Success: true, Data: []dto.WebhookResponse{{}}})},
+		"file":    {Method: "GET", Route: "/api/v1/exports/:id/download", Status: 200, ContentType: "text/csv; charset=utf-8", Body: []byte("id,answer\n1,yes\n")},
+		"stream":  {Method: "GET", Route: "/api/v1/surveys/:id/events", Status: 200, ContentType: "text/event-stream", Body: []byte("retry: 3000\n\nid: 1\nevent: response.completed\ndata: " + string(marshal(events.Event{ID: 1, Type: events.ResponseCompleted})) + "\n\n")},
+	}
+	for name, ex := range agreeing {
+		if problems := contract.check(ex); len(problems) > 0 {
+			t.Errorf("%s: check = %q, want no problems", name, problems)
+		}
+	}
+
+	disagreeing := map[string]exchange{
+		"undocumented route":        {Method: "GET", Route: "/api/v1/surveys/:id/comments", Status: 200, ContentType: "application/json", Body: []byte(`{"success": true}`)},
+		"undocumented method":       {Method: "PATCH", Route: "/api/v1/surveys/:id", Status: 200, ContentType: "application/json", Body: []byte(`{"success": true}`)},
+		"undocumented status":       surveyExchange(409, "application/problem+json", notFound),
+		"undocumented content type": surveyExchange(404, "text/html", []byte("<h1>Not Found</h1>")),
+		"file served as JSON":       {Method: "GET", Route: "/api/v1/exports/:id/download", Status: 200, ContentType: "application/json", Body: []byte(`{}`)},
+		"missing property":          surveyExchange(404, "application/problem+json", []byte(`{"type": "about:blank", "title": "Not Found", "status": 404}`)),
+		"unknown error code":        surveyExchange(404, "application/problem+json", []byte(`{"type": "about:blank", "title": "Not Found", "status": 404, "code": "gone_fishing"}`)),
+		"wrong type":                surveyExchange(200, "application/json", []byte(`{"success": true, "data": {"id": "1"}}`)),
+		"fractional integer":        surveyExchange(404, "application/problem+json", []byte(`{"type": "about:blank", "title": "Not Found", "status": 404.5, "code": "not_found"}`)),
+		"null where not nullable":   surveyExchange(200, "application/json", []byte(`{"success": true, "data": {"id": 1, "created_at": null}}`)),
+		"bad date-time":             surveyExchange(200, "application/json", []byte(`{"success": true, "data": {"id": 1, "created_at": "yesterday"}}`)),
+		"missing envelope data":     surveyExchange(200, "application/json", []byte(`{"success": true}`)),
+		"invalid JSON":              surveyExchange(200, "application/json", []byte(`{"success": tru`)),
+		"bad stream event":          {Method: "GET", Route: "/api/v1/surveys/:id/events", Status: 200, ContentType: "text/event-stream", Body: []byte("event: response.completed\ndata: {\"id\": \"one\"}\n\n")},
+	}
+	for name, ex := range disagreeing {
+		if problems := contract.check(ex); len(problems) == 0 {
+			t.Errorf("%s: check found no problems", name)
+		}
+	}
+}
@@ -0,0 +1,711 @@
+// internal/routes/e2e_test.go
+package routes
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/database"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/middleware"
+	"survey2earn-backend/internal/wallet"
+	"survey2earn-backend/internal/webhook"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/sha3"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The end-to-end tests run the API against the Postgres database at
+// TEST_DATABASE_URL and are skipped without it. The database is wiped
+// before each test, so never point it at one holding data you need.
+const testDatabaseEnv = "TEST_DATABASE_URL"
+
+// exchange is a request the API answered, as recorded on the server
+type exchange struct {
+	Method      string
+	Route       string // the route's path pattern, empty for unknown routes
+	Path        string
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// testAPI is the API served from a fresh database, recording every exchange
+type testAPI struct {
+	url    string
+	routes gin.RoutesInfo
+
+	mu        sync.Mutex
+	exchanges []exchange
+}
+
+// testWallet signs in like a user's wallet would
+type testWallet struct {
+	key     *secp256k1.PrivateKey
+	address string
+}
+
+func newTestWallet(t *testing.T) testWallet {
+	t.Helper()
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("generate wallet key: %v", err)
+	}
+	publicKey := key.PubKey().SerializeUncompressed()[1:]
+	return testWallet{key: key, address: "0x" + hex.EncodeToString(keccak256(publicKey)[12:])}
+}
+
+// sign signs message for personal_sign (EIP-191), returning the r||s||v hex
+// signature wallets send
+func (w testWallet) sign(message string) string {
+	hash := keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)))
+	compact := ecdsa.SignCompact(w.key, hash, false)
+	return "0x" + hex.EncodeToString(append(compact[1:], compact[0]))
+}
+
+func keccak256(data []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+func testConfig(t *testing.T, admin testWallet) *config.Config {
+	unlimited := config.RateLimitRule{RequestsPerMinute: 100000, Burst: 100000}
+	return &config.Config{
+		Server: config.ServerConfig{Env: "test", APIVersion: "v1"},
+		Cache: config.CacheConfig{
+			Driver:                  "memory",
+			SurveyTTLSeconds:        300,
+			PublicSurveysTTLSeconds: 60,
+			AnalyticsTTLSeconds:     300,
+			CategoriesTTLSeconds:    300,
+		},
+		JWT:   config.JWTConfig{Secret: "end-to-end-secret", ExpirationHours: 1, RefreshExpirationHours: 24},
+		Admin: config.AdminConfig{WalletAddresses: []string{admin.address}},
+		RateLimit: config.RateLimitConfig{
+			RequestsPerMinute: unlimited.RequestsPerMinute,
+			Burst:             unlimited.Burst,
+			Driver:            "memory",
+			Login:             unlimited,
+			StartResponse:     unlimited,
+		},
+		Export:      config.ExportConfig{Dir: t.TempDir(), SyncMaxResponses: 100, Workers: 1},
+		Response:    config.ResponseConfig{IdleTimeoutMinutes: 60, ExpiryIntervalSeconds: 60},
+		Idempotency: config.IdempotencyConfig{TTLHours: 1},
+		// The test webhook receiver listens on the loopback interface
+		Webhook: config.WebhookConfig{TimeoutSeconds: 5, MaxAttempts: 3, AllowPrivateNetworks: true},
+		Outbox:  config.OutboxConfig{PollIntervalMs: 50, BatchSize: 100, RetentionHours: 1},
+	}
+}
+
+// newTestAPI migrates and seeds a wiped database and serves the API from it
+// like the server does, until the test ends
+func newTestAPI(t *testing.T, admin testWallet) *testAPI {
+	t.Helper()
+	dsn := os.Getenv(testDatabaseEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseEnv)
+	}
+
+	gormDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	})
+	if err != nil {
+		t.Fatalf("connect to %s: %v", testDatabaseEnv, err)
+	}
+	db := &database.Database{DB: gormDB}
+	if err := gormDB.Exec("DROP SCHEMA public CASCADE; CREATE SCHEMA public").Error; err != nil {
+		t.Fatalf("wipe the test database: %v", err)
+	}
+	migrator, err := db.Migrator()
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := db.Seed(); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	api := &testAPI{}
+	router := gin.New()
+	router.Use(api.record, middleware.Errors())
+	components := SetupRoutes(router, testConfig(t, admin), db)
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(func(c *gin.Context) {
+		c.Error(apperror.New(apperror.NotFound, "Route not found"))
+	})
+	router.NoMethod(func(c *gin.Context) {
+		c.Error(apperror.Newf(apperror.MethodNotAllowed, "Method %s is not allowed on this route", c.Request.Method))
+	})
+	api.routes = router.Routes()
+
+	server := httptest.NewServer(router)
+	api.url = server.URL + "/api/v1"
+	t.Cleanup(func() {
+		// Streams end first, as on shutdown, so the server can close
+		components.CloseStreams()
+		server.Close()
+		components.Stop()
+		db.Close()
+	})
+	return api
+}
+
+// bodyRecorder keeps a copy of the response body
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// record is the outermost middleware, so it sees responses as clients do
+func (api *testAPI) record(c *gin.Context) {
+	recorder := &bodyRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	c.Next()
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.exchanges = append(api.exchanges, exchange{
+		Method:      c.Request.Method,
+		Route:       c.FullPath(),
+		Path:        c.Request.URL.Path,
+		Status:      recorder.Status(),
+		ContentType: recorder.Header().Get("Content-Type"),
+		Body:        recorder.body.Bytes(),
+	})
+}
+
+func (api *testAPI) recorded() []exchange {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return append([]exchange(nil), api.exchanges...)
+}
+
+// waitForExchange waits until a request to route has been answered, for
+// streams that end after the client goes away
+func (api *testAPI) waitForExchange(t *testing.T, method, route string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, ex := range api.recorded() {
+			if ex.Method == method && ex.Route == route {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s %s was never answered", method, route)
+}
+
+// apiClient sends requests as a user, or anonymously without a token
+type apiClient struct {
+	api   *testAPI
+	token string
+}
+
+type apiResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// do sends body as JSON, followed by header name and value pairs
+func (c apiClient) do(t *testing.T, method, path string, body interface{}, header ...string) apiResponse {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("encode %s %s body: %v", method, path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.api.url+path, reader)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("read %s %s: %v", method, path, err)
+	}
+	return apiResponse{status: res.StatusCode, header: res.Header, body: data}
+}
+
+// expect sends a request that must be answered with status, decoding the
+// data of a successful response into out, if given
+func (c apiClient) expect(t *testing.T, status int, method, path string, body, out interface{}, header ...string) apiResponse {
+	t.Helper()
+	res := c.do(t, method, path, body, header...)
+	if res.status != status {
+		t.Fatalf("%s %s = %d, want %d: %s", method, path, res.status, status, res.body)
+	}
+	if out != nil {
+		envelope := struct {
+			Data interface{} `json:"data"`
+		}{Data: out}
+		if err := json.Unmarshal(res.body, &envelope); err != nil {
+			t.Fatalf("decode %s %s: %v: %s", method, path, err, res.body)
+		}
+	}
+	return res
+}
+
+// expectProblem sends a request that must fail with status and code
+func (c apiClient) expectProblem(t *testing.T, status int, code apperror.Code, method, path string, body interface{}) {
+	t.Helper()
+	res := c.expect(t, status, method, path, body, nil)
+	var problem apperror.Problem
+	if err := json.Unmarshal(res.body, &problem); err != nil || problem.Code != code {
+		t.Fatalf("%s %s = %s, want a %s problem", method, path, res.body, code)
+	}
+}
+
+// signIn registers the wallet and logs in with it
+func (api *testAPI) signIn(t *testing.T, wallet testWallet) apiClient {
+	t.Helper()
+	anonymous := apiClient{api: api}
+	anonymous.expect(t, http.StatusCreated, "POST", "/auth/register", dto.RegisterRequest{WalletAddress: wallet.address}, nil)
+
+	var nonce dto.NonceResponse
+	anonymous.expect(t, http.StatusOK, "GET", "/auth/nonce?wallet_address="+wallet.address, nil, &nonce)
+	var login dto.LoginResponse
+	anonymous.expect(t, http.StatusOK, "POST", "/auth/login", dto.LoginRequest{
+		WalletAddress: wallet.address,
+		Signature:     wallet.sign(nonce.Message),
+		Message:       nonce.Message,
+	}, &login)
+	return apiClient{api: api, token: login.AccessToken}
+}
+
+// runScenario drives every route of the API through a survey's life: users
+// sign in, a creator builds and publishes a survey, a respondent completes
+// it while the creator watches, and the creator analyses and exports the
+// results. Admins curate templates and categories along the way.
+func runScenario(t *testing.T) *testAPI {
+	adminWallet := newTestWallet(t)
+	api := newTestAPI(t, adminWallet)
+	anonymous := apiClient{api: api}
+
+	// Authentication
+	creatorWallet := newTestWallet(t)
+	creator := api.signIn(t, creatorWallet)
+	respondent := api.signIn(t, newTestWallet(t))
+	admin := api.signIn(t, adminWallet)
+
+	anonymous.expectProblem(t, http.StatusConflict, apperror.AlreadyRegistered, "POST", "/auth/register", dto.RegisterRequest{WalletAddress: creatorWallet.address})
+	var nonce dto.NonceResponse
+	anonymous.expect(t, http.StatusOK, "GET", "/auth/nonce?wallet_address="+creatorWallet.address, nil, &nonce)
+	anonymous.expectProblem(t, http.StatusUnauthorized, apperror.Unauthorized, "POST", "/auth/login", dto.LoginRequest{
+		WalletAddress: creatorWallet.address,
+		Signature:     newTestWallet(t).sign(nonce.Message),
+		Message:       nonce.Message,
+	})
+	var login dto.LoginResponse
+	anonymous.expect(t, http.StatusOK, "POST", "/auth/login", dto.LoginRequest{
+		WalletAddress: creatorWallet.address,
+		Signature:     creatorWallet.sign(nonce.Message),
+		Message:       nonce.Message,
+	}, &login)
+	var tokens dto.TokenResponse
+	anonymous.expect(t, http.StatusOK, "POST", "/auth/refresh", dto.RefreshTokenRequest{RefreshToken: login.RefreshToken}, &tokens)
+	session := apiClient{api: api, token: tokens.AccessToken}
+	session.expect(t, http.StatusOK, "POST", "/auth/logout", nil, nil)
+	anonymous.expectProblem(t, http.StatusUnauthorized, apperror.Unauthorized, "POST", "/auth/refresh", dto.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+
+	// Profiles
+	anonymous.expectProblem(t, http.StatusUnauthorized, apperror.Unauthorized, "GET", "/user/profile", nil)
+	creator.expect(t, http.StatusOK, "GET", "/user/profile", nil, nil)
+	bio := "Runs surveys end to end"
+	creator.expect(t, http.StatusOK, "PUT", "/user/profile", dto.UpdateProfileRequest{Bio: &bio}, nil)
+	creator.expect(t, http.StatusOK, "GET", "/user/stats", nil, nil)
+
+	// Categories
+	anonymous.expect(t, http.StatusOK, "GET", "/categories", nil, nil)
+	anonymous.expect(t, http.StatusOK, "GET", "/categories/finance", nil, nil)
+	anonymous.expectProblem(t, http.StatusNotFound, apperror.NotFound, "GET", "/categories/no-such-category", nil)
+	respondent.expectProblem(t, http.StatusForbidden, apperror.Forbidden, "GET", "/admin/categories", nil)
+	admin.expect(t, http.StatusOK, "GET", "/admin/categories", nil, nil)
+	var category dto.CategoryResponse
+	admin.expect(t, http.StatusCreated, "POST", "/admin/categories", dto.CreateCategoryRequest{
+		Slug:  "end-to-end",
+		Names: map[string]string{"en": "End to end"},
+	}, &category)
+	icon := "flask"
+	admin.expect(t, http.StatusOK, "PUT", fmt.Sprintf("/admin/categories/%d", category.ID), dto.UpdateCategoryRequest{Icon: &icon}, nil)
+	admin.expect(t, http.StatusOK, "DELETE", fmt.Sprintf("/admin/categories/%d", category.ID), nil, nil)
+
+	// Question bank and templates
+	textQuestion := dto.CreateQuestionRequest{Type: "text", Title: "What would you improve?", Order: 1}
+	var bankQuestion dto.BankQuestionResponse
+	creator.expect(t, http.StatusCreated, "POST", "/question-bank", dto.CreateBankQuestionRequest{Category: "finance", Question: textQuestion}, &bankQuestion)
+	creator.expect(t, http.StatusOK, "GET", "/question-bank", nil, nil)
+	creator.expect(t, http.StatusOK, "DELETE", fmt.Sprintf("/question-bank/%d", bankQuestion.ID), nil, nil)
+	admin.expect(t, http.StatusCreated, "POST", "/admin/question-bank", dto.CreateBankQuestionRequest{Category: "finance", Question: textQuestion}, &bankQuestion)
+	admin.expect(t, http.StatusOK, "DELETE", fmt.Sprintf("/admin/question-bank/%d", bankQuestion.ID), nil, nil)
+
+	templateRequest := dto.CreateTemplateRequest{
+		Name:          "Feedback",
+		Category:      "finance",
+		EstimatedTime: "3-5 min",
+		Questions:     []dto.CreateQuestionRequest{textQuestion},
+	}
+	renamed := "Product feedback"
+	var template dto.TemplateResponse
+	creator.expect(t, http.StatusCreated, "POST", "/templates", templateRequest, &template)
+	creator.expect(t, http.StatusOK, "GET", "/templates", nil, nil)
+	creator.expect(t, http.StatusOK, "GET", fmt.Sprintf("/templates/%d", template.ID), nil, nil)
+	creator.expect(t, http.StatusOK, "PUT", fmt.Sprintf("/templates/%d", template.ID), dto.UpdateTemplateRequest{Name: &renamed}, nil)
+	var fromTemplate dto.SurveyResponse
+	creator.expect(t, http.StatusCreated, "POST", fmt.Sprintf("/surveys/from-template/%d", template.ID), dto.CreateFromTemplateRequest{
+		RewardAmount:    1,
+		MaxParticipants: 10,
+	}, &fromTemplate)
+	respondent.expectProblem(t, http.StatusForbidden, apperror.Forbidden, "DELETE", fmt.Sprintf("/templates/%d", template.ID), nil)
+	creator.expect(t, http.StatusOK, "DELETE", fmt.Sprintf("/templates/%d", template.ID), nil, nil)
+
+	admin.expect(t, http.StatusCreated, "POST", "/admin/templates", templateRequest, &template)
+	admin.expect(t, http.StatusOK, "PUT", fmt.Sprintf("/admin/templates/%d", template.ID), dto.UpdateTemplateRequest{Name: &renamed}, nil)
+	admin.expect(t, http.StatusOK, "DELETE", fmt.Sprintf("/admin/templates/%d", template.ID), nil, nil)
+
+	// Surveys
+	var survey dto.SurveyResponse
+	creator.expect(t, http.StatusCreated, "POST", "/surveys", dto.CreateSurveyRequest{
+		Title:           "DeFi habits",
+		Description:     "How people use DeFi",
+		Category:        "finance",
+		EstimatedTime:   "3-5 min",
+		RewardAmount:    1,
+		MaxParticipants: 10,
+		XpReward:        10,
+		IsPublic:        true,
+		RequireLogin:    true,
+		Questions: []dto.CreateQuestionRequest{
+			{
+				Type:     "single_choice",
+				Title:    "How often do you use DeFi?",
+				Required: true,
+				Order:    1,
+				Options: []dto.QuestionOptionRequest{
+					{Label: "Daily", Value: "daily", Order: 1},
+					{Label: "Weekly", Value: "weekly", Order: 2},
+				},
+			},
+			{Type: "yes_no", Title: "Do you hold stablecoins?", Required: true, Order: 2},
+			{Type: "text", Title: "Why?", Order: 3},
+		},
+	}, &survey)
+	if len(survey.Questions) != 3 {
+		t.Fatalf("created survey has %d questions, want 3", len(survey.Questions))
+	}
+	surveyPath := fmt.Sprintf("/surveys/%d", survey.ID)
+
+	var draft dto.SurveyResponse
+	creator.expect(t, http.StatusCreated, "POST", surveyPath+"/clone", dto.CloneSurveyRequest{}, &draft)
+	draftTitle := "DeFi habits, second wave"
+	creator.expect(t, http.StatusOK, "PUT", fmt.Sprintf("/surveys/%d", draft.ID), dto.UpdateSurveyRequest{Title: &draftTitle}, nil)
+	respondent.expectProblem(t, http.StatusForbidden, apperror.Forbidden, "PUT", fmt.Sprintf("/surveys/%d", draft.ID), dto.UpdateSurveyRequest{Title: &draftTitle})
+	creator.expect(t, http.StatusOK, "DELETE", fmt.Sprintf("/surveys/%d", draft.ID), nil, nil)
+	creator.expect(t, http.StatusOK, "DELETE", fmt.Sprintf("/surveys/%d", fromTemplate.ID), nil, nil)
+
+	definition := creator.expect(t, http.StatusOK, "GET", surveyPath+"/export", nil, nil)
+	var imported dto.SurveyResponse
+	creator.expect(t, http.StatusCreated, "POST", "/surveys/import", json.RawMessage(definition.body), &imported)
+
+	creator.expect(t, http.StatusOK, "POST", surveyPath+"/publish", dto.PublishSurveyRequest{}, nil)
+	creator.expectProblem(t, http.StatusConflict, apperror.Conflict, "DELETE", surveyPath, nil)
+	anonymous.expect(t, http.StatusOK, "GET", "/surveys", nil, nil)
+	anonymous.expect(t, http.StatusOK, "GET", surveyPath, nil, nil)
+	anonymous.expectProblem(t, http.StatusNotFound, apperror.NotFound, "GET", "/surveys/999999", nil)
+	creator.expect(t, http.StatusOK, "GET", "/surveys/my", nil, nil)
+	respondent.expect(t, http.StatusOK, "GET", "/surveys/recommended", nil, nil)
+
+	// Webhooks, delivered to a local receiver
+	received := make(chan string, 16)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case received <- r.Header.Get(webhook.EventHeader):
+		default:
+		}
+	}))
+	defer receiver.Close()
+
+	var hook dto.WebhookResponse
+	creator.expect(t, http.StatusCreated, "POST", "/webhooks", dto.CreateWebhookRequest{
+		URL:      receiver.URL + "/hooks",
+		Events:   []string{"response.completed"},
+		SurveyID: &survey.ID,
+	}, &hook)
+	hookPath := fmt.Sprintf("/webhooks/%d", hook.ID)
+	creator.expect(t, http.StatusOK, "GET", "/webhooks", nil, nil)
+	creator.expect(t, http.StatusOK, "GET", hookPath, nil, nil)
+	respondent.expectProblem(t, http.StatusForbidden, apperror.Forbidden, "GET", hookPath, nil)
+	description := "End-to-end receiver"
+	creator.expect(t, http.StatusOK, "PUT", hookPath, dto.UpdateWebhookRequest{Description: &description}, nil)
+	creator.expect(t, http.StatusOK, "POST", hookPath+"/rotate-secret", nil, nil)
+	var testDelivery dto.WebhookDeliveryResponse
+	creator.expect(t, http.StatusOK, "POST", hookPath+"/test", nil, &testDelivery)
+	waitForWebhook(t, received, "webhook.test")
+	creator.expect(t, http.StatusOK, "GET", hookPath+"/deliveries", nil, nil)
+	creator.expect(t, http.StatusAccepted, "POST", fmt.Sprintf("%s/deliveries/%d/redeliver", hookPath, testDelivery.ID), nil, nil)
+	waitForWebhook(t, received, "webhook.test")
+
+	// The creator watches responses come in
+	streamCtx, stopStream := context.WithCancel(context.Background())
+	defer stopStream()
+	streamEvents := openEventStream(t, streamCtx, creator, surveyPath+"/events")
+
+	// A respondent takes the survey
+	startKey := fmt.Sprintf("start-%d", survey.ID)
+	var started dto.ResponseStartResponse
+	respondent.expect(t, http.StatusCreated, "POST", "/responses/start", dto.StartSurveyRequest{SurveyID: survey.ID}, &started, "Idempotency-Key", startKey)
+	var replayed dto.ResponseStartResponse
+	respondent.expect(t, http.StatusCreated, "POST", "/responses/start", dto.StartSurveyRequest{SurveyID: survey.ID}, &replayed, "Idempotency-Key", startKey)
+	if replayed.ResponseID != started.ResponseID {
+		t.Fatalf("retried start created response %d, want the first response %d", replayed.ResponseID, started.ResponseID)
+	}
+	responsePath := fmt.Sprintf("/responses/%d", started.ResponseID)
+
+	choice := dto.SubmitAnswerRequest{QuestionID: survey.Questions[0].ID, TimeSpent: 5, Answer: dto.AnswerValue{Type: "single_choice", Options: []string{"weekly"}}}
+	yesNo := dto.SubmitAnswerRequest{QuestionID: survey.Questions[1].ID, TimeSpent: 5, Answer: dto.AnswerValue{Type: "boolean", Content: true}}
+	text := dto.SubmitAnswerRequest{QuestionID: survey.Questions[2].ID, TimeSpent: 5, Answer: dto.AnswerValue{Type: "text", Content: "Lower fees than my bank"}}
+	respondent.expect(t, http.StatusOK, "POST", responsePath+"/answers", []dto.SubmitAnswerRequest{choice, yesNo}, nil)
+	creator.expectProblem(t, http.StatusForbidden, apperror.Forbidden, "POST", responsePath+"/answers", []dto.SubmitAnswerRequest{choice})
+	choice.Answer.Options = []string{"daily"}
+	respondent.expect(t, http.StatusOK, "PUT", fmt.Sprintf("%s/questions/%d", responsePath, choice.QuestionID), dto.UpdateAnswerRequest{Answer: choice.Answer, TimeSpent: 3}, nil)
+	respondent.expect(t, http.StatusOK, "GET", responsePath+"/progress", nil, nil)
+	respondent.expect(t, http.StatusOK, "GET", responsePath+"/resume", nil, nil)
+	var completion dto.CompletionResponse
+	respondent.expect(t, http.StatusOK, "POST", "/responses/complete", dto.CompleteSurveyRequest{
+		ResponseID: started.ResponseID,
+		Answers:    []dto.SubmitAnswerRequest{choice, yesNo, text},
+	}, &completion, "Idempotency-Key", fmt.Sprintf("complete-%d", started.ResponseID))
+	respondent.expectProblem(t, http.StatusConflict, apperror.ResponseNotActive, "POST", responsePath+"/answers", []dto.SubmitAnswerRequest{text})
+	respondent.expectProblem(t, http.StatusConflict, apperror.AlreadyResponded, "POST", "/responses/start", dto.StartSurveyRequest{SurveyID: survey.ID})
+	respondent.expect(t, http.StatusOK, "GET", "/responses?page=1&limit=10", nil, nil)
+	respondent.expect(t, http.StatusOK, "GET", responsePath, nil, nil)
+
+	waitForEvent(t, streamEvents, "response.completed")
+	stopStream()
+	api.waitForExchange(t, "GET", "/api/v1/surveys/:id/events")
+	waitForWebhook(t, received, "response.completed")
+
+	// Another respondent gives up halfway
+	var abandoned dto.ResponseStartResponse
+	admin.expect(t, http.StatusCreated, "POST", "/responses/start", dto.StartSurveyRequest{SurveyID: survey.ID}, &abandoned)
+	admin.expect(t, http.StatusOK, "POST", fmt.Sprintf("/responses/%d/abandon", abandoned.ResponseID), nil, nil)
+
+	// The creator looks at the results
+	creator.expect(t, http.StatusOK, "GET", surveyPath+"/analytics", nil, nil)
+	creator.expect(t, http.StatusOK, "GET", surveyPath+"/analytics/crosstab?row=1&column=2", nil, nil)
+	creator.expectProblem(t, http.StatusBadRequest, apperror.InvalidFilter, "GET", surveyPath+"/analytics/crosstab?row=1&column=3", nil)
+	respondent.expectProblem(t, http.StatusForbidden, apperror.Forbidden, "GET", surveyPath+"/analytics", nil)
+	creator.expect(t, http.StatusOK, "GET", surveyPath+"/responses?answer="+url.QueryEscape("1:daily"), nil, nil)
+	creator.expect(t, http.StatusOK, "GET", fmt.Sprintf("%s/responses/%d", surveyPath, started.ResponseID), nil, nil)
+
+	// and exports them, right away and in the background
+	export := creator.expect(t, http.StatusOK, "GET", surveyPath+"/responses/export?format=csv", nil, nil)
+	if !strings.Contains(string(export.body), "Lower fees than my bank") {
+		t.Errorf("the CSV export is missing the answers:\n%s", export.body)
+	}
+	var job dto.ExportJobResponse
+	creator.expect(t, http.StatusAccepted, "GET", surveyPath+"/responses/export?format=csv&async=true", nil, &job)
+	jobPath := fmt.Sprintf("/exports/%d", job.ID)
+	deadline := time.Now().Add(10 * time.Second)
+	for job.Status != "completed" {
+		if job.Status == "failed" || time.Now().After(deadline) {
+			t.Fatalf("export job is %s, want completed", job.Status)
+		}
+		time.Sleep(50 * time.Millisecond)
+		creator.expect(t, http.StatusOK, "GET", jobPath, nil, &job)
+	}
+	respondent.expectProblem(t, http.StatusForbidden, apperror.Forbidden, "GET", jobPath+"/download", nil)
+	download := creator.expect(t, http.StatusOK, "GET", jobPath+"/download", nil, nil)
+	if !bytes.Equal(download.body, export.body) {
+		t.Errorf("the background export differs from the direct one\n got: %s\nwant: %s", download.body, export.body)
+	}
+
+	creator.expect(t, http.StatusOK, "DELETE", hookPath, nil, nil)
+
+	// Rewards: the completed response paid its reward, withdrawals are reserved
+	var balance dto.RewardBalanceResponse
+	respondent.expect(t, http.StatusOK, "GET", "/rewards/balance", nil, &balance)
+	if balance.TotalEarned != completion.RewardEarned || balance.AvailableBalance != completion.RewardEarned {
+		t.Errorf("balance = %+v, want %v earned and available", balance, completion.RewardEarned)
+	}
+	var transactions dto.RewardTransactionListResponse
+	respondent.expect(t, http.StatusOK, "GET", "/rewards/transactions?type=reward", nil, &transactions)
+	if transactions.Total != 1 || len(transactions.Transactions) != 1 {
+		t.Fatalf("reward transactions = %+v, want the one reward", transactions)
+	}
+	if reward := transactions.Transactions[0]; reward.SurveyID != survey.ID || reward.SurveyTitle != survey.Title ||
+		reward.ResponseID == nil || *reward.ResponseID != started.ResponseID || reward.Amount != completion.RewardEarned {
+		t.Errorf("reward transaction = %+v, want %v for response %d of survey %d", reward, completion.RewardEarned, started.ResponseID, survey.ID)
+	}
+	creator.expect(t, http.StatusOK, "GET", "/rewards/transactions?type=withdrawal", nil, &transactions)
+	if transactions.Total != 0 {
+		t.Errorf("the creator has %d withdrawals, want none", transactions.Total)
+	}
+	respondent.expectProblem(t, http.StatusBadRequest, apperror.Validation, "GET", "/rewards/transactions?status=lost", nil)
+	respondent.expectProblem(t, http.StatusNotImplemented, apperror.NotImplemented, "POST", "/rewards/withdraw", nil)
+
+	// Platform management
+	respondent.expectProblem(t, http.StatusForbidden, apperror.Forbidden, "GET", "/admin/users", nil)
+	var allSurveys dto.SurveyListResponse
+	admin.expect(t, http.StatusOK, "GET", "/admin/surveys?status=published", nil, &allSurveys)
+	if allSurveys.Total != 1 || len(allSurveys.Surveys) != 1 || allSurveys.Surveys[0].ID != survey.ID ||
+		allSurveys.Surveys[0].Creator.WalletAddress != survey.Creator.WalletAddress {
+		t.Errorf("published surveys = %+v, want survey %d by its creator", allSurveys, survey.ID)
+	}
+	var users dto.AdminUserListResponse
+	admin.expect(t, http.StatusOK, "GET", "/admin/users?limit=2", nil, &users)
+	if users.Total != 3 || len(users.Users) != 2 || users.TotalPages != 2 {
+		t.Errorf("users = %+v, want 3 users over 2 pages", users)
+	}
+	var analytics dto.PlatformAnalyticsResponse
+	admin.expect(t, http.StatusOK, "GET", "/admin/analytics", nil, &analytics)
+	if analytics.TotalUsers != 3 || analytics.SurveysByStatus["published"] != 1 ||
+		analytics.ResponsesByStatus["completed"] != 1 || analytics.RewardsPaid != survey.RewardPerResponse {
+		t.Errorf("analytics = %+v, want 3 users, 1 published survey, 1 completed response and %v paid", analytics, survey.RewardPerResponse)
+	}
+
+	// Documentation
+	anonymous.expect(t, http.StatusOK, "GET", "/docs", nil, nil)
+	anonymous.expect(t, http.StatusOK, "GET", "/docs/openapi.json", nil, nil)
+
+	return api
+}
+
+// openEventStream opens a survey's event stream, returning the types of the
+// events it sends until ctx is cancelled
+func openEventStream(t *testing.T, ctx context.Context, client apiClient, path string) <-chan string {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, "GET", client.api.url+path, nil)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+client.token)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	if res.StatusCode != http.StatusOK || !strings.HasPrefix(res.Header.Get("Content-Type"), "text/event-stream") {
+		res.Body.Close()
+		t.Fatalf("GET %s = %d %s, want an event stream", path, res.StatusCode, res.Header.Get("Content-Type"))
+	}
+
+	types := make(chan string, 16)
+	go func() {
+		defer res.Body.Close()
+		defer close(types)
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			if eventType, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+				types <- eventType
+			}
+		}
+	}()
+	return types
+}
+
+func waitForEvent(t *testing.T, types <-chan string, want string) {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case eventType, ok := <-types:
+			if !ok {
+				t.Fatalf("the event stream ended before a %s event", want)
+			}
+			if eventType == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no %s event was streamed", want)
+		}
+	}
+}
+
+func waitForWebhook(t *testing.T, received <-chan string, want string) {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case eventType := <-received:
+			if eventType == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no %s webhook was delivered", want)
+		}
+	}
+}
+
+func TestAPIEndToEnd(t *testing.T) {
+	api := runScenario(t)
+
+	// Every route was exercised
+	answered := make(map[string]bool)
+	for _, ex := range api.recorded() {
+		answered[ex.Method+" "+ex.Route] = true
+	}
+	for _, route := range api.routes {
+		if !answered[route.Method+" "+route.Path] {
+			t.Errorf("%s %s was not exercised", route.Method, route.Path)
+		}
+	}
+}
+
+func TestTestWalletSignatures(t *testing.T) {
+	w := newTestWallet(t)
+	if err := wallet.VerifySignature(w.address, "Sign in to Survey2Earn", w.sign("Sign in to Survey2Earn")); err != nil {
+		t.Errorf("VerifySignature of a test wallet signature = %v, want nil", err)
+	}
+}
@@ -0,0 +1,313 @@
+// internal/routes/routes.go
+package routes
+
+import (
+	"context"
+	"survey2earn-backend/internal/apidocs"
+	"survey2earn-backend/internal/cache"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/database"
+	"survey2earn-backend/internal/events"
+	"survey2earn-backend/internal/handler"
+	"survey2earn-backend/internal/idempotency"
+	"survey2earn-backend/internal/middleware"
+	"survey2earn-backend/internal/outbox"
+	"survey2earn-backend/internal/ratelimit"
+	"survey2earn-backend/internal/repository"
+	"survey2earn-backend/internal/service"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Components holds the background workers and connections SetupRoutes
+// starts, so the server can stop them when it shuts down
+type Components struct {
+	cancel      context.CancelFunc
+	workers     sync.WaitGroup
+	eventBus    *events.Bus
+	readCache   cache.Cache
+	rateLimiter ratelimit.Limiter
+}
+
+// CloseStreams ends the live event streams. Streaming requests only return
+// when their stream ends, so this must run when the server starts shutting
+// down rather than after in-flight requests finish.
+func (c *Components) CloseStreams() {
+	c.eventBus.Close()
+}
+
+// Stop stops the outbox dispatcher and the webhook and export workers,
+// waiting for the events and deliveries in progress and requeueing exports
+// in progress, and closes the cache and rate limiter
+func (c *Components) Stop() {
+	c.cancel()
+	c.workers.Wait()
+	c.eventBus.Close()
+
+	if err := c.readCache.Close(); err != nil {
+		logrus.WithError(err).Warn("Failed to close the read cache")
+	}
+	if err := c.rateLimiter.Close(); err != nil {
+		logrus.WithError(err).Warn("Failed to close the rate limiter")
+	}
+}
+
+// SetupRoutes builds the repositories, services and handlers from cfg,
+// registers all API routes and starts the background workers
+func SetupRoutes(router *gin.Engine, cfg *config.Config, db *database.Database) *Components {
+	// Read cache, shared through Redis
+	readCache := cache.New(cfg.Redis, cfg.Cache)
+
+	// Initialize repositories
+	userRepo := repository.NewUserRepository(db.DB)
+	sessionRepo := repository.NewAuthSessionRepository(db.DB)
+	surveyRepo := repository.NewCachedSurveyRepository(repository.NewSurveyRepository(db.DB), readCache, cfg.Cache)
+	responseRepo := repository.NewResponseRepository(db.DB)
+	rewardRepo := repository.NewRewardRepository(db.DB)
+	quotaRepo := repository.NewQuotaRepository(db.DB)
+	templateRepo := repository.NewTemplateRepository(db.DB)
+	categoryRepo := repository.NewCachedCategoryRepository(repository.NewCategoryRepository(db.DB), readCache, cfg.Cache)
+	exportJobRepo := repository.NewExportJobRepository(db.DB)
+	textAnalysisRepo := repository.NewTextAnalysisRepository(db.DB)
+	webhookRepo := repository.NewWebhookRepository(db.DB)
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	idempotencyRepo := repository.NewIdempotencyRepository(db.DB)
+	platformRepo := repository.NewPlatformRepository(db.DB)
+
+	// Live survey events, relayed from the outbox
+	eventBus := events.NewBus(events.DefaultBufferSize)
+
+	// Initialize services
+	authService := service.NewAuthService(userRepo, sessionRepo, cfg.JWT, cfg.Admin)
+	webhookService := service.NewWebhookService(webhookRepo, surveyRepo, cfg.Webhook)
+	surveyService := service.NewSurveyService(surveyRepo, userRepo, rewardRepo, quotaRepo, responseRepo, templateRepo, textAnalysisRepo, categoryRepo, readCache, cfg.Cache)
+	templateService := service.NewTemplateService(templateRepo, categoryRepo, surveyService)
+	categoryService := service.NewCategoryService(categoryRepo, surveyRepo)
+	responseService := service.NewResponseService(responseRepo, surveyRepo, rewardRepo, userRepo, textAnalysisRepo, eventBus, cfg.Response)
+	exportService := service.NewExportService(exportJobRepo, surveyRepo, responseRepo, cfg.Export)
+	rewardService := service.NewRewardService(rewardRepo)
+	adminService := service.NewAdminService(userRepo, platformRepo, surveyService)
+
+	// Relay domain events from the outbox to live subscribers, the read
+	// cache, webhooks and the configured brokers, deliver queued webhooks,
+	// run export jobs, close idle responses and delete expired idempotency
+	// keys in the background
+	dispatcher := outbox.NewDispatcher(outboxRepo, cfg.Outbox)
+	dispatcher.Subscribe(service.LiveEventRelay(eventBus))
+	dispatcher.Subscribe(service.CacheInvalidator(readCache))
+	dispatcher.Consume("webhooks", webhookService.HandleOutboxEvents)
+	sinks, err := outbox.NewSinks(cfg.Outbox)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to set up outbox sinks")
+	}
+	for _, sink := range sinks {
+		dispatcher.AddSink(sink)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	components := &Components{cancel: cancel, eventBus: eventBus, readCache: readCache}
+	idempotencyStore := idempotency.NewStore(idempotencyRepo, cfg.Idempotency)
+	components.workers.Add(5)
+	go func() {
+		defer components.workers.Done()
+		dispatcher.Run(ctx)
+	}()
+	go func() {
+		defer components.workers.Done()
+		webhookService.Run(ctx)
+	}()
+	go func() {
+		defer components.workers.Done()
+		exportService.Run(ctx)
+	}()
+	go func() {
+		defer components.workers.Done()
+		responseService.Run(ctx)
+	}()
+	go func() {
+		defer components.workers.Done()
+		idempotencyStore.Run(ctx)
+	}()
+
+	// Initialize handlers
+	authHandler := handler.NewAuthHandler(authService)
+	surveyHandler := handler.NewSurveyHandler(surveyService)
+	responseHandler := handler.NewResponseHandler(responseService)
+	templateHandler := handler.NewTemplateHandler(templateService)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	exportHandler := handler.NewExportHandler(exportService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	rewardHandler := handler.NewRewardHandler(rewardService)
+	adminHandler := handler.NewAdminHandler(adminService)
+
+	// Rate limits, stricter on routes open to abuse
+	apiPath := "/api/" + cfg.Server.APIVersion
+	rateLimiter := ratelimit.New(cfg.Redis, cfg.RateLimit)
+	components.rateLimiter = rateLimiter
+	rateLimit := middleware.RateLimit(rateLimiter,
+		ratelimit.Rule{Name: "default", RequestsPerMinute: cfg.RateLimit.RequestsPerMinute, Burst: cfg.RateLimit.Burst},
+		map[string]ratelimit.Rule{
+			"POST " + apiPath + "/auth/login":      ratelimit.NewRule("login", cfg.RateLimit.Login),
+			"POST " + apiPath + "/responses/start": ratelimit.NewRule("start_response", cfg.RateLimit.StartResponse),
+		},
+	)
+
+	// Retries of writes sent with an Idempotency-Key get the original response
+	idempotent := middleware.Idempotency(idempotencyStore)
+
+	// API version group
+	api := router.Group(apiPath)
+	{
+		// Public routes (no authentication required), limited per client IP
+		public := api.Group("/")
+		public.Use(rateLimit)
+		{
+			// Authentication routes
+			auth := public.Group("auth")
+			{
+				auth.GET("/nonce", authHandler.GetNonce)
+				auth.POST("/login", authHandler.Login)
+				auth.POST("/register", authHandler.Register)
+				auth.POST("/refresh", authHandler.RefreshToken)
+				auth.POST("/logout", middleware.AuthMiddleware(authService), authHandler.Logout)
+			}
+
+			// Public survey routes
+			public.GET("/surveys", surveyHandler.GetPublicSurveys)
+			public.GET("/surveys/:id", surveyHandler.GetSurvey)
+
+			// Survey category routes
+			public.GET("/categories", categoryHandler.ListCategories)
+			public.GET("/categories/:slug", categoryHandler.GetCategory)
+
+			// API documentation
+			public.GET("/docs", apidocs.UI)
+			public.GET("/docs/openapi.json", apidocs.Spec(apiPath))
+		}
+
+		// Protected routes (authentication required), limited per user
+		protected := api.Group("/")
+		protected.Use(middleware.AuthMiddleware(authService), rateLimit)
+		{
+			// User routes
+			user := protected.Group("user")
+			{
+				user.GET("/profile", authHandler.GetProfile)
+				user.PUT("/profile", authHandler.UpdateProfile)
+				user.GET("/stats", authHandler.GetUserStats)
+			}
+
+			// Survey management routes
+			surveys := protected.Group("surveys")
+			{
+				surveys.POST("/", surveyHandler.CreateSurvey)
+				surveys.GET("/my", surveyHandler.GetUserSurveys)
+				surveys.GET("/recommended", surveyHandler.GetRecommendedSurveys)
+				surveys.PUT("/:id", surveyHandler.UpdateSurvey)
+				surveys.DELETE("/:id", surveyHandler.DeleteSurvey)
+				surveys.POST("/:id/publish", surveyHandler.PublishSurvey)
+				surveys.GET("/:id/analytics", surveyHandler.GetSurveyAnalytics)
+				surveys.GET("/:id/analytics/crosstab", surveyHandler.GetCrosstab)
+				surveys.POST("/:id/clone", surveyHandler.CloneSurvey)
+				surveys.GET("/:id/export", surveyHandler.ExportSurvey)
+				surveys.GET("/:id/responses", responseHandler.GetSurveyResponses)
+				surveys.GET("/:id/responses/export", exportHandler.ExportResponses)
+				surveys.GET("/:id/responses/:response_id", responseHandler.GetSurveyResponse)
+				surveys.GET("/:id/events", responseHandler.StreamSurveyEvents)
+				surveys.POST("/import", surveyHandler.ImportSurvey)
+				surveys.POST("/from-template/:id", templateHandler.CreateSurveyFromTemplate)
+			}
+
+			// Survey template routes
+			templates := protected.Group("templates")
+			{
+				templates.GET("/", templateHandler.ListTemplates)
+				templates.POST("/", templateHandler.CreateTemplate)
+				templates.GET("/:id", templateHandler.GetTemplate)
+				templates.PUT("/:id", templateHandler.UpdateTemplate)
+				templates.DELETE("/:id", templateHandler.DeleteTemplate)
+			}
+
+			// Question bank routes
+			questionBank := protected.Group("question-bank")
+			{
+				questionBank.GET("/", templateHandler.ListBankQuestions)
+				questionBank.POST("/", templateHandler.CreateBankQuestion)
+				questionBank.DELETE("/:id", templateHandler.DeleteBankQuestion)
+			}
+
+			// Survey response routes
+			responses := protected.Group("responses")
+			{
+				responses.POST("/start", idempotent, responseHandler.StartSurvey)
+				responses.GET("/", responseHandler.GetUserResponses)
+				responses.GET("/:id", responseHandler.GetResponse)
+				responses.GET("/:id/progress", responseHandler.GetResponseProgress)
+				responses.GET("/:id/resume", responseHandler.ResumeResponse)
+				responses.POST("/:id/answers", responseHandler.SubmitAnswers)
+				responses.PUT("/:response_id/questions/:question_id", responseHandler.UpdateAnswer)
+				responses.POST("/:id/abandon", responseHandler.AbandonSurvey)
+				responses.POST("/complete", idempotent, responseHandler.CompleteSurvey)
+			}
+
+			// Response export job routes
+			exports := protected.Group("exports")
+			{
+				exports.GET("/:id", exportHandler.GetExportJob)
+				exports.GET("/:id/download", exportHandler.DownloadExport)
+			}
+
+			// Webhook routes
+			webhooks := protected.Group("webhooks")
+			{
+				webhooks.POST("/", webhookHandler.CreateWebhook)
+				webhooks.GET("/", webhookHandler.GetWebhooks)
+				webhooks.GET("/:id", webhookHandler.GetWebhook)
+				webhooks.PUT("/:id", webhookHandler.UpdateWebhook)
+				webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+				webhooks.POST("/:id/rotate-secret", webhookHandler.RotateWebhookSecret)
+				webhooks.POST("/:id/test", webhookHandler.TestWebhook)
+				webhooks.GET("/:id/deliveries", webhookHandler.GetDeliveries)
+				webhooks.POST("/:id/deliveries/:delivery_id/redeliver", webhookHandler.RedeliverDelivery)
+			}
+
+			// Reward routes; withdrawals are answered with 501 until they
+			// are available
+			rewards := protected.Group("rewards")
+			{
+				rewards.GET("/balance", rewardHandler.GetBalance)
+				rewards.GET("/transactions", rewardHandler.GetTransactions)
+				rewards.POST("/withdraw", rewardHandler.Withdraw)
+			}
+		}
+
+		// Admin routes
+		admin := api.Group("admin")
+		admin.Use(middleware.AuthMiddleware(authService))
+		admin.Use(middleware.AdminMiddleware(authService))
+		admin.Use(rateLimit)
+		{
+			// Platform management
+			admin.GET("/surveys", adminHandler.ListSurveys)
+			admin.GET("/users", adminHandler.ListUsers)
+			admin.GET("/analytics", adminHandler.GetAnalytics)
+
+			// Platform template and question bank curation
+			admin.POST("/templates", templateHandler.CreatePlatformTemplate)
+			admin.PUT("/templates/:id", templateHandler.UpdatePlatformTemplate)
+			admin.DELETE("/templates/:id", templateHandler.DeletePlatformTemplate)
+			admin.POST("/question-bank", templateHandler.CreatePlatformBankQuestion)
+			admin.DELETE("/question-bank/:id", templateHandler.DeletePlatformBankQuestion)
+
+			// Survey category management
+			admin.GET("/categories", categoryHandler.ListAllCategories)
+			admin.POST("/categories", categoryHandler.CreateCategory)
+			admin.PUT("/categories/:id", categoryHandler.UpdateCategory)
+			admin.DELETE("/categories/:id", categoryHandler.DeleteCategory)
+		}
+	}
+
+	return components
+}
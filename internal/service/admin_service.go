@@ -0,0 +1,102 @@
+// internal/service/admin_service.go
+package service
+
+import (
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+)
+
+// AdminService backs the platform management views of admins
+type AdminService interface {
+	ListSurveys(req *dto.AdminListSurveysRequest) (*dto.SurveyListResponse, error)
+	ListUsers(req *dto.AdminListUsersRequest) (*dto.AdminUserListResponse, error)
+	GetAnalytics() (*dto.PlatformAnalyticsResponse, error)
+}
+
+type adminService struct {
+	userRepo      repository.UserRepository
+	platformRepo  repository.PlatformRepository
+	surveyService SurveyService
+}
+
+func NewAdminService(
+	userRepo repository.UserRepository,
+	platformRepo repository.PlatformRepository,
+	surveyService SurveyService,
+) AdminService {
+	return &adminService{
+		userRepo:      userRepo,
+		platformRepo:  platformRepo,
+		surveyService: surveyService,
+	}
+}
+
+func (s *adminService) ListSurveys(req *dto.AdminListSurveysRequest) (*dto.SurveyListResponse, error) {
+	return s.surveyService.GetAllSurveys(req.Status, req.Page, req.Limit)
+}
+
+func (s *adminService) ListUsers(req *dto.AdminListUsersRequest) (*dto.AdminUserListResponse, error) {
+	users, total, err := s.userRepo.List(req.Page, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.AdminUserResponse, len(users))
+	for i := range users {
+		responses[i] = userToAdminDTO(&users[i])
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &dto.AdminUserListResponse{
+		Users:      responses,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *adminService) GetAnalytics() (*dto.PlatformAnalyticsResponse, error) {
+	stats, err := s.platformRepo.GetStats()
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &dto.PlatformAnalyticsResponse{
+		TotalUsers:        stats.TotalUsers,
+		ActiveUsers:       stats.ActiveUsers,
+		SurveysByStatus:   stats.SurveysByStatus,
+		ResponsesByStatus: stats.ResponsesByStatus,
+		RewardsFunded:     stats.RewardsFunded,
+		RewardsPaid:       stats.RewardsPaid,
+	}
+	for _, count := range stats.SurveysByStatus {
+		analytics.TotalSurveys += count
+	}
+	for _, count := range stats.ResponsesByStatus {
+		analytics.TotalResponses += count
+	}
+	return analytics, nil
+}
+
+func userToAdminDTO(user *models.User) dto.AdminUserResponse {
+	return dto.AdminUserResponse{
+		ID:              user.ID,
+		WalletAddress:   user.WalletAddress,
+		Username:        user.Username,
+		Email:           user.Email,
+		IsActive:        user.IsActive,
+		IsAdmin:         user.IsAdmin,
+		ReputationScore: user.ReputationScore,
+		TotalEarned:     user.TotalEarned,
+		TotalResponses:  user.TotalResponses,
+		TotalSurveys:    user.TotalSurveys,
+		LastLoginAt:     user.LastLoginAt,
+		CreatedAt:       user.CreatedAt,
+	}
+}
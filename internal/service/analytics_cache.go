@@ -0,0 +1,50 @@
+// internal/service/analytics_cache.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"survey2earn-backend/internal/cache"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+)
+
+// cachedAnalytics serves an analytics result of a survey from the cache,
+// computing it with load on a miss. name identifies the result among the
+// survey's analytics. All of them are dropped together whenever a response
+// of the survey changes.
+func (s *surveyService) cachedAnalytics(surveyID uint, name string, dest interface{}, load func() (interface{}, error)) error {
+	ctx := context.Background()
+	generation := cache.Generation(ctx, s.cache, analyticsGenerationKey(surveyID))
+	key := fmt.Sprintf("analytics:%d:%d:%s", surveyID, generation, name)
+	return cache.Fetch(ctx, s.cache, key, s.analyticsTTL, dest, load)
+}
+
+// cachesAnalytics reports whether analytics results are cached
+func (s *surveyService) cachesAnalytics() bool {
+	return s.cache != nil && s.analyticsTTL > 0
+}
+
+func analyticsGenerationKey(surveyID uint) string {
+	return fmt.Sprintf("analytics:%d:generation", surveyID)
+}
+
+// CacheInvalidator returns an outbox subscriber that drops the cached
+// surveys, survey lists and analytics a committed event makes stale. It runs
+// on every instance, which keeps in-process caches in step too.
+func CacheInvalidator(c cache.Cache) func(event models.OutboxEvent) {
+	return func(event models.OutboxEvent) {
+		ctx := context.Background()
+		switch event.Type {
+		case models.OutboxEventSurveyPublished, models.OutboxEventSurveyUpdated, models.OutboxEventSurveyDeleted:
+			repository.InvalidateSurveyCache(c, event.SurveyID)
+			cache.InvalidateGeneration(ctx, c, analyticsGenerationKey(event.SurveyID))
+		case models.OutboxEventResponseStarted, models.OutboxEventResponseAbandoned,
+			models.OutboxEventResponseScreenedOut, models.OutboxEventResponseCompleted:
+			cache.InvalidateGeneration(ctx, c, analyticsGenerationKey(event.SurveyID))
+		case models.OutboxEventRewardPaid:
+			// A paid reward counts against the survey's quotas
+			repository.DropCachedSurvey(c, event.SurveyID)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// internal/service/answer_aggregation.go
+package service
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"survey2earn-backend/internal/models"
+)
+
+// aggregateAnswers builds the answer distribution of a question for analytics.
+// Skipped answers are expected to be filtered out by the caller.
+func aggregateAnswers(question *models.Question, answers []models.Answer) map[string]interface{} {
+	switch question.Type {
+	case models.QuestionTypeSingleChoice, models.QuestionTypeMultipleChoice, models.QuestionTypeYesNo:
+		return aggregateChoices(answers)
+	case models.QuestionTypeRating, models.QuestionTypeScale, models.QuestionTypeNumber:
+		return aggregateNumbers(answers)
+	case models.QuestionTypeNPS:
+		return aggregateNPS(answers)
+	case models.QuestionTypeMatrix:
+		return aggregateMatrix(question, answers)
+	case models.QuestionTypeRanking:
+		return aggregateRanking(answers)
+	case models.QuestionTypeConstantSum:
+		return aggregateConstantSum(answers)
+	case models.QuestionTypeFileUpload:
+		return aggregateFiles(answers)
+	default:
+		return map[string]interface{}{
+			"answered": len(answers),
+		}
+	}
+}
+
+func aggregateChoices(answers []models.Answer) map[string]interface{} {
+	counts := make(map[string]int)
+	for _, answer := range answers {
+		if len(answer.AnswerValue.Options) > 0 {
+			for _, option := range answer.AnswerValue.Options {
+				counts[option]++
+			}
+			continue
+		}
+		if answer.AnswerText != "" {
+			counts[answer.AnswerText]++
+		}
+	}
+	return map[string]interface{}{
+		"counts": counts,
+	}
+}
+
+func aggregateNumbers(answers []models.Answer) map[string]interface{} {
+	values := make([]float64, 0, len(answers))
+	for _, answer := range answers {
+		if value, ok := numericAnswer(answer.AnswerValue); ok {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return map[string]interface{}{"count": 0}
+	}
+
+	counts := make(map[string]int)
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+		counts[formatNumber(value)]++
+	}
+	sort.Float64s(values)
+
+	return map[string]interface{}{
+		"count":   len(values),
+		"average": sum / float64(len(values)),
+		"min":     values[0],
+		"max":     values[len(values)-1],
+		"median":  median(values),
+		"counts":  counts,
+	}
+}
+
+func aggregateNPS(answers []models.Answer) map[string]interface{} {
+	counts := make(map[string]int)
+	promoters, passives, detractors := 0, 0, 0
+	for _, answer := range answers {
+		if answer.AnswerValue.Rating == nil {
+			continue
+		}
+		score := *answer.AnswerValue.Rating
+		counts[formatNumber(float64(score))]++
+		switch {
+		case score >= 9:
+			promoters++
+		case score >= 7:
+			passives++
+		default:
+			detractors++
+		}
+	}
+
+	total := promoters + passives + detractors
+	nps := 0.0
+	if total > 0 {
+		nps = float64(promoters-detractors) / float64(total) * 100
+	}
+
+	return map[string]interface{}{
+		"count":      total,
+		"promoters":  promoters,
+		"passives":   passives,
+		"detractors": detractors,
+		"nps":        nps,
+		"counts":     counts,
+	}
+}
+
+func aggregateMatrix(question *models.Question, answers []models.Answer) map[string]interface{} {
+	rows := make(map[string]map[string]int, len(question.Rows))
+	for _, row := range question.Rows {
+		rows[row.ID] = make(map[string]int)
+	}
+	for _, answer := range answers {
+		for rowID, column := range answer.AnswerValue.Matrix {
+			if _, ok := rows[rowID]; !ok {
+				rows[rowID] = make(map[string]int)
+			}
+			rows[rowID][column]++
+		}
+	}
+	return map[string]interface{}{
+		"rows": rows,
+	}
+}
+
+func aggregateRanking(answers []models.Answer) map[string]interface{} {
+	rankSums := make(map[string]int)
+	rankCounts := make(map[string]int)
+	firstPlace := make(map[string]int)
+	for _, answer := range answers {
+		for i, option := range answer.AnswerValue.Ranking {
+			rankSums[option] += i + 1
+			rankCounts[option]++
+			if i == 0 {
+				firstPlace[option]++
+			}
+		}
+	}
+
+	averageRank := make(map[string]float64, len(rankSums))
+	for option, sum := range rankSums {
+		averageRank[option] = float64(sum) / float64(rankCounts[option])
+	}
+
+	return map[string]interface{}{
+		"average_rank": averageRank,
+		"first_place":  firstPlace,
+	}
+}
+
+func aggregateConstantSum(answers []models.Answer) map[string]interface{} {
+	totals := make(map[string]float64)
+	respondents := 0
+	for _, answer := range answers {
+		if len(answer.AnswerValue.Allocations) == 0 {
+			continue
+		}
+		respondents++
+		for option, amount := range answer.AnswerValue.Allocations {
+			totals[option] += amount
+		}
+	}
+
+	averages := make(map[string]float64, len(totals))
+	for option, total := range totals {
+		averages[option] = total / float64(respondents)
+	}
+
+	return map[string]interface{}{
+		"count":              respondents,
+		"total_allocation":   totals,
+		"average_allocation": averages,
+	}
+}
+
+func aggregateFiles(answers []models.Answer) map[string]interface{} {
+	byType := make(map[string]int)
+	files := 0
+	var totalSize int64
+	for _, answer := range answers {
+		for _, file := range answer.AnswerValue.Files {
+			files++
+			totalSize += file.SizeBytes
+			byType[file.ContentType]++
+		}
+	}
+	return map[string]interface{}{
+		"files":            files,
+		"total_size_bytes": totalSize,
+		"by_type":          byType,
+	}
+}
+
+// numericAnswer extracts the numeric value of rating, scale and number answers
+func numericAnswer(value models.AnswerValue) (float64, bool) {
+	switch {
+	case value.Rating != nil:
+		return float64(*value.Rating), true
+	case value.Scale != nil:
+		return float64(*value.Scale), true
+	}
+	if number, ok := value.Content.(float64); ok {
+		return number, true
+	}
+	return 0, false
+}
+
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+func formatNumber(value float64) string {
+	if value == math.Trunc(value) {
+		return strconv.FormatInt(int64(value), 10)
+	}
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
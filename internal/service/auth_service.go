@@ -0,0 +1,481 @@
+// internal/service/auth_service.go
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"survey2earn-backend/internal/wallet"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const tokenIssuer = "survey2earn"
+
+var (
+	// ErrInvalidCredentials is returned when a login signature doesn't check out
+	ErrInvalidCredentials = apperror.New(apperror.Unauthorized, "Invalid wallet signature")
+
+	// ErrInvalidToken is returned for bad, expired or revoked tokens
+	ErrInvalidToken = apperror.New(apperror.Unauthorized, "Invalid or expired token")
+
+	// ErrAlreadyRegistered is returned when registering a known wallet
+	ErrAlreadyRegistered = apperror.New(apperror.AlreadyRegistered, "Wallet is already registered")
+
+	// ErrInvalidEmail is returned for a profile email that isn't an address
+	ErrInvalidEmail = apperror.Invalid("Invalid email address", apperror.FieldError{
+		Field:   "email",
+		Code:    "email",
+		Message: "not an email address",
+	})
+
+	// ErrInvalidAddress is returned for a wallet address that isn't one
+	ErrInvalidAddress = apperror.Invalid("Invalid wallet address", apperror.FieldError{
+		Field:   "wallet_address",
+		Code:    "eth_addr",
+		Message: "not a wallet address",
+	})
+)
+
+// ProfileConflictError is returned when a profile update takes a username
+// or email that belongs to another user
+type ProfileConflictError struct {
+	Field string
+}
+
+func (e *ProfileConflictError) Error() string {
+	return e.Field + " is already taken"
+}
+
+func (e *ProfileConflictError) AppError() *apperror.Error {
+	return apperror.New(apperror.ProfileConflict, e.Error())
+}
+
+// ClientInfo describes the client a session is opened from
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// AccessClaims identify the user and session behind an access token
+type AccessClaims struct {
+	UserID    uint
+	SessionID uint
+}
+
+type AuthService interface {
+	Register(req *dto.RegisterRequest) (*dto.RegisterResponse, error)
+	GetNonce(walletAddress string) (*dto.NonceResponse, error)
+	Login(req *dto.LoginRequest, client ClientInfo) (*dto.LoginResponse, error)
+	RefreshToken(req *dto.RefreshTokenRequest, client ClientInfo) (*dto.TokenResponse, error)
+	Logout(userID, sessionID uint) error
+	ValidateToken(token string) (*AccessClaims, error)
+	IsAdmin(userID uint) bool
+	GetProfile(userID uint) (*dto.UserProfileResponse, error)
+	UpdateProfile(userID uint, req *dto.UpdateProfileRequest) (*dto.UserProfileResponse, error)
+	GetUserStats(userID uint) (*dto.UserStatsResponse, error)
+}
+
+type authService struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.AuthSessionRepository
+
+	secret       []byte
+	accessTTL    time.Duration
+	refreshTTL   time.Duration
+	adminWallets map[string]bool
+}
+
+func NewAuthService(userRepo repository.UserRepository, sessionRepo repository.AuthSessionRepository, jwtConfig config.JWTConfig, adminConfig config.AdminConfig) AuthService {
+	adminWallets := make(map[string]bool, len(adminConfig.WalletAddresses))
+	for _, address := range adminConfig.WalletAddresses {
+		adminWallets[strings.ToLower(address)] = true
+	}
+
+	return &authService{
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		secret:       []byte(jwtConfig.Secret),
+		accessTTL:    time.Duration(jwtConfig.ExpirationHours) * time.Hour,
+		refreshTTL:   time.Duration(jwtConfig.RefreshExpirationHours) * time.Hour,
+		adminWallets: adminWallets,
+	}
+}
+
+// Register creates a user for a wallet and returns the message to sign to
+// log in
+func (s *authService) Register(req *dto.RegisterRequest) (*dto.RegisterResponse, error) {
+	address, err := wallet.NormalizeAddress(req.WalletAddress)
+	if err != nil {
+		return nil, ErrInvalidAddress
+	}
+
+	if _, err := s.userRepo.GetByWalletAddress(address); err == nil {
+		return nil, ErrAlreadyRegistered
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+	user := &models.User{
+		WalletAddress: address,
+		Nonce:         nonce,
+		IsActive:      true,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	return &dto.RegisterResponse{
+		User:    userToDTO(user),
+		Message: signInMessage(user),
+	}, nil
+}
+
+// GetNonce returns the message a registered wallet must sign to log in
+func (s *authService) GetNonce(walletAddress string) (*dto.NonceResponse, error) {
+	address, err := wallet.NormalizeAddress(walletAddress)
+	if err != nil {
+		return nil, ErrInvalidAddress
+	}
+	user, err := s.userRepo.GetByWalletAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.NonceResponse{
+		WalletAddress: user.WalletAddress,
+		Nonce:         user.Nonce,
+		Message:       signInMessage(user),
+	}, nil
+}
+
+// Login checks that the wallet signed its current sign-in message and opens
+// a session. The nonce is replaced so the signature can't be replayed.
+func (s *authService) Login(req *dto.LoginRequest, client ClientInfo) (*dto.LoginResponse, error) {
+	address, err := wallet.NormalizeAddress(req.WalletAddress)
+	if err != nil {
+		return nil, ErrInvalidAddress
+	}
+	user, err := s.userRepo.GetByWalletAddress(address)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrInvalidCredentials
+	}
+
+	if req.Message != signInMessage(user) {
+		return nil, ErrInvalidCredentials
+	}
+	if err := wallet.VerifySignature(address, req.Message, req.Signature); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	user.Nonce = nonce
+	user.LastLoginAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	session, refreshToken, err := s.newSession(user.ID, client)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+	accessToken, err := s.issueAccessToken(user.ID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginResponse{
+		User:         userToDTO(user),
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.accessTTL.Seconds()),
+	}, nil
+}
+
+// RefreshToken trades a refresh token for new tokens. Refresh tokens are
+// single use: presenting one that was already used revokes every session of
+// its user, since the token must have leaked.
+func (s *authService) RefreshToken(req *dto.RefreshTokenRequest, client ClientInfo) (*dto.TokenResponse, error) {
+	session, err := s.sessionRepo.GetByToken(hashToken(req.RefreshToken))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !session.IsActive {
+		if err := s.sessionRepo.DeactivateAllForUser(session.UserID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidToken
+	}
+	if !session.IsSessionValid() {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrInvalidToken
+	}
+
+	next, refreshToken, err := s.newSession(user.ID, client)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.Rotate(session, next); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	accessToken, err := s.issueAccessToken(user.ID, next.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.accessTTL.Seconds()),
+	}, nil
+}
+
+// Logout revokes the session's refresh token. Access tokens already issued
+// stay valid until they expire.
+func (s *authService) Logout(userID, sessionID uint) error {
+	return s.sessionRepo.Deactivate(userID, sessionID)
+}
+
+func (s *authService) ValidateToken(token string) (*AccessClaims, error) {
+	claims := &accessTokenClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil || userID == 0 {
+		return nil, ErrInvalidToken
+	}
+	return &AccessClaims{UserID: uint(userID), SessionID: claims.SessionID}, nil
+}
+
+// IsAdmin reports whether the user was made an admin or their wallet is
+// configured as an admin
+func (s *authService) IsAdmin(userID uint) bool {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return false
+	}
+	return user.IsActive && (user.IsAdmin || s.adminWallets[user.WalletAddress])
+}
+
+func (s *authService) GetProfile(userID uint) (*dto.UserProfileResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return userToProfileDTO(user), nil
+}
+
+func (s *authService) UpdateProfile(userID uint, req *dto.UpdateProfileRequest) (*dto.UserProfileResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Username != nil {
+		username := strings.TrimSpace(*req.Username)
+		if username == "" {
+			user.Username = nil
+		} else {
+			if err := s.checkProfileConflict("username", userID, s.userRepo.GetByUsername, username); err != nil {
+				return nil, err
+			}
+			user.Username = &username
+		}
+	}
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		if email == "" {
+			user.Email = nil
+		} else {
+			if _, err := mail.ParseAddress(email); err != nil {
+				return nil, ErrInvalidEmail
+			}
+			if err := s.checkProfileConflict("email", userID, s.userRepo.GetByEmail, email); err != nil {
+				return nil, err
+			}
+			user.Email = &email
+		}
+	}
+	if req.Bio != nil {
+		user.Bio = req.Bio
+	}
+	if req.ProfilePicture != nil {
+		user.ProfilePicture = req.ProfilePicture
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return userToProfileDTO(user), nil
+}
+
+// checkProfileConflict fails if value is already used by another user
+func (s *authService) checkProfileConflict(field string, userID uint, lookup func(string) (*models.User, error), value string) error {
+	other, err := lookup(value)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if other.ID != userID {
+		return &ProfileConflictError{Field: field}
+	}
+	return nil
+}
+
+func (s *authService) GetUserStats(userID uint) (*dto.UserStatsResponse, error) {
+	stats, err := s.userRepo.GetStats(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UserStatsResponse{
+		UserID:               stats.UserID,
+		TotalSurveysCreated:  stats.TotalSurveysCreated,
+		TotalSurveysAnswered: stats.TotalSurveysAnswered,
+		TotalEarned:          stats.TotalEarned,
+		TotalSpent:           stats.TotalSpent,
+		AverageRating:        stats.AverageRating,
+		LastActivityAt:       stats.LastActivityAt,
+	}, nil
+}
+
+// accessTokenClaims are the JWT claims of an access token. The subject is
+// the user ID.
+type accessTokenClaims struct {
+	SessionID uint `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+func (s *authService) issueAccessToken(userID, sessionID uint) (string, error) {
+	now := time.Now()
+	claims := accessTokenClaims{
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
+
+// newSession builds a session for a new refresh token, which is returned
+// alongside since only its hash is stored
+func (s *authService) newSession(userID uint, client ClientInfo) (*models.AuthSession, string, error) {
+	token, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+	return &models.AuthSession{
+		UserID:    userID,
+		Token:     hashToken(token),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+		IsActive:  true,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+	}, token, nil
+}
+
+// signInMessage is the message a wallet signs to log in. It changes with
+// every login.
+func signInMessage(user *models.User) string {
+	return fmt.Sprintf("Sign in to Survey2Earn\n\nWallet: %s\nNonce: %s", user.WalletAddress, user.Nonce)
+}
+
+func newNonce() (string, error) {
+	return randomHex(16)
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func userToDTO(user *models.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:              user.ID,
+		WalletAddress:   user.WalletAddress,
+		Username:        user.Username,
+		ReputationScore: user.ReputationScore,
+	}
+}
+
+func userToProfileDTO(user *models.User) *dto.UserProfileResponse {
+	return &dto.UserProfileResponse{
+		ID:              user.ID,
+		WalletAddress:   user.WalletAddress,
+		Username:        user.Username,
+		Email:           user.Email,
+		Bio:             user.Bio,
+		ProfilePicture:  user.ProfilePicture,
+		ReputationScore: user.ReputationScore,
+		TotalEarned:     user.TotalEarned,
+		TotalResponses:  user.TotalResponses,
+		TotalSurveys:    user.TotalSurveys,
+		IsActive:        user.IsActive,
+		LastLoginAt:     user.LastLoginAt,
+		CreatedAt:       user.CreatedAt,
+	}
+}
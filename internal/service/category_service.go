@@ -0,0 +1,407 @@
+// internal/service/category_service.go
+package service
+
+import (
+	"errors"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// InvalidCategoryError is returned when a category, or a survey's category,
+// is invalid
+type InvalidCategoryError struct {
+	Slug   string
+	Reason string
+}
+
+func (e *InvalidCategoryError) Error() string {
+	if e.Slug == "" {
+		return "invalid category: " + e.Reason
+	}
+	return "invalid category " + e.Slug + ": " + e.Reason
+}
+
+func (e *InvalidCategoryError) AppError() *apperror.Error {
+	return apperror.New(apperror.InvalidCategory, e.Error())
+}
+
+// ErrCategoryInUse is returned when deleting a category that still has
+// subcategories or is still used
+var ErrCategoryInUse = apperror.New(apperror.CategoryInUse, "Category has subcategories or is used by surveys, templates or bank questions")
+
+var errCategoryNotFound = apperror.New(apperror.NotFound, "Category not found")
+
+type CategoryService interface {
+	ListCategories(req *dto.ListCategoriesRequest, locale string) (*dto.CategoryListResponse, error)
+	GetCategory(slug, locale string) (*dto.CategoryResponse, error)
+
+	// Admin management of the categories
+	CreateCategory(req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error)
+	UpdateCategory(categoryID uint, req *dto.UpdateCategoryRequest) (*dto.CategoryResponse, error)
+	DeleteCategory(categoryID uint) error
+}
+
+type categoryService struct {
+	categoryRepo repository.CategoryRepository
+	surveyRepo   repository.SurveyRepository
+}
+
+func NewCategoryService(
+	categoryRepo repository.CategoryRepository,
+	surveyRepo repository.SurveyRepository,
+) CategoryService {
+	return &categoryService{
+		categoryRepo: categoryRepo,
+		surveyRepo:   surveyRepo,
+	}
+}
+
+// ListCategories returns the category tree with public survey counts.
+// Inactive categories, and everything below them, are left out unless
+// req.IncludeInactive is set.
+func (s *categoryService) ListCategories(req *dto.ListCategoriesRequest, locale string) (*dto.CategoryListResponse, error) {
+	tree, err := loadCategoryTree(s.categoryRepo)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.surveyRepo.CountPublicSurveysByCategory(&repository.PublicSurveyFilter{Status: req.Status})
+	if err != nil {
+		return nil, err
+	}
+
+	roots := tree.children[0]
+	items := make([]dto.CategoryResponse, 0, len(roots))
+	for _, root := range roots {
+		if root.IsActive || req.IncludeInactive {
+			items = append(items, tree.toDTO(root, counts, locale, req.IncludeInactive))
+		}
+	}
+
+	return &dto.CategoryListResponse{Categories: items, Locale: locale}, nil
+}
+
+// GetCategory returns an active category with its active subcategories
+func (s *categoryService) GetCategory(slug, locale string) (*dto.CategoryResponse, error) {
+	tree, err := loadCategoryTree(s.categoryRepo)
+	if err != nil {
+		return nil, err
+	}
+	category := tree.bySlug[slug]
+	if category == nil || !category.IsActive {
+		return nil, errCategoryNotFound
+	}
+	counts, err := s.surveyRepo.CountPublicSurveysByCategory(&repository.PublicSurveyFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	response := tree.toDTO(category, counts, locale, false)
+	return &response, nil
+}
+
+func (s *categoryService) CreateCategory(req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
+	if !models.IsValidCategorySlug(req.Slug) {
+		return nil, &InvalidCategoryError{Slug: req.Slug, Reason: "slug must be lowercase letters and digits separated by single hyphens"}
+	}
+	names, err := normalizeCategoryNames(req.Names)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := loadCategoryTree(s.categoryRepo)
+	if err != nil {
+		return nil, err
+	}
+	if tree.bySlug[req.Slug] != nil {
+		return nil, &InvalidCategoryError{Slug: req.Slug, Reason: "slug is already taken"}
+	}
+
+	category := &models.Category{
+		Slug:      req.Slug,
+		Names:     names,
+		Icon:      req.Icon,
+		SortOrder: req.SortOrder,
+		IsActive:  req.IsActive == nil || *req.IsActive,
+	}
+	if req.Parent != "" {
+		parent := tree.bySlug[req.Parent]
+		if parent == nil {
+			return nil, &InvalidCategoryError{Slug: req.Parent, Reason: "parent category not found"}
+		}
+		category.ParentID = &parent.ID
+	}
+
+	if err := s.categoryRepo.Create(category); err != nil {
+		return nil, err
+	}
+
+	response := categoryToDTO(category, req.Parent, models.DefaultLocale)
+	return &response, nil
+}
+
+func (s *categoryService) UpdateCategory(categoryID uint, req *dto.UpdateCategoryRequest) (*dto.CategoryResponse, error) {
+	tree, err := loadCategoryTree(s.categoryRepo)
+	if err != nil {
+		return nil, err
+	}
+	category := tree.byID[categoryID]
+	if category == nil {
+		return nil, errCategoryNotFound
+	}
+
+	if req.Names != nil {
+		names, err := normalizeCategoryNames(req.Names)
+		if err != nil {
+			return nil, err
+		}
+		category.Names = names
+	}
+	if req.Icon != nil {
+		category.Icon = *req.Icon
+	}
+	if req.SortOrder != nil {
+		category.SortOrder = *req.SortOrder
+	}
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+	if req.Parent != nil {
+		category.ParentID = nil
+		if *req.Parent != "" {
+			parent := tree.bySlug[*req.Parent]
+			if parent == nil {
+				return nil, &InvalidCategoryError{Slug: *req.Parent, Reason: "parent category not found"}
+			}
+			// A category can't move below itself
+			for _, slug := range tree.subtree(category.Slug) {
+				if slug == parent.Slug {
+					return nil, &InvalidCategoryError{Slug: category.Slug, Reason: "a category can't be its own parent or move below its subcategories"}
+				}
+			}
+			category.ParentID = &parent.ID
+		}
+	}
+
+	if err := s.categoryRepo.Update(category); err != nil {
+		return nil, err
+	}
+
+	response := categoryToDTO(category, tree.parentSlug(category), models.DefaultLocale)
+	return &response, nil
+}
+
+// DeleteCategory deletes an unused category without subcategories. Used
+// categories can be deactivated instead.
+func (s *categoryService) DeleteCategory(categoryID uint) error {
+	tree, err := loadCategoryTree(s.categoryRepo)
+	if err != nil {
+		return err
+	}
+	category := tree.byID[categoryID]
+	if category == nil {
+		return errCategoryNotFound
+	}
+	if len(tree.children[category.ID]) > 0 {
+		return ErrCategoryInUse
+	}
+
+	usage, err := s.categoryRepo.CountUsage(category.Slug)
+	if err != nil {
+		return err
+	}
+	if usage > 0 {
+		return ErrCategoryInUse
+	}
+
+	return s.categoryRepo.Delete(category.ID)
+}
+
+// checkCategory makes sure slug is an active category surveys, templates
+// and bank questions can be filed under
+func checkCategory(categoryRepo repository.CategoryRepository, slug string) error {
+	category, err := categoryRepo.GetBySlug(slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &InvalidCategoryError{Slug: slug, Reason: "unknown category"}
+	}
+	if err != nil {
+		return err
+	}
+	if !category.IsActive {
+		return &InvalidCategoryError{Slug: slug, Reason: "category is not active"}
+	}
+	return nil
+}
+
+// normalizeCategoryNames lowercases the locales and requires a name in the
+// default locale, which the other locales fall back to
+func normalizeCategoryNames(names map[string]string) (models.LocalizedNames, error) {
+	normalized := make(models.LocalizedNames, len(names))
+	for locale, name := range names {
+		locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+		name = strings.TrimSpace(name)
+		if locale == "" || name == "" {
+			return nil, &InvalidCategoryError{Reason: "names need a locale and a name"}
+		}
+		if len(name) > 100 {
+			return nil, &InvalidCategoryError{Reason: "names can't be longer than 100 characters"}
+		}
+		normalized[locale] = name
+	}
+	if normalized[models.DefaultLocale] == "" {
+		return nil, &InvalidCategoryError{Reason: "names must include a name for " + models.DefaultLocale}
+	}
+	return normalized, nil
+}
+
+// categoryTree is the category hierarchy. Top-level categories are the
+// children of ID 0.
+type categoryTree struct {
+	byID     map[uint]*models.Category
+	bySlug   map[string]*models.Category
+	children map[uint][]*models.Category
+}
+
+func loadCategoryTree(categoryRepo repository.CategoryRepository) (*categoryTree, error) {
+	categories, err := categoryRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	return newCategoryTree(categories), nil
+}
+
+// newCategoryTree builds the tree from the categories in display order
+func newCategoryTree(categories []models.Category) *categoryTree {
+	tree := &categoryTree{
+		byID:     make(map[uint]*models.Category, len(categories)),
+		bySlug:   make(map[string]*models.Category, len(categories)),
+		children: make(map[uint][]*models.Category),
+	}
+	for i := range categories {
+		category := &categories[i]
+		tree.byID[category.ID] = category
+		tree.bySlug[category.Slug] = category
+	}
+	for i := range categories {
+		category := &categories[i]
+		parentID := uint(0)
+		if category.ParentID != nil && tree.byID[*category.ParentID] != nil {
+			parentID = *category.ParentID
+		}
+		tree.children[parentID] = append(tree.children[parentID], category)
+	}
+	return tree
+}
+
+// subtree returns the slugs of the category and all its subcategories, or
+// nil when there is no such category
+func (t *categoryTree) subtree(slug string) []string {
+	root := t.bySlug[slug]
+	if root == nil {
+		return nil
+	}
+
+	slugs := []string{root.Slug}
+	seen := map[uint]bool{root.ID: true}
+	for pending := []*models.Category{root}; len(pending) > 0; pending = pending[1:] {
+		for _, child := range t.children[pending[0].ID] {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			slugs = append(slugs, child.Slug)
+			pending = append(pending, child)
+		}
+	}
+	return slugs
+}
+
+// totalCount adds up the counts of the category and its subcategories
+func (t *categoryTree) totalCount(slug string, counts map[string]int64) int64 {
+	var total int64
+	for _, s := range t.subtree(slug) {
+		total += counts[s]
+	}
+	return total
+}
+
+// subtreeCounts returns the total count of every category in the subtree of
+// slug, or of every category when slug is empty, leaving out empty ones
+func (t *categoryTree) subtreeCounts(slug string, counts map[string]int64) map[string]int64 {
+	var slugs []string
+	if slug == "" {
+		for s := range t.bySlug {
+			slugs = append(slugs, s)
+		}
+	} else {
+		slugs = t.subtree(slug)
+	}
+
+	totals := make(map[string]int64)
+	for _, s := range slugs {
+		if total := t.totalCount(s, counts); total > 0 {
+			totals[s] = total
+		}
+	}
+	return totals
+}
+
+// root returns the slug of the top-level category above slug, slug itself
+// for a top-level category, or "" when there is no such category
+func (t *categoryTree) root(slug string) string {
+	category := t.bySlug[slug]
+	if category == nil {
+		return ""
+	}
+	seen := map[uint]bool{category.ID: true}
+	for category.ParentID != nil {
+		parent := t.byID[*category.ParentID]
+		if parent == nil || seen[parent.ID] {
+			break
+		}
+		seen[parent.ID] = true
+		category = parent
+	}
+	return category.Slug
+}
+
+func (t *categoryTree) parentSlug(category *models.Category) string {
+	if category.ParentID == nil || t.byID[*category.ParentID] == nil {
+		return ""
+	}
+	return t.byID[*category.ParentID].Slug
+}
+
+func (t *categoryTree) toDTO(category *models.Category, counts map[string]int64, locale string, includeInactive bool) dto.CategoryResponse {
+	response := categoryToDTO(category, t.parentSlug(category), locale)
+	response.SurveyCount = counts[category.Slug]
+	response.TotalSurveyCount = response.SurveyCount
+
+	for _, child := range t.children[category.ID] {
+		childResponse := t.toDTO(child, counts, locale, includeInactive)
+		// Inactive subcategories keep their surveys in the parent's total
+		response.TotalSurveyCount += childResponse.TotalSurveyCount
+		if child.IsActive || includeInactive {
+			response.Children = append(response.Children, childResponse)
+		}
+	}
+	return response
+}
+
+func categoryToDTO(category *models.Category, parent, locale string) dto.CategoryResponse {
+	return dto.CategoryResponse{
+		ID:        category.ID,
+		Slug:      category.Slug,
+		Name:      category.Name(locale),
+		Names:     category.Names,
+		Icon:      category.Icon,
+		Parent:    parent,
+		SortOrder: category.SortOrder,
+		IsActive:  category.IsActive,
+		Children:  []dto.CategoryResponse{},
+	}
+}
@@ -0,0 +1,233 @@
+// internal/service/crosstab.go
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"survey2earn-backend/internal/stats"
+)
+
+const defaultConfidenceLevel = 0.95
+
+func (s *surveyService) GetCrosstab(userID, surveyID uint, req *dto.CrosstabRequest) (*dto.CrosstabResponse, error) {
+	survey, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if survey.CreatorID != userID {
+		return nil, errSurveyForbidden
+	}
+
+	rowQuestion, err := crosstabQuestion(survey, "row", req.Row)
+	if err != nil {
+		return nil, err
+	}
+	columnQuestion, err := crosstabQuestion(survey, "column", req.Column)
+	if err != nil {
+		return nil, err
+	}
+	if rowQuestion.ID == columnQuestion.ID {
+		return nil, &InvalidFilterError{Filter: "column", Reason: "row and column must be different questions"}
+	}
+
+	// Like the other analytics, only completed responses count unless asked otherwise
+	params := *req
+	if params.Confidence == 0 {
+		params.Confidence = defaultConfidenceLevel
+	}
+	if params.Status == "" {
+		params.Status = string(models.ResponseStatusCompleted)
+	}
+
+	filter, err := buildResponseFilter(survey, &params.ResponseFilterRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	load := func() (interface{}, error) {
+		counts, err := s.responseRepo.GetCrosstabCounts(surveyID, rowQuestion.ID, columnQuestion.ID, filter)
+		if err != nil {
+			return nil, err
+		}
+		return buildCrosstab(survey.ID, rowQuestion, columnQuestion, counts, params.Confidence), nil
+	}
+	if !s.cachesAnalytics() {
+		result, err := load()
+		if err != nil {
+			return nil, err
+		}
+		return result.(*dto.CrosstabResponse), nil
+	}
+
+	// Crosstabs are cached per set of parameters
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(encoded)
+
+	var result dto.CrosstabResponse
+	if err := s.cachedAnalytics(surveyID, "crosstab:"+hex.EncodeToString(digest[:]), &result, load); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// crosstabQuestion looks up a question by order and checks that its answers are categories
+func crosstabQuestion(survey *models.Survey, name string, order int) (*models.Question, error) {
+	question := questionByOrder(survey, order)
+	if question == nil {
+		return nil, &InvalidFilterError{Filter: name, Reason: "survey has no question " + strconv.Itoa(order)}
+	}
+
+	switch question.Type {
+	case models.QuestionTypeSingleChoice, models.QuestionTypeMultipleChoice, models.QuestionTypeYesNo,
+		models.QuestionTypeRating, models.QuestionTypeScale, models.QuestionTypeNPS:
+		return question, nil
+	}
+	return nil, &InvalidFilterError{
+		Filter: name,
+		Reason: fmt.Sprintf("%s questions can't be cross-tabulated", question.Type),
+	}
+}
+
+func buildCrosstab(surveyID uint, rowQuestion, columnQuestion *models.Question, counts []repository.CrosstabCount, confidence float64) *dto.CrosstabResponse {
+	rowValues := make(map[string]bool)
+	columnValues := make(map[string]bool)
+	for _, count := range counts {
+		rowValues[count.RowValue] = true
+		columnValues[count.ColumnValue] = true
+	}
+	rows := crosstabCategories(rowQuestion, rowValues)
+	columns := crosstabCategories(columnQuestion, columnValues)
+
+	rowIndex := make(map[string]int, len(rows))
+	for i, row := range rows {
+		rowIndex[row.Value] = i
+	}
+	columnIndex := make(map[string]int, len(columns))
+	for i, column := range columns {
+		columnIndex[column.Value] = i
+	}
+
+	observed := make([][]float64, len(rows))
+	for i := range observed {
+		observed[i] = make([]float64, len(columns))
+	}
+	rowTotals := make([]int, len(rows))
+	columnTotals := make([]int, len(columns))
+	total := 0
+	for _, count := range counts {
+		i, j := rowIndex[count.RowValue], columnIndex[count.ColumnValue]
+		observed[i][j] += float64(count.Count)
+		rowTotals[i] += count.Count
+		columnTotals[j] += count.Count
+		total += count.Count
+	}
+
+	cells := make([][]dto.CrosstabCell, len(rows))
+	for i := range rows {
+		cells[i] = make([]dto.CrosstabCell, len(columns))
+		for j := range columns {
+			count := int(observed[i][j])
+			cell := dto.CrosstabCell{
+				Count:         count,
+				RowPercent:    percentage(count, rowTotals[i]),
+				ColumnPercent: percentage(count, columnTotals[j]),
+				TotalPercent:  percentage(count, total),
+			}
+			lower, upper := stats.WilsonInterval(float64(count), float64(columnTotals[j]), confidence)
+			cell.ColumnCI = dto.ConfidenceInterval{Lower: lower * 100, Upper: upper * 100}
+			cells[i][j] = cell
+		}
+	}
+
+	result := &dto.CrosstabResponse{
+		SurveyID:        surveyID,
+		RowQuestion:     crosstabQuestionToDTO(rowQuestion),
+		ColumnQuestion:  crosstabQuestionToDTO(columnQuestion),
+		Rows:            rows,
+		Columns:         columns,
+		Cells:           cells,
+		RowTotals:       rowTotals,
+		ColumnTotals:    columnTotals,
+		Total:           total,
+		ConfidenceLevel: confidence,
+	}
+
+	if chiSquare, err := stats.ChiSquareTest(observed); err == nil {
+		result.ChiSquare = &dto.ChiSquareResponse{
+			Statistic:        chiSquare.Statistic,
+			DegreesOfFreedom: chiSquare.DegreesOfFreedom,
+			PValue:           chiSquare.PValue,
+			CramersV:         chiSquare.CramersV,
+			Significant:      chiSquare.PValue < 1-confidence,
+			LowExpectedCells: chiSquare.LowExpectedCells,
+		}
+	}
+
+	return result
+}
+
+// crosstabCategories lists the defined options of a question in order,
+// followed by any other answered values sorted numerically where possible
+func crosstabCategories(question *models.Question, values map[string]bool) []dto.CrosstabCategory {
+	categories := make([]dto.CrosstabCategory, 0, len(question.Options)+len(values))
+	listed := make(map[string]bool, len(question.Options))
+	for _, option := range question.Options {
+		if listed[option.Value] {
+			continue
+		}
+		listed[option.Value] = true
+		label := option.Label
+		if label == "" {
+			label = option.Value
+		}
+		categories = append(categories, dto.CrosstabCategory{Value: option.Value, Label: label})
+	}
+
+	var others []string
+	for value := range values {
+		if !listed[value] {
+			others = append(others, value)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool {
+		a, errA := strconv.ParseFloat(others[i], 64)
+		b, errB := strconv.ParseFloat(others[j], 64)
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return others[i] < others[j]
+	})
+	for _, value := range others {
+		categories = append(categories, dto.CrosstabCategory{Value: value, Label: value})
+	}
+
+	return categories
+}
+
+func crosstabQuestionToDTO(question *models.Question) dto.CrosstabQuestion {
+	return dto.CrosstabQuestion{
+		ID:    question.ID,
+		Order: question.Order,
+		Text:  question.Text,
+		Type:  string(question.Type),
+	}
+}
+
+func percentage(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
@@ -0,0 +1,104 @@
+// internal/service/crosstab_test.go
+package service
+
+import (
+	"math"
+	"reflect"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"testing"
+)
+
+func crosstabTestQuestions() (row, column *models.Question) {
+	row = &models.Question{BaseModel: models.BaseModel{ID: 1}, Order: 1, Type: models.QuestionTypeSingleChoice, Text: "Do you trade?",
+		Options: models.QuestionOptions{{Value: "yes", Label: "Yes"}, {Value: "no"}, {Value: "unsure", Label: "Not sure"}}}
+	column = &models.Question{BaseModel: models.BaseModel{ID: 2}, Order: 2, Type: models.QuestionTypeRating, Text: "How satisfied are you?"}
+	return row, column
+}
+
+func TestBuildCrosstab(t *testing.T) {
+	row, column := crosstabTestQuestions()
+	counts := []repository.CrosstabCount{
+		{RowValue: "yes", ColumnValue: "10", Count: 10},
+		{RowValue: "yes", ColumnValue: "2", Count: 20},
+		{RowValue: "no", ColumnValue: "10", Count: 30},
+		{RowValue: "no", ColumnValue: "2", Count: 40},
+	}
+	result := buildCrosstab(7, row, column, counts, 0.95)
+
+	// Options in order, even unanswered ones, then answered values numerically
+	wantRows := []dto.CrosstabCategory{{Value: "yes", Label: "Yes"}, {Value: "no", Label: "no"}, {Value: "unsure", Label: "Not sure"}}
+	wantColumns := []dto.CrosstabCategory{{Value: "2", Label: "2"}, {Value: "10", Label: "10"}}
+	if !reflect.DeepEqual(result.Rows, wantRows) || !reflect.DeepEqual(result.Columns, wantColumns) {
+		t.Fatalf("rows, columns = %v, %v, want %v, %v", result.Rows, result.Columns, wantRows, wantColumns)
+	}
+	if !reflect.DeepEqual(result.RowTotals, []int{30, 70, 0}) || !reflect.DeepEqual(result.ColumnTotals, []int{60, 40}) || result.Total != 100 {
+		t.Errorf("totals = %v, %v, %d, want [30 70 0], [60 40], 100", result.RowTotals, result.ColumnTotals, result.Total)
+	}
+
+	cell := result.Cells[0][1] // yes, 10
+	if cell.Count != 10 || !near(cell.RowPercent, 100.0/3) || cell.ColumnPercent != 25 || cell.TotalPercent != 10 {
+		t.Errorf("cell = %+v, want 10 responses, 33.3%% of the row, 25%% of the column, 10%% of all", cell)
+	}
+	// The Wilson interval of 10/40
+	if !near(cell.ColumnCI.Lower, 14.19) || !near(cell.ColumnCI.Upper, 40.19) {
+		t.Errorf("column CI = %+v, want [14.19, 40.19]", cell.ColumnCI)
+	}
+
+	// The unanswered option row counts nothing and doesn't enter the test,
+	// though 0 of a column still has an upper bound
+	for j, empty := range result.Cells[2] {
+		if empty.Count != 0 || empty.RowPercent != 0 || empty.ColumnPercent != 0 || empty.ColumnCI.Lower != 0 || empty.ColumnCI.Upper <= 0 {
+			t.Errorf("cell [unsure][%d] = %+v, want 0%% with an interval from 0", j, empty)
+		}
+	}
+	chiSquare := result.ChiSquare
+	if chiSquare == nil {
+		t.Fatal("no chi-square test for a 2x2 table")
+	}
+	if !near(chiSquare.Statistic, 0.7937) || chiSquare.DegreesOfFreedom != 1 || !near(chiSquare.PValue, 0.373) || chiSquare.Significant {
+		t.Errorf("chi-square = %+v, want 0.7937 with 1 degree of freedom, p = 0.373, not significant", chiSquare)
+	}
+
+	// Significance is judged at the confidence level asked for
+	if result := buildCrosstab(7, row, column, counts, 0.5); !result.ChiSquare.Significant {
+		t.Error("p = 0.373 is not significant at the 50% level")
+	}
+}
+
+func TestBuildCrosstabWithoutResponses(t *testing.T) {
+	row, column := crosstabTestQuestions()
+	result := buildCrosstab(7, row, column, nil, 0.95)
+
+	if len(result.Rows) != 3 || len(result.Columns) != 0 || result.Total != 0 {
+		t.Errorf("rows, columns, total = %d, %d, %d, want the 3 options, no values and 0", len(result.Rows), len(result.Columns), result.Total)
+	}
+	if result.ChiSquare != nil {
+		t.Errorf("chi-square = %+v without responses, want none", result.ChiSquare)
+	}
+}
+
+func TestBuildCrosstabSingleColumn(t *testing.T) {
+	row, column := crosstabTestQuestions()
+	counts := []repository.CrosstabCount{
+		{RowValue: "yes", ColumnValue: "5", Count: 3},
+		{RowValue: "no", ColumnValue: "5", Count: 1},
+	}
+	result := buildCrosstab(7, row, column, counts, 0.95)
+
+	if result.ChiSquare != nil {
+		t.Errorf("chi-square = %+v for a single column, want none", result.ChiSquare)
+	}
+	if cell := result.Cells[0][0]; cell.ColumnPercent != 75 || cell.RowPercent != 100 {
+		t.Errorf("cell = %+v, want 75%% of the column and all of the row", cell)
+	}
+	// Percentages of an empty row are 0, not NaN
+	if cell := result.Cells[2][0]; cell.RowPercent != 0 || cell.ColumnCI.Lower != 0 {
+		t.Errorf("cell [unsure][5] = %+v, want 0%%", cell)
+	}
+}
+
+func near(got, want float64) bool {
+	return math.Abs(got-want) < 0.01
+}
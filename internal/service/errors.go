@@ -0,0 +1,48 @@
+// internal/service/errors.go
+package service
+
+import (
+	"fmt"
+	"survey2earn-backend/internal/apperror"
+)
+
+// Errors returned by several services
+var (
+	errSurveyForbidden   = apperror.New(apperror.Forbidden, "You don't have permission to access this survey")
+	errResponseForbidden = apperror.New(apperror.Forbidden, "You don't have permission to access this response")
+)
+
+// errNoQuestions is returned for a survey or template without questions
+var errNoQuestions = apperror.Invalid("At least one question is required", apperror.FieldError{
+	Field:   "questions",
+	Code:    "required",
+	Message: "add a question or pick one from the question bank",
+})
+
+// unknownQuestionType reports a question of a type that doesn't exist
+func unknownQuestionType(field, questionType string) error {
+	return apperror.Invalid(fmt.Sprintf("Unknown question type %q", questionType), apperror.FieldError{
+		Field:   field,
+		Code:    "oneof",
+		Message: "not a supported question type",
+	})
+}
+
+// unsupportedLanguage reports a survey language that isn't supported
+func unsupportedLanguage(language string) error {
+	return apperror.Invalid(fmt.Sprintf("Unsupported survey language %q", language), apperror.FieldError{
+		Field:   "language",
+		Code:    "oneof",
+		Message: "not a supported language",
+	})
+}
+
+// bankQuestionNotFound reports a picked bank question that doesn't exist or
+// that the user can't use
+func bankQuestionNotFound(id uint) error {
+	return apperror.Invalid(fmt.Sprintf("Bank question %d not found", id), apperror.FieldError{
+		Field:   "bankQuestionIds",
+		Code:    "not_found",
+		Message: fmt.Sprintf("bank question %d doesn't exist or isn't available to you", id),
+	})
+}
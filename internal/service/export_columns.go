@@ -0,0 +1,271 @@
+// internal/service/export_columns.go
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"survey2earn-backend/internal/export"
+	"survey2earn-backend/internal/models"
+	"time"
+)
+
+// exportField is a single column of a response export and how to fill it
+type exportField struct {
+	column export.Column
+	value  func(response *models.Response, answers map[uint]*models.Answer) interface{}
+}
+
+// buildExportFields lays out the export columns for a survey: response
+// metadata first, then one or more columns per question in question order.
+// Respondent identifying columns are left out of anonymous surveys.
+func buildExportFields(survey *models.Survey) []exportField {
+	fields := []exportField{
+		numberField("response_id", "Response ID", func(r *models.Response) interface{} {
+			return float64(r.ID)
+		}),
+	}
+
+	if !survey.IsAnonymous {
+		fields = append(fields,
+			numberField("respondent_id", "Respondent ID", func(r *models.Response) interface{} {
+				return float64(r.UserID)
+			}),
+			stringField("wallet_address", "Wallet address", func(r *models.Response) interface{} {
+				return r.User.WalletAddress
+			}),
+		)
+	}
+
+	fields = append(fields,
+		stringField("status", "Status", func(r *models.Response) interface{} {
+			return string(r.Status)
+		}),
+		stringField("started_at", "Started at", func(r *models.Response) interface{} {
+			return exportTime(&r.StartedAt)
+		}),
+		stringField("completed_at", "Completed at", func(r *models.Response) interface{} {
+			return exportTime(r.CompletedAt)
+		}),
+		numberField("duration_seconds", "Duration in seconds", func(r *models.Response) interface{} {
+			return float64(r.Duration)
+		}),
+		stringField("language", "Language", func(r *models.Response) interface{} {
+			return r.Language
+		}),
+		stringField("timezone", "Timezone", func(r *models.Response) interface{} {
+			return r.Timezone
+		}),
+	)
+
+	if !survey.IsAnonymous {
+		fields = append(fields,
+			stringField("ip_address", "IP address", func(r *models.Response) interface{} {
+				return r.IPAddress
+			}),
+			stringField("user_agent", "User agent", func(r *models.Response) interface{} {
+				return r.UserAgent
+			}),
+		)
+	}
+
+	fields = append(fields,
+		numberField("quality_score", "Quality score", func(r *models.Response) interface{} {
+			return r.QualityScore
+		}),
+		numberField("is_valid", "Valid response (1 = yes)", func(r *models.Response) interface{} {
+			if r.IsValid {
+				return float64(1)
+			}
+			return float64(0)
+		}),
+		stringField("flagged_reason", "Flagged reason", func(r *models.Response) interface{} {
+			if r.FlaggedReason == nil {
+				return nil
+			}
+			return *r.FlaggedReason
+		}),
+	)
+
+	questions := make([]models.Question, len(survey.Questions))
+	copy(questions, survey.Questions)
+	sort.Slice(questions, func(i, j int) bool {
+		return questions[i].Order < questions[j].Order
+	})
+	for i := range questions {
+		fields = append(fields, questionFields(&questions[i])...)
+	}
+
+	return fields
+}
+
+// questionFields returns the columns of a question. Multiple choice, ranking
+// and constant-sum questions get one column per option and matrix questions
+// one column per row, named q<order>_<n>.
+func questionFields(question *models.Question) []exportField {
+	questionID := question.ID
+	name := fmt.Sprintf("q%d", question.Order)
+	answerOf := func(answers map[uint]*models.Answer) *models.Answer {
+		answer := answers[questionID]
+		if answer == nil || answer.IsSkipped {
+			return nil
+		}
+		return answer
+	}
+
+	switch question.Type {
+	case models.QuestionTypeMultipleChoice:
+		fields := make([]exportField, len(question.Options))
+		for i, option := range question.Options {
+			value := option.Value
+			fields[i] = answerField(fmt.Sprintf("%s_%d", name, i+1), optionLabel(question, option), export.KindNumber, answerOf,
+				func(answer *models.Answer) interface{} {
+					for _, selected := range answer.AnswerValue.Options {
+						if selected == value {
+							return float64(1)
+						}
+					}
+					return float64(0)
+				})
+		}
+		return fields
+
+	case models.QuestionTypeMatrix:
+		fields := make([]exportField, len(question.Rows))
+		for i, row := range question.Rows {
+			rowID := row.ID
+			fields[i] = answerField(fmt.Sprintf("%s_%d", name, i+1), optionLabel(question, row), export.KindString, answerOf,
+				func(answer *models.Answer) interface{} {
+					if column, ok := answer.AnswerValue.Matrix[rowID]; ok {
+						return column
+					}
+					return nil
+				})
+		}
+		return fields
+
+	case models.QuestionTypeRanking:
+		fields := make([]exportField, len(question.Options))
+		for i, option := range question.Options {
+			value := option.Value
+			fields[i] = answerField(fmt.Sprintf("%s_%d", name, i+1), optionLabel(question, option), export.KindNumber, answerOf,
+				func(answer *models.Answer) interface{} {
+					for rank, ranked := range answer.AnswerValue.Ranking {
+						if ranked == value {
+							return float64(rank + 1)
+						}
+					}
+					return nil
+				})
+		}
+		return fields
+
+	case models.QuestionTypeConstantSum:
+		fields := make([]exportField, len(question.Options))
+		for i, option := range question.Options {
+			value := option.Value
+			fields[i] = answerField(fmt.Sprintf("%s_%d", name, i+1), optionLabel(question, option), export.KindNumber, answerOf,
+				func(answer *models.Answer) interface{} {
+					if amount, ok := answer.AnswerValue.Allocations[value]; ok {
+						return amount
+					}
+					return nil
+				})
+		}
+		return fields
+
+	case models.QuestionTypeRating, models.QuestionTypeScale, models.QuestionTypeNPS, models.QuestionTypeNumber:
+		return []exportField{answerField(name, question.Text, export.KindNumber, answerOf,
+			func(answer *models.Answer) interface{} {
+				if value, ok := numericAnswer(answer.AnswerValue); ok {
+					return value
+				}
+				return nil
+			})}
+
+	case models.QuestionTypeDate:
+		return []exportField{answerField(name, question.Text, export.KindString, answerOf,
+			func(answer *models.Answer) interface{} {
+				if answer.AnswerValue.Date != nil {
+					return answer.AnswerValue.Date.Format("2006-01-02")
+				}
+				return textOrNil(answer.AnswerText)
+			})}
+
+	case models.QuestionTypeFileUpload:
+		return []exportField{answerField(name, question.Text, export.KindString, answerOf,
+			func(answer *models.Answer) interface{} {
+				urls := make([]string, len(answer.AnswerValue.Files))
+				for i, file := range answer.AnswerValue.Files {
+					urls[i] = file.URL
+				}
+				return textOrNil(strings.Join(urls, " "))
+			})}
+
+	default:
+		// Single choice, yes/no and free text answers are stored as text already
+		return []exportField{answerField(name, question.Text, export.KindString, answerOf,
+			func(answer *models.Answer) interface{} {
+				return textOrNil(answer.AnswerText)
+			})}
+	}
+}
+
+func numberField(name, label string, value func(r *models.Response) interface{}) exportField {
+	return responseField(name, label, export.KindNumber, value)
+}
+
+func stringField(name, label string, value func(r *models.Response) interface{}) exportField {
+	return responseField(name, label, export.KindString, value)
+}
+
+func responseField(name, label string, kind export.ColumnKind, value func(r *models.Response) interface{}) exportField {
+	return exportField{
+		column: export.Column{Name: name, Label: label, Kind: kind},
+		value: func(r *models.Response, _ map[uint]*models.Answer) interface{} {
+			return value(r)
+		},
+	}
+}
+
+// answerField builds a question column; value is only called for answers
+// that were given, unanswered and skipped questions export as missing
+func answerField(
+	name, label string,
+	kind export.ColumnKind,
+	answerOf func(answers map[uint]*models.Answer) *models.Answer,
+	value func(answer *models.Answer) interface{},
+) exportField {
+	return exportField{
+		column: export.Column{Name: name, Label: label, Kind: kind},
+		value: func(_ *models.Response, answers map[uint]*models.Answer) interface{} {
+			answer := answerOf(answers)
+			if answer == nil {
+				return nil
+			}
+			return value(answer)
+		},
+	}
+}
+
+func optionLabel(question *models.Question, option models.QuestionOption) string {
+	label := option.Label
+	if label == "" {
+		label = option.Value
+	}
+	return question.Text + " - " + label
+}
+
+func exportTime(t *time.Time) interface{} {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func textOrNil(text string) interface{} {
+	if text == "" {
+		return nil
+	}
+	return text
+}
@@ -0,0 +1,359 @@
+// internal/service/export_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/export"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// exportBatchSize is the number of responses loaded from the database at a time
+	exportBatchSize = 500
+
+	// exportPollInterval is how often idle workers look for pending jobs,
+	// such as those started on another server
+	exportPollInterval = 5 * time.Second
+
+	// exportHeartbeatInterval is how often a job being worked on is touched.
+	// A processing job that hasn't been touched for exportStaleAfter is
+	// assumed to belong to a server that stopped, and is requeued.
+	exportHeartbeatInterval = 30 * time.Second
+	exportStaleAfter        = 2 * time.Minute
+)
+
+// ErrExportNotReady is returned when downloading an export job that hasn't completed
+var ErrExportNotReady = apperror.New(apperror.ExportNotReady, "The export has not completed yet")
+
+var errExportForbidden = apperror.New(apperror.Forbidden, "You don't have permission to access this export")
+
+type ExportService interface {
+	// ExportResponses streams the export to the writer returned by open, or
+	// starts a background job and returns it when the survey has more
+	// responses than can be exported inline (or async is requested)
+	ExportResponses(userID, surveyID uint, format string, async bool, open func(fileName string) io.Writer) (*dto.ExportJobResponse, error)
+	GetExportJob(userID, jobID uint) (*dto.ExportJobResponse, error)
+	GetExportFile(userID, jobID uint) (*models.ExportJob, error)
+
+	// Run works through pending export jobs on the configured number of
+	// workers until ctx is done
+	Run(ctx context.Context)
+}
+
+type exportService struct {
+	exportJobRepo repository.ExportJobRepository
+	surveyRepo    repository.SurveyRepository
+	responseRepo  repository.ResponseRepository
+	config        config.ExportConfig
+	wake          chan struct{}
+}
+
+func NewExportService(
+	exportJobRepo repository.ExportJobRepository,
+	surveyRepo repository.SurveyRepository,
+	responseRepo repository.ResponseRepository,
+	config config.ExportConfig,
+) ExportService {
+	return &exportService{
+		exportJobRepo: exportJobRepo,
+		surveyRepo:    surveyRepo,
+		responseRepo:  responseRepo,
+		config:        config,
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+func (s *exportService) ExportResponses(userID, surveyID uint, format string, async bool, open func(fileName string) io.Writer) (*dto.ExportJobResponse, error) {
+	if !export.IsValidFormat(format) {
+		return nil, apperror.Newf(apperror.InvalidFormat, "Unsupported export format %q", format)
+	}
+
+	survey, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if survey.CreatorID != userID {
+		return nil, errSurveyForbidden
+	}
+
+	count, err := s.responseRepo.CountBySurveyID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := fmt.Sprintf("survey-%d-responses.%s", surveyID, format)
+	if !async && count <= int64(s.config.SyncMaxResponses) {
+		_, err := s.writeResponses(context.Background(), survey, format, open(fileName))
+		return nil, err
+	}
+
+	job := &models.ExportJob{
+		SurveyID:    surveyID,
+		RequestedBy: userID,
+		Format:      format,
+		Status:      models.ExportJobStatusPending,
+		FileName:    fileName,
+	}
+	if err := s.exportJobRepo.Create(job); err != nil {
+		return nil, fmt.Errorf("failed to create export job: %w", err)
+	}
+
+	s.wakeUp()
+
+	return exportJobToDTO(job), nil
+}
+
+func (s *exportService) GetExportJob(userID, jobID uint) (*dto.ExportJobResponse, error) {
+	job, err := s.exportJobRepo.GetByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.RequestedBy != userID {
+		return nil, errExportForbidden
+	}
+
+	return exportJobToDTO(job), nil
+}
+
+func (s *exportService) GetExportFile(userID, jobID uint) (*models.ExportJob, error) {
+	job, err := s.exportJobRepo.GetByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.RequestedBy != userID {
+		return nil, errExportForbidden
+	}
+
+	if job.Status != models.ExportJobStatusCompleted {
+		return nil, ErrExportNotReady
+	}
+
+	return job, nil
+}
+
+func (s *exportService) Run(ctx context.Context) {
+	// Jobs left processing by a server that stopped are picked up again
+	s.requeueStale()
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(exportStaleAfter)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+			s.requeueStale()
+		}
+	}
+}
+
+// work runs claimed jobs one at a time until ctx is done
+func (s *exportService) work(ctx context.Context) {
+	ticker := time.NewTicker(exportPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			job, err := s.exportJobRepo.ClaimPending()
+			if err != nil {
+				logrus.WithError(err).Error("Failed to claim export job")
+				break
+			}
+			if job == nil {
+				break
+			}
+			s.runExportJob(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+func (s *exportService) requeueStale() {
+	requeued, err := s.exportJobRepo.RequeueStale(time.Now().Add(-exportStaleAfter))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to requeue stale export jobs")
+		return
+	}
+	if requeued > 0 {
+		logrus.WithField("jobs", requeued).Warn("Requeued stale export jobs")
+		s.wakeUp()
+	}
+}
+
+// wakeUp lets an idle worker know a job is pending
+func (s *exportService) wakeUp() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// runExportJob writes the export of a claimed job to the export directory
+// and records the outcome on the job. A job interrupted because ctx is done
+// is put back in the queue.
+func (s *exportService) runExportJob(ctx context.Context, job *models.ExportJob) {
+	log := logrus.WithFields(logrus.Fields{
+		"export_job_id": job.ID,
+		"survey_id":     job.SurveyID,
+		"format":        job.Format,
+	})
+
+	// Keep the job from being requeued as stale while it is written
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(exportHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := s.exportJobRepo.Touch(job.ID); err != nil {
+					log.WithError(err).Error("Failed to touch export job")
+				}
+			}
+		}
+	}()
+
+	rowCount, size, err := s.writeExportFile(ctx, job)
+	switch {
+	case ctx.Err() != nil:
+		log.Info("Response export interrupted, requeueing it")
+		job.Status = models.ExportJobStatusPending
+	case err != nil:
+		log.WithError(err).Error("Response export failed")
+		job.MarkAsFailed(err.Error())
+	default:
+		log.WithField("rows", rowCount).Info("Response export completed")
+		job.MarkAsCompleted(rowCount, size)
+	}
+
+	if err := s.exportJobRepo.Update(job); err != nil {
+		log.WithError(err).Error("Failed to update export job")
+	}
+}
+
+func (s *exportService) writeExportFile(ctx context.Context, job *models.ExportJob) (int, int64, error) {
+	survey, err := s.surveyRepo.GetByID(job.SurveyID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := os.MkdirAll(s.config.Dir, 0o755); err != nil {
+		return 0, 0, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	job.FilePath = filepath.Join(s.config.Dir, fmt.Sprintf("export-%d.%s", job.ID, job.Format))
+	file, err := os.Create(job.FilePath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create export file: %w", err)
+	}
+
+	rowCount, err := s.writeResponses(ctx, survey, job.Format, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(job.FilePath)
+		job.FilePath = ""
+		return 0, 0, err
+	}
+
+	info, err := os.Stat(job.FilePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	return rowCount, info.Size(), nil
+}
+
+// writeResponses streams every response of the survey to w in batches,
+// stopping between batches once ctx is done
+func (s *exportService) writeResponses(ctx context.Context, survey *models.Survey, format string, w io.Writer) (int, error) {
+	fields := buildExportFields(survey)
+	columns := make([]export.Column, len(fields))
+	for i, field := range fields {
+		columns[i] = field.column
+	}
+
+	writer, err := export.NewWriter(format, w, survey.Title, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	rowCount := 0
+	values := make([]interface{}, len(fields))
+	err = s.responseRepo.FindInBatchesBySurveyID(survey.ID, exportBatchSize, func(responses []models.Response) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for i := range responses {
+			response := &responses[i]
+			answers := make(map[uint]*models.Answer, len(response.Answers))
+			for j := range response.Answers {
+				answers[response.Answers[j].QuestionID] = &response.Answers[j]
+			}
+
+			for j, field := range fields {
+				values[j] = field.value(response, answers)
+			}
+			if err := writer.WriteRow(values); err != nil {
+				return err
+			}
+			rowCount++
+		}
+		return nil
+	})
+	if err != nil {
+		return rowCount, fmt.Errorf("failed to export responses: %w", err)
+	}
+
+	return rowCount, writer.Close()
+}
+
+func exportJobToDTO(job *models.ExportJob) *dto.ExportJobResponse {
+	return &dto.ExportJobResponse{
+		ID:          job.ID,
+		SurveyID:    job.SurveyID,
+		Format:      job.Format,
+		Status:      string(job.Status),
+		FileName:    job.FileName,
+		RowCount:    job.RowCount,
+		SizeBytes:   job.SizeBytes,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+	}
+}
@@ -0,0 +1,196 @@
+// internal/service/export_service_test.go
+package service
+
+import (
+	"context"
+	"os"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/export"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryExportJobs keeps export jobs in memory. Touch is a no-op, as the
+// tests finish long before a job could go stale.
+type memoryExportJobs struct {
+	repository.ExportJobRepository
+	mu   sync.Mutex
+	jobs map[uint]models.ExportJob
+}
+
+func (r *memoryExportJobs) Create(job *models.ExportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job.ID = uint(len(r.jobs) + 1)
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+func (r *memoryExportJobs) Update(job *models.ExportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+func (r *memoryExportJobs) GetByID(id uint) (*models.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[id]
+	return &job, nil
+}
+
+func (r *memoryExportJobs) ClaimPending() (*models.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id := uint(1); id <= uint(len(r.jobs)); id++ {
+		if job := r.jobs[id]; job.Status == models.ExportJobStatusPending {
+			job.Status = models.ExportJobStatusProcessing
+			r.jobs[id] = job
+			return &job, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryExportJobs) Touch(uint) error {
+	return nil
+}
+
+func (r *memoryExportJobs) RequeueStale(time.Time) (int64, error) {
+	return 0, nil
+}
+
+// memoryResponses serves a fixed set of responses in batches of one. When
+// paused is set, it blocks after the first batch until paused is closed.
+type memoryResponses struct {
+	repository.ResponseRepository
+	responses []models.Response
+	started   chan struct{}
+	paused    chan struct{}
+}
+
+func (r *memoryResponses) CountBySurveyID(uint) (int64, error) {
+	return int64(len(r.responses)), nil
+}
+
+func (r *memoryResponses) FindInBatchesBySurveyID(surveyID uint, batchSize int, fn func(responses []models.Response) error) error {
+	for i := range r.responses {
+		if err := fn(r.responses[i : i+1]); err != nil {
+			return err
+		}
+		if i == 0 && r.paused != nil {
+			close(r.started)
+			<-r.paused
+		}
+	}
+	return nil
+}
+
+func newExportTestService(t *testing.T, responses *memoryResponses) (ExportService, *memoryExportJobs, uint) {
+	_, surveys := newDefinitionTestService()
+	surveyID := seedSurvey(surveys, 7)
+	for i := range responses.responses {
+		responses.responses[i].SurveyID = surveyID
+	}
+
+	jobs := &memoryExportJobs{jobs: map[uint]models.ExportJob{}}
+	cfg := config.ExportConfig{Dir: t.TempDir(), SyncMaxResponses: 0, Workers: 2}
+	return NewExportService(jobs, surveys, responses, cfg), jobs, surveyID
+}
+
+// startExportWorkers runs the service's workers until the returned stop is called
+func startExportWorkers(service ExportService) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		service.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func waitForExportJob(t *testing.T, jobs *memoryExportJobs, id uint, status models.ExportJobStatus) models.ExportJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, _ := jobs.GetByID(id)
+		if job.Status == status {
+			return *job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("export job is %s, want %s", job.Status, status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestExportWorkersRunQueuedJobs(t *testing.T) {
+	responses := &memoryResponses{responses: make([]models.Response, 3)}
+	service, jobs, surveyID := newExportTestService(t, responses)
+	stop := startExportWorkers(service)
+	defer stop()
+
+	started, err := service.ExportResponses(7, surveyID, export.FormatCSV, true, nil)
+	if err != nil {
+		t.Fatalf("ExportResponses: %v", err)
+	}
+	if started == nil || started.Status != string(models.ExportJobStatusPending) {
+		t.Fatalf("ExportResponses = %+v, want a pending job", started)
+	}
+
+	job := waitForExportJob(t, jobs, started.ID, models.ExportJobStatusCompleted)
+	if job.RowCount != 3 {
+		t.Errorf("row count = %d, want 3", job.RowCount)
+	}
+	if info, err := os.Stat(job.FilePath); err != nil || info.Size() != job.SizeBytes {
+		t.Errorf("export file %q: %v, want %d bytes", job.FilePath, err, job.SizeBytes)
+	}
+}
+
+func TestExportWorkersRequeueInterruptedJobs(t *testing.T) {
+	responses := &memoryResponses{
+		responses: make([]models.Response, 3),
+		started:   make(chan struct{}),
+		paused:    make(chan struct{}),
+	}
+	service, jobs, surveyID := newExportTestService(t, responses)
+	stop := startExportWorkers(service)
+
+	started, err := service.ExportResponses(7, surveyID, export.FormatCSV, true, nil)
+	if err != nil {
+		t.Fatalf("ExportResponses: %v", err)
+	}
+	<-responses.started
+
+	// Stop the workers while the job is half written
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(responses.paused)
+	}()
+	stop()
+
+	job, _ := jobs.GetByID(started.ID)
+	if job.Status != models.ExportJobStatusPending {
+		t.Fatalf("interrupted job is %s, want it requeued as pending", job.Status)
+	}
+	if entries, _ := os.ReadDir(exportDir(service)); len(entries) != 0 {
+		t.Errorf("the partial export file was left behind: %v", entries)
+	}
+
+	// The next workers pick it up again
+	responses.paused = nil
+	stop = startExportWorkers(service)
+	defer stop()
+	waitForExportJob(t, jobs, started.ID, models.ExportJobStatusCompleted)
+}
+
+func exportDir(service ExportService) string {
+	return service.(*exportService).config.Dir
+}
@@ -0,0 +1,270 @@
+// internal/service/maintenance_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"survey2earn-backend/internal/wallet"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Demo data is owned by these wallets, so seeding it twice finds it again
+const (
+	demoCreatorWallet    = "0x00000000000000000000000000000000000de301"
+	demoRespondentWallet = "0x00000000000000000000000000000000000de302"
+	demoSurveyCount      = 3
+)
+
+// balanceTolerance absorbs float rounding when comparing balances
+const balanceTolerance = 1e-6
+
+// DemoSeedResult describes the demo data created by SeedDemoData
+type DemoSeedResult struct {
+	CreatorID    uint
+	RespondentID uint
+	SurveyIDs    []uint
+}
+
+// BalanceDiscrepancy is a user whose recorded balance differs from the
+// reward ledger
+type BalanceDiscrepancy struct {
+	UserID   uint
+	Recorded models.UserBalance
+	Expected models.UserBalance
+	// Missing is set when the user has no balance row yet
+	Missing bool
+	// UserTotalEarned is the total earned recorded on the user
+	UserTotalEarned float64
+}
+
+// MaintenanceService holds the operator tasks run from survey2earnctl:
+// seeding, granting admin rights and repairing derived data
+type MaintenanceService interface {
+	SeedDemoData() (*DemoSeedResult, error)
+	CreateAdmin(walletAddress string) (user *models.User, created bool, err error)
+	RecomputeSurveyStatistics(surveyID uint) (int64, error)
+	RecomputeUserStats(userID uint) (int64, error)
+	ReconcileBalances(fix bool) ([]BalanceDiscrepancy, error)
+	ExpireStaleResponses(olderThan time.Duration) (int, error)
+	RetryFailedRewards() (requeued, exhausted int64, err error)
+}
+
+type maintenanceService struct {
+	maintenanceRepo repository.MaintenanceRepository
+	userRepo        repository.UserRepository
+	responseRepo    repository.ResponseRepository
+	rewardRepo      repository.RewardRepository
+	surveyService   SurveyService
+	templateService TemplateService
+}
+
+func NewMaintenanceService(
+	maintenanceRepo repository.MaintenanceRepository,
+	userRepo repository.UserRepository,
+	responseRepo repository.ResponseRepository,
+	rewardRepo repository.RewardRepository,
+	surveyService SurveyService,
+	templateService TemplateService,
+) MaintenanceService {
+	return &maintenanceService{
+		maintenanceRepo: maintenanceRepo,
+		userRepo:        userRepo,
+		responseRepo:    responseRepo,
+		rewardRepo:      rewardRepo,
+		surveyService:   surveyService,
+		templateService: templateService,
+	}
+}
+
+// SeedDemoData creates a demo creator with surveys published from the
+// platform templates, and a demo respondent to answer them. It does nothing
+// if the demo creator already has surveys.
+func (s *maintenanceService) SeedDemoData() (*DemoSeedResult, error) {
+	creator, _, err := s.findOrCreateUser(demoCreatorWallet)
+	if err != nil {
+		return nil, err
+	}
+	respondent, _, err := s.findOrCreateUser(demoRespondentWallet)
+	if err != nil {
+		return nil, err
+	}
+	result := &DemoSeedResult{CreatorID: creator.ID, RespondentID: respondent.ID}
+
+	existing, err := s.surveyService.GetUserSurveys(creator.ID, "", 1, demoSurveyCount)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Total > 0 {
+		return result, nil
+	}
+
+	templates, err := s.templateService.ListTemplates(creator.ID, &dto.ListTemplatesRequest{
+		Scope: "platform",
+		Page:  1,
+		Limit: demoSurveyCount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(templates.Templates) == 0 {
+		return nil, errors.New("no platform templates to build demo surveys from, run the platform seed first")
+	}
+
+	for _, template := range templates.Templates {
+		survey, err := s.templateService.CreateSurveyFromTemplate(creator.ID, template.ID, &dto.CreateFromTemplateRequest{
+			RewardAmount:    5,
+			MaxParticipants: 100,
+			IsAnonymous:     true,
+			IsPublic:        true,
+			RequireLogin:    true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create a demo survey from template %d: %w", template.ID, err)
+		}
+		if _, err := s.surveyService.PublishSurvey(creator.ID, survey.ID, &dto.PublishSurveyRequest{}); err != nil {
+			return nil, fmt.Errorf("failed to publish demo survey %d: %w", survey.ID, err)
+		}
+		result.SurveyIDs = append(result.SurveyIDs, survey.ID)
+	}
+
+	return result, nil
+}
+
+// CreateAdmin makes the wallet's user an admin, registering the wallet first
+// if needed
+func (s *maintenanceService) CreateAdmin(walletAddress string) (*models.User, bool, error) {
+	user, created, err := s.findOrCreateUser(walletAddress)
+	if err != nil {
+		return nil, false, err
+	}
+	if user.IsAdmin {
+		return user, created, nil
+	}
+
+	user.IsAdmin = true
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, false, err
+	}
+	return user, created, nil
+}
+
+func (s *maintenanceService) findOrCreateUser(walletAddress string) (*models.User, bool, error) {
+	address, err := wallet.NormalizeAddress(walletAddress)
+	if err != nil {
+		return nil, false, err
+	}
+
+	user, err := s.userRepo.GetByWalletAddress(address)
+	if err == nil {
+		return user, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, false, err
+	}
+	user = &models.User{
+		WalletAddress: address,
+		Nonce:         nonce,
+		IsActive:      true,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+// RecomputeSurveyStatistics rebuilds the response summaries of a survey, or of
+// every survey when surveyID is 0, and returns how many surveys it updated
+func (s *maintenanceService) RecomputeSurveyStatistics(surveyID uint) (int64, error) {
+	return s.maintenanceRepo.RecomputeSurveyStatistics(surveyID)
+}
+
+// RecomputeUserStats rebuilds the statistics of a user, or of every user when
+// userID is 0, and returns how many users it updated
+func (s *maintenanceService) RecomputeUserStats(userID uint) (int64, error) {
+	return s.maintenanceRepo.RecomputeUserStats(userID)
+}
+
+// ReconcileBalances compares every user's recorded balance with the reward
+// ledger and returns the users whose balances differ. With fix, the recorded
+// balances are rewritten from the ledger.
+func (s *maintenanceService) ReconcileBalances(fix bool) ([]BalanceDiscrepancy, error) {
+	var discrepancies []BalanceDiscrepancy
+	err := s.maintenanceRepo.FindBalanceLedgersInBatches(500, func(ledgers []repository.BalanceLedger) error {
+		var fixes []models.UserBalance
+		for _, ledger := range ledgers {
+			expected := models.UserBalance{
+				UserID:           ledger.UserID,
+				TotalEarned:      ledger.Earned,
+				TotalWithdrawn:   ledger.Withdrawn,
+				PendingBalance:   ledger.Pending,
+				AvailableBalance: ledger.Earned - ledger.Withdrawn - ledger.Pending,
+			}
+			recorded := models.UserBalance{
+				UserID:           ledger.UserID,
+				TotalEarned:      ledger.BalanceEarned,
+				TotalWithdrawn:   ledger.BalanceWithdrawn,
+				PendingBalance:   ledger.BalancePending,
+				AvailableBalance: ledger.BalanceAvailable,
+			}
+
+			// Users who never earned anything need no balance row
+			missing := !ledger.HasBalance && (expected.TotalEarned != 0 || expected.PendingBalance != 0)
+			if !missing && balancesMatch(recorded, expected) && sameAmount(ledger.UserTotalEarned, expected.TotalEarned) {
+				continue
+			}
+
+			discrepancies = append(discrepancies, BalanceDiscrepancy{
+				UserID:          ledger.UserID,
+				Recorded:        recorded,
+				Expected:        expected,
+				Missing:         !ledger.HasBalance,
+				UserTotalEarned: ledger.UserTotalEarned,
+			})
+			fixes = append(fixes, expected)
+		}
+
+		if !fix {
+			return nil
+		}
+		return s.maintenanceRepo.SaveBalances(fixes)
+	})
+	return discrepancies, err
+}
+
+func balancesMatch(a, b models.UserBalance) bool {
+	return sameAmount(a.TotalEarned, b.TotalEarned) &&
+		sameAmount(a.TotalWithdrawn, b.TotalWithdrawn) &&
+		sameAmount(a.PendingBalance, b.PendingBalance) &&
+		sameAmount(a.AvailableBalance, b.AvailableBalance)
+}
+
+func sameAmount(a, b float64) bool {
+	return math.Abs(a-b) < balanceTolerance
+}
+
+// ExpireStaleResponses abandons responses in progress that have been idle
+// for longer than olderThan, and returns how many it abandoned
+func (s *maintenanceService) ExpireStaleResponses(olderThan time.Duration) (int, error) {
+	if olderThan <= 0 {
+		return 0, errors.New("the idle time of stale responses must be positive")
+	}
+	return s.responseRepo.AbandonStale(time.Now().Add(-olderThan), 100)
+}
+
+// RetryFailedRewards puts failed reward transactions with retries left back
+// in the pending queue. It returns how many were requeued and how many have
+// no retries left and need a look.
+func (s *maintenanceService) RetryFailedRewards() (int64, int64, error) {
+	return s.rewardRepo.RequeueFailedTransactions()
+}
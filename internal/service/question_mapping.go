@@ -0,0 +1,263 @@
+// internal/service/question_mapping.go
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+)
+
+func buildQuestions(surveyID uint, reqs []dto.CreateQuestionRequest) []models.Question {
+	questions := make([]models.Question, len(reqs))
+	for i, q := range reqs {
+		var fileConfig *models.FileUploadConfig
+		if q.FileConfig != nil {
+			fileConfig = &models.FileUploadConfig{
+				AllowedTypes: q.FileConfig.AllowedTypes,
+				MaxSizeBytes: q.FileConfig.MaxSizeBytes,
+				MaxFiles:     q.FileConfig.MaxFiles,
+			}
+		}
+
+		questions[i] = models.Question{
+			SurveyID:    surveyID,
+			Type:        models.QuestionType(q.Type),
+			Text:        q.Title,
+			Description: q.Description,
+			Options:     buildOptions(q.Options),
+			Required:    q.Required,
+			Order:       q.Order,
+			MinLength:   q.MinLength,
+			MaxLength:   q.MaxLength,
+			MinValue:    q.MinValue,
+			MaxValue:    q.MaxValue,
+			Rows:        buildOptions(q.Rows),
+			SumTotal:    q.SumTotal,
+			FileConfig:  fileConfig,
+		}
+
+		// NPS questions always use the standard 0-10 range
+		if questions[i].Type == models.QuestionTypeNPS {
+			minValue, maxValue := float64(models.NPSMinScore), float64(models.NPSMaxScore)
+			questions[i].MinValue = &minValue
+			questions[i].MaxValue = &maxValue
+		}
+	}
+	return questions
+}
+
+func buildOptions(reqs []dto.QuestionOptionRequest) models.QuestionOptions {
+	options := make(models.QuestionOptions, len(reqs))
+	for i, opt := range reqs {
+		options[i] = models.QuestionOption{
+			ID:    opt.ID,
+			Label: opt.Label,
+			Value: opt.Value,
+			Order: opt.Order,
+		}
+	}
+	return options
+}
+
+func questionToDTO(q *models.Question) dto.QuestionResponse {
+	var fileConfig *dto.FileUploadConfigResponse
+	if q.FileConfig != nil {
+		fileConfig = &dto.FileUploadConfigResponse{
+			AllowedTypes: q.FileConfig.AllowedTypes,
+			MaxSizeBytes: q.FileConfig.MaxSizeBytes,
+			MaxFiles:     q.FileConfig.MaxFiles,
+		}
+	}
+
+	return dto.QuestionResponse{
+		ID:          q.ID,
+		Type:        string(q.Type),
+		Text:        q.Text,
+		Description: q.Description,
+		Required:    q.Required,
+		Order:       q.Order,
+		Options:     optionsToDTO(q.Options),
+		MinLength:   q.MinLength,
+		MaxLength:   q.MaxLength,
+		MinValue:    q.MinValue,
+		MaxValue:    q.MaxValue,
+		Rows:        optionsToDTO(q.Rows),
+		SumTotal:    q.SumTotal,
+		FileConfig:  fileConfig,
+		ShowIf:      conditionalLogicToDTO(q.ShowIf),
+	}
+}
+
+func conditionalLogicToDTO(logic *models.ConditionalLogic) *dto.ConditionalLogicResponse {
+	if logic == nil {
+		return nil
+	}
+	questionID, _ := strconv.ParseUint(logic.QuestionID, 10, 32)
+	return &dto.ConditionalLogicResponse{
+		QuestionID: uint(questionID),
+		Operator:   logic.Operator,
+		Value:      logic.Value,
+	}
+}
+
+func optionsToDTO(options models.QuestionOptions) []dto.QuestionOptionResponse {
+	items := make([]dto.QuestionOptionResponse, len(options))
+	for i, opt := range options {
+		items[i] = dto.QuestionOptionResponse{
+			ID:    opt.ID,
+			Label: opt.Label,
+			Value: opt.Value,
+			Order: opt.Order,
+		}
+	}
+	return items
+}
+
+// definitionToRequest converts a question bank or template definition into a
+// question creation request so it goes through the regular survey creation path
+func definitionToRequest(d models.QuestionDefinition) dto.CreateQuestionRequest {
+	var fileConfig *dto.FileUploadConfigRequest
+	if d.FileConfig != nil {
+		fileConfig = &dto.FileUploadConfigRequest{
+			AllowedTypes: d.FileConfig.AllowedTypes,
+			MaxSizeBytes: d.FileConfig.MaxSizeBytes,
+			MaxFiles:     d.FileConfig.MaxFiles,
+		}
+	}
+
+	return dto.CreateQuestionRequest{
+		Type:        string(d.Type),
+		Title:       d.Text,
+		Description: d.Description,
+		Required:    d.Required,
+		Options:     optionsToRequest(d.Options),
+		MinLength:   d.MinLength,
+		MaxLength:   d.MaxLength,
+		MinValue:    d.MinValue,
+		MaxValue:    d.MaxValue,
+		Order:       d.Order,
+		Rows:        optionsToRequest(d.Rows),
+		SumTotal:    d.SumTotal,
+		FileConfig:  fileConfig,
+	}
+}
+
+// requestToDefinition converts a question creation request into a survey-independent definition
+func requestToDefinition(req dto.CreateQuestionRequest) models.QuestionDefinition {
+	questions := buildQuestions(0, []dto.CreateQuestionRequest{req})
+	return models.NewQuestionDefinition(&questions[0])
+}
+
+func definitionToDTO(d models.QuestionDefinition) dto.QuestionResponse {
+	question := models.Question{
+		Type:        d.Type,
+		Text:        d.Text,
+		Description: d.Description,
+		Options:     d.Options,
+		Required:    d.Required,
+		Order:       d.Order,
+		MinLength:   d.MinLength,
+		MaxLength:   d.MaxLength,
+		MinValue:    d.MinValue,
+		MaxValue:    d.MaxValue,
+		Rows:        d.Rows,
+		SumTotal:    d.SumTotal,
+		FileConfig:  d.FileConfig,
+	}
+	return questionToDTO(&question)
+}
+
+func optionsToRequest(options models.QuestionOptions) []dto.QuestionOptionRequest {
+	items := make([]dto.QuestionOptionRequest, len(options))
+	for i, opt := range options {
+		items[i] = dto.QuestionOptionRequest{
+			ID:    opt.ID,
+			Label: opt.Label,
+			Value: opt.Value,
+			Order: opt.Order,
+		}
+	}
+	return items
+}
+
+// validateConditionalLogic checks that every display condition references an
+// earlier question by its order and uses a supported operator
+func validateConditionalLogic(reqs []dto.CreateQuestionRequest) error {
+	orders := make(map[int]bool, len(reqs))
+	for _, q := range reqs {
+		orders[q.Order] = true
+	}
+
+	for i, q := range reqs {
+		if q.ShowIf == nil {
+			continue
+		}
+		field := fmt.Sprintf("questions[%d].showIf", i)
+		if !orders[q.ShowIf.QuestionOrder] {
+			return invalidCondition(q.Order, field+".questionOrder", "not_found", fmt.Sprintf("condition references unknown question %d", q.ShowIf.QuestionOrder))
+		}
+		if q.ShowIf.QuestionOrder >= q.Order {
+			return invalidCondition(q.Order, field+".questionOrder", "invalid", "condition must reference an earlier question")
+		}
+		if !models.IsValidConditionOperator(q.ShowIf.Operator) {
+			return invalidCondition(q.Order, field+".operator", "oneof", fmt.Sprintf("unknown condition operator %q", q.ShowIf.Operator))
+		}
+	}
+	return nil
+}
+
+// invalidCondition reports a problem with the condition of a question
+func invalidCondition(order int, field, code, message string) error {
+	return apperror.Invalid(fmt.Sprintf("Question %d: %s", order, message), apperror.FieldError{
+		Field:   field,
+		Code:    code,
+		Message: message,
+	})
+}
+
+// resolveConditionalLogic sets ShowIf on saved questions, turning the
+// questionOrder references of the requests into question IDs. questions must
+// be built from reqs in the same order. It returns the questions that changed.
+func resolveConditionalLogic(questions []models.Question, reqs []dto.CreateQuestionRequest) []models.Question {
+	idByOrder := make(map[int]uint, len(questions))
+	for _, q := range questions {
+		idByOrder[q.Order] = q.ID
+	}
+
+	var changed []models.Question
+	for i, q := range reqs {
+		if q.ShowIf == nil || i >= len(questions) {
+			continue
+		}
+		questions[i].ShowIf = &models.ConditionalLogic{
+			QuestionID: strconv.FormatUint(uint64(idByOrder[q.ShowIf.QuestionOrder]), 10),
+			Operator:   q.ShowIf.Operator,
+			Value:      q.ShowIf.Value,
+		}
+		changed = append(changed, questions[i])
+	}
+	return changed
+}
+
+// conditionalLogicToRequest turns a stored display condition back into an
+// order-based reference
+func conditionalLogicToRequest(logic *models.ConditionalLogic, orderByQuestionID map[uint]int) *dto.ConditionalLogicRequest {
+	if logic == nil {
+		return nil
+	}
+	questionID, err := strconv.ParseUint(logic.QuestionID, 10, 32)
+	if err != nil {
+		return nil
+	}
+	order, ok := orderByQuestionID[uint(questionID)]
+	if !ok {
+		return nil
+	}
+	return &dto.ConditionalLogicRequest{
+		QuestionOrder: order,
+		Operator:      logic.Operator,
+		Value:         logic.Value,
+	}
+}
@@ -0,0 +1,113 @@
+// internal/service/response_events.go
+package service
+
+import (
+	"encoding/json"
+	"sort"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/events"
+	"survey2earn-backend/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SubscribeSurveyEvents streams the activity of a survey to its creator
+func (s *responseService) SubscribeSurveyEvents(userID, surveyID uint) (*events.Subscription, error) {
+	survey, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if survey.CreatorID != userID {
+		return nil, errSurveyForbidden
+	}
+
+	if s.eventBus == nil {
+		return nil, apperror.New(apperror.Unavailable, "Live events are not available")
+	}
+	return s.eventBus.Subscribe(surveyID), nil
+}
+
+// LiveEventRelay returns an outbox subscriber that publishes committed
+// response and pool events to the live subscribers of their survey
+func LiveEventRelay(bus *events.Bus) func(event models.OutboxEvent) {
+	return func(event models.OutboxEvent) {
+		if bus.Subscribers(event.SurveyID) == 0 {
+			return
+		}
+
+		var data interface{} = json.RawMessage(event.Payload)
+		switch event.Type {
+		case models.OutboxEventResponseStarted, models.OutboxEventResponseAbandoned,
+			models.OutboxEventResponseScreenedOut, models.OutboxEventPoolUpdated:
+		case models.OutboxEventResponseCompleted:
+			// Live subscribers get the summary of a completion, not its answers
+			var completed dto.ResponseCompletedEventData
+			if err := json.Unmarshal([]byte(event.Payload), &completed); err != nil {
+				logrus.WithError(err).WithField("event_id", event.EventID).Warn("Failed to decode completion for live update")
+				return
+			}
+			data = dto.ResponseEventData{
+				ResponseID:   completed.ResponseID,
+				Status:       string(models.ResponseStatusCompleted),
+				QualityScore: &completed.QualityScore,
+				Duration:     &completed.Duration,
+				RewardAmount: &completed.RewardAmount,
+			}
+		default:
+			return
+		}
+
+		bus.Publish(events.Event{
+			Type:     events.Type(event.Type),
+			SurveyID: event.SurveyID,
+			Data:     data,
+			Time:     event.CreatedAt,
+		})
+	}
+}
+
+func responseCompletedEventData(response *models.Response, survey *models.Survey, rewardAmount float64, xpEarned int) dto.ResponseCompletedEventData {
+	answers := make([]dto.AnswerEventData, 0, len(response.Answers))
+	for _, answer := range response.Answers {
+		data := dto.AnswerEventData{
+			QuestionID: answer.QuestionID,
+			Answer:     answer.AnswerText,
+			IsSkipped:  answer.IsSkipped,
+		}
+		if question, err := survey.GetQuestionByID(answer.QuestionID); err == nil {
+			data.Order = question.Order
+			data.Question = question.Text
+		}
+		answers = append(answers, data)
+	}
+	sort.Slice(answers, func(i, j int) bool {
+		return answers[i].Order < answers[j].Order
+	})
+
+	data := dto.ResponseCompletedEventData{
+		ResponseID:   response.ID,
+		SurveyID:     survey.ID,
+		UserID:       respondentID(response, survey),
+		QualityScore: response.QualityScore,
+		Duration:     response.Duration,
+		RewardAmount: rewardAmount,
+		XpEarned:     xpEarned,
+		Answers:      answers,
+	}
+	if response.CompletedAt != nil {
+		data.CompletedAt = *response.CompletedAt
+	}
+	return data
+}
+
+// respondentID identifies the respondent to the creator, unless the survey is anonymous
+func respondentID(response *models.Response, survey *models.Survey) *uint {
+	if survey.IsAnonymous {
+		return nil
+	}
+	userID := response.UserID
+	return &userID
+}
@@ -0,0 +1,98 @@
+// internal/service/response_filter.go
+package service
+
+import (
+	"strconv"
+	"strings"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"time"
+)
+
+// buildResponseFilter validates the creator's list filters and resolves
+// answer filters, which reference questions by their order, to question IDs
+func buildResponseFilter(survey *models.Survey, req *dto.ResponseFilterRequest) (*repository.ResponseFilter, error) {
+	filter := &repository.ResponseFilter{
+		IsValid:    req.IsValid,
+		MinQuality: req.MinQuality,
+		MaxQuality: req.MaxQuality,
+	}
+
+	if req.Status != "" {
+		switch models.ResponseStatus(req.Status) {
+		case models.ResponseStatusStarted, models.ResponseStatusCompleted,
+			models.ResponseStatusAbandoned, models.ResponseStatusScreenedOut:
+			filter.Status = req.Status
+		default:
+			return nil, &InvalidFilterError{Filter: "status", Reason: "unknown status " + strconv.Quote(req.Status)}
+		}
+	}
+
+	if req.MinQuality != nil && req.MaxQuality != nil && *req.MinQuality > *req.MaxQuality {
+		return nil, &InvalidFilterError{Filter: "quality", Reason: "min_quality is greater than max_quality"}
+	}
+
+	if req.StartDate != "" {
+		from, _, err := parseFilterDate(req.StartDate)
+		if err != nil {
+			return nil, &InvalidFilterError{Filter: "start_date", Reason: "expected YYYY-MM-DD or RFC 3339"}
+		}
+		filter.From = &from
+	}
+	if req.EndDate != "" {
+		to, dateOnly, err := parseFilterDate(req.EndDate)
+		if err != nil {
+			return nil, &InvalidFilterError{Filter: "end_date", Reason: "expected YYYY-MM-DD or RFC 3339"}
+		}
+		// A plain date includes the whole day
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, &InvalidFilterError{Filter: "date", Reason: "start_date must be before end_date"}
+	}
+
+	for _, raw := range req.Answers {
+		reference, value, found := strings.Cut(raw, ":")
+		if !found || value == "" {
+			return nil, &InvalidFilterError{Filter: "answer", Reason: "expected <question order>:<value>, got " + strconv.Quote(raw)}
+		}
+
+		order, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(reference)), "q"))
+		if err != nil {
+			return nil, &InvalidFilterError{Filter: "answer", Reason: "invalid question order " + strconv.Quote(reference)}
+		}
+		question := questionByOrder(survey, order)
+		if question == nil {
+			return nil, &InvalidFilterError{Filter: "answer", Reason: "survey has no question " + strconv.Itoa(order)}
+		}
+
+		filter.Answers = append(filter.Answers, repository.AnswerFilter{
+			QuestionID: question.ID,
+			Value:      value,
+		})
+	}
+
+	return filter, nil
+}
+
+// parseFilterDate accepts a date or an RFC 3339 timestamp and reports which it was
+func parseFilterDate(value string) (time.Time, bool, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, true, nil
+	}
+	timestamp, err := time.Parse(time.RFC3339, value)
+	return timestamp, false, err
+}
+
+func questionByOrder(survey *models.Survey, order int) *models.Question {
+	for i := range survey.Questions {
+		if survey.Questions[i].Order == order {
+			return &survey.Questions[i]
+		}
+	}
+	return nil
+}
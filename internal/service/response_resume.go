@@ -0,0 +1,251 @@
+// internal/service/response_resume.go
+package service
+
+import (
+	"context"
+	"errors"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// responseExpiryBatchSize is how many idle responses are abandoned per batch
+const responseExpiryBatchSize = 100
+
+// responseProgress is how far a response got through the questions its
+// answers make visible
+type responseProgress struct {
+	visible  []models.Question
+	answered int
+	next     *models.Question // first visible question without an answer
+}
+
+func newResponseProgress(survey *models.Survey, response *models.Response) responseProgress {
+	progress := responseProgress{visible: survey.VisibleQuestions(response.Answers)}
+
+	// Skipped questions count as answered, the respondent moved past them
+	answered := make(map[uint]bool, len(response.Answers))
+	for _, answer := range response.Answers {
+		answered[answer.QuestionID] = true
+	}
+	for i := range progress.visible {
+		switch {
+		case answered[progress.visible[i].ID]:
+			progress.answered++
+		case progress.next == nil:
+			progress.next = &progress.visible[i]
+		}
+	}
+	return progress
+}
+
+// percent is the share of visible questions answered, from 0 to 100
+func (p responseProgress) percent() float64 {
+	if len(p.visible) == 0 {
+		return 100
+	}
+	return float64(p.answered) / float64(len(p.visible)) * 100
+}
+
+// ResumeResponse returns where a response in progress was left: the answers
+// so far and the next question to answer, so it can be picked up on any
+// device
+func (s *responseService) ResumeResponse(userID, responseID uint) (*dto.ResumeResponse, error) {
+	response, err := s.responseRepo.GetWithAnswers(responseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if response.UserID != userID {
+		return nil, errResponseForbidden
+	}
+
+	// Only responses in progress can be resumed
+	if response.Status != models.ResponseStatusStarted {
+		return nil, repository.ErrResponseNotInProgress
+	}
+	if err := s.closeIfOver(response); err != nil {
+		return nil, err
+	}
+
+	survey, err := s.surveyRepo.GetByID(response.SurveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := newResponseProgress(survey, response)
+	var nextQuestion *dto.QuestionResponse
+	if progress.next != nil {
+		question := questionToDTO(progress.next)
+		nextQuestion = &question
+	}
+
+	return &dto.ResumeResponse{
+		ResponseID:        response.ID,
+		SurveyID:          response.SurveyID,
+		Status:            string(response.Status),
+		Version:           response.Version,
+		NextQuestion:      nextQuestion,
+		Answers:           s.answersToDTO(response.Answers),
+		Progress:          progress.percent(),
+		QuestionsTotal:    len(progress.visible),
+		QuestionsAnswered: progress.answered,
+		TimeSpent:         response.CalculateDuration(),
+		TimeLeft:          remainingTime(survey, response),
+		StartedAt:         response.StartedAt,
+		LastAnsweredAt:    response.LastAnsweredAt(),
+		Deadline:          response.Deadline,
+		ExpiresAt:         s.expiresAt(response),
+	}, nil
+}
+
+// Run closes the responses past their deadline and abandons the ones idle
+// for longer than the idle timeout, every expiry interval until ctx is done.
+// It returns right away when the expiry interval is 0.
+func (s *responseService) Run(ctx context.Context) {
+	if s.expiryInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.expiryInterval)
+	defer ticker.Stop()
+
+	for {
+		s.closePastDeadline()
+
+		if s.idleTimeout > 0 {
+			abandoned, err := s.responseRepo.AbandonStale(time.Now().Add(-s.idleTimeout), responseExpiryBatchSize)
+			if err != nil {
+				logrus.WithError(err).Error("Failed to expire idle responses")
+			} else if abandoned > 0 {
+				logrus.WithField("abandoned", abandoned).Info("Expired idle responses")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// closePastDeadline submits or abandons every response in progress past its
+// deadline. Responses that fail to close are retried on the next run.
+func (s *responseService) closePastDeadline() {
+	closed := 0
+	var lastID uint
+	for {
+		ids, err := s.responseRepo.GetPastDeadline(time.Now(), lastID, responseExpiryBatchSize)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to find responses past their deadline")
+			return
+		}
+
+		for _, id := range ids {
+			response, err := s.responseRepo.GetByID(id)
+			if err == nil {
+				err = s.submitAtDeadline(response)
+			}
+			if err != nil {
+				logrus.WithError(err).WithField("response_id", id).Error("Failed to close response past its deadline")
+				continue
+			}
+			closed++
+		}
+
+		if len(ids) < responseExpiryBatchSize {
+			break
+		}
+		lastID = ids[len(ids)-1]
+	}
+
+	if closed > 0 {
+		logrus.WithField("closed", closed).Info("Closed responses past their deadline")
+	}
+}
+
+// closeIfOver closes a response in progress that is past its deadline, or
+// abandons one idle for longer than the idle timeout, without waiting for
+// the next expiry run. It returns ErrTimeLimitExceeded or ErrResponseExpired
+// if it did.
+func (s *responseService) closeIfOver(response *models.Response) error {
+	if response.PastDeadline() {
+		if err := s.submitAtDeadline(response); err != nil {
+			return err
+		}
+		return ErrTimeLimitExceeded
+	}
+
+	if s.idleTimeout <= 0 || time.Since(response.LastActivity()) < s.idleTimeout {
+		return nil
+	}
+	response.MarkAsAbandoned()
+	if err := s.responseRepo.Update(response); err != nil {
+		return err
+	}
+	return ErrResponseExpired
+}
+
+// submitAtDeadline completes a response past its deadline with the answers
+// saved by then, or abandons it without any or with required questions left
+// unanswered. A response closed meanwhile, or screened out on completion,
+// counts as closed.
+func (s *responseService) submitAtDeadline(response *models.Response) error {
+	withAnswers, err := s.responseRepo.GetWithAnswers(response.ID)
+	if err != nil {
+		return err
+	}
+	survey, err := s.surveyRepo.GetByID(withAnswers.SurveyID)
+	if err != nil {
+		return err
+	}
+
+	if len(withAnswers.Answers) == 0 || len(survey.MissingRequiredAnswers(withAnswers.Answers)) > 0 {
+		withAnswers.MarkAsAbandoned()
+		err = s.responseRepo.Update(withAnswers)
+	} else {
+		_, err = s.complete(withAnswers, survey)
+	}
+
+	var screenedOut *ScreenedOutError
+	if errors.Is(err, repository.ErrResponseNotInProgress) || errors.As(err, &screenedOut) {
+		return nil
+	}
+	return err
+}
+
+// expiresAt is when a response in progress is abandoned if left idle, nil
+// when it isn't in progress or the expiry is disabled
+func (s *responseService) expiresAt(response *models.Response) *time.Time {
+	if s.idleTimeout <= 0 || response.Status != models.ResponseStatusStarted {
+		return nil
+	}
+	expiresAt := response.LastActivity().Add(s.idleTimeout)
+	return &expiresAt
+}
+
+// remainingTime is how many seconds are left until the response's deadline
+// or, without one, until the survey's estimated duration is up. It is nil
+// without either, or once the estimated duration is up.
+func remainingTime(survey *models.Survey, response *models.Response) *int {
+	if response.Deadline != nil {
+		remaining := int(time.Until(*response.Deadline).Seconds())
+		if remaining < 0 {
+			remaining = 0
+		}
+		return &remaining
+	}
+	if survey.EstimatedDuration <= 0 {
+		return nil
+	}
+	remaining := survey.EstimatedDuration*60 - response.CalculateDuration()
+	if remaining <= 0 {
+		return nil
+	}
+	return &remaining
+}
@@ -0,0 +1,990 @@
+// internal/service/response_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/events"
+	"survey2earn-backend/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type ResponseService interface {
+	StartSurvey(userID, surveyID uint, req *dto.StartSurveyRequest) (*dto.ResponseStartResponse, error)
+	SubmitAnswers(userID uint, responseID uint, answers []dto.SubmitAnswerRequest) error
+	CompleteSurvey(userID uint, req *dto.CompleteSurveyRequest) (*dto.CompletionResponse, error)
+	GetResponse(userID, responseID uint) (*dto.SurveyResponseResponse, error)
+	GetUserResponses(userID uint, req *dto.ListResponsesRequest) (*dto.ResponseListResponse, error)
+	GetSurveyResponses(userID, surveyID uint, req *dto.ListSurveyResponsesRequest) (*dto.CreatorResponseListResponse, error)
+	GetSurveyResponse(userID, surveyID, responseID uint) (*dto.CreatorResponseResponse, error)
+	GetResponseProgress(userID, responseID uint) (*dto.SurveyProgressResponse, error)
+	ResumeResponse(userID, responseID uint) (*dto.ResumeResponse, error)
+	UpdateAnswer(userID, responseID, questionID uint, req *dto.UpdateAnswerRequest) (*dto.AnswerSavedResponse, error)
+	AbandonSurvey(userID, responseID uint) error
+	SubscribeSurveyEvents(userID, surveyID uint) (*events.Subscription, error)
+	Run(ctx context.Context)
+}
+
+var (
+	// ErrVersionConflict is returned when an answer was made on another
+	// version of the response than the current one
+	ErrVersionConflict = repository.ErrVersionConflict
+
+	// ErrResponseExpired is returned when a response in progress has been
+	// idle for too long and was abandoned
+	ErrResponseExpired = apperror.New(apperror.ResponseExpired, "The response expired after being idle for too long")
+
+	// ErrTimeLimitExceeded is returned when answering a response past its
+	// deadline. The response is closed with the answers saved by then.
+	ErrTimeLimitExceeded = apperror.New(apperror.TimeLimitExceeded, "The survey's time limit is up, the answers saved before it were submitted")
+)
+
+// ScreenedOutError is returned when a respondent falls into a full quota
+type ScreenedOutError struct {
+	ResponseID uint
+	Quota      string
+}
+
+func (e *ScreenedOutError) Error() string {
+	return fmt.Sprintf("response screened out: quota %q is full", e.Quota)
+}
+
+// InvalidFilterError is returned when a response list filter can't be applied
+type InvalidFilterError struct {
+	Filter string
+	Reason string
+}
+
+func (e *InvalidFilterError) Error() string {
+	return fmt.Sprintf("invalid %s filter: %s", e.Filter, e.Reason)
+}
+
+func (e *InvalidFilterError) AppError() *apperror.Error {
+	return &apperror.Error{
+		Code:    apperror.InvalidFilter,
+		Message: e.Error(),
+		Fields:  []apperror.FieldError{{Field: e.Filter, Code: "invalid", Message: e.Reason}},
+	}
+}
+
+type responseService struct {
+	responseRepo     repository.ResponseRepository
+	surveyRepo       repository.SurveyRepository
+	rewardRepo       repository.RewardRepository
+	userRepo         repository.UserRepository
+	textAnalysisRepo repository.TextAnalysisRepository
+	eventBus         *events.Bus
+	idleTimeout      time.Duration
+	expiryInterval   time.Duration
+}
+
+func NewResponseService(
+	responseRepo repository.ResponseRepository,
+	surveyRepo repository.SurveyRepository,
+	rewardRepo repository.RewardRepository,
+	userRepo repository.UserRepository,
+	textAnalysisRepo repository.TextAnalysisRepository,
+	eventBus *events.Bus,
+	config config.ResponseConfig,
+) ResponseService {
+	return &responseService{
+		responseRepo:     responseRepo,
+		surveyRepo:       surveyRepo,
+		rewardRepo:       rewardRepo,
+		userRepo:         userRepo,
+		textAnalysisRepo: textAnalysisRepo,
+		eventBus:         eventBus,
+		idleTimeout:      time.Duration(config.IdleTimeoutMinutes) * time.Minute,
+		expiryInterval:   time.Duration(config.ExpiryIntervalSeconds) * time.Second,
+	}
+}
+
+func (s *responseService) StartSurvey(userID, surveyID uint, req *dto.StartSurveyRequest) (*dto.ResponseStartResponse, error) {
+	// Get survey
+	survey, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if survey is active
+	if !survey.IsActive() {
+		return nil, apperror.New(apperror.SurveyNotActive, "The survey is not accepting responses")
+	}
+
+	// Check if user can participate
+	if survey.RequireLogin && userID == 0 {
+		return nil, apperror.New(apperror.Unauthorized, "Login is required to take this survey")
+	}
+
+	// Check if user already responded (if multiple responses not allowed)
+	if !survey.AllowMultiple {
+		exists, err := s.responseRepo.HasUserResponded(userID, surveyID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, apperror.New(apperror.AlreadyResponded, "You have already responded to this survey")
+		}
+	}
+
+	// Check if survey has reached max participants
+	if survey.ResponseCount >= survey.MaxResponses {
+		return nil, apperror.New(apperror.SurveyFull, "The survey has reached its maximum number of participants")
+	}
+
+	// Create response
+	response := &models.Response{
+		SurveyID:  surveyID,
+		UserID:    userID,
+		Status:    models.ResponseStatusStarted,
+		StartedAt: time.Now(),
+		IPAddress: req.IPAddress,
+		UserAgent: req.UserAgent,
+		Timezone:  req.Timezone,
+		Language:  req.Language,
+		IsValid:   true,
+	}
+
+	// Screen out respondents whose metadata falls into a full quota
+	if quota := survey.FullQuota(response, nil); quota != nil {
+		response.MarkAsScreenedOut("quota full: " + quota.Name)
+		if err := s.responseRepo.Create(response); err != nil {
+			return nil, err
+		}
+		return &dto.ResponseStartResponse{
+			ResponseID: response.ID,
+			SurveyID:   surveyID,
+			Status:     string(response.Status),
+			StartedAt:  response.StartedAt,
+			Message:    "Thank you for your interest. This survey is no longer looking for respondents like you.",
+		}, nil
+	}
+
+	// The time limit in force when the response starts is the one it keeps
+	if survey.TimeLimitMinutes != nil {
+		deadline := response.StartedAt.Add(time.Duration(*survey.TimeLimitMinutes) * time.Minute)
+		response.Deadline = &deadline
+	}
+
+	if err := s.responseRepo.Create(response); err != nil {
+		return nil, err
+	}
+
+	return &dto.ResponseStartResponse{
+		ResponseID: response.ID,
+		SurveyID:   surveyID,
+		Status:     string(response.Status),
+		StartedAt:  response.StartedAt,
+		TimeLeft:   remainingTime(survey, response),
+		Deadline:   response.Deadline,
+	}, nil
+}
+
+func (s *responseService) SubmitAnswers(userID uint, responseID uint, answers []dto.SubmitAnswerRequest) error {
+	// Get response
+	response, err := s.responseRepo.GetByID(responseID)
+	if err != nil {
+		return err
+	}
+
+	// Check ownership
+	if response.UserID != userID {
+		return errResponseForbidden
+	}
+
+	// Check if response is still active
+	if response.Status != models.ResponseStatusStarted {
+		return repository.ErrResponseNotInProgress
+	}
+	if err := s.closeIfOver(response); err != nil {
+		return err
+	}
+
+	// Get survey with questions
+	survey, err := s.surveyRepo.GetByID(response.SurveyID)
+	if err != nil {
+		return err
+	}
+
+	// Validate every answer before saving any of them
+	validated := make([]*models.Answer, 0, len(answers))
+	var validationErrors models.AnswerValidationErrors
+	for _, answerReq := range answers {
+		answer, err := s.buildAnswer(survey, responseID, answerReq.QuestionID, answerReq.Answer, answerReq.TimeSpent, answerReq.IsSkipped)
+		if err != nil {
+			var answerErr *models.AnswerValidationError
+			if errors.As(err, &answerErr) {
+				validationErrors = append(validationErrors, *answerErr)
+				continue
+			}
+			return err
+		}
+		validated = append(validated, answer)
+	}
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+
+	// Save or update answers, whatever version the response is at
+	if _, err := s.responseRepo.SaveAnswers(responseID, 0, validated); err != nil {
+		return err
+	}
+
+	return s.screenQuotas(response, survey)
+}
+
+func (s *responseService) CompleteSurvey(userID uint, req *dto.CompleteSurveyRequest) (*dto.CompletionResponse, error) {
+	// Get response
+	response, err := s.responseRepo.GetByID(req.ResponseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if response.UserID != userID {
+		return nil, errResponseForbidden
+	}
+
+	// Check if response is still active
+	if response.Status != models.ResponseStatusStarted {
+		return nil, repository.ErrResponseNotInProgress
+	}
+	if err := s.closeIfOver(response); err != nil {
+		return nil, err
+	}
+
+	// Submit final answers if provided
+	if len(req.Answers) > 0 {
+		if err := s.SubmitAnswers(userID, req.ResponseID, req.Answers); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get survey
+	survey, err := s.surveyRepo.GetByID(response.SurveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload answers so quotas and quality scoring see the final state
+	response, err = s.responseRepo.GetWithAnswers(req.ResponseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.complete(response, survey)
+}
+
+func (s *responseService) GetResponse(userID, responseID uint) (*dto.SurveyResponseResponse, error) {
+	response, err := s.responseRepo.GetWithAnswers(responseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if response.UserID != userID {
+		return nil, errResponseForbidden
+	}
+
+	return s.responseToDTO(response), nil
+}
+
+func (s *responseService) GetUserResponses(userID uint, req *dto.ListResponsesRequest) (*dto.ResponseListResponse, error) {
+	responses, total, err := s.responseRepo.GetByUserID(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.ResponseItemResponse, len(responses))
+	for i, response := range responses {
+		items[i] = s.responseToItemDTO(&response)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &dto.ResponseListResponse{
+		Responses:  items,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *responseService) GetSurveyResponses(userID, surveyID uint, req *dto.ListSurveyResponsesRequest) (*dto.CreatorResponseListResponse, error) {
+	survey, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if survey.CreatorID != userID {
+		return nil, errSurveyForbidden
+	}
+
+	filter, err := buildResponseFilter(survey, &req.ResponseFilterRequest)
+	if err != nil {
+		return nil, err
+	}
+	filter.Page, filter.Limit = req.Page, req.Limit
+
+	responses, total, err := s.responseRepo.GetBySurveyID(surveyID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.CreatorResponseResponse, len(responses))
+	for i := range responses {
+		items[i] = *s.responseToCreatorDTO(&responses[i], survey)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &dto.CreatorResponseListResponse{
+		Responses:  items,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *responseService) GetSurveyResponse(userID, surveyID, responseID uint) (*dto.CreatorResponseResponse, error) {
+	survey, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if survey.CreatorID != userID {
+		return nil, errSurveyForbidden
+	}
+
+	response, err := s.responseRepo.GetWithAnswers(responseID)
+	if err != nil {
+		return nil, err
+	}
+	if response.SurveyID != surveyID {
+		return nil, apperror.New(apperror.NotFound, "Response not found")
+	}
+
+	if !survey.IsAnonymous {
+		if user, err := s.userRepo.GetByID(response.UserID); err == nil {
+			response.User = *user
+		}
+	}
+
+	return s.responseToCreatorDTO(response, survey), nil
+}
+
+func (s *responseService) GetResponseProgress(userID, responseID uint) (*dto.SurveyProgressResponse, error) {
+	response, err := s.responseRepo.GetWithAnswers(responseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if response.UserID != userID {
+		return nil, errResponseForbidden
+	}
+
+	// Get survey
+	survey, err := s.surveyRepo.GetByID(response.SurveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := newResponseProgress(survey, response)
+	var nextQuestionID *uint
+	if progress.next != nil {
+		nextQuestionID = &progress.next.ID
+	}
+
+	return &dto.SurveyProgressResponse{
+		ResponseID:        response.ID,
+		SurveyID:          response.SurveyID,
+		Status:            string(response.Status),
+		Progress:          progress.percent(),
+		QuestionsTotal:    len(progress.visible),
+		QuestionsAnswered: progress.answered,
+		TimeSpent:         response.CalculateDuration(),
+		TimeLeft:          remainingTime(survey, response),
+		StartedAt:         response.StartedAt,
+		LastAnsweredAt:    response.LastAnsweredAt(),
+		Deadline:          response.Deadline,
+		Version:           response.Version,
+		NextQuestionID:    nextQuestionID,
+		ExpiresAt:         s.expiresAt(response),
+	}, nil
+}
+
+// UpdateAnswer autosaves a single answer. A non-zero req.Version must match
+// the current version of the response, so an answer made on a stale copy,
+// say on another device, doesn't overwrite newer ones.
+func (s *responseService) UpdateAnswer(userID, responseID, questionID uint, req *dto.UpdateAnswerRequest) (*dto.AnswerSavedResponse, error) {
+	// Get response
+	response, err := s.responseRepo.GetByID(responseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if response.UserID != userID {
+		return nil, errResponseForbidden
+	}
+
+	// Check if response is still active
+	if response.Status != models.ResponseStatusStarted {
+		return nil, repository.ErrResponseNotInProgress
+	}
+	if err := s.closeIfOver(response); err != nil {
+		return nil, err
+	}
+	if req.Version != 0 && req.Version != response.Version {
+		return nil, ErrVersionConflict
+	}
+
+	// Get survey with questions
+	survey, err := s.surveyRepo.GetByID(response.SurveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build and validate answer
+	answer, err := s.buildAnswer(survey, responseID, questionID, req.Answer, req.TimeSpent, req.IsSkipped)
+	if err != nil {
+		var answerErr *models.AnswerValidationError
+		if errors.As(err, &answerErr) {
+			return nil, models.AnswerValidationErrors{*answerErr}
+		}
+		return nil, err
+	}
+
+	version, err := s.responseRepo.SaveAnswers(responseID, req.Version, []*models.Answer{answer})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.screenQuotas(response, survey); err != nil {
+		return nil, err
+	}
+
+	// Work out the next question from the answers as saved
+	saved, err := s.responseRepo.GetWithAnswers(responseID)
+	if err != nil {
+		return nil, err
+	}
+	progress := newResponseProgress(survey, saved)
+
+	result := &dto.AnswerSavedResponse{
+		ResponseID: responseID,
+		QuestionID: questionID,
+		Version:    version,
+		SavedAt:    answer.UpdatedAt,
+		ExpiresAt:  s.expiresAt(saved),
+	}
+	if progress.next != nil {
+		result.NextQuestionID = &progress.next.ID
+	}
+	return result, nil
+}
+
+func (s *responseService) AbandonSurvey(userID, responseID uint) error {
+	// Get response
+	response, err := s.responseRepo.GetByID(responseID)
+	if err != nil {
+		return err
+	}
+
+	// Check ownership
+	if response.UserID != userID {
+		return errResponseForbidden
+	}
+
+	// Check if response can be abandoned
+	if response.Status != models.ResponseStatusStarted {
+		return repository.ErrResponseNotInProgress
+	}
+
+	// Mark as abandoned
+	response.MarkAsAbandoned()
+
+	if err := s.responseRepo.Update(response); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// complete marks a response, loaded with its answers, as completed and
+// rewards the respondent, unless required questions are left unanswered,
+// which is reported as models.AnswerValidationErrors. The duration is
+// measured from the start, never taken from the client.
+func (s *responseService) complete(response *models.Response, survey *models.Survey) (*dto.CompletionResponse, error) {
+	// Every required question the answers show must be answered
+	if missing := survey.MissingRequiredAnswers(response.Answers); len(missing) > 0 {
+		return nil, missing
+	}
+
+	// Mark response as completed, it is saved with the reward that settles it
+	response.MarkAsCompleted()
+
+	// Calculate quality score
+	response.QualityScore = s.calculateQualityScore(response, survey)
+
+	// Process rewards
+	rewardAmount, xpEarned, err := s.processRewards(response, survey)
+	if errors.Is(err, models.ErrQuotaFull) {
+		// A concurrent completion filled the quota first
+		quotaName := "quota"
+		if quota := survey.FullQuota(response, response.Answers); quota != nil {
+			quotaName = quota.Name
+		}
+		response.CompletedAt = nil
+		response.MarkAsScreenedOut("quota full: " + quotaName)
+		if err := s.responseRepo.Update(response); err != nil {
+			return nil, err
+		}
+		return nil, &ScreenedOutError{ResponseID: response.ID, Quota: quotaName}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Update survey statistics
+	if err := s.surveyRepo.UpdateStatistics(survey.ID); err != nil {
+		return nil, err
+	}
+
+	// Text analyses catch up from the answers on the next analytics request
+	// if this update fails, so it doesn't fail the completion
+	if err := addTextAnswers(s.textAnalysisRepo, survey, response); err != nil {
+		logrus.WithError(err).WithField("response_id", response.ID).Warn("Failed to update text analysis")
+	}
+
+	// Generate NFT certificate (mock)
+	nftCertificate := s.generateNFTCertificate(response, survey)
+
+	return &dto.CompletionResponse{
+		ResponseID:      response.ID,
+		Status:          string(response.Status),
+		CompletedAt:     *response.CompletedAt,
+		Duration:        response.Duration,
+		RewardEarned:    rewardAmount,
+		XpEarned:        xpEarned,
+		NFTCertificate:  &nftCertificate,
+		TransactionHash: nil, // Will be updated when blockchain transaction is processed
+		Message:         "Survey completed successfully! Your rewards will be processed shortly.",
+	}, nil
+}
+
+// Helper methods
+
+// buildAnswer converts a submitted answer into a model answer and validates it
+// against its question. Answers to questions outside the survey are rejected.
+func (s *responseService) buildAnswer(survey *models.Survey, responseID, questionID uint, value dto.AnswerValue, timeSpent int, isSkipped bool) (*models.Answer, error) {
+	question, err := survey.GetQuestionByID(questionID)
+	if err != nil {
+		return nil, models.NewUnknownQuestionError(questionID)
+	}
+
+	answerValue := s.toAnswerValue(value)
+	answer := &models.Answer{
+		ResponseID:  responseID,
+		QuestionID:  questionID,
+		AnswerText:  s.extractAnswerText(answerValue),
+		AnswerValue: answerValue,
+		TimeSpent:   timeSpent,
+		IsSkipped:   isSkipped,
+	}
+
+	if err := answer.ValidateAnswer(question); err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+func (s *responseService) extractAnswerText(answerValue models.AnswerValue) string {
+	switch answerValue.Type {
+	case "text", "textarea":
+		if str, ok := answerValue.Content.(string); ok {
+			return str
+		}
+	case "number":
+		if num, ok := answerValue.Content.(float64); ok {
+			return fmt.Sprintf("%.2f", num)
+		}
+		if str, ok := answerValue.Content.(string); ok {
+			return strings.TrimSpace(str)
+		}
+	case "boolean", "yes_no":
+		if options := answerValue.Options; len(options) > 0 {
+			return strings.Join(options, ", ")
+		}
+		if b, ok := answerValue.Content.(bool); ok {
+			if b {
+				return "true"
+			}
+			return "false"
+		}
+	case "array", "single_choice", "multiple_choice":
+		if options := answerValue.Options; len(options) > 0 {
+			return strings.Join(options, ", ")
+		}
+	case "rating":
+		if answerValue.Rating != nil {
+			return fmt.Sprintf("%d", *answerValue.Rating)
+		}
+	case "scale":
+		if answerValue.Scale != nil {
+			return fmt.Sprintf("%d", *answerValue.Scale)
+		}
+		if answerValue.Rating != nil {
+			return fmt.Sprintf("%d", *answerValue.Rating)
+		}
+	case "date":
+		if answerValue.Date != nil {
+			return answerValue.Date.Format("2006-01-02")
+		}
+	case "nps":
+		if answerValue.Rating != nil {
+			return fmt.Sprintf("%d", *answerValue.Rating)
+		}
+	case "email", "url", "phone":
+		if str, ok := answerValue.Content.(string); ok {
+			return strings.TrimSpace(str)
+		}
+	case "matrix":
+		rows := make([]string, 0, len(answerValue.Matrix))
+		for row := range answerValue.Matrix {
+			rows = append(rows, row)
+		}
+		sort.Strings(rows)
+		pairs := make([]string, len(rows))
+		for i, row := range rows {
+			pairs[i] = row + ": " + answerValue.Matrix[row]
+		}
+		return strings.Join(pairs, "; ")
+	case "ranking":
+		ranked := make([]string, len(answerValue.Ranking))
+		for i, option := range answerValue.Ranking {
+			ranked[i] = fmt.Sprintf("%d. %s", i+1, option)
+		}
+		return strings.Join(ranked, ", ")
+	case "constant_sum":
+		options := make([]string, 0, len(answerValue.Allocations))
+		for option := range answerValue.Allocations {
+			options = append(options, option)
+		}
+		sort.Strings(options)
+		allocations := make([]string, len(options))
+		for i, option := range options {
+			allocations[i] = fmt.Sprintf("%s: %g", option, answerValue.Allocations[option])
+		}
+		return strings.Join(allocations, ", ")
+	case "file_upload":
+		names := make([]string, len(answerValue.Files))
+		for i, file := range answerValue.Files {
+			names[i] = file.Name
+			if names[i] == "" {
+				names[i] = file.URL
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+func (s *responseService) toAnswerValue(answer dto.AnswerValue) models.AnswerValue {
+	var files []models.FileAnswer
+	for _, file := range answer.Files {
+		files = append(files, models.FileAnswer{
+			URL:         file.URL,
+			Name:        file.Name,
+			ContentType: file.ContentType,
+			SizeBytes:   file.SizeBytes,
+		})
+	}
+
+	return models.AnswerValue{
+		Type:        answer.Type,
+		Content:     answer.Content,
+		Options:     answer.Options,
+		Rating:      answer.Rating,
+		Scale:       answer.Scale,
+		Date:        answer.Date,
+		Matrix:      answer.Matrix,
+		Ranking:     answer.Ranking,
+		Allocations: answer.Allocations,
+		Files:       files,
+	}
+}
+
+func (s *responseService) answerValueToDTO(value models.AnswerValue) dto.AnswerValue {
+	var files []dto.FileAnswer
+	for _, file := range value.Files {
+		files = append(files, dto.FileAnswer{
+			URL:         file.URL,
+			Name:        file.Name,
+			ContentType: file.ContentType,
+			SizeBytes:   file.SizeBytes,
+		})
+	}
+
+	return dto.AnswerValue{
+		Type:        value.Type,
+		Content:     value.Content,
+		Options:     value.Options,
+		Rating:      value.Rating,
+		Scale:       value.Scale,
+		Date:        value.Date,
+		Matrix:      value.Matrix,
+		Ranking:     value.Ranking,
+		Allocations: value.Allocations,
+		Files:       files,
+	}
+}
+
+// screenQuotas screens the response out when its answers so far put it into a
+// quota that is already full
+func (s *responseService) screenQuotas(response *models.Response, survey *models.Survey) error {
+	if len(survey.Quotas) == 0 {
+		return nil
+	}
+
+	withAnswers, err := s.responseRepo.GetWithAnswers(response.ID)
+	if err != nil {
+		return err
+	}
+
+	quota := survey.FullQuota(withAnswers, withAnswers.Answers)
+	if quota == nil {
+		return nil
+	}
+
+	response.MarkAsScreenedOut("quota full: " + quota.Name)
+	if err := s.responseRepo.Update(response); err != nil {
+		return err
+	}
+	return &ScreenedOutError{ResponseID: response.ID, Quota: quota.Name}
+}
+
+func (s *responseService) calculateQualityScore(response *models.Response, survey *models.Survey) float64 {
+	// Simple quality score calculation
+	// In a real implementation, this would be more sophisticated
+	score := 5.0
+
+	// Check completion rate
+	questionsTotal := len(survey.Questions)
+	questionsAnswered := len(response.Answers)
+	completionRate := float64(questionsAnswered) / float64(questionsTotal)
+
+	score *= completionRate
+
+	// Check time spent (penalize too fast responses)
+	avgTimePerQuestion := float64(response.Duration) / float64(questionsAnswered)
+	if avgTimePerQuestion < 5 { // Less than 5 seconds per question
+		score *= 0.7
+	}
+
+	// Check for skipped required questions
+	skippedRequired := 0
+	for _, answer := range response.Answers {
+		if answer.IsSkipped {
+			// Find if question was required
+			for _, question := range survey.Questions {
+				if question.ID == answer.QuestionID && question.Required {
+					skippedRequired++
+					break
+				}
+			}
+		}
+	}
+
+	if skippedRequired > 0 {
+		score *= (1.0 - float64(skippedRequired)*0.1)
+	}
+
+	if score < 0 {
+		score = 0
+	}
+	if score > 5 {
+		score = 5
+	}
+
+	return score
+}
+
+func (s *responseService) processRewards(response *models.Response, survey *models.Survey) (float64, int, error) {
+	// Get reward pool
+	pool, err := s.rewardRepo.GetPoolBySurveyID(survey.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Check if pool can process reward
+	if !pool.CanProcessReward() {
+		return 0, 0, models.ErrPoolExhausted
+	}
+
+	// Calculate rewards based on quality score
+	baseReward := survey.RewardPerResponse
+	qualityMultiplier := response.QualityScore / 5.0
+	finalReward := baseReward * qualityMultiplier
+
+	// Calculate XP (mock calculation)
+	xpEarned := int(float64(survey.EstimatedDuration) * 10 * qualityMultiplier)
+
+	// Create reward transaction
+	transaction := &models.RewardTransaction{
+		UserID:   response.UserID,
+		SurveyID: survey.ID,
+		ResponseID: &response.ID,
+		PoolID:   &pool.ID,
+		Type:     models.TransactionTypeReward,
+		Amount:   finalReward,
+		Status:   models.TransactionStatusPending,
+	}
+
+	// The completion is recorded with the reward that settles it
+	completed, err := models.NewOutboxEvent(models.OutboxEventResponseCompleted, models.OutboxAggregateResponse, response.ID, survey.ID,
+		responseCompletedEventData(response, survey, finalReward, xpEarned))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Process reward, saving the completion, crediting the user and counting
+	// the response against its quotas in the same transaction
+	quotas := survey.MatchingQuotas(response, response.Answers)
+	if err := s.rewardRepo.ProcessRewardWithQuotas(response, pool, transaction, quotas, survey.MaxResponses, completed); err != nil {
+		return 0, 0, err
+	}
+
+	return finalReward, xpEarned, nil
+}
+
+func (s *responseService) generateNFTCertificate(response *models.Response, survey *models.Survey) string {
+	// Mock NFT certificate generation
+	// In a real implementation, this would interact with NFT smart contract
+	return fmt.Sprintf("NFT-CERT-%d-%d-%d", survey.ID, response.UserID, response.ID)
+}
+
+func (s *responseService) answersToDTO(answers []models.Answer) []dto.AnswerResponse {
+	result := make([]dto.AnswerResponse, len(answers))
+	for i, answer := range answers {
+		result[i] = dto.AnswerResponse{
+			ID:         answer.ID,
+			QuestionID: answer.QuestionID,
+			Answer:     s.answerValueToDTO(answer.AnswerValue),
+			TimeSpent: answer.TimeSpent,
+			IsSkipped: answer.IsSkipped,
+			CreatedAt: answer.CreatedAt,
+			UpdatedAt: answer.UpdatedAt,
+		}
+	}
+	return result
+}
+
+func (s *responseService) responseToDTO(response *models.Response) *dto.SurveyResponseResponse {
+	answers := s.answersToDTO(response.Answers)
+
+	// Get reward earned (mock)
+	rewardEarned := 0.0
+	xpEarned := 0
+	if response.Transaction != nil {
+		rewardEarned = response.Transaction.Amount
+		xpEarned = int(rewardEarned * 10) // Mock XP calculation
+	}
+
+	// Generate NFT certificate if completed
+	var nftCertificate *string
+	if response.IsCompleted() {
+		cert := s.generateNFTCertificate(response, &response.Survey)
+		nftCertificate = &cert
+	}
+
+	return &dto.SurveyResponseResponse{
+		ID:             response.ID,
+		SurveyID:       response.SurveyID,
+		UserID:         response.UserID,
+		Status:         string(response.Status),
+		StartedAt:      response.StartedAt,
+		CompletedAt:    response.CompletedAt,
+		Duration:       response.Duration,
+		QualityScore:   response.QualityScore,
+		IsValid:        response.IsValid,
+		Answers:        answers,
+		RewardEarned:   rewardEarned,
+		XpEarned:       xpEarned,
+		NFTCertificate: nftCertificate,
+	}
+}
+
+// responseToCreatorDTO adds response metadata and, unless the survey is
+// anonymous, the respondent's identity to the response detail
+func (s *responseService) responseToCreatorDTO(response *models.Response, survey *models.Survey) *dto.CreatorResponseResponse {
+	detail := &dto.CreatorResponseResponse{
+		SurveyResponseResponse: *s.responseToDTO(response),
+		Language:               response.Language,
+		Timezone:               response.Timezone,
+		FlaggedReason:          response.FlaggedReason,
+	}
+
+	if survey.IsAnonymous {
+		detail.UserID = 0
+		return detail
+	}
+
+	detail.Respondent = &dto.RespondentResponse{
+		UserID:        response.UserID,
+		WalletAddress: response.User.WalletAddress,
+		Username:      response.User.Username,
+		IPAddress:     response.IPAddress,
+		UserAgent:     response.UserAgent,
+	}
+	return detail
+}
+
+func (s *responseService) responseToItemDTO(response *models.Response) dto.ResponseItemResponse {
+	// Calculate progress
+	progress := 0.0
+	if len(response.Survey.Questions) > 0 {
+		progress = float64(len(response.Answers)) / float64(len(response.Survey.Questions)) * 100
+	}
+
+	// Get reward earned (mock)
+	rewardEarned := 0.0
+	xpEarned := 0
+	if response.Transaction != nil {
+		rewardEarned = response.Transaction.Amount
+		xpEarned = int(rewardEarned * 10)
+	}
+
+	return dto.ResponseItemResponse{
+		ID:           response.ID,
+		SurveyID:     response.SurveyID,
+		SurveyTitle:  response.Survey.Title,
+		Status:       string(response.Status),
+		StartedAt:    response.StartedAt,
+		CompletedAt:  response.CompletedAt,
+		Duration:     response.Duration,
+		RewardEarned: rewardEarned,
+		XpEarned:     xpEarned,
+		QualityScore: response.QualityScore,
+		Progress:     progress,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,79 @@
+// internal/service/reward_service.go
+package service
+
+import (
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+)
+
+type RewardService interface {
+	GetBalance(userID uint) (*dto.RewardBalanceResponse, error)
+	GetTransactions(userID uint, req *dto.ListRewardTransactionsRequest) (*dto.RewardTransactionListResponse, error)
+}
+
+type rewardService struct {
+	rewardRepo repository.RewardRepository
+}
+
+func NewRewardService(rewardRepo repository.RewardRepository) RewardService {
+	return &rewardService{
+		rewardRepo: rewardRepo,
+	}
+}
+
+// GetBalance sums the user's balance from their reward transactions, as the
+// balance rebuild does
+func (s *rewardService) GetBalance(userID uint) (*dto.RewardBalanceResponse, error) {
+	ledger, err := s.rewardRepo.GetLedger(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RewardBalanceResponse{
+		TotalEarned:        ledger.Earned,
+		TotalWithdrawn:     ledger.Withdrawn,
+		PendingWithdrawals: ledger.Pending,
+		AvailableBalance:   ledger.Earned - ledger.Withdrawn - ledger.Pending,
+	}, nil
+}
+
+func (s *rewardService) GetTransactions(userID uint, req *dto.ListRewardTransactionsRequest) (*dto.RewardTransactionListResponse, error) {
+	transactions, total, err := s.rewardRepo.GetTransactionsByUserID(userID, req.Type, req.Status, req.Page, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.RewardTransactionResponse, len(transactions))
+	for i := range transactions {
+		responses[i] = rewardTransactionToDTO(&transactions[i])
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &dto.RewardTransactionListResponse{
+		Transactions: responses,
+		Total:        total,
+		Page:         req.Page,
+		Limit:        req.Limit,
+		TotalPages:   totalPages,
+	}, nil
+}
+
+func rewardTransactionToDTO(transaction *models.RewardTransaction) dto.RewardTransactionResponse {
+	return dto.RewardTransactionResponse{
+		ID:          transaction.ID,
+		SurveyID:    transaction.SurveyID,
+		SurveyTitle: transaction.Survey.Title,
+		ResponseID:  transaction.ResponseID,
+		Type:        string(transaction.Type),
+		Amount:      transaction.Amount,
+		Status:      string(transaction.Status),
+		TxHash:      transaction.TxHash,
+		ProcessedAt: transaction.ProcessedAt,
+		CreatedAt:   transaction.CreatedAt,
+	}
+}
@@ -0,0 +1,191 @@
+// internal/service/survey_definition.go
+package service
+
+import (
+	"sort"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/surveydef"
+)
+
+// surveyToDefinition converts a survey into the portable definition format,
+// replacing question IDs with question orders
+func surveyToDefinition(survey *models.Survey) *surveydef.SurveyDefinition {
+	orderByQuestionID := make(map[uint]int, len(survey.Questions))
+	for _, q := range survey.Questions {
+		orderByQuestionID[q.ID] = q.Order
+	}
+
+	questions := make([]surveydef.QuestionDefinition, len(survey.Questions))
+	for i, q := range survey.Questions {
+		questions[i] = surveydef.QuestionDefinition{
+			Order:       q.Order,
+			Type:        string(q.Type),
+			Text:        q.Text,
+			Description: q.Description,
+			Required:    q.Required,
+			Options:     optionsToDefinition(q.Options),
+			Rows:        optionsToDefinition(q.Rows),
+			MinLength:   q.MinLength,
+			MaxLength:   q.MaxLength,
+			MinValue:    q.MinValue,
+			MaxValue:    q.MaxValue,
+			SumTotal:    q.SumTotal,
+		}
+		if q.FileConfig != nil {
+			questions[i].FileConfig = &surveydef.FileConfig{
+				AllowedTypes: q.FileConfig.AllowedTypes,
+				MaxSizeBytes: q.FileConfig.MaxSizeBytes,
+				MaxFiles:     q.FileConfig.MaxFiles,
+			}
+		}
+		if logic := conditionalLogicToRequest(q.ShowIf, orderByQuestionID); logic != nil {
+			questions[i].ShowIf = &surveydef.Condition{
+				Question: logic.QuestionOrder,
+				Operator: logic.Operator,
+				Value:    logic.Value,
+			}
+		}
+	}
+
+	sort.Slice(questions, func(i, j int) bool {
+		return questions[i].Order < questions[j].Order
+	})
+
+	var quotas []surveydef.QuotaDefinition
+	for _, q := range survey.Quotas {
+		quota := surveydef.QuotaDefinition{
+			Name:       q.Name,
+			Value:      q.Value,
+			Limit:      q.Limit,
+			Percentage: q.Percentage,
+		}
+		if q.QuestionID != nil {
+			order := orderByQuestionID[*q.QuestionID]
+			quota.Question = &order
+		}
+		if q.MetadataField != nil {
+			field := string(*q.MetadataField)
+			quota.MetadataField = &field
+		}
+		quotas = append(quotas, quota)
+	}
+
+	return &surveydef.SurveyDefinition{
+		SchemaVersion: surveydef.SchemaVersion,
+		Title:         survey.Title,
+		Description:   survey.Description,
+		Category:      survey.Category,
+		Language:      survey.Language,
+		EstimatedTime: formatEstimatedTime(survey.EstimatedDuration),
+		Settings: surveydef.Settings{
+			MaxResponses:      survey.MaxResponses,
+			RewardPerResponse: survey.RewardPerResponse,
+			TimeLimitMinutes:  survey.TimeLimitMinutes,
+			IsAnonymous:       survey.IsAnonymous,
+			IsPublic:          survey.IsPublic,
+			RequireLogin:      survey.RequireLogin,
+			AllowMultiple:     survey.AllowMultiple,
+			StartDate:         survey.StartDate,
+			EndDate:           survey.EndDate,
+		},
+		Questions: questions,
+		Quotas:    quotas,
+	}
+}
+
+// definitionToCreateRequest converts a validated definition into a survey
+// creation request so imports go through the regular creation path
+func definitionToCreateRequest(def *surveydef.SurveyDefinition) *dto.CreateSurveyRequest {
+	questions := make([]dto.CreateQuestionRequest, len(def.Questions))
+	for i, q := range def.Questions {
+		questions[i] = dto.CreateQuestionRequest{
+			Type:        q.Type,
+			Title:       q.Text,
+			Description: q.Description,
+			Required:    q.Required,
+			Options:     definitionOptionsToRequest(q.Options),
+			MinLength:   q.MinLength,
+			MaxLength:   q.MaxLength,
+			MinValue:    q.MinValue,
+			MaxValue:    q.MaxValue,
+			Order:       q.Order,
+			Rows:        definitionOptionsToRequest(q.Rows),
+			SumTotal:    q.SumTotal,
+		}
+		if q.FileConfig != nil {
+			questions[i].FileConfig = &dto.FileUploadConfigRequest{
+				AllowedTypes: q.FileConfig.AllowedTypes,
+				MaxSizeBytes: q.FileConfig.MaxSizeBytes,
+				MaxFiles:     q.FileConfig.MaxFiles,
+			}
+		}
+		if q.ShowIf != nil {
+			questions[i].ShowIf = &dto.ConditionalLogicRequest{
+				QuestionOrder: q.ShowIf.Question,
+				Operator:      q.ShowIf.Operator,
+				Value:         q.ShowIf.Value,
+			}
+		}
+	}
+
+	quotas := make([]dto.QuotaRequest, len(def.Quotas))
+	for i, q := range def.Quotas {
+		quotas[i] = dto.QuotaRequest{
+			Name:          q.Name,
+			QuestionOrder: q.Question,
+			MetadataField: q.MetadataField,
+			Value:         q.Value,
+			Limit:         q.Limit,
+			Percentage:    q.Percentage,
+		}
+	}
+
+	return &dto.CreateSurveyRequest{
+		Title:            def.Title,
+		Description:      def.Description,
+		Category:         def.Category,
+		Language:         def.Language,
+		EstimatedTime:    def.EstimatedTime,
+		TimeLimitMinutes: def.Settings.TimeLimitMinutes,
+		RewardAmount:     def.Settings.RewardPerResponse,
+		MaxParticipants:  def.Settings.MaxResponses,
+		Questions:        questions,
+		Quotas:           quotas,
+		IsAnonymous:      def.Settings.IsAnonymous,
+		IsPublic:         def.Settings.IsPublic,
+		RequireLogin:     def.Settings.RequireLogin,
+		AllowMultiple:    def.Settings.AllowMultiple,
+		StartDate:        def.Settings.StartDate,
+		EndDate:          def.Settings.EndDate,
+	}
+}
+
+func optionsToDefinition(options models.QuestionOptions) []surveydef.Option {
+	if len(options) == 0 {
+		return nil
+	}
+	items := make([]surveydef.Option, len(options))
+	for i, opt := range options {
+		items[i] = surveydef.Option{
+			ID:    opt.ID,
+			Label: opt.Label,
+			Value: opt.Value,
+			Order: opt.Order,
+		}
+	}
+	return items
+}
+
+func definitionOptionsToRequest(options []surveydef.Option) []dto.QuestionOptionRequest {
+	items := make([]dto.QuestionOptionRequest, len(options))
+	for i, opt := range options {
+		items[i] = dto.QuestionOptionRequest{
+			ID:    opt.ID,
+			Label: opt.Label,
+			Value: opt.Value,
+			Order: opt.Order,
+		}
+	}
+	return items
+}
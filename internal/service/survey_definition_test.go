@@ -0,0 +1,282 @@
+// internal/service/survey_definition_test.go
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"survey2earn-backend/internal/surveydef"
+	"testing"
+	"time"
+)
+
+// memorySurveys keeps surveys in memory, handing out IDs like the database.
+// Only the methods export and import use are implemented.
+type memorySurveys struct {
+	repository.SurveyRepository
+	quotas  *memoryQuotas
+	surveys map[uint]models.Survey
+	nextID  uint
+}
+
+func (r *memorySurveys) id() uint {
+	r.nextID++
+	return r.nextID
+}
+
+func (r *memorySurveys) Create(survey *models.Survey) error {
+	survey.ID = r.id()
+	for i := range survey.Questions {
+		survey.Questions[i].ID = r.id()
+		survey.Questions[i].SurveyID = survey.ID
+	}
+	r.surveys[survey.ID] = copySurvey(survey)
+	return nil
+}
+
+func (r *memorySurveys) GetByID(id uint) (*models.Survey, error) {
+	stored, ok := r.surveys[id]
+	if !ok {
+		return nil, errors.New("survey not found")
+	}
+	survey := copySurvey(&stored)
+	survey.Quotas = r.quotas.bySurvey[id]
+	return &survey, nil
+}
+
+func (r *memorySurveys) UpdateQuestionConditions(questions []models.Question) error {
+	for _, question := range questions {
+		survey := r.surveys[question.SurveyID]
+		for i := range survey.Questions {
+			if survey.Questions[i].ID == question.ID {
+				survey.Questions[i].ShowIf = question.ShowIf
+			}
+		}
+	}
+	return nil
+}
+
+func copySurvey(survey *models.Survey) models.Survey {
+	copied := *survey
+	copied.Questions = append([]models.Question(nil), survey.Questions...)
+	copied.Quotas = nil
+	return copied
+}
+
+type memoryQuotas struct {
+	repository.QuotaRepository
+	bySurvey map[uint][]models.SurveyQuota
+	nextID   uint
+}
+
+func (r *memoryQuotas) ReplaceForSurvey(surveyID uint, quotas []models.SurveyQuota) error {
+	for i := range quotas {
+		r.nextID++
+		quotas[i].ID = r.nextID
+		quotas[i].SurveyID = surveyID
+	}
+	r.bySurvey[surveyID] = append([]models.SurveyQuota(nil), quotas...)
+	return nil
+}
+
+type memoryUsers struct {
+	repository.UserRepository
+}
+
+func (memoryUsers) GetByID(id uint) (*models.User, error) {
+	return &models.User{BaseModel: models.BaseModel{ID: id}}, nil
+}
+
+type memoryCategories struct {
+	repository.CategoryRepository
+}
+
+func (memoryCategories) GetBySlug(slug string) (*models.Category, error) {
+	return &models.Category{Slug: slug, IsActive: true}, nil
+}
+
+func newDefinitionTestService() (SurveyService, *memorySurveys) {
+	quotas := &memoryQuotas{bySurvey: map[uint][]models.SurveyQuota{}}
+	surveys := &memorySurveys{quotas: quotas, surveys: map[uint]models.Survey{}, nextID: 100}
+	service := NewSurveyService(surveys, memoryUsers{}, nil, quotas, nil, nil, nil, memoryCategories{}, nil, config.CacheConfig{})
+	return service, surveys
+}
+
+// seedSurvey stores a survey using every part of the definition format, with
+// IDs that don't match the question orders
+func seedSurvey(surveys *memorySurveys, creatorID uint) uint {
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+	startDate := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	endDate := time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC)
+	language := models.QuotaMetadataLanguage
+
+	survey := &models.Survey{
+		CreatorID:         creatorID,
+		Title:             "DeFi habits",
+		Description:       "How people use DeFi",
+		Category:          "finance",
+		Language:          "en",
+		Status:            models.SurveyStatusPublished,
+		MaxResponses:      200,
+		RewardPerResponse: 2.5,
+		TotalRewardPool:   500,
+		EstimatedDuration: 10,
+		TimeLimitMinutes:  intPtr(15),
+		IsAnonymous:       true,
+		IsPublic:          true,
+		RequireLogin:      true,
+		StartDate:         &startDate,
+		EndDate:           &endDate,
+		Questions: []models.Question{
+			{
+				Type:     models.QuestionTypeSingleChoice,
+				Text:     "How often do you use DeFi?",
+				Required: true,
+				Order:    1,
+				Options: models.QuestionOptions{
+					{ID: "daily", Label: "Daily", Value: "daily", Order: 1},
+					{ID: "weekly", Label: "Weekly", Value: "weekly", Order: 2},
+				},
+			},
+			{
+				Type:     models.QuestionTypeRating,
+				Text:     "How do you rate your wallet?",
+				Order:    2,
+				MinValue: floatPtr(1),
+				MaxValue: floatPtr(5),
+			},
+			{
+				Type:        models.QuestionTypeText,
+				Text:        "Why?",
+				Description: "A sentence or two",
+				Order:       3,
+				MinLength:   intPtr(5),
+				MaxLength:   intPtr(200),
+			},
+			{
+				Type:    models.QuestionTypeMatrix,
+				Text:    "Rate each protocol",
+				Order:   4,
+				Options: models.QuestionOptions{{Label: "Bad", Value: "bad", Order: 1}, {Label: "Good", Value: "good", Order: 2}},
+				Rows:    models.QuestionOptions{{Label: "Uniswap", Value: "uniswap", Order: 1}, {Label: "Aave", Value: "aave", Order: 2}},
+			},
+			{
+				Type:     models.QuestionTypeConstantSum,
+				Text:     "Split 100 points",
+				Order:    5,
+				Options:  models.QuestionOptions{{Label: "Fees", Value: "fees", Order: 1}, {Label: "Speed", Value: "speed", Order: 2}},
+				SumTotal: floatPtr(100),
+			},
+			{
+				Type:  models.QuestionTypeFileUpload,
+				Text:  "Upload a screenshot",
+				Order: 6,
+				FileConfig: &models.FileUploadConfig{
+					AllowedTypes: []string{"image/png", "image/jpeg"},
+					MaxSizeBytes: 1 << 20,
+					MaxFiles:     2,
+				},
+			},
+		},
+	}
+	surveys.Create(survey)
+
+	// Conditions and quotas reference questions by ID once they are saved
+	questions := survey.Questions
+	questions[1].ShowIf = &models.ConditionalLogic{QuestionID: strconv.FormatUint(uint64(questions[0].ID), 10), Operator: models.ConditionEquals, Value: "daily"}
+	questions[2].ShowIf = &models.ConditionalLogic{QuestionID: strconv.FormatUint(uint64(questions[1].ID), 10), Operator: models.ConditionGreaterThan, Value: float64(3)}
+	surveys.UpdateQuestionConditions(questions[1:3])
+
+	screener := questions[0].ID
+	surveys.quotas.ReplaceForSurvey(survey.ID, []models.SurveyQuota{
+		{Name: "Daily users", QuestionID: &screener, Value: "daily", Percentage: floatPtr(40), IsActive: true},
+		{Name: "English speakers", MetadataField: &language, Value: "en", Limit: intPtr(50), IsActive: true},
+	})
+	return survey.ID
+}
+
+func TestSurveyDefinitionRoundTrip(t *testing.T) {
+	const creatorID = 7
+
+	for _, format := range []string{surveydef.FormatJSON, surveydef.FormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			service, surveys := newDefinitionTestService()
+			sourceID := seedSurvey(surveys, creatorID)
+
+			exported, err := service.ExportSurvey(creatorID, sourceID)
+			if err != nil {
+				t.Fatalf("ExportSurvey: %v", err)
+			}
+			if len(exported.Questions) != 6 || len(exported.Quotas) != 2 {
+				t.Fatalf("exported %d questions and %d quotas, want 6 and 2", len(exported.Questions), len(exported.Quotas))
+			}
+			if showIf := exported.Questions[2].ShowIf; showIf == nil || showIf.Question != 2 {
+				t.Fatalf("exported condition of question 3 = %+v, want one on question order 2", showIf)
+			}
+
+			data, err := surveydef.Encode(exported, format)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			decoded, err := surveydef.Decode(data, format)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			imported, err := service.ImportSurvey(creatorID, decoded)
+			if err != nil {
+				t.Fatalf("ImportSurvey: %v", err)
+			}
+			if imported.ID == sourceID {
+				t.Fatalf("import reused the source survey ID %d", sourceID)
+			}
+
+			// The copy references its own questions, not the source's
+			copied, _ := surveys.GetByID(imported.ID)
+			idByOrder := map[int]uint{}
+			for _, q := range copied.Questions {
+				idByOrder[q.Order] = q.ID
+			}
+			if got, want := copied.Questions[1].ShowIf.QuestionID, strconv.FormatUint(uint64(idByOrder[1]), 10); got != want {
+				t.Errorf("imported condition references question %s, want %s", got, want)
+			}
+			if got := copied.Quotas[0].QuestionID; got == nil || *got != idByOrder[1] {
+				t.Errorf("imported quota references question %v, want %d", got, idByOrder[1])
+			}
+
+			reexported, err := service.ExportSurvey(creatorID, imported.ID)
+			if err != nil {
+				t.Fatalf("ExportSurvey of the import: %v", err)
+			}
+			if !reflect.DeepEqual(reexported, exported) {
+				t.Errorf("survey changed on the round trip\n got: %+v\nwant: %+v\n%s", reexported, exported, data)
+			}
+		})
+	}
+}
+
+func TestImportSurveyRejectsSchemaVersion(t *testing.T) {
+	const creatorID = 7
+
+	for _, version := range []int{0, -1, surveydef.SchemaVersion + 1} {
+		service, surveys := newDefinitionTestService()
+		def, err := service.ExportSurvey(creatorID, seedSurvey(surveys, creatorID))
+		if err != nil {
+			t.Fatalf("ExportSurvey: %v", err)
+		}
+		def.SchemaVersion = version
+		stored := len(surveys.surveys)
+
+		_, err = service.ImportSurvey(creatorID, def)
+		var validationErr *surveydef.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("version %d: ImportSurvey = %v, want a *surveydef.ValidationError", version, err)
+		}
+		if len(surveys.surveys) != stored {
+			t.Errorf("version %d: the rejected definition was saved", version)
+		}
+	}
+}
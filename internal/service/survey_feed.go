@@ -0,0 +1,135 @@
+// internal/service/survey_feed.go
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+)
+
+// feedCursor is the opaque cursor handed to clients. It remembers the sort
+// it was made for, since its key means nothing under another one.
+type feedCursor struct {
+	repository.SurveyCursor
+	Sort string `json:"s"`
+}
+
+// buildPublicSurveyFilter validates the search, filters and sort of the
+// public feed. Categories are resolved by the caller.
+func buildPublicSurveyFilter(req *dto.PublicSurveysRequest) (*repository.PublicSurveyFilter, error) {
+	filter := &repository.PublicSurveyFilter{
+		Status:      req.Status,
+		Query:       strings.TrimSpace(req.Query),
+		MinReward:   req.MinReward,
+		MaxReward:   req.MaxReward,
+		MinDuration: req.MinDuration,
+		MaxDuration: req.MaxDuration,
+		MinSlots:    req.MinSlots,
+		Offset:      (req.Page - 1) * req.Limit,
+		Limit:       req.Limit,
+	}
+
+	if len(filter.Query) > 200 {
+		return nil, &InvalidFilterError{Filter: "q", Reason: "can't be longer than 200 characters"}
+	}
+	language, ok := models.NormalizeSurveyLanguage(req.Lang)
+	if !ok {
+		return nil, &InvalidFilterError{Filter: "lang", Reason: "unknown language " + strconv.Quote(req.Lang)}
+	}
+	filter.Language = language
+
+	if req.Status != "" {
+		switch models.SurveyStatus(req.Status) {
+		case models.SurveyStatusDraft, models.SurveyStatusPublished, models.SurveyStatusPaused,
+			models.SurveyStatusCompleted, models.SurveyStatusCancelled:
+		default:
+			return nil, &InvalidFilterError{Filter: "status", Reason: "unknown status " + strconv.Quote(req.Status)}
+		}
+	}
+
+	if req.MinReward != nil && req.MaxReward != nil && *req.MinReward > *req.MaxReward {
+		return nil, &InvalidFilterError{Filter: "reward", Reason: "min_reward is greater than max_reward"}
+	}
+	if req.MinDuration != nil && req.MaxDuration != nil && *req.MinDuration > *req.MaxDuration {
+		return nil, &InvalidFilterError{Filter: "duration", Reason: "min_duration is greater than max_duration"}
+	}
+
+	if req.EndsAfter != "" {
+		after, _, err := parseFilterDate(req.EndsAfter)
+		if err != nil {
+			return nil, &InvalidFilterError{Filter: "ends_after", Reason: "expected YYYY-MM-DD or RFC 3339"}
+		}
+		filter.EndsAfter = &after
+	}
+	if req.EndsBefore != "" {
+		before, dateOnly, err := parseFilterDate(req.EndsBefore)
+		if err != nil {
+			return nil, &InvalidFilterError{Filter: "ends_before", Reason: "expected YYYY-MM-DD or RFC 3339"}
+		}
+		// A plain date includes the whole day
+		if dateOnly {
+			before = before.AddDate(0, 0, 1)
+		}
+		filter.EndsBefore = &before
+	}
+	if filter.EndsAfter != nil && filter.EndsBefore != nil && !filter.EndsAfter.Before(*filter.EndsBefore) {
+		return nil, &InvalidFilterError{Filter: "end_date", Reason: "ends_after must be before ends_before"}
+	}
+
+	switch req.Sort {
+	case "":
+		filter.Sort = repository.SurveySortNewest
+		if filter.Query != "" {
+			filter.Sort = repository.SurveySortRelevance
+		}
+	case repository.SurveySortRelevance:
+		if filter.Query == "" {
+			return nil, &InvalidFilterError{Filter: "sort", Reason: "relevance needs a search query"}
+		}
+		filter.Sort = req.Sort
+	case repository.SurveySortNewest, repository.SurveySortEndingSoon,
+		repository.SurveySortRewardPerMinute, repository.SurveySortPopular:
+		filter.Sort = req.Sort
+	default:
+		return nil, &InvalidFilterError{Filter: "sort", Reason: "unknown sort " + strconv.Quote(req.Sort)}
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeSurveyCursor(req.Cursor)
+		if err != nil || cursor.Sort != filter.Sort {
+			return nil, &InvalidFilterError{Filter: "cursor", Reason: "cursor is invalid or belongs to another sort"}
+		}
+		filter.After = &cursor.SurveyCursor
+		filter.Offset = 0
+	}
+
+	return filter, nil
+}
+
+// encodeSurveyCursor returns the cursor of the next page, or "" on the last
+func encodeSurveyCursor(next *repository.SurveyCursor, sort string) string {
+	if next == nil {
+		return ""
+	}
+	data, err := json.Marshal(feedCursor{SurveyCursor: *next, Sort: sort})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSurveyCursor(value string) (*feedCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	var cursor feedCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,257 @@
+// internal/service/survey_recommendations.go
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+)
+
+// recommendationCandidates is how many of the newest open surveys are
+// scored for each recommendation request
+const recommendationCandidates = 200
+
+// Weights of the recommendation factors, adding up to 1
+const (
+	categoryWeight = 0.40
+	rewardWeight   = 0.30
+	lengthWeight   = 0.15
+	capacityWeight = 0.15
+)
+
+// longSurveyMinutes is the length from which surveys count as long for
+// respondents who often leave surveys unfinished
+const longSurveyMinutes = 30
+
+// neutralQuality is the quality assumed for respondents without completed
+// responses, out of 5
+const neutralQuality = 2.5
+
+// respondentRecord is what the user's responses say about them
+type respondentRecord struct {
+	completed      int
+	completionRate float64
+	averageQuality float64
+	counts         map[string]int     // completed responses per category
+	affinity       map[string]float64 // completed responses per category, weighed by their quality
+	maxAffinity    float64
+}
+
+func newRespondentRecord(history []repository.CategoryHistory) *respondentRecord {
+	record := &respondentRecord{
+		counts:   make(map[string]int),
+		affinity: make(map[string]float64),
+	}
+
+	abandoned := 0
+	qualitySum := 0.0
+	for _, h := range history {
+		record.completed += h.Completed
+		abandoned += h.Abandoned
+		qualitySum += h.AverageQuality * float64(h.Completed)
+		if h.Completed == 0 {
+			continue
+		}
+
+		record.counts[h.Category] = h.Completed
+		// Categories the respondent answers well in count for more
+		affinity := float64(h.Completed) * (0.5 + 0.5*h.AverageQuality/5)
+		record.affinity[h.Category] = affinity
+		record.maxAffinity = math.Max(record.maxAffinity, affinity)
+	}
+
+	// Both estimates start out neutral and follow the record as it grows
+	record.completionRate = float64(record.completed+1) / float64(record.completed+abandoned+2)
+	record.averageQuality = (qualitySum + neutralQuality) / float64(record.completed+1)
+	return record
+}
+
+// GetRecommendedSurveys ranks the open public surveys the user can still
+// take by how well they suit the user, explaining each score
+func (s *surveyService) GetRecommendedSurveys(userID uint, req *dto.RecommendedSurveysRequest) (*dto.RecommendationListResponse, error) {
+	history, err := s.responseRepo.GetCategoryHistory(userID)
+	if err != nil {
+		return nil, err
+	}
+	record := newRespondentRecord(history)
+
+	tree, err := loadCategoryTree(s.categoryRepo)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.surveyRepo.GetRecommendationCandidates(recommendationCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	// Leave out the surveys the user can't take
+	probe := &models.Response{Language: req.Language, Timezone: req.Timezone}
+	eligible := candidates[:0]
+	maxRewardPerMinute := 0.0
+	for _, candidate := range candidates {
+		survey := &candidate.Survey
+		if survey.CreatorID == userID || !survey.IsActive() || survey.FullQuota(probe, nil) != nil {
+			continue
+		}
+		eligible = append(eligible, candidate)
+		maxRewardPerMinute = math.Max(maxRewardPerMinute, rewardPerMinute(survey))
+	}
+
+	recommendations := make([]dto.RecommendationResponse, len(eligible))
+	for i := range eligible {
+		recommendations[i] = s.scoreRecommendation(&eligible[i], record, tree, maxRewardPerMinute, req.Language)
+	}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Survey.ID > recommendations[j].Survey.ID
+	})
+
+	// Only the best ones need checking for an earlier response
+	picked := make([]dto.RecommendationResponse, 0, req.Limit)
+	for _, recommendation := range recommendations {
+		if len(picked) == req.Limit {
+			break
+		}
+		responded, err := s.responseRepo.HasUserResponded(userID, recommendation.Survey.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !responded {
+			picked = append(picked, recommendation)
+		}
+	}
+
+	return &dto.RecommendationListResponse{
+		Recommendations: picked,
+		Profile: dto.RespondentProfile{
+			CompletedResponses: record.completed,
+			CompletionRate:     roundScore(record.completionRate),
+			AverageQuality:     roundScore(record.averageQuality),
+			Categories:         record.counts,
+		},
+	}, nil
+}
+
+func (s *surveyService) scoreRecommendation(
+	candidate *repository.RecommendationCandidate,
+	record *respondentRecord,
+	tree *categoryTree,
+	maxRewardPerMinute float64,
+	locale string,
+) dto.RecommendationResponse {
+	survey := &candidate.Survey
+	factors := []dto.RecommendationFactor{
+		categoryFactor(survey, record, tree, locale),
+		rewardFactor(survey, maxRewardPerMinute),
+		lengthFactor(survey, record),
+		capacityFactor(survey, candidate.PoolRemaining),
+	}
+
+	score := 0.0
+	for i := range factors {
+		factors[i].Value = roundScore(factors[i].Value)
+		factors[i].Points = roundScore(factors[i].Weight * factors[i].Value * 100)
+		score += factors[i].Points
+	}
+
+	return dto.RecommendationResponse{
+		Survey:  s.surveyToItemDTO(survey),
+		Score:   roundScore(score),
+		Factors: factors,
+	}
+}
+
+// categoryFactor favours the categories the user completed surveys in, and
+// at half the weight the other categories under the same top-level one
+func categoryFactor(survey *models.Survey, record *respondentRecord, tree *categoryTree, locale string) dto.RecommendationFactor {
+	factor := dto.RecommendationFactor{Name: "category", Weight: categoryWeight}
+	name := survey.Category
+	if category := tree.bySlug[survey.Category]; category != nil {
+		name = category.Name(locale)
+	}
+
+	if record.maxAffinity == 0 {
+		factor.Reason = "No completed surveys to learn your interests from yet"
+		return factor
+	}
+
+	related := 0.0
+	root := tree.root(survey.Category)
+	for slug, affinity := range record.affinity {
+		if slug != survey.Category && root != "" && tree.root(slug) == root {
+			related += affinity
+		}
+	}
+	factor.Value = math.Min(1, (record.affinity[survey.Category]+related/2)/record.maxAffinity)
+
+	switch {
+	case record.counts[survey.Category] == 1:
+		factor.Reason = fmt.Sprintf("You completed a survey in %s", name)
+	case record.counts[survey.Category] > 1:
+		factor.Reason = fmt.Sprintf("You completed %d surveys in %s", record.counts[survey.Category], name)
+	case related > 0:
+		factor.Reason = fmt.Sprintf("You completed surveys in categories related to %s", name)
+	default:
+		factor.Reason = fmt.Sprintf("You haven't taken surveys in %s yet", name)
+	}
+	return factor
+}
+
+// rewardFactor compares the reward per estimated minute with the best one
+// on offer
+func rewardFactor(survey *models.Survey, maxRewardPerMinute float64) dto.RecommendationFactor {
+	factor := dto.RecommendationFactor{Name: "reward", Weight: rewardWeight}
+	perMinute := rewardPerMinute(survey)
+	if maxRewardPerMinute > 0 {
+		factor.Value = perMinute / maxRewardPerMinute
+	}
+	factor.Reason = fmt.Sprintf("Pays %.2f per estimated minute, the best open survey pays %.2f", perMinute, maxRewardPerMinute)
+	return factor
+}
+
+// lengthFactor holds long surveys back for users who often leave surveys
+// unfinished
+func lengthFactor(survey *models.Survey, record *respondentRecord) dto.RecommendationFactor {
+	length := math.Min(float64(survey.EstimatedDuration)/longSurveyMinutes, 1)
+	return dto.RecommendationFactor{
+		Name:   "length",
+		Weight: lengthWeight,
+		Value:  1 - (1-record.completionRate)*length,
+		Reason: fmt.Sprintf("Takes about %d minutes, and you finish %.0f%% of the surveys you start",
+			survey.EstimatedDuration, record.completionRate*100),
+	}
+}
+
+// capacityFactor favours surveys with plenty of room left, in responses and
+// in their reward pool, which are least likely to fill up before the user
+// is done
+func capacityFactor(survey *models.Survey, poolRemaining float64) dto.RecommendationFactor {
+	factor := dto.RecommendationFactor{Name: "capacity", Weight: capacityWeight}
+	open := survey.MaxResponses - survey.ResponseCount
+	if survey.RewardPerResponse > 0 {
+		if paid := int(poolRemaining / survey.RewardPerResponse); paid < open {
+			open = paid
+		}
+	}
+	if open < 0 {
+		open = 0
+	}
+	if survey.MaxResponses > 0 {
+		factor.Value = float64(open) / float64(survey.MaxResponses)
+	}
+	factor.Reason = fmt.Sprintf("%d of %d responses still open", open, survey.MaxResponses)
+	return factor
+}
+
+func rewardPerMinute(survey *models.Survey) float64 {
+	return survey.RewardPerResponse / math.Max(float64(survey.EstimatedDuration), 1)
+}
+
+func roundScore(value float64) float64 {
+	return math.Round(value*100) / 100
+}
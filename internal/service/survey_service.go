@@ -0,0 +1,952 @@
+// internal/service/survey_service.go
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/cache"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/repository"
+	"survey2earn-backend/internal/surveydef"
+)
+
+type SurveyService interface {
+	CreateSurvey(userID uint, req *dto.CreateSurveyRequest) (*dto.SurveyResponse, error)
+	UpdateSurvey(userID, surveyID uint, req *dto.UpdateSurveyRequest) (*dto.SurveyResponse, error)
+	PublishSurvey(userID, surveyID uint, req *dto.PublishSurveyRequest) (*dto.SurveyResponse, error)
+	GetSurvey(surveyID uint) (*dto.SurveyResponse, error)
+	GetUserSurveys(userID uint, status string, page, limit int) (*dto.SurveyListResponse, error)
+	GetAllSurveys(status string, page, limit int) (*dto.SurveyListResponse, error)
+	GetPublicSurveys(req *dto.PublicSurveysRequest) (*dto.SurveyListResponse, error)
+	GetRecommendedSurveys(userID uint, req *dto.RecommendedSurveysRequest) (*dto.RecommendationListResponse, error)
+	DeleteSurvey(userID, surveyID uint) error
+	GetSurveyAnalytics(userID, surveyID uint) (*dto.SurveyAnalyticsResponse, error)
+	GetCrosstab(userID, surveyID uint, req *dto.CrosstabRequest) (*dto.CrosstabResponse, error)
+	CloneSurvey(userID, surveyID uint, req *dto.CloneSurveyRequest) (*dto.SurveyResponse, error)
+	ExportSurvey(userID, surveyID uint) (*surveydef.SurveyDefinition, error)
+	ImportSurvey(userID uint, def *surveydef.SurveyDefinition) (*dto.SurveyResponse, error)
+}
+
+type surveyService struct {
+	surveyRepo       repository.SurveyRepository
+	userRepo         repository.UserRepository
+	rewardRepo       repository.RewardRepository
+	quotaRepo        repository.QuotaRepository
+	responseRepo     repository.ResponseRepository
+	templateRepo     repository.TemplateRepository
+	textAnalysisRepo repository.TextAnalysisRepository
+	categoryRepo     repository.CategoryRepository
+
+	cache        cache.Cache
+	analyticsTTL time.Duration
+}
+
+func NewSurveyService(
+	surveyRepo repository.SurveyRepository,
+	userRepo repository.UserRepository,
+	rewardRepo repository.RewardRepository,
+	quotaRepo repository.QuotaRepository,
+	responseRepo repository.ResponseRepository,
+	templateRepo repository.TemplateRepository,
+	textAnalysisRepo repository.TextAnalysisRepository,
+	categoryRepo repository.CategoryRepository,
+	analyticsCache cache.Cache,
+	cacheConfig config.CacheConfig,
+) SurveyService {
+	return &surveyService{
+		surveyRepo:       surveyRepo,
+		userRepo:         userRepo,
+		rewardRepo:       rewardRepo,
+		quotaRepo:        quotaRepo,
+		responseRepo:     responseRepo,
+		templateRepo:     templateRepo,
+		textAnalysisRepo: textAnalysisRepo,
+		categoryRepo:     categoryRepo,
+
+		cache:        analyticsCache,
+		analyticsTTL: time.Duration(cacheConfig.AnalyticsTTLSeconds) * time.Second,
+	}
+}
+
+func (s *surveyService) CreateSurvey(userID uint, req *dto.CreateSurveyRequest) (*dto.SurveyResponse, error) {
+	// Validate user exists
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkCategory(s.categoryRepo, req.Category); err != nil {
+		return nil, err
+	}
+	language, ok := models.NormalizeSurveyLanguage(req.Language)
+	if !ok {
+		return nil, unsupportedLanguage(req.Language)
+	}
+
+	// Parse estimated time to minutes
+	estimatedMinutes := s.parseEstimatedTime(req.EstimatedTime)
+
+	// Calculate total reward pool
+	totalRewardPool := req.RewardAmount * float64(req.MaxParticipants)
+
+	// Create survey model
+	survey := &models.Survey{
+		CreatorID:         userID,
+		Title:             req.Title,
+		Description:       req.Description,
+		Category:          req.Category,
+		Language:          language,
+		Status:            models.SurveyStatusDraft,
+		MaxResponses:      req.MaxParticipants,
+		RewardPerResponse: req.RewardAmount,
+		TotalRewardPool:   totalRewardPool,
+		EstimatedDuration: estimatedMinutes,
+		TimeLimitMinutes:  req.TimeLimitMinutes,
+		IsAnonymous:       req.IsAnonymous,
+		IsPublic:          req.IsPublic,
+		RequireLogin:      req.RequireLogin,
+		AllowMultiple:     req.AllowMultiple,
+		StartDate:         req.StartDate,
+		EndDate:           req.EndDate,
+	}
+
+	// Create questions, appending any picked from the question bank
+	questionReqs, err := s.withBankQuestions(userID, req.Questions, req.BankQuestionIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(questionReqs) == 0 {
+		return nil, errNoQuestions
+	}
+	if err := validateConditionalLogic(questionReqs); err != nil {
+		return nil, err
+	}
+	survey.Questions = buildQuestions(0, questionReqs)
+
+	// Save survey
+	if err := s.surveyRepo.Create(survey); err != nil {
+		return nil, err
+	}
+	survey.Creator = *user
+
+	// Attach display conditions now that question IDs are known
+	if err := s.saveConditionalLogic(survey.Questions, questionReqs); err != nil {
+		return nil, err
+	}
+
+	// Create quotas now that question IDs are known
+	if len(req.Quotas) > 0 {
+		quotas, err := s.buildQuotas(survey.Questions, req.Quotas)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.quotaRepo.ReplaceForSurvey(survey.ID, quotas); err != nil {
+			return nil, err
+		}
+		survey.Quotas = quotas
+	}
+
+	// Convert to response DTO
+	return s.surveyToDTO(survey), nil
+}
+
+func (s *surveyService) UpdateSurvey(userID, surveyID uint, req *dto.UpdateSurveyRequest) (*dto.SurveyResponse, error) {
+	// Get survey
+	survey, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if survey.CreatorID != userID {
+		return nil, errSurveyForbidden
+	}
+
+	// Check if survey can be edited
+	if !survey.CanBeEdited() {
+		return nil, apperror.New(apperror.Conflict, "The survey can't be edited after publishing")
+	}
+
+	// Update fields
+	if req.Title != nil {
+		survey.Title = *req.Title
+	}
+	if req.Description != nil {
+		survey.Description = *req.Description
+	}
+	if req.Category != nil && *req.Category != survey.Category {
+		if err := checkCategory(s.categoryRepo, *req.Category); err != nil {
+			return nil, err
+		}
+		survey.Category = *req.Category
+	}
+	if req.Language != nil {
+		language, ok := models.NormalizeSurveyLanguage(*req.Language)
+		if !ok {
+			return nil, unsupportedLanguage(*req.Language)
+		}
+		survey.Language = language
+	}
+	if req.EstimatedTime != nil {
+		survey.EstimatedDuration = s.parseEstimatedTime(*req.EstimatedTime)
+	}
+	if req.TimeLimitMinutes != nil {
+		survey.TimeLimitMinutes = req.TimeLimitMinutes
+		if *req.TimeLimitMinutes == 0 {
+			survey.TimeLimitMinutes = nil
+		}
+	}
+	if req.RewardAmount != nil {
+		survey.RewardPerResponse = *req.RewardAmount
+		survey.TotalRewardPool = *req.RewardAmount * float64(survey.MaxResponses)
+	}
+	if req.MaxParticipants != nil {
+		survey.MaxResponses = *req.MaxParticipants
+		survey.TotalRewardPool = survey.RewardPerResponse * float64(*req.MaxParticipants)
+	}
+	if req.IsAnonymous != nil {
+		survey.IsAnonymous = *req.IsAnonymous
+	}
+	if req.IsPublic != nil {
+		survey.IsPublic = *req.IsPublic
+	}
+	if req.RequireLogin != nil {
+		survey.RequireLogin = *req.RequireLogin
+	}
+	if req.AllowMultiple != nil {
+		survey.AllowMultiple = *req.AllowMultiple
+	}
+
+	// Update questions if provided
+	quotaReqs := req.Quotas
+	if req.Questions != nil {
+		if err := validateConditionalLogic(req.Questions); err != nil {
+			return nil, err
+		}
+
+		// Without new quotas, the existing ones move to the replacement
+		// questions with the same orders as their screener questions
+		if quotaReqs == nil && len(survey.Quotas) > 0 {
+			quotaReqs = quotasToRequest(survey.Quotas, questionOrders(survey.Questions))
+			if err := checkQuotaQuestions(quotaReqs, req.Questions); err != nil {
+				return nil, err
+			}
+		}
+
+		// Delete existing questions
+		if err := s.surveyRepo.DeleteQuestions(surveyID); err != nil {
+			return nil, err
+		}
+
+		// Create new questions
+		survey.Questions = buildQuestions(surveyID, req.Questions)
+	}
+
+	// Save survey
+	if err := s.surveyRepo.Update(survey); err != nil {
+		return nil, err
+	}
+
+	if req.Questions != nil {
+		if err := s.saveConditionalLogic(survey.Questions, req.Questions); err != nil {
+			return nil, err
+		}
+	}
+
+	// Replace quotas if provided or carried over, re-resolving screener
+	// questions since replaced questions get new IDs
+	if quotaReqs != nil {
+		quotas, err := s.buildQuotas(survey.Questions, quotaReqs)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.quotaRepo.ReplaceForSurvey(surveyID, quotas); err != nil {
+			return nil, err
+		}
+		survey.Quotas = quotas
+	}
+
+	return s.surveyToDTO(survey), nil
+}
+
+func (s *surveyService) PublishSurvey(userID, surveyID uint, req *dto.PublishSurveyRequest) (*dto.SurveyResponse, error) {
+	// Get survey
+	survey, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if survey.CreatorID != userID {
+		return nil, errSurveyForbidden
+	}
+
+	// Check if survey can be published
+	if survey.Status != models.SurveyStatusDraft {
+		return nil, apperror.New(apperror.Conflict, "Only draft surveys can be published")
+	}
+
+	// Validate survey has questions
+	if len(survey.Questions) == 0 {
+		return nil, errNoQuestions
+	}
+
+	// Update survey status and dates
+	survey.Status = models.SurveyStatusPublished
+	if req.StartDate != nil {
+		survey.StartDate = req.StartDate
+	}
+	if req.EndDate != nil {
+		survey.EndDate = req.EndDate
+	}
+
+	// Create reward pool
+	rewardPool := &models.RewardPool{
+		SurveyID:          surveyID,
+		TotalAmount:       survey.TotalRewardPool,
+		RewardPerResponse: survey.RewardPerResponse,
+		MaxResponses:      survey.MaxResponses,
+		RemainingAmount:   survey.TotalRewardPool,
+		IsActive:          true,
+	}
+
+	// Save in transaction
+	err = s.surveyRepo.PublishWithRewardPool(survey, rewardPool)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.surveyToDTO(survey), nil
+}
+
+func (s *surveyService) GetSurvey(surveyID uint) (*dto.SurveyResponse, error) {
+	survey, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.surveyToDTO(survey), nil
+}
+
+func (s *surveyService) GetUserSurveys(userID uint, status string, page, limit int) (*dto.SurveyListResponse, error) {
+	surveys, total, err := s.surveyRepo.GetByUserID(userID, status, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.SurveyItemResponse, len(surveys))
+	for i, survey := range surveys {
+		items[i] = s.surveyToItemDTO(&survey)
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return &dto.SurveyListResponse{
+		Surveys:    items,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetAllSurveys pages through every survey on the platform with its
+// creator, for admins
+func (s *surveyService) GetAllSurveys(status string, page, limit int) (*dto.SurveyListResponse, error) {
+	surveys, total, err := s.surveyRepo.List(status, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.SurveyItemResponse, len(surveys))
+	for i, survey := range surveys {
+		items[i] = s.surveyToItemDTO(&survey)
+		items[i].Creator = userToDTO(&survey.Creator)
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return &dto.SurveyListResponse{
+		Surveys:    items,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetPublicSurveys searches the public feed, in the category and its
+// subcategories when one is set, with how many matching surveys each of
+// those categories holds
+func (s *surveyService) GetPublicSurveys(req *dto.PublicSurveysRequest) (*dto.SurveyListResponse, error) {
+	filter, err := buildPublicSurveyFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := loadCategoryTree(s.categoryRepo)
+	if err != nil {
+		return nil, err
+	}
+	if req.Category != "" {
+		if filter.Categories = tree.subtree(req.Category); filter.Categories == nil {
+			return nil, &InvalidCategoryError{Slug: req.Category, Reason: "unknown category"}
+		}
+	}
+
+	page, err := s.surveyRepo.GetPublicSurveys(filter)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.surveyRepo.CountPublicSurveysByCategory(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.SurveyItemResponse, len(page.Surveys))
+	for i, survey := range page.Surveys {
+		items[i] = s.surveyToItemDTO(&survey)
+	}
+
+	totalPages := int(page.Total) / req.Limit
+	if int(page.Total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &dto.SurveyListResponse{
+		Surveys:        items,
+		Total:          page.Total,
+		Page:           req.Page,
+		Limit:          req.Limit,
+		TotalPages:     totalPages,
+		NextCursor:     encodeSurveyCursor(page.Next, filter.Sort),
+		CategoryCounts: tree.subtreeCounts(req.Category, counts),
+	}, nil
+}
+
+func (s *surveyService) DeleteSurvey(userID, surveyID uint) error {
+	// Get survey
+	survey, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return err
+	}
+
+	// Check ownership
+	if survey.CreatorID != userID {
+		return errSurveyForbidden
+	}
+
+	// Check if survey can be deleted
+	if survey.Status != models.SurveyStatusDraft {
+		return apperror.New(apperror.Conflict, "Only draft surveys can be deleted")
+	}
+
+	return s.surveyRepo.Delete(surveyID)
+}
+
+func (s *surveyService) GetSurveyAnalytics(userID, surveyID uint) (*dto.SurveyAnalyticsResponse, error) {
+	// Get survey
+	survey, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if survey.CreatorID != userID {
+		return nil, errSurveyForbidden
+	}
+
+	if !s.cachesAnalytics() {
+		return s.computeSurveyAnalytics(survey)
+	}
+
+	var analytics dto.SurveyAnalyticsResponse
+	err = s.cachedAnalytics(surveyID, "summary", &analytics, func() (interface{}, error) {
+		return s.computeSurveyAnalytics(survey)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &analytics, nil
+}
+
+// computeSurveyAnalytics aggregates the responses of a survey
+func (s *surveyService) computeSurveyAnalytics(survey *models.Survey) (*dto.SurveyAnalyticsResponse, error) {
+	surveyID := survey.ID
+	responses, err := s.responseRepo.GetAllBySurveyID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response level statistics
+	completed := 0
+	totalDuration := 0
+	languages := make(map[string]int)
+	responseLanguages := make(map[uint]string)
+	trends := make(map[string]*dto.ResponseTrendData)
+	answersByQuestion := make(map[uint][]models.Answer)
+	for _, response := range responses {
+		day := response.StartedAt.Format("2006-01-02")
+		if trends[day] == nil {
+			trends[day] = &dto.ResponseTrendData{Date: day}
+		}
+		trends[day].Count++
+
+		if response.Language != "" {
+			languages[response.Language]++
+		}
+		responseLanguages[response.ID] = response.Language
+
+		if response.Status != models.ResponseStatusCompleted {
+			continue
+		}
+		completed++
+		totalDuration += response.Duration
+		trends[day].Completed++
+		for _, answer := range response.Answers {
+			answersByQuestion[answer.QuestionID] = append(answersByQuestion[answer.QuestionID], answer)
+		}
+	}
+
+	completionRate := 0.0
+	averageDuration := 0
+	if len(responses) > 0 {
+		completionRate = float64(completed) / float64(len(responses)) * 100
+	}
+	if completed > 0 {
+		averageDuration = totalDuration / completed
+	}
+
+	textAnalyses, err := s.textAnalysisRepo.GetBySurveyID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+	storedTextAnalyses := make(map[uint]*models.TextAnalysis, len(textAnalyses))
+	for i := range textAnalyses {
+		storedTextAnalyses[textAnalyses[i].QuestionID] = &textAnalyses[i]
+	}
+
+	// Question level statistics, over completed responses only
+	questionAnalytics := make([]dto.QuestionAnalytics, len(survey.Questions))
+	for i, question := range survey.Questions {
+		answers := answersByQuestion[question.ID]
+
+		answered := make([]models.Answer, 0, len(answers))
+		totalTimeSpent := 0
+		for _, answer := range answers {
+			totalTimeSpent += answer.TimeSpent
+			if !answer.IsSkipped {
+				answered = append(answered, answer)
+			}
+		}
+
+		skipRate := 0.0
+		averageTimeSpent := 0
+		if completed > 0 {
+			skipRate = float64(completed-len(answered)) / float64(completed) * 100
+		}
+		if len(answers) > 0 {
+			averageTimeSpent = totalTimeSpent / len(answers)
+		}
+
+		distribution := aggregateAnswers(&question, answered)
+		if isTextQuestion(&question) {
+			distribution["text_analysis"] = s.textAnalysis(survey.ID, &question, answered, responseLanguages, storedTextAnalyses[question.ID])
+		}
+
+		questionAnalytics[i] = dto.QuestionAnalytics{
+			QuestionID:         question.ID,
+			QuestionText:       question.Text,
+			QuestionType:       string(question.Type),
+			ResponseCount:      len(answered),
+			SkipRate:           skipRate,
+			AverageTimeSpent:   averageTimeSpent,
+			AnswerDistribution: distribution,
+		}
+	}
+
+	days := make([]string, 0, len(trends))
+	for day := range trends {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	responseTrends := make([]dto.ResponseTrendData, len(days))
+	for i, day := range days {
+		responseTrends[i] = *trends[day]
+	}
+
+	return &dto.SurveyAnalyticsResponse{
+		SurveyID:        survey.ID,
+		TotalResponses:  len(responses),
+		CompletionRate:  completionRate,
+		AverageRating:   survey.AverageRating,
+		AverageDuration: averageDuration,
+		Demographics: dto.DemographicsData{
+			AgeGroups: map[string]int{},
+			Countries: map[string]int{},
+			Languages: languages,
+		},
+		QuestionAnalytics: questionAnalytics,
+		ResponseTrends:    responseTrends,
+	}, nil
+}
+
+func (s *surveyService) CloneSurvey(userID, surveyID uint, req *dto.CloneSurveyRequest) (*dto.SurveyResponse, error) {
+	// Get source survey
+	source, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if source.CreatorID != userID {
+		return nil, errSurveyForbidden
+	}
+
+	title := "Copy of " + source.Title
+	if req.Title != nil {
+		title = *req.Title
+	}
+
+	orderByQuestionID := questionOrders(source.Questions)
+
+	questions := make([]dto.CreateQuestionRequest, len(source.Questions))
+	for i, q := range source.Questions {
+		questions[i] = definitionToRequest(models.NewQuestionDefinition(&q))
+		questions[i].ShowIf = conditionalLogicToRequest(q.ShowIf, orderByQuestionID)
+	}
+
+	quotas := quotasToRequest(source.Quotas, orderByQuestionID)
+
+	// Clones always start as drafts without a schedule
+	return s.CreateSurvey(userID, &dto.CreateSurveyRequest{
+		Title:            title,
+		Description:      source.Description,
+		Category:         source.Category,
+		Language:         source.Language,
+		EstimatedTime:    formatEstimatedTime(source.EstimatedDuration),
+		TimeLimitMinutes: source.TimeLimitMinutes,
+		RewardAmount:     source.RewardPerResponse,
+		MaxParticipants:  source.MaxResponses,
+		Questions:        questions,
+		Quotas:           quotas,
+		IsAnonymous:      source.IsAnonymous,
+		IsPublic:         source.IsPublic,
+		RequireLogin:     source.RequireLogin,
+		AllowMultiple:    source.AllowMultiple,
+	})
+}
+
+func (s *surveyService) ExportSurvey(userID, surveyID uint) (*surveydef.SurveyDefinition, error) {
+	survey, err := s.surveyRepo.GetByID(surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	if survey.CreatorID != userID {
+		return nil, errSurveyForbidden
+	}
+
+	return surveyToDefinition(survey), nil
+}
+
+func (s *surveyService) ImportSurvey(userID uint, def *surveydef.SurveyDefinition) (*dto.SurveyResponse, error) {
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Imports always start as drafts
+	return s.CreateSurvey(userID, definitionToCreateRequest(def))
+}
+
+// Helper methods
+
+// withBankQuestions appends the requested question bank entries after the
+// explicitly defined questions
+func (s *surveyService) withBankQuestions(userID uint, questions []dto.CreateQuestionRequest, bankQuestionIDs []uint) ([]dto.CreateQuestionRequest, error) {
+	if len(bankQuestionIDs) == 0 {
+		return questions, nil
+	}
+
+	bankQuestions, err := s.templateRepo.GetBankQuestionsByIDs(bankQuestionIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.BankQuestion, len(bankQuestions))
+	for _, bq := range bankQuestions {
+		byID[bq.ID] = bq
+	}
+
+	nextOrder := 0
+	for _, q := range questions {
+		if q.Order > nextOrder {
+			nextOrder = q.Order
+		}
+	}
+
+	result := append([]dto.CreateQuestionRequest{}, questions...)
+	for _, id := range bankQuestionIDs {
+		bq, ok := byID[id]
+		if !ok || !bq.IsAccessibleBy(userID) {
+			return nil, bankQuestionNotFound(id)
+		}
+		nextOrder++
+		q := definitionToRequest(bq.Definition)
+		q.Order = nextOrder
+		result = append(result, q)
+	}
+
+	if err := s.templateRepo.IncrementBankQuestionUsage(bankQuestionIDs); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// saveConditionalLogic resolves and stores the display conditions of freshly
+// saved questions
+func (s *surveyService) saveConditionalLogic(questions []models.Question, reqs []dto.CreateQuestionRequest) error {
+	changed := resolveConditionalLogic(questions, reqs)
+	if len(changed) == 0 {
+		return nil
+	}
+	return s.surveyRepo.UpdateQuestionConditions(changed)
+}
+
+func (s *surveyService) parseEstimatedTime(timeStr string) int {
+	// Parse time strings like "5-10 min", "15+ min" to minutes
+	switch timeStr {
+	case "1-3 min":
+		return 3
+	case "3-5 min":
+		return 5
+	case "5-10 min":
+		return 10
+	case "10-15 min":
+		return 15
+	case "15+ min":
+		return 20
+	default:
+		return 10
+	}
+}
+
+// formatEstimatedTime is the inverse of parseEstimatedTime
+func formatEstimatedTime(minutes int) string {
+	switch {
+	case minutes <= 3:
+		return "1-3 min"
+	case minutes <= 5:
+		return "3-5 min"
+	case minutes <= 10:
+		return "5-10 min"
+	case minutes <= 15:
+		return "10-15 min"
+	default:
+		return "15+ min"
+	}
+}
+
+func (s *surveyService) buildQuotas(questions []models.Question, reqs []dto.QuotaRequest) ([]models.SurveyQuota, error) {
+	quotas := make([]models.SurveyQuota, len(reqs))
+	for i, q := range reqs {
+		quota := models.SurveyQuota{
+			Name:       q.Name,
+			Value:      q.Value,
+			Limit:      q.Limit,
+			Percentage: q.Percentage,
+			IsActive:   true,
+		}
+
+		if q.QuestionOrder != nil {
+			for _, question := range questions {
+				if question.Order == *q.QuestionOrder {
+					questionID := question.ID
+					quota.QuestionID = &questionID
+					break
+				}
+			}
+			if quota.QuestionID == nil {
+				return nil, apperror.Invalid(fmt.Sprintf("Quota %q references unknown question order %d", q.Name, *q.QuestionOrder), apperror.FieldError{
+					Field:   fmt.Sprintf("quotas[%d].questionOrder", i),
+					Code:    "not_found",
+					Message: "no question has this order",
+				})
+			}
+		}
+		if q.MetadataField != nil {
+			field := models.QuotaMetadataField(*q.MetadataField)
+			quota.MetadataField = &field
+		}
+
+		if err := quota.Validate(); err != nil {
+			return nil, apperror.Invalid(fmt.Sprintf("Invalid quota %q", q.Name), apperror.FieldError{
+				Field:   fmt.Sprintf("quotas[%d]", i),
+				Code:    "invalid",
+				Message: err.Error(),
+			})
+		}
+		quotas[i] = quota
+	}
+	return quotas, nil
+}
+
+// questionOrders maps the IDs of questions to their orders
+func questionOrders(questions []models.Question) map[uint]int {
+	orderByQuestionID := make(map[uint]int, len(questions))
+	for _, q := range questions {
+		orderByQuestionID[q.ID] = q.Order
+	}
+	return orderByQuestionID
+}
+
+// quotasToRequest converts saved quotas back into quota requests,
+// referencing screener questions by their order
+func quotasToRequest(quotas []models.SurveyQuota, orderByQuestionID map[uint]int) []dto.QuotaRequest {
+	reqs := make([]dto.QuotaRequest, len(quotas))
+	for i, q := range quotas {
+		reqs[i] = dto.QuotaRequest{
+			Name:       q.Name,
+			Value:      q.Value,
+			Limit:      q.Limit,
+			Percentage: q.Percentage,
+		}
+		if q.QuestionID != nil {
+			order := orderByQuestionID[*q.QuestionID]
+			reqs[i].QuestionOrder = &order
+		}
+		if q.MetadataField != nil {
+			field := string(*q.MetadataField)
+			reqs[i].MetadataField = &field
+		}
+	}
+	return reqs
+}
+
+// checkQuotaQuestions checks that the questions replacing a survey's
+// questions still have the screener questions of its quotas, so a question
+// replacement can't leave quotas keyed by deleted questions
+func checkQuotaQuestions(quotas []dto.QuotaRequest, questions []dto.CreateQuestionRequest) error {
+	orders := make(map[int]bool, len(questions))
+	for _, q := range questions {
+		orders[q.Order] = true
+	}
+	for i, quota := range quotas {
+		if quota.QuestionOrder == nil || orders[*quota.QuestionOrder] {
+			continue
+		}
+		return apperror.Invalid(fmt.Sprintf("Quota %q is keyed by question %d, which the new questions don't have; send the quotas with the questions", quota.Name, *quota.QuestionOrder), apperror.FieldError{
+			Field:   fmt.Sprintf("quotas[%d].questionOrder", i),
+			Code:    "not_found",
+			Message: "no question has this order",
+		})
+	}
+	return nil
+}
+
+func (s *surveyService) surveyToDTO(survey *models.Survey) *dto.SurveyResponse {
+	questions := make([]dto.QuestionResponse, len(survey.Questions))
+	for i, q := range survey.Questions {
+		questions[i] = questionToDTO(&q)
+	}
+
+	quotas := make([]dto.QuotaResponse, len(survey.Quotas))
+	for i, q := range survey.Quotas {
+		capacity := q.Capacity(survey.MaxResponses)
+		remaining := capacity - q.CurrentCount
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		var metadataField *string
+		if q.MetadataField != nil {
+			field := string(*q.MetadataField)
+			metadataField = &field
+		}
+
+		quotas[i] = dto.QuotaResponse{
+			ID:            q.ID,
+			Name:          q.Name,
+			QuestionID:    q.QuestionID,
+			MetadataField: metadataField,
+			Value:         q.Value,
+			Limit:         q.Limit,
+			Percentage:    q.Percentage,
+			Capacity:      capacity,
+			CurrentCount:  q.CurrentCount,
+			Remaining:     remaining,
+			FillRate:      q.FillRate(survey.MaxResponses),
+			IsFull:        q.IsFull(survey.MaxResponses),
+		}
+	}
+
+	return &dto.SurveyResponse{
+		ID:                survey.ID,
+		CreatorID:         survey.CreatorID,
+		Title:             survey.Title,
+		Description:       survey.Description,
+		Category:          survey.Category,
+		Language:          survey.Language,
+		Status:            string(survey.Status),
+		MaxResponses:      survey.MaxResponses,
+		RewardPerResponse: survey.RewardPerResponse,
+		TotalRewardPool:   survey.TotalRewardPool,
+		EstimatedDuration: survey.EstimatedDuration,
+		TimeLimitMinutes:  survey.TimeLimitMinutes,
+		ResponseCount:     survey.ResponseCount,
+		CompletionRate:    survey.CompletionRate,
+		AverageRating:     survey.AverageRating,
+		IsAnonymous:       survey.IsAnonymous,
+		IsPublic:          survey.IsPublic,
+		RequireLogin:      survey.RequireLogin,
+		AllowMultiple:     survey.AllowMultiple,
+		StartDate:         survey.StartDate,
+		EndDate:           survey.EndDate,
+		CreatedAt:         survey.CreatedAt,
+		UpdatedAt:         survey.UpdatedAt,
+		Questions:         questions,
+		Quotas:            quotas,
+		Creator: dto.UserResponse{
+			ID:              survey.Creator.ID,
+			WalletAddress:   survey.Creator.WalletAddress,
+			Username:        survey.Creator.Username,
+			ReputationScore: survey.Creator.ReputationScore,
+		},
+	}
+}
+
+func (s *surveyService) surveyToItemDTO(survey *models.Survey) dto.SurveyItemResponse {
+	progress := float64(survey.ResponseCount) / float64(survey.MaxResponses) * 100
+	if progress > 100 {
+		progress = 100
+	}
+
+	return dto.SurveyItemResponse{
+		ID:                survey.ID,
+		Title:             survey.Title,
+		Description:       survey.Description,
+		Category:          survey.Category,
+		Language:          survey.Language,
+		Status:            string(survey.Status),
+		RewardPerResponse: survey.RewardPerResponse,
+		XpReward:          survey.EstimatedDuration * 10, // Mock XP calculation
+		EstimatedDuration: survey.EstimatedDuration,
+		TimeLimitMinutes:  survey.TimeLimitMinutes,
+		ResponseCount:     survey.ResponseCount,
+		MaxResponses:      survey.MaxResponses,
+		EndDate:           survey.EndDate,
+		CompletionRate:    survey.CompletionRate,
+		AverageRating:     survey.AverageRating,
+		CreatedAt:         survey.CreatedAt,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,457 @@
+// internal/service/template_service.go
+package service
+
+import (
+	"fmt"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+)
+
+type TemplateService interface {
+	ListTemplates(userID uint, req *dto.ListTemplatesRequest) (*dto.TemplateListResponse, error)
+	GetTemplate(userID, templateID uint) (*dto.TemplateResponse, error)
+	CreateTemplate(userID uint, req *dto.CreateTemplateRequest) (*dto.TemplateResponse, error)
+	UpdateTemplate(userID, templateID uint, req *dto.UpdateTemplateRequest) (*dto.TemplateResponse, error)
+	DeleteTemplate(userID, templateID uint) error
+	CreateSurveyFromTemplate(userID, templateID uint, req *dto.CreateFromTemplateRequest) (*dto.SurveyResponse, error)
+
+	ListBankQuestions(userID uint, req *dto.ListBankQuestionsRequest) (*dto.BankQuestionListResponse, error)
+	CreateBankQuestion(userID uint, req *dto.CreateBankQuestionRequest) (*dto.BankQuestionResponse, error)
+	DeleteBankQuestion(userID, bankQuestionID uint) error
+
+	// Admin curation of platform templates and the platform question bank
+	CreatePlatformTemplate(req *dto.CreateTemplateRequest) (*dto.TemplateResponse, error)
+	UpdatePlatformTemplate(templateID uint, req *dto.UpdateTemplateRequest) (*dto.TemplateResponse, error)
+	DeletePlatformTemplate(templateID uint) error
+	CreatePlatformBankQuestion(req *dto.CreateBankQuestionRequest) (*dto.BankQuestionResponse, error)
+	DeletePlatformBankQuestion(bankQuestionID uint) error
+}
+
+var (
+	errTemplateForbidden    = apperror.New(apperror.Forbidden, "You don't have permission to access this template")
+	errNotPlatformTemplate  = apperror.New(apperror.Forbidden, "Only platform templates can be curated")
+	errBankQuestionNotFound = apperror.New(apperror.NotFound, "Bank question not found")
+)
+
+type templateService struct {
+	templateRepo  repository.TemplateRepository
+	categoryRepo  repository.CategoryRepository
+	surveyService SurveyService
+}
+
+func NewTemplateService(
+	templateRepo repository.TemplateRepository,
+	categoryRepo repository.CategoryRepository,
+	surveyService SurveyService,
+) TemplateService {
+	return &templateService{
+		templateRepo:  templateRepo,
+		categoryRepo:  categoryRepo,
+		surveyService: surveyService,
+	}
+}
+
+func (s *templateService) ListTemplates(userID uint, req *dto.ListTemplatesRequest) (*dto.TemplateListResponse, error) {
+	templates, total, err := s.templateRepo.ListTemplates(userID, req.Scope, req.Category, req.Page, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.TemplateResponse, len(templates))
+	for i, template := range templates {
+		items[i] = s.templateToDTO(&template)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &dto.TemplateListResponse{
+		Templates:  items,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *templateService) GetTemplate(userID, templateID uint) (*dto.TemplateResponse, error) {
+	template, err := s.templateRepo.GetTemplateByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !template.IsAccessibleBy(userID) {
+		return nil, errTemplateForbidden
+	}
+
+	response := s.templateToDTO(template)
+	return &response, nil
+}
+
+func (s *templateService) CreateTemplate(userID uint, req *dto.CreateTemplateRequest) (*dto.TemplateResponse, error) {
+	return s.createTemplate(&userID, req)
+}
+
+func (s *templateService) UpdateTemplate(userID, templateID uint, req *dto.UpdateTemplateRequest) (*dto.TemplateResponse, error) {
+	template, err := s.templateRepo.GetTemplateByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only personal templates can be edited by their owner
+	if template.IsPlatform() || *template.OwnerID != userID {
+		return nil, errTemplateForbidden
+	}
+
+	return s.updateTemplate(template, req)
+}
+
+func (s *templateService) DeleteTemplate(userID, templateID uint) error {
+	template, err := s.templateRepo.GetTemplateByID(templateID)
+	if err != nil {
+		return err
+	}
+
+	if template.IsPlatform() || *template.OwnerID != userID {
+		return errTemplateForbidden
+	}
+
+	return s.templateRepo.DeleteTemplate(templateID)
+}
+
+func (s *templateService) CreateSurveyFromTemplate(userID, templateID uint, req *dto.CreateFromTemplateRequest) (*dto.SurveyResponse, error) {
+	template, err := s.templateRepo.GetTemplateByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !template.IsAccessibleBy(userID) {
+		return nil, errTemplateForbidden
+	}
+
+	questions := make([]dto.CreateQuestionRequest, len(template.Questions))
+	for i, definition := range template.Questions {
+		questions[i] = definitionToRequest(definition)
+	}
+
+	surveyReq := &dto.CreateSurveyRequest{
+		Title:           template.Name,
+		Description:     template.Description,
+		Category:        template.Category,
+		EstimatedTime:   formatEstimatedTime(template.EstimatedDuration),
+		RewardAmount:    req.RewardAmount,
+		MaxParticipants: req.MaxParticipants,
+		XpReward:        req.XpReward,
+		Questions:       questions,
+		IsAnonymous:     req.IsAnonymous,
+		IsPublic:        req.IsPublic,
+		RequireLogin:    req.RequireLogin,
+		AllowMultiple:   req.AllowMultiple,
+		StartDate:       req.StartDate,
+		EndDate:         req.EndDate,
+	}
+	if req.Title != nil {
+		surveyReq.Title = *req.Title
+	}
+	if req.Description != nil {
+		surveyReq.Description = *req.Description
+	}
+	if req.Category != nil {
+		surveyReq.Category = *req.Category
+	}
+
+	survey, err := s.surveyService.CreateSurvey(userID, surveyReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.IncrementTemplateUsage(templateID); err != nil {
+		return nil, err
+	}
+
+	return survey, nil
+}
+
+func (s *templateService) ListBankQuestions(userID uint, req *dto.ListBankQuestionsRequest) (*dto.BankQuestionListResponse, error) {
+	questions, total, err := s.templateRepo.ListBankQuestions(userID, req.Scope, req.Category, req.Type, req.Page, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.BankQuestionResponse, len(questions))
+	for i, question := range questions {
+		items[i] = s.bankQuestionToDTO(&question)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &dto.BankQuestionListResponse{
+		Questions:  items,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *templateService) CreateBankQuestion(userID uint, req *dto.CreateBankQuestionRequest) (*dto.BankQuestionResponse, error) {
+	return s.createBankQuestion(&userID, req)
+}
+
+func (s *templateService) DeleteBankQuestion(userID, bankQuestionID uint) error {
+	questions, err := s.templateRepo.GetBankQuestionsByIDs([]uint{bankQuestionID})
+	if err != nil {
+		return err
+	}
+	if len(questions) == 0 {
+		return errBankQuestionNotFound
+	}
+
+	if questions[0].IsPlatform() || *questions[0].OwnerID != userID {
+		return apperror.New(apperror.Forbidden, "You don't have permission to delete this bank question")
+	}
+
+	return s.templateRepo.DeleteBankQuestion(bankQuestionID)
+}
+
+func (s *templateService) CreatePlatformTemplate(req *dto.CreateTemplateRequest) (*dto.TemplateResponse, error) {
+	return s.createTemplate(nil, req)
+}
+
+func (s *templateService) UpdatePlatformTemplate(templateID uint, req *dto.UpdateTemplateRequest) (*dto.TemplateResponse, error) {
+	template, err := s.templateRepo.GetTemplateByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !template.IsPlatform() {
+		return nil, errNotPlatformTemplate
+	}
+
+	return s.updateTemplate(template, req)
+}
+
+func (s *templateService) DeletePlatformTemplate(templateID uint) error {
+	template, err := s.templateRepo.GetTemplateByID(templateID)
+	if err != nil {
+		return err
+	}
+
+	if !template.IsPlatform() {
+		return errNotPlatformTemplate
+	}
+
+	return s.templateRepo.DeleteTemplate(templateID)
+}
+
+func (s *templateService) CreatePlatformBankQuestion(req *dto.CreateBankQuestionRequest) (*dto.BankQuestionResponse, error) {
+	// Platform bank questions are always filed under a category
+	if err := checkCategory(s.categoryRepo, req.Category); err != nil {
+		return nil, err
+	}
+	return s.createBankQuestion(nil, req)
+}
+
+func (s *templateService) DeletePlatformBankQuestion(bankQuestionID uint) error {
+	questions, err := s.templateRepo.GetBankQuestionsByIDs([]uint{bankQuestionID})
+	if err != nil {
+		return err
+	}
+	if len(questions) == 0 {
+		return errBankQuestionNotFound
+	}
+
+	if !questions[0].IsPlatform() {
+		return apperror.New(apperror.Forbidden, "Only platform bank questions can be curated")
+	}
+
+	return s.templateRepo.DeleteBankQuestion(bankQuestionID)
+}
+
+// Helper methods
+
+func (s *templateService) createTemplate(ownerID *uint, req *dto.CreateTemplateRequest) (*dto.TemplateResponse, error) {
+	userID := uint(0)
+	if ownerID != nil {
+		userID = *ownerID
+	}
+
+	if err := checkCategory(s.categoryRepo, req.Category); err != nil {
+		return nil, err
+	}
+
+	definitions, err := s.buildDefinitions(userID, req.Questions, req.BankQuestionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.SurveyTemplate{
+		OwnerID:           ownerID,
+		Name:              req.Name,
+		Description:       req.Description,
+		Category:          req.Category,
+		EstimatedDuration: parseTemplateDuration(req.EstimatedTime, len(definitions)),
+		Questions:         definitions,
+		IsCurated:         ownerID == nil,
+		IsActive:          true,
+	}
+
+	if err := s.templateRepo.CreateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	response := s.templateToDTO(template)
+	return &response, nil
+}
+
+func (s *templateService) updateTemplate(template *models.SurveyTemplate, req *dto.UpdateTemplateRequest) (*dto.TemplateResponse, error) {
+	if req.Name != nil {
+		template.Name = *req.Name
+	}
+	if req.Description != nil {
+		template.Description = *req.Description
+	}
+	if req.Category != nil && *req.Category != template.Category {
+		if err := checkCategory(s.categoryRepo, *req.Category); err != nil {
+			return nil, err
+		}
+		template.Category = *req.Category
+	}
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+	if req.Questions != nil {
+		definitions, err := s.buildDefinitions(0, req.Questions, nil)
+		if err != nil {
+			return nil, err
+		}
+		template.Questions = definitions
+	}
+	if req.EstimatedTime != nil {
+		template.EstimatedDuration = parseTemplateDuration(*req.EstimatedTime, len(template.Questions))
+	}
+
+	if err := s.templateRepo.UpdateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	response := s.templateToDTO(template)
+	return &response, nil
+}
+
+func (s *templateService) createBankQuestion(ownerID *uint, req *dto.CreateBankQuestionRequest) (*dto.BankQuestionResponse, error) {
+	if !models.QuestionType(req.Question.Type).IsValid() {
+		return nil, unknownQuestionType("question.type", req.Question.Type)
+	}
+	if req.Category != "" {
+		if err := checkCategory(s.categoryRepo, req.Category); err != nil {
+			return nil, err
+		}
+	}
+
+	question := &models.BankQuestion{
+		OwnerID:    ownerID,
+		Category:   req.Category,
+		Definition: requestToDefinition(req.Question),
+	}
+
+	if err := s.templateRepo.CreateBankQuestion(question); err != nil {
+		return nil, err
+	}
+
+	response := s.bankQuestionToDTO(question)
+	return &response, nil
+}
+
+// buildDefinitions converts explicit questions and question bank picks into
+// template question definitions, ordered explicit questions first
+func (s *templateService) buildDefinitions(userID uint, questions []dto.CreateQuestionRequest, bankQuestionIDs []uint) (models.QuestionDefinitions, error) {
+	definitions := make(models.QuestionDefinitions, 0, len(questions)+len(bankQuestionIDs))
+	for i, q := range questions {
+		if !models.QuestionType(q.Type).IsValid() {
+			return nil, unknownQuestionType(fmt.Sprintf("questions[%d].type", i), q.Type)
+		}
+		definitions = append(definitions, requestToDefinition(q))
+	}
+
+	if len(bankQuestionIDs) > 0 {
+		bankQuestions, err := s.templateRepo.GetBankQuestionsByIDs(bankQuestionIDs)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[uint]models.BankQuestion, len(bankQuestions))
+		for _, bq := range bankQuestions {
+			byID[bq.ID] = bq
+		}
+		for _, id := range bankQuestionIDs {
+			bq, ok := byID[id]
+			if !ok || !bq.IsAccessibleBy(userID) {
+				return nil, bankQuestionNotFound(id)
+			}
+			definitions = append(definitions, bq.Definition)
+		}
+		if err := s.templateRepo.IncrementBankQuestionUsage(bankQuestionIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(definitions) == 0 {
+		return nil, errNoQuestions
+	}
+
+	// Renumber so bank questions follow the explicit ones
+	for i := range definitions {
+		definitions[i].Order = i + 1
+	}
+	return definitions, nil
+}
+
+func (s *templateService) templateToDTO(template *models.SurveyTemplate) dto.TemplateResponse {
+	questions := make([]dto.QuestionResponse, len(template.Questions))
+	for i, definition := range template.Questions {
+		questions[i] = definitionToDTO(definition)
+	}
+
+	return dto.TemplateResponse{
+		ID:                template.ID,
+		Name:              template.Name,
+		Description:       template.Description,
+		Category:          template.Category,
+		EstimatedDuration: template.EstimatedDuration,
+		IsPlatform:        template.IsPlatform(),
+		IsCurated:         template.IsCurated,
+		IsActive:          template.IsActive,
+		UsageCount:        template.UsageCount,
+		Questions:         questions,
+		CreatedAt:         template.CreatedAt,
+		UpdatedAt:         template.UpdatedAt,
+	}
+}
+
+func (s *templateService) bankQuestionToDTO(question *models.BankQuestion) dto.BankQuestionResponse {
+	return dto.BankQuestionResponse{
+		ID:         question.ID,
+		Category:   question.Category,
+		IsPlatform: question.IsPlatform(),
+		UsageCount: question.UsageCount,
+		Question:   definitionToDTO(question.Definition),
+		CreatedAt:  question.CreatedAt,
+	}
+}
+
+// parseTemplateDuration parses an estimated time like "5-10 min", falling
+// back to one minute per question when no estimate was given
+func parseTemplateDuration(estimatedTime string, questionCount int) int {
+	if estimatedTime == "" {
+		return questionCount
+	}
+	var parser surveyService
+	return parser.parseEstimatedTime(estimatedTime)
+}
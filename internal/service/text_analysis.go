@@ -0,0 +1,119 @@
+// internal/service/text_analysis.go
+package service
+
+import (
+	"sort"
+	"strings"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"survey2earn-backend/internal/textanalysis"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	textKeywordLimit = 20
+	textClusterTerms = 3
+)
+
+func isTextQuestion(question *models.Question) bool {
+	return question.Type == models.QuestionTypeText || question.Type == models.QuestionTypeTextArea
+}
+
+// addTextAnswers adds the answers of a completed response to the text
+// analyses of their questions
+func addTextAnswers(repo repository.TextAnalysisRepository, survey *models.Survey, response *models.Response) error {
+	for _, answer := range response.Answers {
+		if answer.IsSkipped || strings.TrimSpace(answer.AnswerText) == "" {
+			continue
+		}
+		question, err := survey.GetQuestionByID(answer.QuestionID)
+		if err != nil || !isTextQuestion(question) {
+			continue
+		}
+
+		text := answer.AnswerText
+		err = repo.Update(survey.ID, question.ID, func(analysis *models.TextAnalysis) error {
+			analysis.State.Add(text, response.Language)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// textAnalysis returns the stored analysis of a text question. When it
+// doesn't account for every answer, e.g. for responses completed before
+// analyses were kept or when an update failed, it is rebuilt from the
+// answers and stored again.
+func (s *surveyService) textAnalysis(surveyID uint, question *models.Question, answers []models.Answer, languages map[uint]string, stored *models.TextAnalysis) *dto.TextAnalysisResponse {
+	texts := make([]models.Answer, 0, len(answers))
+	for _, answer := range answers {
+		if strings.TrimSpace(answer.AnswerText) != "" {
+			texts = append(texts, answer)
+		}
+	}
+
+	if stored != nil && stored.State.Documents == len(texts) {
+		return textAnalysisToDTO(&stored.State)
+	}
+
+	var state textanalysis.State
+	for _, answer := range texts {
+		state.Add(answer.AnswerText, languages[answer.ResponseID])
+	}
+	err := s.textAnalysisRepo.Update(surveyID, question.ID, func(analysis *models.TextAnalysis) error {
+		analysis.State = state
+		return nil
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("question_id", question.ID).Warn("Failed to store rebuilt text analysis")
+	}
+
+	return textAnalysisToDTO(&state)
+}
+
+func textAnalysisToDTO(state *textanalysis.State) *dto.TextAnalysisResponse {
+	keywords := state.TopKeywords(textKeywordLimit)
+	result := &dto.TextAnalysisResponse{
+		Keywords: make([]dto.KeywordFrequency, len(keywords)),
+		Sentiment: dto.SentimentSummary{
+			AverageScore: state.AverageSentiment(),
+			Positive:     state.Positive,
+			Neutral:      state.Neutral,
+			Negative:     state.Negative,
+		},
+		Clusters:    make([]dto.TextCluster, 0, len(state.Clusters)),
+		Unclustered: state.Unclustered,
+	}
+	for i, keyword := range keywords {
+		result.Keywords[i] = dto.KeywordFrequency{
+			Term:      keyword.Term,
+			Count:     keyword.Count,
+			Responses: keyword.Documents,
+		}
+	}
+
+	// Answers alone in their cluster aren't a theme
+	for i := range state.Clusters {
+		cluster := &state.Clusters[i]
+		if cluster.Size < 2 {
+			result.Unclustered += cluster.Size
+			continue
+		}
+		result.Clusters = append(result.Clusters, dto.TextCluster{
+			Terms:    cluster.TopTerms(textClusterTerms),
+			Size:     cluster.Size,
+			Percent:  percentage(cluster.Size, state.Documents),
+			Examples: cluster.Examples,
+		})
+	}
+	sort.SliceStable(result.Clusters, func(i, j int) bool {
+		return result.Clusters[i].Size > result.Clusters[j].Size
+	})
+
+	return result
+}
@@ -0,0 +1,644 @@
+// internal/service/webhook_service.go
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/config"
+	"survey2earn-backend/internal/dto"
+	"survey2earn-backend/internal/models"
+	"survey2earn-backend/internal/repository"
+	"survey2earn-backend/internal/webhook"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// webhookPollInterval is how often the worker looks for due deliveries,
+	// such as those queued by the outbox consumer
+	webhookPollInterval = 5 * time.Second
+
+	// webhookBatchSize is the number of deliveries sent concurrently
+	webhookBatchSize = 20
+
+	// webhookRetryBase is the delay before the first retry; it doubles with
+	// every attempt up to webhookMaxRetryDelay
+	webhookRetryBase     = 30 * time.Second
+	webhookMaxRetryDelay = 6 * time.Hour
+
+	// webhookMaxResponseBody is how much of an endpoint's response is logged
+	webhookMaxResponseBody = 4096
+)
+
+// InvalidWebhookError is returned when a webhook registration is invalid
+type InvalidWebhookError struct {
+	Reason string
+}
+
+func (e *InvalidWebhookError) Error() string {
+	return "invalid webhook: " + e.Reason
+}
+
+func (e *InvalidWebhookError) AppError() *apperror.Error {
+	return apperror.New(apperror.InvalidWebhook, e.Error())
+}
+
+// errWebhookAddressNotPublic is returned when a delivery would connect to a
+// loopback, private, link-local or otherwise non-public address
+var errWebhookAddressNotPublic = errors.New("webhook url resolves to a non-public address")
+
+type WebhookService interface {
+	CreateWebhook(userID uint, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
+	GetWebhooks(userID uint) ([]dto.WebhookResponse, error)
+	GetWebhook(userID, webhookID uint) (*dto.WebhookResponse, error)
+	UpdateWebhook(userID, webhookID uint, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error)
+	DeleteWebhook(userID, webhookID uint) error
+	RotateWebhookSecret(userID, webhookID uint) (*dto.WebhookResponse, error)
+	GetDeliveries(userID, webhookID uint, req *dto.ListWebhookDeliveriesRequest) (*dto.WebhookDeliveryListResponse, error)
+	RedeliverDelivery(userID, webhookID, deliveryID uint) (*dto.WebhookDeliveryResponse, error)
+	TestWebhook(userID, webhookID uint) (*dto.WebhookDeliveryResponse, error)
+
+	// HandleOutboxEvents queues deliveries of outbox events, as the webhooks outbox consumer
+	HandleOutboxEvents(tx *gorm.DB, events []models.OutboxEvent) error
+
+	// Run sends due deliveries until ctx is done
+	Run(ctx context.Context)
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	surveyRepo  repository.SurveyRepository
+	config      config.WebhookConfig
+	client      *http.Client
+	wake        chan struct{}
+}
+
+func NewWebhookService(
+	webhookRepo repository.WebhookRepository,
+	surveyRepo repository.SurveyRepository,
+	config config.WebhookConfig,
+) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		surveyRepo:  surveyRepo,
+		config:      config,
+		client: &http.Client{
+			Timeout:   time.Duration(config.TimeoutSeconds) * time.Second,
+			Transport: webhookTransport(config.AllowPrivateNetworks),
+			// Redirects are not followed so a delivery can't be sent somewhere
+			// the creator didn't register
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// webhookTransport makes the connections of deliveries. Unless private
+// networks are allowed, it refuses to connect to non-public addresses. The
+// check is made on the address dialed, after the host name is resolved, so
+// a public name can't resolve to an internal service, and no proxy is used
+// so the address dialed is the endpoint's own.
+func webhookTransport(allowPrivateNetworks bool) http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if allowPrivateNetworks {
+		return transport
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return errWebhookAddressNotPublic
+			}
+			return nil
+		},
+	}
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+func (s *webhookService) CreateWebhook(userID uint, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	if err := validateWebhookURL(req.URL, s.config.AllowPrivateNetworks); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookEvents(req.Events); err != nil {
+		return nil, err
+	}
+
+	if req.SurveyID != nil {
+		survey, err := s.surveyRepo.GetByID(*req.SurveyID)
+		if err != nil {
+			return nil, err
+		}
+		if survey.CreatorID != userID {
+			return nil, errSurveyForbidden
+		}
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	hook := &models.Webhook{
+		UserID:      userID,
+		SurveyID:    req.SurveyID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      uniqueStrings(req.Events),
+		Description: req.Description,
+		IsActive:    true,
+	}
+	if err := s.webhookRepo.Create(hook); err != nil {
+		return nil, err
+	}
+
+	response := webhookToDTO(hook)
+	response.Secret = secret
+	return response, nil
+}
+
+func (s *webhookService) GetWebhooks(userID uint) ([]dto.WebhookResponse, error) {
+	webhooks, err := s.webhookRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.WebhookResponse, len(webhooks))
+	for i := range webhooks {
+		responses[i] = *webhookToDTO(&webhooks[i])
+	}
+	return responses, nil
+}
+
+func (s *webhookService) GetWebhook(userID, webhookID uint) (*dto.WebhookResponse, error) {
+	hook, err := s.getOwnWebhook(userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	return webhookToDTO(hook), nil
+}
+
+func (s *webhookService) UpdateWebhook(userID, webhookID uint, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	hook, err := s.getOwnWebhook(userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL, s.config.AllowPrivateNetworks); err != nil {
+			return nil, err
+		}
+		hook.URL = *req.URL
+	}
+	if req.Events != nil {
+		if err := validateWebhookEvents(req.Events); err != nil {
+			return nil, err
+		}
+		hook.Events = uniqueStrings(req.Events)
+	}
+	if req.Description != nil {
+		hook.Description = *req.Description
+	}
+	if req.IsActive != nil {
+		hook.IsActive = *req.IsActive
+	}
+
+	if err := s.webhookRepo.Update(hook); err != nil {
+		return nil, err
+	}
+	return webhookToDTO(hook), nil
+}
+
+func (s *webhookService) DeleteWebhook(userID, webhookID uint) error {
+	if _, err := s.getOwnWebhook(userID, webhookID); err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(webhookID)
+}
+
+// RotateWebhookSecret replaces the signing secret of a webhook. Deliveries
+// are signed with the new secret from then on, including retries.
+func (s *webhookService) RotateWebhookSecret(userID, webhookID uint) (*dto.WebhookResponse, error) {
+	hook, err := s.getOwnWebhook(userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	hook.Secret = secret
+	if err := s.webhookRepo.Update(hook); err != nil {
+		return nil, err
+	}
+
+	response := webhookToDTO(hook)
+	response.Secret = secret
+	return response, nil
+}
+
+func (s *webhookService) GetDeliveries(userID, webhookID uint, req *dto.ListWebhookDeliveriesRequest) (*dto.WebhookDeliveryListResponse, error) {
+	if _, err := s.getOwnWebhook(userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := s.webhookRepo.GetDeliveries(webhookID, req.Status, req.Page, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		responses[i] = *webhookDeliveryToDTO(&deliveries[i])
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &dto.WebhookDeliveryListResponse{
+		Deliveries: responses,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// RedeliverDelivery queues the event of a past delivery to be sent again.
+// The replay keeps the event ID so receivers can recognize it.
+func (s *webhookService) RedeliverDelivery(userID, webhookID, deliveryID uint) (*dto.WebhookDeliveryResponse, error) {
+	if _, err := s.getOwnWebhook(userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	original, err := s.webhookRepo.GetDelivery(webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	replay := []models.WebhookDelivery{{
+		WebhookID:     webhookID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        models.WebhookDeliveryStatusPending,
+		NextAttemptAt: &now,
+	}}
+	if err := s.webhookRepo.CreateDeliveries(replay); err != nil {
+		return nil, err
+	}
+	s.wakeUp()
+
+	return webhookDeliveryToDTO(&replay[0]), nil
+}
+
+// TestWebhook sends a webhook.test event right away and returns the result,
+// even if the webhook is inactive. Failed test deliveries are not retried.
+func (s *webhookService) TestWebhook(userID, webhookID uint) (*dto.WebhookDeliveryResponse, error) {
+	hook, err := s.getOwnWebhook(userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID, err := models.NewEventID()
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := newWebhookDeliveries([]models.Webhook{*hook}, eventID, models.WebhookEventTest, time.Now(), hook.SurveyID, map[string]interface{}{
+		"webhook_id": hook.ID,
+		"message":    "This is a test event from Survey2Earn",
+	})
+	if err != nil {
+		return nil, err
+	}
+	delivery := &deliveries[0]
+	delivery.NextAttemptAt = nil
+	if err := s.webhookRepo.CreateDeliveries(deliveries); err != nil {
+		return nil, err
+	}
+
+	delivery.Webhook = *hook
+	s.deliver(delivery, false)
+
+	return webhookDeliveryToDTO(delivery), nil
+}
+
+// HandleOutboxEvents queues deliveries of outbox events to the subscribed
+// webhooks of each survey's creator. It runs in the transaction that moves
+// the webhooks consumer past the events, so every event is queued once.
+func (s *webhookService) HandleOutboxEvents(tx *gorm.DB, events []models.OutboxEvent) error {
+	webhookRepo := s.webhookRepo.WithTx(tx)
+
+	for _, event := range events {
+		if !models.IsWebhookEventType(event.Type) {
+			continue
+		}
+
+		webhooks, err := webhookRepo.GetSubscribed(event.SurveyID, event.Type)
+		if err != nil {
+			return err
+		}
+		if len(webhooks) == 0 {
+			continue
+		}
+
+		surveyID := event.SurveyID
+		deliveries, err := newWebhookDeliveries(webhooks, event.EventID, event.Type, event.CreatedAt, &surveyID, json.RawMessage(event.Payload))
+		if err != nil {
+			return err
+		}
+		if err := webhookRepo.CreateDeliveries(deliveries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *webhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.deliverDue()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// deliverDue sends claimed deliveries until none are due
+func (s *webhookService) deliverDue() {
+	// A claimed delivery isn't picked up again until its attempt has had time to finish
+	lease := 2*s.client.Timeout + time.Minute
+
+	for {
+		deliveries, err := s.webhookRepo.ClaimDueDeliveries(time.Now(), lease, webhookBatchSize)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to claim webhook deliveries")
+			return
+		}
+
+		var wg sync.WaitGroup
+		for i := range deliveries {
+			wg.Add(1)
+			go func(delivery *models.WebhookDelivery) {
+				defer wg.Done()
+				s.deliver(delivery, true)
+			}(&deliveries[i])
+		}
+		wg.Wait()
+
+		if len(deliveries) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+// deliver makes one attempt at a delivery and records its outcome. Queued
+// deliveries are retried on failure and dropped if their webhook was
+// deactivated in the meantime.
+func (s *webhookService) deliver(delivery *models.WebhookDelivery, queued bool) {
+	log := logrus.WithFields(logrus.Fields{
+		"delivery_id": delivery.ID,
+		"webhook_id":  delivery.WebhookID,
+		"event":       delivery.EventType,
+	})
+
+	if delivery.Webhook.ID == 0 || (queued && !delivery.Webhook.IsActive) {
+		delivery.MarkAttemptFailed(0, "", "webhook is deleted or inactive", nil)
+	} else {
+		statusCode, body, err := s.post(&delivery.Webhook, delivery)
+		switch {
+		case err == nil:
+			delivery.MarkAsSucceeded(statusCode, body)
+		case queued && delivery.Attempts+1 < s.config.MaxAttempts:
+			next := time.Now().Add(webhookRetryDelay(delivery.Attempts + 1))
+			delivery.MarkAttemptFailed(statusCode, body, err.Error(), &next)
+		default:
+			delivery.MarkAttemptFailed(statusCode, body, err.Error(), nil)
+		}
+	}
+
+	if err := s.webhookRepo.UpdateDelivery(delivery); err != nil {
+		log.WithError(err).Error("Failed to record webhook delivery attempt")
+		return
+	}
+	if delivery.Status == models.WebhookDeliveryStatusFailed {
+		log.WithField("attempts", delivery.Attempts).Warn("Webhook delivery failed: " + delivery.Error)
+	}
+}
+
+// post sends a delivery to its webhook. Any response other than 2xx is an error.
+func (s *webhookService) post(hook *models.Webhook, delivery *models.WebhookDelivery) (int, string, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Survey2Earn-Webhooks/1.0")
+	req.Header.Set(webhook.EventHeader, delivery.EventType)
+	req.Header.Set(webhook.DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(hook.Secret, time.Now(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, string(responseBody), fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+	return resp.StatusCode, string(responseBody), nil
+}
+
+// newWebhookDeliveries builds a pending delivery of one event for every webhook
+func newWebhookDeliveries(webhooks []models.Webhook, eventID, eventType string, createdAt time.Time, surveyID *uint, data interface{}) ([]models.WebhookDelivery, error) {
+	payload, err := json.Marshal(dto.WebhookPayload{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: createdAt.UTC(),
+		SurveyID:  surveyID,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deliveries := make([]models.WebhookDelivery, len(webhooks))
+	for i, hook := range webhooks {
+		deliveries[i] = models.WebhookDelivery{
+			WebhookID:     hook.ID,
+			EventID:       eventID,
+			EventType:     eventType,
+			Payload:       string(payload),
+			Status:        models.WebhookDeliveryStatusPending,
+			NextAttemptAt: &now,
+		}
+	}
+	return deliveries, nil
+}
+
+func (s *webhookService) getOwnWebhook(userID, webhookID uint) (*models.Webhook, error) {
+	hook, err := s.webhookRepo.GetByID(webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if hook.UserID != userID {
+		return nil, apperror.New(apperror.Forbidden, "You don't have permission to manage this webhook")
+	}
+	return hook, nil
+}
+
+// wakeUp tells the worker there are deliveries to send without waiting for its next poll
+func (s *webhookService) wakeUp() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// webhookRetryDelay is the wait after a failed attempt, doubling from webhookRetryBase
+func webhookRetryDelay(attempt int) time.Duration {
+	delay := webhookRetryBase
+	for i := 1; i < attempt && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > webhookMaxRetryDelay {
+		return webhookMaxRetryDelay
+	}
+	return delay
+}
+
+// validateWebhookURL checks a webhook URL when it is registered. Unless
+// private networks are allowed, localhost and non-public IP addresses are
+// rejected; host names that resolve to them are refused when delivering.
+func validateWebhookURL(rawURL string, allowPrivateNetworks bool) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return &InvalidWebhookError{Reason: "url must be an absolute URL"}
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return &InvalidWebhookError{Reason: "url must use http or https"}
+	}
+	if parsed.User != nil {
+		return &InvalidWebhookError{Reason: "url must not contain credentials"}
+	}
+	if allowPrivateNetworks {
+		return nil
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return &InvalidWebhookError{Reason: "url must not point at localhost"}
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return &InvalidWebhookError{Reason: "url must not point at a private or reserved address"}
+	}
+	return nil
+}
+
+// isPublicIP reports whether ip can be reached on the public internet, as
+// opposed to loopback, private, link-local, unspecified or multicast
+// addresses
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, which is private to
+// the provider's network
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func validateWebhookEvents(events []string) error {
+	for _, event := range events {
+		if !models.IsWebhookEventType(event) {
+			return &InvalidWebhookError{Reason: fmt.Sprintf("unknown event %q", event)}
+		}
+	}
+	return nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+func webhookToDTO(hook *models.Webhook) *dto.WebhookResponse {
+	return &dto.WebhookResponse{
+		ID:          hook.ID,
+		SurveyID:    hook.SurveyID,
+		URL:         hook.URL,
+		Events:      hook.Events,
+		Description: hook.Description,
+		IsActive:    hook.IsActive,
+		CreatedAt:   hook.CreatedAt,
+		UpdatedAt:   hook.UpdatedAt,
+	}
+}
+
+func webhookDeliveryToDTO(delivery *models.WebhookDelivery) *dto.WebhookDeliveryResponse {
+	return &dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		ResponseBody:   delivery.ResponseBody,
+		Error:          delivery.Error,
+		DeliveredAt:    delivery.DeliveredAt,
+		Payload:        json.RawMessage(delivery.Payload),
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
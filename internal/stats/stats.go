@@ -0,0 +1,190 @@
+// internal/stats/stats.go
+//
+// Package stats implements the statistical tests used by survey analytics.
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// ChiSquareResult is the outcome of Pearson's chi-square test of independence
+type ChiSquareResult struct {
+	Statistic        float64
+	DegreesOfFreedom int
+	PValue           float64
+	CramersV         float64
+	LowExpectedCells int // cells with an expected count below 5, where the test is unreliable
+}
+
+// ChiSquareTest runs Pearson's chi-square test of independence on a
+// contingency table of observed counts. Rows and columns without any
+// observations are ignored.
+func ChiSquareTest(observed [][]float64) (*ChiSquareResult, error) {
+	rowTotals, columnTotals, total := marginals(observed)
+	if total == 0 {
+		return nil, errors.New("contingency table is empty")
+	}
+
+	rows, columns := 0, 0
+	for _, t := range rowTotals {
+		if t > 0 {
+			rows++
+		}
+	}
+	for _, t := range columnTotals {
+		if t > 0 {
+			columns++
+		}
+	}
+	if rows < 2 || columns < 2 {
+		return nil, errors.New("chi-square test needs at least two rows and two columns with observations")
+	}
+
+	result := &ChiSquareResult{DegreesOfFreedom: (rows - 1) * (columns - 1)}
+	for i, row := range observed {
+		if rowTotals[i] == 0 {
+			continue
+		}
+		for j, count := range row {
+			if columnTotals[j] == 0 {
+				continue
+			}
+			expected := rowTotals[i] * columnTotals[j] / total
+			if expected < 5 {
+				result.LowExpectedCells++
+			}
+			diff := count - expected
+			result.Statistic += diff * diff / expected
+		}
+	}
+
+	result.PValue = ChiSquarePValue(result.Statistic, result.DegreesOfFreedom)
+	result.CramersV = math.Sqrt(result.Statistic / (total * float64(min(rows, columns)-1)))
+	return result, nil
+}
+
+// ChiSquarePValue returns the probability of a chi-square statistic at least
+// as large as x with df degrees of freedom
+func ChiSquarePValue(x float64, df int) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return upperIncompleteGamma(float64(df)/2, x/2)
+}
+
+// WilsonInterval returns the Wilson score interval of a proportion with the
+// given confidence level, e.g. 0.95
+func WilsonInterval(successes, n, confidence float64) (lower, upper float64) {
+	if n <= 0 {
+		return 0, 0
+	}
+	z := NormalQuantile(1 - (1-confidence)/2)
+	p := successes / n
+	z2 := z * z
+	denominator := 1 + z2/n
+	center := (p + z2/(2*n)) / denominator
+	margin := z * math.Sqrt(p*(1-p)/n+z2/(4*n*n)) / denominator
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// NormalQuantile returns the inverse of the standard normal CDF, using
+// Acklam's rational approximation (relative error below 1.15e-9)
+func NormalQuantile(p float64) float64 {
+	if p <= 0 {
+		return math.Inf(-1)
+	}
+	if p >= 1 {
+		return math.Inf(1)
+	}
+
+	a := [...]float64{-3.969683028665376e+01, 2.209460984245205e+02, -2.759285104469687e+02,
+		1.383577518672690e+02, -3.066479806614716e+01, 2.506628277459239e+00}
+	b := [...]float64{-5.447609879822406e+01, 1.615858368580409e+02, -1.556989798598866e+02,
+		6.680131188771972e+01, -1.328068155288572e+01}
+	c := [...]float64{-7.784894002430293e-03, -3.223964580411365e-01, -2.400758277161838e+00,
+		-2.549732539343734e+00, 4.374664141464968e+00, 2.938163982698783e+00}
+	d := [...]float64{7.784695709041462e-03, 3.224671290700398e-01, 2.445134137142996e+00,
+		3.754408661907416e+00}
+
+	const low = 0.02425
+	switch {
+	case p < low:
+		q := math.Sqrt(-2 * math.Log(p))
+		return (((((c[0]*q+c[1])*q+c[2])*q+c[3])*q+c[4])*q + c[5]) /
+			((((d[0]*q+d[1])*q+d[2])*q+d[3])*q + 1)
+	case p > 1-low:
+		q := math.Sqrt(-2 * math.Log(1-p))
+		return -(((((c[0]*q+c[1])*q+c[2])*q+c[3])*q+c[4])*q + c[5]) /
+			((((d[0]*q+d[1])*q+d[2])*q+d[3])*q + 1)
+	default:
+		q := p - 0.5
+		r := q * q
+		return (((((a[0]*r+a[1])*r+a[2])*r+a[3])*r+a[4])*r + a[5]) * q /
+			(((((b[0]*r+b[1])*r+b[2])*r+b[3])*r+b[4])*r + 1)
+	}
+}
+
+func marginals(observed [][]float64) (rowTotals, columnTotals []float64, total float64) {
+	rowTotals = make([]float64, len(observed))
+	for i, row := range observed {
+		if len(row) > len(columnTotals) {
+			columnTotals = append(columnTotals, make([]float64, len(row)-len(columnTotals))...)
+		}
+		for j, count := range row {
+			rowTotals[i] += count
+			columnTotals[j] += count
+			total += count
+		}
+	}
+	return rowTotals, columnTotals, total
+}
+
+// upperIncompleteGamma returns the regularized upper incomplete gamma
+// function Q(a, x), by series expansion for small x and by continued
+// fraction otherwise
+func upperIncompleteGamma(a, x float64) float64 {
+	const (
+		maxIterations = 500
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+	lgamma, _ := math.Lgamma(a)
+
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < maxIterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+		return math.Max(0, 1-sum*math.Exp(-x+a*math.Log(x)-lgamma))
+	}
+
+	// Modified Lentz's method
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < maxIterations; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lgamma) * h
+}
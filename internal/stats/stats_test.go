@@ -0,0 +1,141 @@
+// internal/stats/stats_test.go
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
+
+func TestChiSquareTest(t *testing.T) {
+	// Expected counts 12, 18, 28 and 42: 4/12 + 4/18 + 4/28 + 4/42
+	tests := []struct {
+		name     string
+		observed [][]float64
+	}{
+		{"2x2", [][]float64{{10, 20}, {30, 40}}},
+		{"with an empty row", [][]float64{{10, 20}, {0, 0}, {30, 40}}},
+		{"with an empty column", [][]float64{{10, 0, 20}, {30, 0, 40}}},
+		{"with ragged rows", [][]float64{{10, 20, 0}, {30, 40}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := ChiSquareTest(test.observed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !near(result.Statistic, 0.7937, 1e-4) {
+				t.Errorf("statistic = %v, want 0.7937", result.Statistic)
+			}
+			if result.DegreesOfFreedom != 1 {
+				t.Errorf("degrees of freedom = %d, want 1", result.DegreesOfFreedom)
+			}
+			if !near(result.PValue, 0.373, 1e-3) {
+				t.Errorf("p-value = %v, want 0.373", result.PValue)
+			}
+			if !near(result.CramersV, 0.0891, 1e-4) {
+				t.Errorf("Cramér's V = %v, want 0.0891", result.CramersV)
+			}
+			if result.LowExpectedCells != 0 {
+				t.Errorf("%d cells have a low expected count, want none", result.LowExpectedCells)
+			}
+		})
+	}
+}
+
+func TestChiSquareTestLargerTable(t *testing.T) {
+	// Only the last row, with 6 observations, has expected counts below 5
+	result, err := ChiSquareTest([][]float64{{20, 5, 5}, {5, 20, 5}, {1, 2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DegreesOfFreedom != 4 {
+		t.Errorf("degrees of freedom = %d, want 4", result.DegreesOfFreedom)
+	}
+	if result.LowExpectedCells != 3 {
+		t.Errorf("%d cells have a low expected count, want the 3 of the last row", result.LowExpectedCells)
+	}
+	if result.PValue >= 0.001 {
+		t.Errorf("p-value = %v, want a strong association", result.PValue)
+	}
+}
+
+func TestChiSquareTestDegenerate(t *testing.T) {
+	tests := map[string][][]float64{
+		"no table":            nil,
+		"no observations":     {{0, 0}, {0, 0}},
+		"a single row":        {{10, 20}},
+		"one row observed":    {{10, 20}, {0, 0}},
+		"one column observed": {{10, 0}, {30, 0}},
+	}
+	for name, observed := range tests {
+		if result, err := ChiSquareTest(observed); err == nil {
+			t.Errorf("%s: ChiSquareTest() = %+v, want an error", name, result)
+		}
+	}
+}
+
+func TestChiSquarePValue(t *testing.T) {
+	tests := []struct {
+		x    float64
+		df   int
+		want float64
+	}{
+		{3.841459, 1, 0.05}, // critical values at the 5% level
+		{5.991465, 2, 0.05},
+		{18.307038, 10, 0.05},
+		{6.634897, 1, 0.01},
+		{4, 2, math.Exp(-2)}, // with two degrees of freedom, P = exp(-x/2)
+		{0.5, 2, math.Exp(-0.25)},
+		{0, 3, 1},
+		{-1, 3, 1},
+		{200, 1, 0},
+	}
+	for _, test := range tests {
+		if got := ChiSquarePValue(test.x, test.df); !near(got, test.want, 1e-6) {
+			t.Errorf("ChiSquarePValue(%v, %d) = %v, want %v", test.x, test.df, got, test.want)
+		}
+	}
+}
+
+func TestWilsonInterval(t *testing.T) {
+	tests := []struct {
+		successes, n, confidence float64
+		lower, upper             float64
+	}{
+		{5, 10, 0.95, 0.2366, 0.7634},
+		{0, 10, 0.95, 0, 0.2775},
+		{10, 10, 0.95, 0.7225, 1},
+		{50, 100, 0.99, 0.3753, 0.6247},
+		{0, 0, 0.95, 0, 0},
+	}
+	for _, test := range tests {
+		lower, upper := WilsonInterval(test.successes, test.n, test.confidence)
+		if !near(lower, test.lower, 1e-4) || !near(upper, test.upper, 1e-4) {
+			t.Errorf("WilsonInterval(%v, %v, %v) = [%.4f, %.4f], want [%v, %v]",
+				test.successes, test.n, test.confidence, lower, upper, test.lower, test.upper)
+		}
+	}
+}
+
+func TestNormalQuantile(t *testing.T) {
+	tests := []struct{ p, want float64 }{
+		{0.5, 0},
+		{0.975, 1.959964},
+		{0.995, 2.575829},
+		{0.01, -2.326348}, // lower tail
+		{0.999, 3.090232}, // upper tail
+		{1e-9, -5.997807},
+	}
+	for _, test := range tests {
+		if got := NormalQuantile(test.p); !near(got, test.want, 1e-6) {
+			t.Errorf("NormalQuantile(%v) = %v, want %v", test.p, got, test.want)
+		}
+	}
+	if !math.IsInf(NormalQuantile(0), -1) || !math.IsInf(NormalQuantile(1), 1) {
+		t.Error("NormalQuantile(0) and NormalQuantile(1) aren't infinite")
+	}
+}
@@ -0,0 +1,120 @@
+// internal/surveydef/codec.go
+package surveydef
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported serialization formats
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// FormatFromPath guesses the format from a file extension, defaulting to JSON
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatJSON
+	}
+}
+
+// FormatFromContentType guesses the format from a Content-Type header, defaulting to JSON
+func FormatFromContentType(contentType string) string {
+	if strings.Contains(strings.ToLower(contentType), "yaml") {
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+// ContentType returns the MIME type for a format
+func ContentType(format string) string {
+	if format == FormatYAML {
+		return "application/yaml"
+	}
+	return "application/json"
+}
+
+// Encode serializes a definition in the given format
+func Encode(def *SurveyDefinition, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(def, "", "  ")
+	case FormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(def); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// Decode parses a definition in the given format. Unknown fields are rejected
+// so typos don't silently drop configuration.
+func Decode(data []byte, format string) (*SurveyDefinition, error) {
+	var def SurveyDefinition
+
+	switch format {
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&def); err != nil {
+			return nil, fmt.Errorf("invalid JSON survey definition: %w", err)
+		}
+	case FormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&def); err != nil {
+			return nil, fmt.Errorf("invalid YAML survey definition: %w", err)
+		}
+		// YAML decodes integers as int while JSON uses float64; normalize so
+		// condition values compare the same whichever format was used
+		for i := range def.Questions {
+			if def.Questions[i].ShowIf != nil {
+				def.Questions[i].ShowIf.Value = normalizeNumbers(def.Questions[i].ShowIf.Value)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	return &def, nil
+}
+
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeNumbers(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = normalizeNumbers(v[key])
+		}
+		return v
+	default:
+		return value
+	}
+}
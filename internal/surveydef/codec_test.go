@@ -0,0 +1,165 @@
+// internal/surveydef/codec_test.go
+package surveydef
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int              { return &v }
+func floatPtr(v float64) *float64    { return &v }
+func stringPtr(v string) *string     { return &v }
+func timePtr(t time.Time) *time.Time { return &t }
+
+// testDefinition uses every part of the format: settings, options, matrix
+// rows, constraints, file uploads, conditions and both kinds of quota
+func testDefinition() *SurveyDefinition {
+	return &SurveyDefinition{
+		SchemaVersion: SchemaVersion,
+		Title:         "DeFi habits",
+		Description:   "How people use DeFi",
+		Category:      "finance",
+		Language:      "en",
+		EstimatedTime: "5-10 min",
+		Settings: Settings{
+			MaxResponses:      200,
+			RewardPerResponse: 2.5,
+			TimeLimitMinutes:  intPtr(15),
+			IsAnonymous:       true,
+			IsPublic:          true,
+			RequireLogin:      true,
+			AllowMultiple:     false,
+			StartDate:         timePtr(time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)),
+			EndDate:           timePtr(time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC)),
+		},
+		Questions: []QuestionDefinition{
+			{
+				Order:    1,
+				Type:     "single_choice",
+				Text:     "How often do you use DeFi?",
+				Required: true,
+				Options: []Option{
+					{ID: "daily", Label: "Daily", Value: "daily", Order: 1},
+					{ID: "weekly", Label: "Weekly", Value: "weekly", Order: 2},
+				},
+			},
+			{
+				Order:    2,
+				Type:     "rating",
+				Text:     "How do you rate your wallet?",
+				MinValue: floatPtr(1),
+				MaxValue: floatPtr(5),
+				ShowIf:   &Condition{Question: 1, Operator: "equals", Value: "daily"},
+			},
+			{
+				Order:       3,
+				Type:        "text",
+				Text:        "Why?",
+				Description: "A sentence or two",
+				MinLength:   intPtr(5),
+				MaxLength:   intPtr(200),
+				ShowIf:      &Condition{Question: 2, Operator: "greater_than", Value: float64(3)},
+			},
+			{
+				Order:   4,
+				Type:    "matrix",
+				Text:    "Rate each protocol",
+				Options: []Option{{Label: "Bad", Value: "bad", Order: 1}, {Label: "Good", Value: "good", Order: 2}},
+				Rows:    []Option{{Label: "Uniswap", Value: "uniswap", Order: 1}, {Label: "Aave", Value: "aave", Order: 2}},
+			},
+			{
+				Order:    5,
+				Type:     "constant_sum",
+				Text:     "Split 100 points",
+				Options:  []Option{{Label: "Fees", Value: "fees", Order: 1}, {Label: "Speed", Value: "speed", Order: 2}},
+				SumTotal: floatPtr(100),
+			},
+			{
+				Order: 6,
+				Type:  "file_upload",
+				Text:  "Upload a screenshot",
+				FileConfig: &FileConfig{
+					AllowedTypes: []string{"image/png", "image/jpeg"},
+					MaxSizeBytes: 1 << 20,
+					MaxFiles:     2,
+				},
+			},
+		},
+		Quotas: []QuotaDefinition{
+			{Name: "Daily users", Question: intPtr(1), Value: "daily", Percentage: floatPtr(40)},
+			{Name: "English speakers", MetadataField: stringPtr("language"), Value: "en", Limit: intPtr(50)},
+		},
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			def := testDefinition()
+			data, err := Encode(def, format)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			decoded, err := Decode(data, format)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if err := decoded.Validate(); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, def) {
+				t.Errorf("decoded definition differs from the encoded one\n got: %+v\nwant: %+v\n%s", decoded, def, data)
+			}
+		})
+	}
+}
+
+func TestDecodeRejectsUnknownFields(t *testing.T) {
+	tests := map[string]string{
+		FormatJSON: `{"schema_version": 1, "title": "Survey", "category": "finance", "colour": "blue"}`,
+		FormatYAML: "schema_version: 1\ntitle: Survey\ncategory: finance\ncolour: blue\n",
+	}
+	for format, data := range tests {
+		t.Run(format, func(t *testing.T) {
+			_, err := Decode([]byte(data), format)
+			if err == nil || !strings.Contains(err.Error(), "colour") {
+				t.Errorf("Decode = %v, want an error naming the unknown field", err)
+			}
+		})
+	}
+}
+
+func TestValidateSchemaVersion(t *testing.T) {
+	tests := []struct {
+		version int
+		valid   bool
+	}{
+		{version: SchemaVersion, valid: true},
+		{version: 0},
+		{version: -1},
+		{version: SchemaVersion + 1},
+	}
+	for _, tt := range tests {
+		def := testDefinition()
+		def.SchemaVersion = tt.version
+
+		err := def.Validate()
+		if tt.valid {
+			if err != nil {
+				t.Errorf("version %d: Validate = %v, want nil", tt.version, err)
+			}
+			continue
+		}
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("version %d: Validate = %v, want a *ValidationError", tt.version, err)
+		}
+		if len(validationErr.Problems) != 1 || validationErr.Problems[0].Path != "schema_version" {
+			t.Errorf("version %d: problems = %+v, want one on schema_version", tt.version, validationErr.Problems)
+		}
+	}
+}
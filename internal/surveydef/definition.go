@@ -0,0 +1,92 @@
+// internal/surveydef/definition.go
+//
+// Package surveydef defines the portable, versioned survey definition format
+// used to move surveys between environments and keep them in version control.
+// Definitions reference questions by their order instead of database IDs, so
+// they can be imported into any environment.
+package surveydef
+
+import (
+	"time"
+)
+
+// SchemaVersion is the current version of the survey definition format.
+// Bump it whenever a change is not backwards compatible.
+const SchemaVersion = 1
+
+// SurveyDefinition is a survey with its questions, logic, quotas and settings
+type SurveyDefinition struct {
+	SchemaVersion int                  `json:"schema_version" yaml:"schema_version"`
+	Title         string               `json:"title" yaml:"title"`
+	Description   string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Category      string               `json:"category" yaml:"category"`
+	Language      string               `json:"language,omitempty" yaml:"language,omitempty"`
+	EstimatedTime string               `json:"estimated_time,omitempty" yaml:"estimated_time,omitempty"` // 1-3 min, 3-5 min, 5-10 min, 10-15 min, 15+ min
+	Settings      Settings             `json:"settings" yaml:"settings"`
+	Questions     []QuestionDefinition `json:"questions" yaml:"questions"`
+	Quotas        []QuotaDefinition    `json:"quotas,omitempty" yaml:"quotas,omitempty"`
+}
+
+// Settings holds the survey configuration
+type Settings struct {
+	MaxResponses      int        `json:"max_responses" yaml:"max_responses"`
+	RewardPerResponse float64    `json:"reward_per_response" yaml:"reward_per_response"`
+	TimeLimitMinutes  *int       `json:"time_limit_minutes,omitempty" yaml:"time_limit_minutes,omitempty"`
+	IsAnonymous       bool       `json:"is_anonymous" yaml:"is_anonymous"`
+	IsPublic          bool       `json:"is_public" yaml:"is_public"`
+	RequireLogin      bool       `json:"require_login" yaml:"require_login"`
+	AllowMultiple     bool       `json:"allow_multiple" yaml:"allow_multiple"`
+	StartDate         *time.Time `json:"start_date,omitempty" yaml:"start_date,omitempty"`
+	EndDate           *time.Time `json:"end_date,omitempty" yaml:"end_date,omitempty"`
+}
+
+// QuestionDefinition is a single question. Order identifies the question
+// within the definition and is what conditions and quotas refer to.
+type QuestionDefinition struct {
+	Order       int         `json:"order" yaml:"order"`
+	Type        string      `json:"type" yaml:"type"`
+	Text        string      `json:"text" yaml:"text"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool        `json:"required" yaml:"required"`
+	Options     []Option    `json:"options,omitempty" yaml:"options,omitempty"`
+	Rows        []Option    `json:"rows,omitempty" yaml:"rows,omitempty"` // matrix rows; options are the columns
+	MinLength   *int        `json:"min_length,omitempty" yaml:"min_length,omitempty"`
+	MaxLength   *int        `json:"max_length,omitempty" yaml:"max_length,omitempty"`
+	MinValue    *float64    `json:"min_value,omitempty" yaml:"min_value,omitempty"`
+	MaxValue    *float64    `json:"max_value,omitempty" yaml:"max_value,omitempty"`
+	SumTotal    *float64    `json:"sum_total,omitempty" yaml:"sum_total,omitempty"`
+	FileConfig  *FileConfig `json:"file_config,omitempty" yaml:"file_config,omitempty"`
+	ShowIf      *Condition  `json:"show_if,omitempty" yaml:"show_if,omitempty"`
+}
+
+// Option is a choice, a ranking item, a constant-sum item or a matrix row/column
+type Option struct {
+	ID    string `json:"id,omitempty" yaml:"id,omitempty"`
+	Label string `json:"label" yaml:"label"`
+	Value string `json:"value" yaml:"value"`
+	Order int    `json:"order" yaml:"order"`
+}
+
+// FileConfig holds the constraints of a file upload question
+type FileConfig struct {
+	AllowedTypes []string `json:"allowed_types,omitempty" yaml:"allowed_types,omitempty"`
+	MaxSizeBytes int64    `json:"max_size_bytes,omitempty" yaml:"max_size_bytes,omitempty"`
+	MaxFiles     int      `json:"max_files,omitempty" yaml:"max_files,omitempty"`
+}
+
+// Condition shows a question only when the answer to an earlier question matches
+type Condition struct {
+	Question int         `json:"question" yaml:"question"` // order of the referenced question
+	Operator string      `json:"operator" yaml:"operator"` // equals, not_equals, contains, greater_than, less_than
+	Value    interface{} `json:"value" yaml:"value"`
+}
+
+// QuotaDefinition caps completed responses by a screener answer or a metadata field
+type QuotaDefinition struct {
+	Name          string   `json:"name" yaml:"name"`
+	Question      *int     `json:"question,omitempty" yaml:"question,omitempty"`             // order of the screener question
+	MetadataField *string  `json:"metadata_field,omitempty" yaml:"metadata_field,omitempty"` // language, timezone
+	Value         string   `json:"value" yaml:"value"`
+	Limit         *int     `json:"limit,omitempty" yaml:"limit,omitempty"`
+	Percentage    *float64 `json:"percentage,omitempty" yaml:"percentage,omitempty"`
+}
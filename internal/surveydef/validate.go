@@ -0,0 +1,209 @@
+// internal/surveydef/validate.go
+package surveydef
+
+import (
+	"fmt"
+	"strings"
+	"survey2earn-backend/internal/apperror"
+	"survey2earn-backend/internal/models"
+	"unicode/utf8"
+)
+
+// Problem describes a single issue found while validating a definition
+type Problem struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every problem found in a definition
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		messages[i] = p.Path + ": " + p.Message
+	}
+	return "invalid survey definition: " + strings.Join(messages, "; ")
+}
+
+// AppError lists every problem as a field error on its path
+func (e *ValidationError) AppError() *apperror.Error {
+	fields := make([]apperror.FieldError, len(e.Problems))
+	for i, p := range e.Problems {
+		fields[i] = apperror.FieldError{Field: p.Path, Code: "invalid", Message: p.Message}
+	}
+	return &apperror.Error{
+		Code:    apperror.InvalidDefinition,
+		Message: "The survey definition is invalid",
+		Fields:  fields,
+	}
+}
+
+// questionTypesWithOptions lists the question types that need at least one option
+var questionTypesWithOptions = map[models.QuestionType]bool{
+	models.QuestionTypeSingleChoice:   true,
+	models.QuestionTypeMultipleChoice: true,
+	models.QuestionTypeMatrix:         true,
+	models.QuestionTypeRanking:        true,
+	models.QuestionTypeConstantSum:    true,
+}
+
+// Validate checks the definition against the schema, returning a
+// *ValidationError listing every problem found
+func (d *SurveyDefinition) Validate() error {
+	v := &validator{}
+
+	switch {
+	case d.SchemaVersion == 0:
+		v.add("schema_version", "is required")
+	case d.SchemaVersion < 0 || d.SchemaVersion > SchemaVersion:
+		v.add("schema_version", fmt.Sprintf("version %d is not supported (latest is %d)", d.SchemaVersion, SchemaVersion))
+	}
+
+	if length := utf8.RuneCountInString(d.Title); length < 3 || length > 255 {
+		v.add("title", "must be between 3 and 255 characters")
+	}
+	if d.Category == "" {
+		v.add("category", "is required")
+	}
+	if _, ok := models.NormalizeSurveyLanguage(d.Language); !ok {
+		v.add("language", "must be a language code such as en or pt-BR")
+	}
+	if d.Settings.MaxResponses <= 0 {
+		v.add("settings.max_responses", "must be greater than 0")
+	}
+	if d.Settings.RewardPerResponse <= 0 {
+		v.add("settings.reward_per_response", "must be greater than 0")
+	}
+	if d.Settings.TimeLimitMinutes != nil && *d.Settings.TimeLimitMinutes <= 0 {
+		v.add("settings.time_limit_minutes", "must be greater than 0")
+	}
+	if d.Settings.StartDate != nil && d.Settings.EndDate != nil && d.Settings.EndDate.Before(*d.Settings.StartDate) {
+		v.add("settings.end_date", "must be after start_date")
+	}
+
+	if len(d.Questions) == 0 {
+		v.add("questions", "at least one question is required")
+	}
+	orders := make(map[int]bool, len(d.Questions))
+	for i, q := range d.Questions {
+		path := fmt.Sprintf("questions[%d]", i)
+		if q.Order <= 0 {
+			v.add(path+".order", "must be greater than 0")
+		} else if orders[q.Order] {
+			v.add(path+".order", fmt.Sprintf("duplicate order %d", q.Order))
+		}
+		orders[q.Order] = true
+
+		v.validateQuestion(path, &q)
+	}
+
+	// Conditions may only reference earlier questions
+	for i, q := range d.Questions {
+		if q.ShowIf == nil {
+			continue
+		}
+		path := fmt.Sprintf("questions[%d].show_if", i)
+		if !orders[q.ShowIf.Question] {
+			v.add(path+".question", fmt.Sprintf("references unknown question %d", q.ShowIf.Question))
+		} else if q.ShowIf.Question >= q.Order {
+			v.add(path+".question", "must reference an earlier question")
+		}
+		if !models.IsValidConditionOperator(q.ShowIf.Operator) {
+			v.add(path+".operator", fmt.Sprintf("unknown operator %q", q.ShowIf.Operator))
+		}
+	}
+
+	for i, q := range d.Quotas {
+		path := fmt.Sprintf("quotas[%d]", i)
+		if q.Name == "" {
+			v.add(path+".name", "is required")
+		}
+		if q.Question != nil && !orders[*q.Question] {
+			v.add(path+".question", fmt.Sprintf("references unknown question %d", *q.Question))
+		}
+
+		// Reuse the model rules for the condition and limits
+		quota := models.SurveyQuota{
+			Value:      q.Value,
+			Limit:      q.Limit,
+			Percentage: q.Percentage,
+		}
+		if q.Question != nil {
+			questionID := uint(*q.Question)
+			quota.QuestionID = &questionID
+		}
+		if q.MetadataField != nil {
+			field := models.QuotaMetadataField(*q.MetadataField)
+			quota.MetadataField = &field
+		}
+		if err := quota.Validate(); err != nil {
+			v.add(path, err.Error())
+		}
+	}
+
+	return v.err()
+}
+
+func (v *validator) validateQuestion(path string, q *QuestionDefinition) {
+	questionType := models.QuestionType(q.Type)
+	if !questionType.IsValid() {
+		v.add(path+".type", fmt.Sprintf("unknown question type %q", q.Type))
+	}
+	if strings.TrimSpace(q.Text) == "" {
+		v.add(path+".text", "is required")
+	}
+
+	if questionTypesWithOptions[questionType] && len(q.Options) == 0 {
+		v.add(path+".options", "at least one option is required")
+	}
+	v.validateOptions(path+".options", q.Options)
+
+	if questionType == models.QuestionTypeMatrix && len(q.Rows) == 0 {
+		v.add(path+".rows", "at least one row is required")
+	}
+	v.validateOptions(path+".rows", q.Rows)
+
+	if q.MinLength != nil && q.MaxLength != nil && *q.MinLength > *q.MaxLength {
+		v.add(path+".max_length", "must not be less than min_length")
+	}
+	if q.MinValue != nil && q.MaxValue != nil && *q.MinValue > *q.MaxValue {
+		v.add(path+".max_value", "must not be less than min_value")
+	}
+	if questionType == models.QuestionTypeConstantSum && q.SumTotal != nil && *q.SumTotal <= 0 {
+		v.add(path+".sum_total", "must be greater than 0")
+	}
+}
+
+func (v *validator) validateOptions(path string, options []Option) {
+	values := make(map[string]bool, len(options))
+	for i, opt := range options {
+		optionPath := fmt.Sprintf("%s[%d]", path, i)
+		if opt.Label == "" {
+			v.add(optionPath+".label", "is required")
+		}
+		if opt.Value == "" {
+			v.add(optionPath+".value", "is required")
+		} else if values[opt.Value] {
+			v.add(optionPath+".value", fmt.Sprintf("duplicate value %q", opt.Value))
+		}
+		values[opt.Value] = true
+	}
+}
+
+type validator struct {
+	problems []Problem
+}
+
+func (v *validator) add(path, message string) {
+	v.problems = append(v.problems, Problem{Path: path, Message: message})
+}
+
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: v.problems}
+}
@@ -0,0 +1,157 @@
+// internal/textanalysis/sentiment.go
+package textanalysis
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Sentiment labels
+const (
+	Positive = "positive"
+	Neutral  = "neutral"
+	Negative = "negative"
+)
+
+const (
+	// sentimentThreshold separates positive and negative scores from neutral ones
+	sentimentThreshold = 0.05
+
+	// negationScope is how many words after a negation it applies to
+	negationScope = 3
+
+	// negationFactor flips and dampens the valence of a negated word
+	negationFactor = -0.75
+
+	// normalizationAlpha controls how fast summed valences approach ±1
+	normalizationAlpha = 15
+)
+
+// SentimentScore rates tokens from -1 (negative) to 1 (positive) by summing
+// the valence of known words, flipping words that follow a negation
+func SentimentScore(tokens []string, language string) float64 {
+	language = Language(language)
+	lexicon, negations := lexicons[language], negators[language]
+
+	sum := 0.0
+	negated := 0
+	for _, token := range tokens {
+		if negations[token] {
+			negated = negationScope
+			continue
+		}
+		if valence, ok := lexicon[token]; ok {
+			if negated > 0 {
+				valence *= negationFactor
+			}
+			sum += valence
+		}
+		if negated > 0 {
+			negated--
+		}
+	}
+
+	return sum / math.Sqrt(sum*sum+normalizationAlpha)
+}
+
+// SentimentLabel classifies a sentiment score
+func SentimentLabel(score float64) string {
+	switch {
+	case score >= sentimentThreshold:
+		return Positive
+	case score <= -sentimentThreshold:
+		return Negative
+	default:
+		return Neutral
+	}
+}
+
+var negators = map[string]map[string]bool{
+	"en": wordSet(`not no never none nobody nothing neither nor without hardly barely can't cannot couldn't didn't
+		doesn't don't hadn't hasn't haven't isn't aren't wasn't weren't won't wouldn't shouldn't mustn't`),
+	"es": wordSet(`no nunca jamás tampoco ni nada nadie ninguno ninguna sin`),
+	"fr": wordSet(`ne pas jamais rien personne aucun aucune sans ni`),
+	"de": wordSet(`nicht kein keine keinen keinem keiner nie niemals nichts ohne weder`),
+	"pt": wordSet(`não nunca jamais nem nada ninguém nenhum nenhuma sem`),
+	"it": wordSet(`non mai né niente nulla nessuno nessuna senza`),
+}
+
+// lexicons rate words from -3 to 3
+var lexicons = map[string]map[string]float64{
+	"en": valenceSet(`
+		3: amazing awesome excellent fantastic outstanding perfect superb wonderful brilliant exceptional love loved
+		   loving incredible phenomenal
+		2: good great nice happy pleased glad enjoy enjoyed enjoyable helpful useful easy intuitive fast quick
+		   reliable recommend recommended satisfied beautiful friendly impressive impressed liked lovely smooth
+		   clean clear convenient efficient effective fun valuable worth best better favorite favourite
+		1: fine ok okay decent fair simple solid improved improving interesting thanks thank affordable cheap
+		   works working polite
+		-1: slow confusing confused unclear expensive boring complicated difficult hard lacking limited missing
+		    average mediocre meh issue issues problem problems bug bugs glitch glitchy outdated annoying
+		-2: bad poor disappointing disappointed frustrating frustrated broken useless unhappy hate hated dislike
+		    disliked ugly crash crashes crashed crashing fail failed fails failure error errors wrong waste rude
+		    unreliable laggy overpriced worse
+		-3: awful terrible horrible worst disgusting unacceptable scam pathetic abysmal atrocious`),
+	"es": valenceSet(`
+		3: excelente increíble maravilloso maravillosa perfecto perfecta fantástico fantástica encanta encantó
+		2: bueno buena buenos buenas genial feliz fácil útil rápido rápida recomiendo agradable satisfecho
+		   satisfecha mejor gusta gustó bonito bonita eficiente
+		1: bien correcto correcta interesante gracias simple barato
+		-1: lento lenta confuso confusa caro cara aburrido aburrida difícil complicado problema problemas error
+		-2: malo mala decepcionante frustrante inútil roto rota odio peor fallo falla molesto
+		-3: horrible terrible pésimo pésima asqueroso inaceptable estafa`),
+	"fr": valenceSet(`
+		3: excellent excellente parfait parfaite incroyable merveilleux merveilleuse fantastique génial géniale adore
+		2: bon bonne super heureux heureuse facile utile rapide agréable satisfait satisfaite recommande meilleur
+		   meilleure aime efficace beau belle
+		1: bien correct correcte intéressant intéressante merci simple
+		-1: lent lente confus confuse cher chère ennuyeux difficile compliqué problème problèmes erreur bug
+		-2: mauvais mauvaise décevant décevante frustrant inutile cassé déteste pire panne énervant
+		-3: horrible terrible affreux nul nulle inacceptable arnaque`),
+	"de": valenceSet(`
+		3: ausgezeichnet hervorragend perfekt großartig fantastisch wunderbar toll liebe
+		2: gut gute guten schön einfach nützlich schnell hilfreich zufrieden empfehlen empfehle besser beste
+		   angenehm freundlich effizient
+		1: okay ordentlich interessant danke günstig
+		-1: langsam verwirrend teuer langweilig schwierig kompliziert problem probleme fehler
+		-2: schlecht schlechte enttäuschend frustrierend nutzlos kaputt hasse schlechter ärgerlich
+		-3: schrecklich furchtbar katastrophal inakzeptabel betrug`),
+	"pt": valenceSet(`
+		3: excelente incrível maravilhoso maravilhosa perfeito perfeita fantástico fantástica adoro adorei
+		2: bom boa bons boas ótimo ótima feliz fácil útil rápido rápida recomendo agradável satisfeito satisfeita
+		   melhor gosto gostei bonito bonita eficiente
+		1: legal interessante obrigado obrigada simples barato
+		-1: lento lenta confuso confusa caro cara chato chata difícil complicado problema problemas erro
+		-2: ruim mau má decepcionante frustrante inútil quebrado odeio pior falha
+		-3: horrível terrível péssimo péssima inaceitável golpe`),
+	"it": valenceSet(`
+		3: eccellente perfetto perfetta incredibile meraviglioso meravigliosa fantastico fantastica adoro
+		2: buono buona bello bella felice facile utile veloce piacevole soddisfatto soddisfatta consiglio migliore
+		   piace piaciuto efficiente ottimo ottima
+		1: bene corretto interessante grazie semplice economico
+		-1: lento lenta confuso confusa caro cara noioso difficile complicato problema problemi errore
+		-2: cattivo cattiva deludente frustrante inutile rotto odio peggio peggiore guasto
+		-3: orribile terribile pessimo pessima inaccettabile truffa`),
+}
+
+// valenceSet parses lines of "<valence>: <words>", where a line without a
+// valence continues the previous one
+func valenceSet(text string) map[string]float64 {
+	set := make(map[string]float64)
+	valence := 0.0
+	for _, line := range strings.Split(text, "\n") {
+		if label, words, found := strings.Cut(line, ":"); found {
+			value, err := strconv.ParseFloat(strings.TrimSpace(label), 64)
+			if err != nil {
+				panic("textanalysis: invalid valence " + label)
+			}
+			valence = value
+			line = words
+		}
+		for _, word := range strings.Fields(line) {
+			set[word] = valence
+		}
+	}
+	return set
+}
@@ -0,0 +1,241 @@
+// internal/textanalysis/state.go
+package textanalysis
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxClusters bounds the number of groups of similar answers. Once
+	// reached, answers not similar to any group are left unclustered.
+	MaxClusters = 10
+
+	// clusterSimilarity is the minimum cosine similarity between an answer
+	// and a cluster centroid for the answer to join the cluster
+	clusterSimilarity = 0.3
+
+	// centroidTerms bounds the number of weighted terms kept per centroid
+	centroidTerms = 30
+
+	// clusterExamples is the number of sample answers kept per cluster
+	clusterExamples = 3
+
+	// maxExampleLength truncates sample answers, in characters
+	maxExampleLength = 200
+
+	// maxVocabulary bounds the terms tracked; beyond it, terms seen in a
+	// single answer are forgotten
+	maxVocabulary = 10000
+)
+
+// State is the running analysis of a set of answers. It is serialized as
+// JSON and updated with Add as answers arrive, so adding an answer costs
+// the same however many answers came before it. Clustering is online: each
+// answer joins the most similar cluster at the time it is added, weighted
+// by TF-IDF over the answers seen so far.
+type State struct {
+	Documents         int            `json:"documents"`
+	TermCounts        map[string]int `json:"term_counts"`
+	DocumentFrequency map[string]int `json:"document_frequency"`
+
+	Positive     int     `json:"positive"`
+	Neutral      int     `json:"neutral"`
+	Negative     int     `json:"negative"`
+	SentimentSum float64 `json:"sentiment_sum"`
+
+	Clusters    []Cluster `json:"clusters"`
+	Unclustered int       `json:"unclustered"` // answers with keywords that fit no cluster
+}
+
+// Cluster is a group of similar answers
+type Cluster struct {
+	Size     int                `json:"size"`
+	Centroid map[string]float64 `json:"centroid"` // mean TF-IDF vector, truncated to the heaviest terms
+	Examples []string           `json:"examples"`
+}
+
+// Keyword is the frequency of a term across answers
+type Keyword struct {
+	Term      string
+	Count     int // occurrences
+	Documents int // answers containing the term
+}
+
+// Add analyzes an answer written in a language and adds it to the state
+func (s *State) Add(text, language string) {
+	if s.TermCounts == nil {
+		s.TermCounts = make(map[string]int)
+	}
+	if s.DocumentFrequency == nil {
+		s.DocumentFrequency = make(map[string]int)
+	}
+
+	tokens := Tokenize(text)
+	s.Documents++
+
+	score := SentimentScore(tokens, language)
+	s.SentimentSum += score
+	switch SentimentLabel(score) {
+	case Positive:
+		s.Positive++
+	case Negative:
+		s.Negative++
+	default:
+		s.Neutral++
+	}
+
+	frequencies := make(map[string]int)
+	for _, term := range Keywords(tokens, language) {
+		frequencies[term]++
+	}
+	if len(frequencies) == 0 {
+		return
+	}
+	for term, count := range frequencies {
+		s.TermCounts[term] += count
+		s.DocumentFrequency[term]++
+	}
+
+	s.cluster(s.vector(frequencies), text)
+	s.prune()
+}
+
+// AverageSentiment is the mean sentiment score of the answers
+func (s *State) AverageSentiment() float64 {
+	if s.Documents == 0 {
+		return 0
+	}
+	return s.SentimentSum / float64(s.Documents)
+}
+
+// TopKeywords returns the n most frequent terms
+func (s *State) TopKeywords(n int) []Keyword {
+	keywords := make([]Keyword, 0, len(s.TermCounts))
+	for term, count := range s.TermCounts {
+		keywords = append(keywords, Keyword{Term: term, Count: count, Documents: s.DocumentFrequency[term]})
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Count != keywords[j].Count {
+			return keywords[i].Count > keywords[j].Count
+		}
+		return keywords[i].Term < keywords[j].Term
+	})
+	if len(keywords) > n {
+		keywords = keywords[:n]
+	}
+	return keywords
+}
+
+// TopTerms returns the n heaviest terms of the cluster centroid, which describe it
+func (c *Cluster) TopTerms(n int) []string {
+	terms := heaviestTerms(c.Centroid)
+	if len(terms) > n {
+		terms = terms[:n]
+	}
+	return terms
+}
+
+// vector weights term frequencies by inverse document frequency and normalizes the result
+func (s *State) vector(frequencies map[string]int) map[string]float64 {
+	vector := make(map[string]float64, len(frequencies))
+	norm := 0.0
+	for term, count := range frequencies {
+		idf := math.Log(float64(1+s.Documents)/float64(1+s.DocumentFrequency[term])) + 1
+		weight := float64(count) * idf
+		vector[term] = weight
+		norm += weight * weight
+	}
+	norm = math.Sqrt(norm)
+	for term := range vector {
+		vector[term] /= norm
+	}
+	return vector
+}
+
+func (s *State) cluster(vector map[string]float64, text string) {
+	best, bestSimilarity := -1, 0.0
+	for i := range s.Clusters {
+		if similarity := cosine(vector, s.Clusters[i].Centroid); similarity > bestSimilarity {
+			best, bestSimilarity = i, similarity
+		}
+	}
+
+	if best < 0 || bestSimilarity < clusterSimilarity {
+		if len(s.Clusters) >= MaxClusters {
+			s.Unclustered++
+			return
+		}
+		s.Clusters = append(s.Clusters, Cluster{Centroid: make(map[string]float64)})
+		best = len(s.Clusters) - 1
+	}
+
+	cluster := &s.Clusters[best]
+	size := float64(cluster.Size)
+	for term := range cluster.Centroid {
+		cluster.Centroid[term] *= size / (size + 1)
+	}
+	for term, weight := range vector {
+		cluster.Centroid[term] += weight / (size + 1)
+	}
+	if len(cluster.Centroid) > centroidTerms {
+		for _, term := range heaviestTerms(cluster.Centroid)[centroidTerms:] {
+			delete(cluster.Centroid, term)
+		}
+	}
+	cluster.Size++
+	if len(cluster.Examples) < clusterExamples {
+		cluster.Examples = append(cluster.Examples, example(text))
+	}
+}
+
+// prune forgets the rarest terms once the vocabulary grows too large
+func (s *State) prune() {
+	if len(s.TermCounts) <= maxVocabulary {
+		return
+	}
+	for term, documents := range s.DocumentFrequency {
+		if documents <= 1 {
+			delete(s.DocumentFrequency, term)
+			delete(s.TermCounts, term)
+		}
+	}
+}
+
+func cosine(a, b map[string]float64) float64 {
+	dot, normB := 0.0, 0.0
+	for term, weight := range b {
+		dot += a[term] * weight
+		normB += weight * weight
+	}
+	if normB == 0 {
+		return 0
+	}
+	// a is normalized
+	return dot / math.Sqrt(normB)
+}
+
+func heaviestTerms(weights map[string]float64) []string {
+	terms := make([]string, 0, len(weights))
+	for term := range weights {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if weights[terms[i]] != weights[terms[j]] {
+			return weights[terms[i]] > weights[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	return terms
+}
+
+func example(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= maxExampleLength {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:maxExampleLength]) + "…"
+}
@@ -0,0 +1,46 @@
+// internal/textanalysis/stopwords.go
+package textanalysis
+
+import "strings"
+
+// stopwords per base language, excluded from keywords and clustering
+var stopwords = map[string]map[string]bool{
+	"en": wordSet(`a about above after again against all also am an and any are aren't as at be because been
+		before being below between both but by can can't cannot could couldn't did didn't do does doesn't doing
+		don't down during each even ever few for from further get got had hadn't has hasn't have haven't having
+		he her here hers herself him himself his how however i i'd i'll i'm i've if in into is isn't it it's its
+		itself just let's like lot lots me more most much mustn't my myself no nor not now of off on once only or
+		other ought our ours ourselves out over own really same shan't she should shouldn't so some such than that
+		that's the their theirs them themselves then there there's these they they'd they'll they're they've this
+		those through to too under until up us very was wasn't we we'd we'll we're we've were weren't what what's
+		when where which while who whom why will with won't would wouldn't yes you you'd you'll you're you've your
+		yours yourself yourselves`),
+	"es": wordSet(`a al algo algunas algunos ante antes como con contra cual cuando de del desde donde durante e el
+		ella ellas ellos en entre era eran es esa esas ese eso esos esta estaba estado estan estar este esto estos
+		está están fue fueron ha han hasta hay la las le les lo los me mi mis mucho muy más ni no nos nosotros nunca
+		o os otra otro para pero poco por porque que quien se sea ser si sin sobre son su sus también tanto te tiene
+		tengo todo todos tu tus un una uno unos y ya yo él qué sí`),
+	"fr": wordSet(`ai à au aux avec ce ces cette comme dans de des du elle elles en est et été être eu il ils
+		je la le les leur leurs lui ma mais me même mes moi mon ne ni nos notre nous on ou où par pas plus pour
+		que qui sa sans se ses si son sont sur ta te tes toi ton tous tout très tu un une vos votre vous y
+		était avait fait bien aussi`),
+	"de": wordSet(`aber alle als also am an auch auf aus bei bin bis bist da damit dann das dass dem den der des
+		die dies diese dieser doch du durch ein eine einem einen einer es für gegen hab habe haben hat hatte ich ihr
+		im in ist ja jetzt kann kein keine man mehr mein meine mich mir mit nach nicht nie noch nur ob oder ohne schon
+		sehr sein sich sie sind so über um und uns unter vom von vor war waren was weil wenn wer wie wir wird zu zum
+		zur`),
+	"pt": wordSet(`a ao aos as até com como da das de dela dele do dos e ela ele eles em entre era essa esse esta
+		este eu foi for há isso isto já lhe mais mas me meu minha muito na nas nem no nos não nunca o os ou para pela
+		pelo por porque que quando se sem ser seu sua são também tem tenho um uma você é está estão`),
+	"it": wordSet(`a ai al alla alle anche che chi ci come con da dal dalla degli dei del della delle di e è ed gli
+		ha hanno ho i il in io la le lei lo loro lui ma mai mi mia mio molto ne nei nel nella né noi non o per più
+		perché quando questa questo se si sono su sua suo tra tu tutto un una uno voi`),
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
@@ -0,0 +1,79 @@
+// internal/textanalysis/textanalysis.go
+
+// Package textanalysis summarizes free-text answers offline: keyword
+// frequencies, a lexicon based sentiment score and groups of similar answers.
+// A State is built one answer at a time so it can be stored and updated as
+// responses complete instead of being recomputed from every answer.
+package textanalysis
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultLanguage is used for answers in a language without stopwords or a lexicon
+const DefaultLanguage = "en"
+
+// Tokenize lowercases text and splits it into words. Numbers and single
+// letters are dropped; apostrophes inside words are kept so contractions
+// like "don't" still read as negations, while elided articles such as the
+// "l'" of "l'application" are removed.
+func Tokenize(text string) []string {
+	text = strings.ReplaceAll(strings.ToLower(text), "’", "'")
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+
+	tokens := words[:0]
+	for _, word := range words {
+		word = strings.Trim(word, "'")
+		if prefix, rest, found := strings.Cut(word, "'"); found && elisions[prefix] {
+			word = rest
+		}
+		if utf8.RuneCountInString(word) < 2 || isNumber(word) {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// Language reduces a language tag such as "pt-BR" to a supported base
+// language, falling back to DefaultLanguage
+func Language(tag string) string {
+	base := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	if _, ok := stopwords[base]; ok {
+		return base
+	}
+	return DefaultLanguage
+}
+
+// Keywords removes the stopwords of a language from tokens
+func Keywords(tokens []string, language string) []string {
+	words := stopwords[Language(language)]
+	keywords := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if !words[token] {
+			keywords = append(keywords, token)
+		}
+	}
+	return keywords
+}
+
+// elisions are the articles and pronouns French and Italian contract onto the next word
+var elisions = map[string]bool{
+	"c": true, "d": true, "j": true, "l": true, "m": true, "n": true, "s": true, "t": true, "qu": true,
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
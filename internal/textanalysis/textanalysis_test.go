@@ -0,0 +1,197 @@
+// internal/textanalysis/textanalysis_test.go
+package textanalysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Don't LOVE it!!", []string{"don't", "love", "it"}},
+		{"I don’t know", []string{"don't", "know"}}, // typographic apostrophe
+		{"I paid 100 USD, 2x", []string{"paid", "usd", "2x"}},
+		{"'quoted' words", []string{"quoted", "words"}},
+		{"L’application est géniale", []string{"application", "est", "géniale"}},
+		{"qu'il dell'app", []string{"il", "dell'app"}},
+		{"über-cool ñandú", []string{"über", "cool", "ñandú"}},
+		{"  ... 42 !", []string{}},
+	}
+	for _, test := range tests {
+		if got := Tokenize(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	for tag, want := range map[string]string{"en": "en", "pt-BR": "pt", " DE_at ": "de", "fr-CA": "fr", "ja": DefaultLanguage, "": DefaultLanguage} {
+		if got := Language(tag); got != want {
+			t.Errorf("Language(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	tests := []struct {
+		text, language string
+		want           []string
+	}{
+		{"The app is really fast and I like it", "en", []string{"app", "fast"}},
+		{"La aplicación es muy rápida", "es-MX", []string{"aplicación", "rápida"}},
+		{"Die App ist sehr schnell", "de", []string{"app", "schnell"}},
+		{"The app is fast", "ja", []string{"app", "fast"}}, // English stopwords
+		{"la app", "en", []string{"la", "app"}},            // Spanish stopwords don't apply
+	}
+	for _, test := range tests {
+		if got := Keywords(Tokenize(test.text), test.language); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Keywords(%q, %s) = %q, want %q", test.text, test.language, got, test.want)
+		}
+	}
+}
+
+func TestSentiment(t *testing.T) {
+	tests := []struct {
+		text, language string
+		score          float64
+		label          string
+	}{
+		{"great", "en", 2 / math.Sqrt(4+normalizationAlpha), Positive},
+		{"not great", "en", -1.5 / math.Sqrt(2.25+normalizationAlpha), Negative},
+		{"not really very great", "en", -1.5 / math.Sqrt(2.25+normalizationAlpha), Negative},
+		{"not that the app is great", "en", 2 / math.Sqrt(4+normalizationAlpha), Positive}, // past the negation's scope
+		{"great but slow", "en", 1 / math.Sqrt(1+normalizationAlpha), Positive},
+		{"the app", "en", 0, Neutral},
+		{"es muy malo", "es", -2 / math.Sqrt(4+normalizationAlpha), Negative},
+		{"no es malo", "es", 1.5 / math.Sqrt(2.25+normalizationAlpha), Positive},
+		{"terrible", "ja", -3 / math.Sqrt(9+normalizationAlpha), Negative},
+	}
+	for _, test := range tests {
+		score := SentimentScore(Tokenize(test.text), test.language)
+		if math.Abs(score-test.score) > 1e-12 || SentimentLabel(score) != test.label {
+			t.Errorf("sentiment of %q = %v (%s), want %v (%s)", test.text, score, SentimentLabel(score), test.score, test.label)
+		}
+	}
+}
+
+func TestStateAdd(t *testing.T) {
+	answers := []struct{ text, language string }{
+		{"The app is great, great support", "en"},
+		{"Support was slow", "en"},
+		{"", "en"},
+		{"La aplicación es genial", "es"},
+	}
+	var state State
+	for _, answer := range answers {
+		state.Add(answer.text, answer.language)
+	}
+
+	if state.Documents != 4 || state.Positive != 2 || state.Negative != 1 || state.Neutral != 1 {
+		t.Errorf("documents, positive, negative, neutral = %d, %d, %d, %d, want 4, 2, 1, 1",
+			state.Documents, state.Positive, state.Negative, state.Neutral)
+	}
+	wantCounts := map[string]int{"app": 1, "great": 2, "support": 2, "slow": 1, "aplicación": 1, "genial": 1}
+	if !reflect.DeepEqual(state.TermCounts, wantCounts) {
+		t.Errorf("term counts = %v, want %v", state.TermCounts, wantCounts)
+	}
+	wantFrequency := map[string]int{"app": 1, "great": 1, "support": 2, "slow": 1, "aplicación": 1, "genial": 1}
+	if !reflect.DeepEqual(state.DocumentFrequency, wantFrequency) {
+		t.Errorf("document frequency = %v, want %v", state.DocumentFrequency, wantFrequency)
+	}
+	wantKeywords := []Keyword{{Term: "great", Count: 2, Documents: 1}, {Term: "support", Count: 2, Documents: 2}}
+	if got := state.TopKeywords(2); !reflect.DeepEqual(got, wantKeywords) {
+		t.Errorf("TopKeywords(2) = %+v, want %+v", got, wantKeywords)
+	}
+	want := (4/math.Sqrt(16+normalizationAlpha) - 1/math.Sqrt(1+normalizationAlpha) + 2/math.Sqrt(4+normalizationAlpha)) / 4
+	if got := state.AverageSentiment(); math.Abs(got-want) > 1e-12 {
+		t.Errorf("average sentiment = %v, want %v", got, want)
+	}
+}
+
+// TestStateResumes adds answers to a state stored and loaded in between,
+// as the analysis is kept between responses
+func TestStateResumes(t *testing.T) {
+	answers := []string{"fees are too high", "high fees everywhere", "the wallet keeps crashing", "wallet crashing again", "great wallet"}
+
+	var whole State
+	for _, answer := range answers {
+		whole.Add(answer, "en")
+	}
+
+	var resumed State
+	for _, answer := range answers {
+		stored, err := json.Marshal(&resumed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resumed = State{}
+		if err := json.Unmarshal(stored, &resumed); err != nil {
+			t.Fatal(err)
+		}
+		resumed.Add(answer, "en")
+	}
+
+	// Centroids are compared loosely, summing over a map isn't ordered
+	wholeClusters, resumedClusters := whole.Clusters, resumed.Clusters
+	whole.Clusters, resumed.Clusters = nil, nil
+	if !reflect.DeepEqual(resumed, whole) {
+		t.Errorf("resumed state = %+v, want %+v", resumed, whole)
+	}
+	if len(resumedClusters) != len(wholeClusters) {
+		t.Fatalf("resumed state has %d clusters, want %d", len(resumedClusters), len(wholeClusters))
+	}
+	for i, cluster := range resumedClusters {
+		if cluster.Size != wholeClusters[i].Size || !reflect.DeepEqual(cluster.TopTerms(3), wholeClusters[i].TopTerms(3)) {
+			t.Errorf("resumed cluster %d = %+v, want %+v", i, cluster, wholeClusters[i])
+		}
+	}
+}
+
+func TestStateClusters(t *testing.T) {
+	var state State
+	for _, answer := range []string{"Fees are too high", "high   fees\neverywhere", "The wallet keeps crashing", "wallet crashing again", "the"} {
+		state.Add(answer, "en")
+	}
+
+	// Answers without keywords join no cluster and aren't unclustered either
+	if len(state.Clusters) != 2 || state.Unclustered != 0 {
+		t.Fatalf("clusters = %+v, unclustered = %d, want 2 clusters", state.Clusters, state.Unclustered)
+	}
+	fees, wallet := state.Clusters[0], state.Clusters[1]
+	if fees.Size != 2 || !reflect.DeepEqual(fees.TopTerms(2), []string{"fees", "high"}) ||
+		!reflect.DeepEqual(fees.Examples, []string{"Fees are too high", "high fees everywhere"}) {
+		t.Errorf("first cluster = %+v, want the answers about fees", fees)
+	}
+	if wallet.Size != 2 || !reflect.DeepEqual(wallet.TopTerms(2), []string{"crashing", "wallet"}) {
+		t.Errorf("second cluster = %+v, want the answers about crashes", wallet)
+	}
+}
+
+func TestStateClusterLimits(t *testing.T) {
+	var state State
+	for i := 0; i <= MaxClusters; i++ {
+		state.Add(fmt.Sprintf("topic%c", 'a'+i), "en")
+	}
+	if len(state.Clusters) != MaxClusters || state.Unclustered != 1 {
+		t.Errorf("%d clusters and %d unclustered, want %d and 1", len(state.Clusters), state.Unclustered, MaxClusters)
+	}
+
+	// Examples are capped in number and length
+	for i := 0; i < clusterExamples+1; i++ {
+		state.Add("topica "+strings.Repeat("é", maxExampleLength), "en")
+	}
+	examples := state.Clusters[0].Examples
+	if len(examples) != clusterExamples {
+		t.Fatalf("%d examples, want %d", len(examples), clusterExamples)
+	}
+	if got := examples[1]; got != "topica "+strings.Repeat("é", maxExampleLength-7)+"…" {
+		t.Errorf("long example = %q, want %d characters and an ellipsis", got, maxExampleLength)
+	}
+}
@@ -0,0 +1,87 @@
+// internal/wallet/signature.go
+
+// Package wallet verifies that a message was signed by an EVM wallet, as
+// wallets do for "personal_sign" (EIP-191), so users can sign in with the
+// wallet that holds their rewards.
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	ErrInvalidAddress   = errors.New("invalid wallet address")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrSignerMismatch   = errors.New("message was not signed by the wallet")
+)
+
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// NormalizeAddress validates a wallet address and returns it in lower case,
+// as addresses are stored
+func NormalizeAddress(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if !addressPattern.MatchString(address) {
+		return "", ErrInvalidAddress
+	}
+	return strings.ToLower(address), nil
+}
+
+// VerifySignature checks that signature, a hex encoded 65 byte r||s||v
+// signature, was made over message by the wallet at address
+func VerifySignature(address, message, signature string) error {
+	address, err := NormalizeAddress(address)
+	if err != nil {
+		return err
+	}
+
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(sig) != 65 {
+		return ErrInvalidSignature
+	}
+
+	// Wallets set v to 27 or 28; some send the bare recovery ID
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return ErrInvalidSignature
+	}
+
+	// Compact signatures put the recovery code first
+	compact := make([]byte, 65)
+	compact[0] = 27 + v
+	copy(compact[1:], sig[:64])
+
+	publicKey, _, err := ecdsa.RecoverCompact(compact, messageHash(message))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	// The address is the last 20 bytes of the Keccak-256 hash of the
+	// uncompressed public key, without its 0x04 prefix
+	signer := "0x" + hex.EncodeToString(keccak256(publicKey.SerializeUncompressed()[1:])[12:])
+	if signer != address {
+		return ErrSignerMismatch
+	}
+	return nil
+}
+
+// messageHash is the EIP-191 hash wallets sign for personal_sign
+func messageHash(message string) []byte {
+	return keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)))
+}
+
+func keccak256(data []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	return hash.Sum(nil)
+}